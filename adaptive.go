@@ -0,0 +1,187 @@
+package tokenestimate
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// adaptivePrior is the variance of the prior AdaptiveEstimator puts on each
+// coefficient of the wrapped estimator. Observations enter as counts per
+// token, mostly between 0.1 and 5, so the prior weighs about as much as a
+// few observations of texts with the feature: the wrapped coefficients
+// hold until there is evidence against them, and then give way quickly.
+const adaptivePrior = 1.0
+
+// AdaptiveEstimator wraps an estimator and corrects its coefficients from
+// the actual token counts of the texts it is used on, such as the usage
+// reported in API responses, so that it adapts to a tokenizer or a traffic
+// mix that differs from the one the preset was fitted to. Each observation
+// updates the coefficients and the intercept by exponentially weighted
+// recursive least squares on the relative error, starting from the
+// wrapped estimator's coefficients. Dictionary words and nonlinear terms
+// keep their pricing.
+//
+// An AdaptiveEstimator is safe for concurrent use. Estimates never wait
+// for an observation to be processed.
+type AdaptiveEstimator struct {
+	base    *Estimator
+	current atomic.Pointer[Estimator]
+
+	mu           sync.Mutex
+	params       []string // features whose coefficients adapt; the intercept is last
+	column       map[string]int
+	theta        []float64 // coefficients of params
+	p            []float64 // covariance of theta, row-major
+	forgetting   float64
+	observations int
+}
+
+// NewAdaptiveEstimator returns an AdaptiveEstimator starting from e. The
+// weight of an observation halves after halfLife later observations, so
+// that the estimator follows a traffic mix that changes over time; with a
+// halfLife of 0 every observation keeps its weight. If e is nil, the
+// default estimator is used.
+func NewAdaptiveEstimator(e *Estimator, halfLife int) *AdaptiveEstimator {
+	if e == nil {
+		e = NewEstimator()
+	}
+	a := &AdaptiveEstimator{base: e.Clone(), column: make(map[string]int), forgetting: 1}
+	if halfLife > 0 {
+		a.forgetting = math.Exp2(-1 / float64(halfLife))
+	}
+	for _, f := range features {
+		param := a.base.pricedBy(f.name)
+		if _, ok := a.column[param]; param != "" && !ok {
+			a.column[param] = len(a.params)
+			a.params = append(a.params, param)
+			a.theta = append(a.theta, *a.base.coefficientField(param))
+		}
+	}
+	a.params = append(a.params, "")
+	a.theta = append(a.theta, a.base.intercept)
+
+	n := len(a.params)
+	a.p = make([]float64, n*n)
+	for j := range n {
+		a.p[j*n+j] = adaptivePrior
+	}
+	a.current.Store(a.base)
+	return a
+}
+
+// pricedBy returns the feature whose coefficient prices the named feature
+// under the estimator's policies, or "" if the feature has no coefficient,
+// as for DictionaryTokens.
+func (e *Estimator) pricedBy(name string) string {
+	switch {
+	case name == "InvalidBytes" && e.UTF8Mode != UTF8ByteFallback:
+		return e.pricedBy("Unknown")
+	case name == "Unknown" && e.UnknownPolicy != UnknownAsCategory:
+		return "Symbols"
+	case e.coefficientField(name) == nil:
+		return ""
+	}
+	return name
+}
+
+// Estimate returns the estimated token count of text with the coefficients
+// adapted so far.
+func (a *AdaptiveEstimator) Estimate(text string) int {
+	return a.current.Load().Estimate(text)
+}
+
+// Estimator returns a snapshot of the adapted estimator, which later
+// observations do not change, for example to register as a preset.
+func (a *AdaptiveEstimator) Estimator() *Estimator {
+	return a.current.Load().Clone()
+}
+
+// Observations returns the number of observations made so far.
+func (a *AdaptiveEstimator) Observations() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.observations
+}
+
+// Observe updates the coefficients from the actual number of tokens text
+// encodes to. Negative counts are ignored.
+func (a *AdaptiveEstimator) Observe(text string, actualTokens int) {
+	if actualTokens < 0 {
+		return
+	}
+	stats := a.base.Analyze(text)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// The observation is weighted by its token count, so that it is the
+	// relative error that is minimized and long texts do not drown out the
+	// rest.
+	n := len(a.params)
+	w := 1 / float64(max(actualTokens, 1))
+	x := make([]float64, n)
+	for _, f := range features {
+		if j, ok := a.column[a.base.pricedBy(f.name)]; ok {
+			x[j] += w * float64(*f.field(&stats))
+		}
+	}
+	x[n-1] = w
+	fixed := float64(stats.DictionaryTokens)
+	for _, term := range a.base.Terms {
+		fixed += term.value(&stats)
+	}
+	y := w * (float64(actualTokens) - fixed)
+
+	// k = P·x / (λ + xᵀ·P·x); θ += k·(y - xᵀ·θ); P = (P - k·xᵀ·P) / λ.
+	px := make([]float64, n)
+	for i := range n {
+		px[i] = dot(a.p[i*n:(i+1)*n], x)
+	}
+	denom := a.forgetting + dot(x, px)
+	residual := y - dot(x, a.theta)
+	for i := range n {
+		a.theta[i] += px[i] / denom * residual
+	}
+	for i := range n {
+		for j := range n {
+			a.p[i*n+j] = (a.p[i*n+j] - px[i]*px[j]/denom) / a.forgetting
+		}
+	}
+	a.limitCovariance()
+	a.observations++
+
+	coefs := make(map[string]float64, n-1)
+	for j, param := range a.params[:n-1] {
+		coefs[param] = a.theta[j]
+	}
+	adapted, _ := a.base.WithCoefficients(a.theta[n-1], coefs)
+	a.current.Store(adapted)
+}
+
+// limitCovariance keeps the variance of every coefficient within the
+// prior. Forgetting otherwise inflates the variance of the coefficients
+// of features that have not been observed without bound, and the first
+// text with such a feature would then move them arbitrarily far.
+func (a *AdaptiveEstimator) limitCovariance() {
+	n := len(a.params)
+	for j := range n {
+		if v := a.p[j*n+j]; v > adaptivePrior {
+			// Scaling row and column j alike keeps P positive definite.
+			s := math.Sqrt(adaptivePrior / v)
+			for i := range n {
+				a.p[i*n+j] *= s
+				a.p[j*n+i] *= s
+			}
+		}
+	}
+}
+
+// dot returns the dot product of u and v.
+func dot(u, v []float64) float64 {
+	s := 0.0
+	for i := range u {
+		s += u[i] * v[i]
+	}
+	return s
+}
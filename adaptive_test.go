@@ -0,0 +1,135 @@
+package tokenestimate
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// mixedTexts returns n random texts mixing English, Chinese, Russian and
+// numbers.
+func mixedTexts(n int, seed uint64) []string {
+	pieces := []string{
+		"the", "quick", "brown", "fox", "tokenization", "of", "estimate",
+		"你好", "世界", "测试句子", "привет", "мир", "42", "2024", "!", ",",
+	}
+	rng := rand.New(rand.NewPCG(seed, 0))
+	texts := make([]string, n)
+	for i := range texts {
+		words := make([]string, 5+rng.IntN(60))
+		for j := range words {
+			words[j] = pieces[rng.IntN(len(pieces))]
+		}
+		texts[i] = strings.Join(words, " ")
+	}
+	return texts
+}
+
+// otherTokenizer returns an estimator standing in for a tokenizer that
+// prices Chinese and Latin text differently from e.
+func otherTokenizer(t *testing.T, e *Estimator, chinese, latin float64) *Estimator {
+	other, err := e.WithCoefficients(e.intercept, map[string]float64{
+		"ChineseChars": chinese * e.coefChinese,
+		"LatinLetters": latin * e.coefLatinLetters,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return other
+}
+
+// meanRelError returns the mean relative error of estimate against truth
+// on texts.
+func meanRelError(estimate func(string) int, truth *Estimator, texts []string) float64 {
+	sum := 0.0
+	for _, text := range texts {
+		want := truth.Estimate(text)
+		sum += math.Abs(float64(estimate(text)-want)) / float64(max(want, 1))
+	}
+	return sum / float64(len(texts))
+}
+
+func TestAdaptiveEstimator(t *testing.T) {
+	e := NewEstimator()
+	truth := otherTokenizer(t, e, 1.5, 0.8)
+	held := mixedTexts(100, 2)
+	before := meanRelError(e.Estimate, truth, held)
+
+	a := NewAdaptiveEstimator(e, 0)
+	for _, text := range mixedTexts(500, 1) {
+		a.Observe(text, truth.Estimate(text))
+	}
+	if got := a.Observations(); got != 500 {
+		t.Errorf("Expected 500 observations, got %d", got)
+	}
+	after := meanRelError(a.Estimate, truth, held)
+	if after > before/3 || after > 0.03 {
+		t.Errorf("Expected the error of %.3f to fall well below a third, got %.3f", before, after)
+	}
+	if got := meanRelError(a.Estimator().Estimate, truth, held); got != after {
+		t.Errorf("Expected the snapshot to estimate like the adaptive estimator, got %.3f instead of %.3f", got, after)
+	}
+	if got, want := e.Estimate(held[0]), NewEstimator().Estimate(held[0]); got != want {
+		t.Errorf("Observe modified the wrapped estimator: %d instead of %d", got, want)
+	}
+
+	// Negative counts are ignored.
+	a.Observe("hello", -1)
+	if got := a.Observations(); got != 500 {
+		t.Errorf("Expected a negative count to be ignored, got %d observations", got)
+	}
+}
+
+func TestAdaptiveEstimatorHalfLife(t *testing.T) {
+	e := NewEstimator()
+	first, second := otherTokenizer(t, e, 1.5, 0.8), otherTokenizer(t, e, 0.7, 1.2)
+	held := mixedTexts(100, 3)
+
+	a := NewAdaptiveEstimator(e, 50)
+	for _, text := range mixedTexts(300, 4) {
+		a.Observe(text, first.Estimate(text))
+	}
+	for _, text := range mixedTexts(300, 5) {
+		a.Observe(text, second.Estimate(text))
+	}
+	if got := meanRelError(a.Estimate, second, held); got > 0.03 {
+		t.Errorf("Expected the estimator to follow the new traffic, got a mean relative error of %.3f", got)
+	}
+
+	// Without forgetting the old traffic still weighs in.
+	b := NewAdaptiveEstimator(e, 0)
+	for _, text := range mixedTexts(300, 4) {
+		b.Observe(text, first.Estimate(text))
+	}
+	for _, text := range mixedTexts(300, 5) {
+		b.Observe(text, second.Estimate(text))
+	}
+	if meanRelError(b.Estimate, second, held) <= meanRelError(a.Estimate, second, held) {
+		t.Error("Expected forgetting to adapt faster to the new traffic")
+	}
+}
+
+func TestAdaptiveEstimatorConcurrent(t *testing.T) {
+	a := NewAdaptiveEstimator(nil, 100)
+	texts := mixedTexts(50, 6)
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, text := range texts {
+				if (i+g)%2 == 0 {
+					a.Observe(text, len(text)/3)
+				} else {
+					a.Estimate(text)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got := a.Observations(); got != 100 {
+		t.Errorf("Expected 100 observations, got %d", got)
+	}
+}
@@ -0,0 +1,56 @@
+package tokenestimate
+
+// Analysis is the detailed result of AnalyzeDetailed.
+type Analysis struct {
+	Stats      Stats // Statistics of the text, extrapolated from a sample if Sampled
+	Tokens     int   // Estimate from Stats
+	Sampled    bool  // Whether the statistics were extrapolated from a sample
+	SampleSize int   // Number of characters analyzed if Sampled
+
+	// StdErr gives the standard error of each extrapolated Stats field
+	// that is uncertain, by field name. It is nil unless Sampled.
+	StdErr map[string]float64
+
+	// TokensStdErr is the standard error of Tokens due to sampling: the
+	// actual count for the text is within one TokensStdErr of Tokens about
+	// two times in three, and within two about 95% of the time, before
+	// the model's own error. It is 0 unless Sampled, and +Inf when the
+	// sample is too small to tell.
+	TokensStdErr float64
+}
+
+// RelativeError returns TokensStdErr relative to Tokens, for example 0.02
+// for an estimate within about ±2% of what analyzing the whole text would
+// give.
+func (a Analysis) RelativeError() float64 {
+	if a.TokensStdErr == 0 {
+		return 0
+	}
+	return a.TokensStdErr / float64(a.Tokens)
+}
+
+// AnalyzeDetailed is like Analyze followed by EstimateFromStats, and in
+// addition reports how precise the result is when the text was sampled.
+// The standard errors come from the spread between groups of neighbouring
+// samples, as in WithAdaptiveSampling, so callers can tell whether the
+// sampled estimate of a 50MB file is ±2% or ±20%.
+func (e *Estimator) AnalyzeDetailed(text string) Analysis {
+	var r sampleResult
+	var a Analysis
+	e.analyze(text, &a.Stats, &r, nil)
+	a.Tokens = e.EstimateFromStats(a.Stats)
+	if r.size == 0 {
+		return a
+	}
+
+	a.Sampled = true
+	a.SampleSize = r.size
+	_, a.TokensStdErr = standardError(r.tokens[:r.ngroups])
+	a.StdErr = make(map[string]float64)
+	for _, f := range features {
+		if stdErr := r.stdErr(f); stdErr > 0 {
+			a.StdErr[f.name] = stdErr
+		}
+	}
+	return a
+}
@@ -0,0 +1,69 @@
+package tokenestimate
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestAnalyzeDetailed(t *testing.T) {
+	var b strings.Builder
+	for i := range 400 {
+		if i%4 == 0 {
+			b.WriteString(strings.Repeat("数据分析报告显示增长。", i%3+1))
+		} else {
+			b.WriteString("The quarterly report shows steady growth in 12 regions. ")
+		}
+	}
+	mixed := b.String()
+
+	t.Run("Full analysis", func(t *testing.T) {
+		estimator := NewEstimator()
+		a := estimator.AnalyzeDetailed(mixed)
+		if a.Sampled || a.StdErr != nil || a.TokensStdErr != 0 || a.RelativeError() != 0 {
+			t.Errorf("Unsampled analysis reports sampling: %+v", a)
+		}
+		if a.Stats != estimator.Analyze(mixed) || a.Tokens != estimator.Estimate(mixed) {
+			t.Errorf("AnalyzeDetailed = %+v, want Analyze and Estimate", a)
+		}
+	})
+
+	t.Run("Sampled", func(t *testing.T) {
+		estimator := NewEstimator().WithSampling(1000, 500)
+		a := estimator.AnalyzeDetailed(mixed)
+		if !a.Sampled || a.SampleSize != 500 {
+			t.Fatalf("AnalyzeDetailed sampled %v with %d characters, want 500", a.Sampled, a.SampleSize)
+		}
+		if a.Stats != estimator.Analyze(mixed) || a.Tokens != estimator.Estimate(mixed) {
+			t.Errorf("AnalyzeDetailed = %+v, want Analyze and Estimate", a)
+		}
+		if a.StdErr["ChineseChars"] <= 0 || a.StdErr["LatinLetters"] <= 0 || a.TokensStdErr <= 0 {
+			t.Errorf("Expected uncertainty in the mix of scripts, got %+v", a)
+		}
+		if _, ok := a.StdErr["KhmerChars"]; ok {
+			t.Error("Expected no uncertainty for a script the text does not contain")
+		}
+
+		full := NewEstimator().Estimate(mixed)
+		if diff := math.Abs(float64(a.Tokens - full)); diff > 3*a.TokensStdErr {
+			t.Errorf("Sampled estimate %d is %.0f off the full %d, beyond 3 standard errors of %.1f",
+				a.Tokens, diff, full, a.TokensStdErr)
+		}
+	})
+
+	t.Run("Uniform text", func(t *testing.T) {
+		a := NewEstimator().WithSampling(1000, 500).AnalyzeDetailed(strings.Repeat("ab ", 3000))
+		if a.TokensStdErr != 0 || len(a.StdErr) != 0 {
+			t.Errorf("Expected no uncertainty for a uniform text, got %+v", a)
+		}
+	})
+
+	t.Run("Adaptive meets its target", func(t *testing.T) {
+		a := NewEstimator().WithSampling(1000, 50).WithAdaptiveSampling(0.01).AnalyzeDetailed(mixed)
+		if a.RelativeError() > 0.01 && a.SampleSize < utf8.RuneCountInString(mixed) {
+			t.Errorf("Adaptive sampling stopped at %d characters with relative error %.3f",
+				a.SampleSize, a.RelativeError())
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package tokenestimate
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// AnomalyHooks logs estimates of unusual texts with log/slog, so that
+// operators can audit oversized prompts, prompts in scripts a preset does
+// not model, and prompts estimated from a sample, without wrapping every
+// call site. Each condition that holds logs a record of its own, with the
+// preset and the size of the text. The hooks run in Estimate, EstimateFloat,
+// EstimateWithOptions and the methods estimating through them, such as
+// EstimateBytes, Count and EstimateMessages, which log every message
+// separately. Methods that search for a text that fits, such as Truncate,
+// TrimMessages and PromptBuilder.Build, log their result only.
+type AnomalyHooks struct {
+	// Logger receives the records (default: the logger of SetLogger)
+	Logger *slog.Logger
+
+	// Level is the level of the records (default: slog.LevelInfo)
+	Level slog.Level
+
+	// MaxTokens logs "tokenestimate: estimate above threshold" for
+	// estimates of more than MaxTokens tokens; 0 disables it
+	MaxTokens int
+
+	// UnsupportedScripts logs "tokenestimate: unsupported scripts" for
+	// texts with characters of scripts the preset does not model, as
+	// Metadata.Supports reports. Presets without Metadata.Scripts are
+	// not checked.
+	UnsupportedScripts bool
+
+	// Sampling logs "tokenestimate: sampled estimate" for texts estimated
+	// from a sample of their characters
+	Sampling bool
+
+	// TextPrefix is the number of leading bytes of the text each record
+	// includes, cut at a character boundary; 0 includes none
+	TextPrefix int
+}
+
+// defaultAnomalyHooks holds the hooks set by SetAnomalyHooks.
+var defaultAnomalyHooks atomic.Pointer[AnomalyHooks]
+
+// SetAnomalyHooks sets the hooks of estimators without hooks of their own,
+// such as those of NewEstimator. A nil h removes them.
+func SetAnomalyHooks(h *AnomalyHooks) {
+	if h != nil {
+		c := *h
+		h = &c
+	}
+	defaultAnomalyHooks.Store(h)
+}
+
+// WithAnomalyHooks returns a clone of the estimator that logs its
+// anomalies with h instead of the hooks of SetAnomalyHooks.
+func (e *Estimator) WithAnomalyHooks(h AnomalyHooks) *Estimator {
+	clone := e.Clone()
+	clone.Anomalies = &h
+	return clone
+}
+
+// anomalyHooks returns the hooks of the estimator, or nil if it has none.
+func (e *Estimator) anomalyHooks() *AnomalyHooks {
+	if e.Anomalies != nil {
+		return e.Anomalies
+	}
+	return defaultAnomalyHooks.Load()
+}
+
+// analyzeSampled is like Analyze but also returns the number of characters
+// sampled, or 0 if the whole text was analyzed.
+func (e *Estimator) analyzeSampled(text string) (Stats, int) {
+	var stats Stats
+	var detail sampleResult
+	e.analyze(text, &stats, &detail, nil)
+	return stats, detail.size
+}
+
+// estimateQuietly is like Estimate but does not run the anomaly hooks, for
+// the probes of searches such as Truncate, which run the hooks once on
+// their result with runAnomalyHooks.
+func (e *Estimator) estimateQuietly(text string) int {
+	return e.roundTokens(e.calculateTokenCount(e.Analyze(text)))
+}
+
+// runAnomalyHooks logs the anomalies of the estimate of text, if the
+// estimator has hooks.
+func (e *Estimator) runAnomalyHooks(text string) {
+	if h := e.anomalyHooks(); h != nil {
+		stats, sampled := e.analyzeSampled(text)
+		h.check(e, text, stats, sampled, float64(e.EstimateFromStats(stats)))
+	}
+}
+
+// check logs the anomalies of an estimate of text, whose statistics are
+// stats, from a sample of sampled characters if sampled is not zero.
+func (h *AnomalyHooks) check(e *Estimator, text string, stats Stats, sampled int, tokens float64) {
+	logger := h.Logger
+	if logger == nil {
+		logger = getLogger()
+	}
+	ctx := context.Background()
+	if !logger.Enabled(ctx, h.Level) {
+		return
+	}
+	attrs := []any{"preset", e.Key(), "bytes", len(text)}
+	if h.TextPrefix > 0 {
+		attrs = append(attrs, "text", textPrefix(text, h.TextPrefix))
+	}
+
+	if h.MaxTokens > 0 && tokens > float64(h.MaxTokens) {
+		logger.Log(ctx, h.Level, "tokenestimate: estimate above threshold",
+			append(attrs, "tokens", tokens, "max_tokens", h.MaxTokens)...)
+	}
+	if h.UnsupportedScripts && len(e.Metadata.Scripts) > 0 {
+		var scripts []string
+		p := profileOfStats(stats)
+		for _, s := range p.shares() {
+			if *s.share > 0 && isScript(s.name) && !e.Metadata.Supports(s.name) {
+				scripts = append(scripts, s.name)
+			}
+		}
+		if len(scripts) > 0 {
+			logger.Log(ctx, h.Level, "tokenestimate: unsupported scripts",
+				append(attrs, "scripts", scripts)...)
+		}
+	}
+	if h.Sampling && sampled > 0 {
+		logger.Log(ctx, h.Level, "tokenestimate: sampled estimate",
+			append(attrs, "sampled", sampled)...)
+	}
+}
+
+// textPrefix returns the first n bytes of text, shortened to end at a
+// character boundary.
+func textPrefix(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return text[:n]
+}
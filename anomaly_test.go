@@ -0,0 +1,119 @@
+package tokenestimate
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestAnomalyHooks(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	tests := []struct {
+		name  string
+		hooks AnomalyHooks
+		text  string
+		want  []string // substrings of the log, or none for no records
+	}{
+		{"under threshold", AnomalyHooks{MaxTokens: 100}, "Hello, world!", nil},
+		{"above threshold", AnomalyHooks{MaxTokens: 2}, "Hello, world!",
+			[]string{`msg="tokenestimate: estimate above threshold" preset=kimi-k2@12 bytes=13 tokens=`, "max_tokens=2"}},
+		{"supported scripts", AnomalyHooks{UnsupportedScripts: true}, "Hello, 世界!", nil},
+		{"unsupported scripts", AnomalyHooks{UnsupportedScripts: true}, "Hello ជំរាបសួរ ສະບາຍດີ",
+			[]string{`msg="tokenestimate: unsupported scripts"`, `scripts="[Khmer Lao]"`}},
+		{"not sampled", AnomalyHooks{Sampling: true}, "Hello, world!", nil},
+		{"sampled", AnomalyHooks{Sampling: true}, strings.Repeat("Hello, world! ", 1000),
+			[]string{`msg="tokenestimate: sampled estimate"`, "bytes=14000 sampled="}},
+		{"text prefix", AnomalyHooks{MaxTokens: 1, TextPrefix: 8}, "Hello, 世界!",
+			[]string{`text="Hello, "`}},
+		{"level", AnomalyHooks{MaxTokens: 1, Level: slog.LevelWarn}, "Hello, world!",
+			[]string{"level=WARN"}},
+		{"disabled level", AnomalyHooks{MaxTokens: 1, Level: slog.LevelDebug}, "Hello, world!", nil},
+	}
+	base := NewEstimator().WithSampling(1000, 100)
+	base.Metadata.Scripts = []string{"Latin", "LatinExtended", "Chinese"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.hooks.Logger = logger
+			e := base.WithAnomalyHooks(tt.hooks)
+			if got, want := e.Estimate(tt.text), base.Estimate(tt.text); got != want {
+				t.Errorf("Estimate with hooks = %d, want %d", got, want)
+			}
+			log := buf.String()
+			if len(tt.want) == 0 && log != "" {
+				t.Errorf("Unexpected log:\n%s", log)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(log, want) {
+					t.Errorf("Log does not contain %q:\n%s", want, log)
+				}
+			}
+		})
+	}
+}
+
+func TestSetAnomalyHooks(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	defer logger.Store(nil)
+	hooks := &AnomalyHooks{MaxTokens: 1}
+	SetAnomalyHooks(hooks)
+	defer SetAnomalyHooks(nil)
+	hooks.MaxTokens = 1000 // not seen by the estimators
+
+	NewEstimator().EstimateBytes([]byte("Hello, world!"))
+	if n := strings.Count(buf.String(), "estimate above threshold"); n != 1 {
+		t.Errorf("Default hooks logged %d records, want 1:\n%s", n, buf.String())
+	}
+
+	buf.Reset()
+	NewEstimator().WithAnomalyHooks(AnomalyHooks{MaxTokens: 100}).EstimateFloat("Hello, world!")
+	if buf.Len() != 0 {
+		t.Errorf("Hooks of the estimator did not replace the default hooks:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	SetAnomalyHooks(nil)
+	NewEstimator().Estimate("Hello, world!")
+	if buf.Len() != 0 {
+		t.Errorf("Removed hooks logged:\n%s", buf.String())
+	}
+}
+
+func TestAnomalyHooksOnce(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEstimator().WithAnomalyHooks(AnomalyHooks{
+		Logger:    slog.New(slog.NewTextHandler(&buf, nil)),
+		MaxTokens: 5,
+	})
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 40)
+	msgs := []Message{
+		{Role: RoleSystem, Content: "You are a helpful assistant who answers briefly."},
+		{Role: "user", Content: text},
+		{Role: "assistant", Content: text},
+	}
+	json := ContentJSON
+
+	tests := []struct {
+		name    string
+		call    func()
+		records int
+	}{
+		{"Truncate", func() { e.Truncate(text, 100) }, 1},
+		{"TrimMessages", func() { e.TrimMessages(msgs, 400, TrimTruncateOldest) }, 3},
+		{"PromptBuilder", func() { NewPromptBuilder(e).Add(text, 0, TruncateEnd).Build(100) }, 1},
+		{"EstimateWithOptions", func() { e.EstimateWithOptions(text, EstimateOpts{ContentType: &json}) }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.call()
+			if n := strings.Count(buf.String(), "estimate above threshold"); n != tt.records {
+				t.Errorf("Logged %d records, want %d:\n%s", n, tt.records, buf.String())
+			}
+		})
+	}
+}
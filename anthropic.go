@@ -0,0 +1,223 @@
+package tokenestimate
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	_ "image/gif"  // register GIF for image block sizes
+	_ "image/jpeg" // register JPEG for image block sizes
+	_ "image/png"  // register PNG for image block sizes
+	"math"
+	"strings"
+)
+
+// AnthropicRequest is the input of an Anthropic Messages API request. It
+// decodes from the JSON body of a request with encoding/json; fields that do
+// not affect the input size, such as model and max_tokens, are ignored.
+type AnthropicRequest struct {
+	System     AnthropicContent     `json:"system,omitempty"`
+	Messages   []AnthropicMessage   `json:"messages"`
+	Tools      []AnthropicTool      `json:"tools,omitempty"`
+	ToolChoice *AnthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+// AnthropicMessage is one message of an AnthropicRequest.
+type AnthropicMessage struct {
+	Role    string           `json:"role"`
+	Content AnthropicContent `json:"content"`
+}
+
+// AnthropicContent is a list of content blocks. In JSON it may also be given
+// as a plain string, which decodes to a single text block.
+type AnthropicContent []AnthropicBlock
+
+// UnmarshalJSON decodes either a string or an array of content blocks.
+func (c *AnthropicContent) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = AnthropicContent{{Type: "text", Text: text}}
+		return nil
+	}
+	var blocks []AnthropicBlock
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return err
+	}
+	*c = blocks
+	return nil
+}
+
+// AnthropicBlock is a content block. Type selects which other fields are
+// used: "text" uses Text, "thinking" uses Thinking, "tool_use" uses Name
+// and Input, "tool_result" uses Content, and "image" and "document" use
+// Source.
+type AnthropicBlock struct {
+	Type     string           `json:"type"`
+	Text     string           `json:"text,omitempty"`
+	Thinking string           `json:"thinking,omitempty"`
+	Name     string           `json:"name,omitempty"`
+	Input    json.RawMessage  `json:"input,omitempty"`
+	Content  AnthropicContent `json:"content,omitempty"`
+	Source   *AnthropicSource `json:"source,omitempty"`
+}
+
+// AnthropicSource is the source of an image or document block.
+type AnthropicSource struct {
+	Type      string `json:"type"` // "base64", "text" or "url"
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// AnthropicTool is a tool definition of an AnthropicRequest.
+type AnthropicTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"input_schema,omitempty"`
+}
+
+// AnthropicToolChoice is the tool_choice of an AnthropicRequest.
+type AnthropicToolChoice struct {
+	Type string `json:"type"` // "auto", "any", "tool" or "none"
+}
+
+// Overheads of the Messages API, from Anthropic's documentation where it
+// gives them and measured from count_tokens responses otherwise.
+const (
+	anthropicRequestTokens  = 4    // framing of the request and the reply priming
+	anthropicMessageTokens  = 3    // role header of each message
+	anthropicBlockTokens    = 2    // framing of each additional content block
+	anthropicToolTokens     = 10   // framing of each tool definition
+	anthropicToolUseTokens  = 346  // tool-use system prompt with tool_choice auto or none
+	anthropicToolAnyTokens  = 313  // tool-use system prompt with tool_choice any or tool
+	anthropicImagePixels    = 750  // pixels per image token
+	anthropicImageMaxTokens = 1600 // tokens of an image after resizing to the maximum size
+	anthropicImageMaxEdge   = 1568 // longest image edge before resizing
+	anthropicPDFPageTokens  = 1500 // tokens of a typical PDF page, text and image
+)
+
+// EstimateAnthropicRequest estimates the input tokens of an Anthropic
+// Messages API request: the system prompt, every message with the
+// overhead of its content blocks, and tool definitions together with the
+// system prompt the API adds for tool use. Text is estimated with the
+// estimator's coefficients and JSON, such as tool inputs and schemas,
+// with its JSON model. Images are priced from their pixel size as the API
+// does, or at the maximum size when it cannot be read; PDF documents are
+// priced per page.
+func (e *Estimator) EstimateAnthropicRequest(req *AnthropicRequest) int {
+	total := anthropicRequestTokens + e.estimateAnthropicContent(req.System)
+	for _, m := range req.Messages {
+		total += anthropicMessageTokens + e.estimateAnthropicContent(m.Content)
+	}
+
+	if len(req.Tools) > 0 {
+		if req.ToolChoice != nil && (req.ToolChoice.Type == "any" || req.ToolChoice.Type == "tool") {
+			total += anthropicToolAnyTokens
+		} else {
+			total += anthropicToolUseTokens
+		}
+	}
+	for _, tool := range req.Tools {
+		total += anthropicToolTokens + e.Estimate(tool.Name) + e.Estimate(tool.Description) +
+			e.EstimateJSONText(string(tool.InputSchema))
+	}
+	return total
+}
+
+// EstimateAnthropicJSON decodes the JSON body of an Anthropic Messages API
+// request and estimates it with EstimateAnthropicRequest.
+func (e *Estimator) EstimateAnthropicJSON(body []byte) (int, error) {
+	var req AnthropicRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return 0, err
+	}
+	return e.EstimateAnthropicRequest(&req), nil
+}
+
+// estimateAnthropicContent estimates a list of content blocks.
+func (e *Estimator) estimateAnthropicContent(content AnthropicContent) int {
+	total := 0
+	for i, block := range content {
+		if i > 0 {
+			total += anthropicBlockTokens
+		}
+		total += e.estimateAnthropicBlock(block)
+	}
+	return total
+}
+
+// estimateAnthropicBlock estimates a single content block.
+func (e *Estimator) estimateAnthropicBlock(block AnthropicBlock) int {
+	switch block.Type {
+	case "text":
+		return e.Estimate(block.Text)
+	case "thinking":
+		return e.Estimate(block.Thinking)
+	case "tool_use":
+		return e.Estimate(block.Name) + e.EstimateJSONText(string(block.Input))
+	case "tool_result":
+		return e.estimateAnthropicContent(block.Content)
+	case "image":
+		return anthropicImageTokens(block.Source)
+	case "document":
+		return e.estimateAnthropicDocument(block.Source)
+	default:
+		return 0
+	}
+}
+
+// anthropicImageTokens prices an image by its size after the API's
+// resizing, or at the maximum when the size cannot be read.
+func anthropicImageTokens(src *AnthropicSource) int {
+	if src == nil || src.Type != "base64" {
+		return anthropicImageMaxTokens
+	}
+	config, _, err := image.DecodeConfig(base64.NewDecoder(base64.StdEncoding, strings.NewReader(src.Data)))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return anthropicImageMaxTokens
+	}
+
+	w, h := float64(config.Width), float64(config.Height)
+	if edge := math.Max(w, h); edge > anthropicImageMaxEdge {
+		w, h = w*anthropicImageMaxEdge/edge, h*anthropicImageMaxEdge/edge
+	}
+	return min(int(math.Ceil(w*h/anthropicImagePixels)), anthropicImageMaxTokens)
+}
+
+// estimateAnthropicDocument prices a document block: plain text by its
+// estimate and a PDF by its number of pages.
+func (e *Estimator) estimateAnthropicDocument(src *AnthropicSource) int {
+	if src == nil {
+		return 0
+	}
+	switch src.Type {
+	case "text":
+		return e.Estimate(src.Data)
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(src.Data)
+		if err != nil {
+			return anthropicPDFPageTokens
+		}
+		return max(countPDFPages(data), 1) * anthropicPDFPageTokens
+	default:
+		return anthropicPDFPageTokens
+	}
+}
+
+// countPDFPages counts the page objects of a PDF file.
+func countPDFPages(data []byte) int {
+	pages := 0
+	for _, marker := range [][]byte{[]byte("/Type /Page"), []byte("/Type/Page")} {
+		for rest := data; ; {
+			i := bytes.Index(rest, marker)
+			if i < 0 {
+				break
+			}
+			rest = rest[i+len(marker):]
+			if len(rest) == 0 || rest[0] != 's' {
+				pages++
+			}
+		}
+	}
+	return pages
+}
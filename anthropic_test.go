@@ -0,0 +1,97 @@
+package tokenestimate
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestEstimateAnthropicRequest(t *testing.T) {
+	estimator := NewEstimator()
+
+	t.Run("String and block content agree", func(t *testing.T) {
+		asString := `{"model": "claude", "max_tokens": 1024, "system": "Be brief.",
+			"messages": [{"role": "user", "content": "What is the capital of France?"}]}`
+		asBlocks := `{"system": [{"type": "text", "text": "Be brief."}],
+			"messages": [{"role": "user", "content": [{"type": "text", "text": "What is the capital of France?"}]}]}`
+		a, err := estimator.EstimateAnthropicJSON([]byte(asString))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		b, err := estimator.EstimateAnthropicJSON([]byte(asBlocks))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want := anthropicRequestTokens + estimator.Estimate("Be brief.") +
+			anthropicMessageTokens + estimator.Estimate("What is the capital of France?")
+		if a != want || b != want {
+			t.Errorf("Expected %d for both forms, got %d and %d", want, a, b)
+		}
+	})
+
+	t.Run("Block overhead", func(t *testing.T) {
+		req := &AnthropicRequest{Messages: []AnthropicMessage{{Role: "user", Content: AnthropicContent{
+			{Type: "text", Text: "first"},
+			{Type: "text", Text: "second"},
+		}}}}
+		want := anthropicRequestTokens + anthropicMessageTokens +
+			estimator.Estimate("first") + anthropicBlockTokens + estimator.Estimate("second")
+		if got := estimator.EstimateAnthropicRequest(req); got != want {
+			t.Errorf("Expected %d, got %d", want, got)
+		}
+	})
+
+	t.Run("Tools", func(t *testing.T) {
+		body := `{"messages": [{"role": "user", "content": "Weather in Paris?"},
+			{"role": "assistant", "content": [{"type": "tool_use", "id": "t1", "name": "get_weather", "input": {"city": "Paris"}}]},
+			{"role": "user", "content": [{"type": "tool_result", "tool_use_id": "t1", "content": "18°C, sunny"}]}],
+			"tools": [{"name": "get_weather", "description": "Get the weather for a city.",
+				"input_schema": {"type": "object", "properties": {"city": {"type": "string"}}}}]}`
+		withTools, err := estimator.EstimateAnthropicJSON([]byte(body))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if withTools < anthropicToolUseTokens+anthropicToolTokens+20 {
+			t.Errorf("Expected the tool-use system prompt to be counted, got %d", withTools)
+		}
+
+		anyChoice, err := estimator.EstimateAnthropicJSON([]byte(body[:len(body)-1] + `, "tool_choice": {"type": "any"}}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if withTools-anyChoice != anthropicToolUseTokens-anthropicToolAnyTokens {
+			t.Errorf("Expected tool_choice any to use the shorter prompt, got %d and %d", withTools, anyChoice)
+		}
+	})
+
+	t.Run("Image size", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 200, 150))); err != nil {
+			t.Fatal(err)
+		}
+		src := &AnthropicSource{Type: "base64", MediaType: "image/png", Data: base64.StdEncoding.EncodeToString(buf.Bytes())}
+		if got := anthropicImageTokens(src); got != 40 {
+			t.Errorf("Expected 200x150 image to cost 40 tokens, got %d", got)
+		}
+		if got := anthropicImageTokens(&AnthropicSource{Type: "url", URL: "https://example.com/a.png"}); got != anthropicImageMaxTokens {
+			t.Errorf("Expected URL image to cost %d tokens, got %d", anthropicImageMaxTokens, got)
+		}
+	})
+
+	t.Run("PDF pages", func(t *testing.T) {
+		pdf := "%PDF-1.4\n1 0 obj << /Type /Pages /Count 2 >> endobj\n" +
+			"2 0 obj << /Type /Page >> endobj\n3 0 obj <</Type/Page>> endobj\n"
+		src := &AnthropicSource{Type: "base64", MediaType: "application/pdf", Data: base64.StdEncoding.EncodeToString([]byte(pdf))}
+		if got := estimator.estimateAnthropicDocument(src); got != 2*anthropicPDFPageTokens {
+			t.Errorf("Expected 2 pages, got %d tokens", got)
+		}
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		if _, err := estimator.EstimateAnthropicJSON([]byte(`{"messages": [{"content": 42}]}`)); err == nil {
+			t.Error("Expected error for invalid content")
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package tokenestimate
+
+import "math/bits"
+
+// Most of English prose and code is runs of lowercase ASCII letters inside
+// words, where every rune has the same effect on the statistics. The
+// analyzer skips such runs with lowerRun, which tests eight bytes at a
+// time with SWAR (SIMD within a register) arithmetic on a uint64; the
+// compiler turns load64 into a single load on amd64 and arm64. It is
+// portable Go, with no assembly or vector instructions, and makes English
+// prose about twice as fast to analyze. Wider vectors would not give much
+// more: lowerRun is about a third of the time left, and the rest goes to
+// the characters around the runs, which carry context one at a time.
+
+const (
+	swarOnes  = 0x0101010101010101 // 0x01 in every byte
+	swarHighs = 0x8080808080808080 // the high bit of every byte
+)
+
+// load64 returns the eight bytes of s starting at i as a little-endian
+// word.
+func load64(s string, i int) uint64 {
+	_ = s[i+7]
+	return uint64(s[i]) | uint64(s[i+1])<<8 | uint64(s[i+2])<<16 | uint64(s[i+3])<<24 |
+		uint64(s[i+4])<<32 | uint64(s[i+5])<<40 | uint64(s[i+6])<<48 | uint64(s[i+7])<<56
+}
+
+// lowerMask returns a word with the high bit set in every byte of x that
+// is an ASCII lowercase letter, and in nonHex every such byte past 'f'.
+// Adding 0x80-c to a byte below 0x80 sets its high bit exactly when the
+// byte is at least c, and cannot carry into the next byte.
+func lowerMask(x uint64) (lower, nonHex uint64) {
+	ascii := ^x & swarHighs
+	atLeastA := x + (0x80-'a')*swarOnes
+	pastZ := x + (0x80-'z'-1)*swarOnes
+	pastF := x + (0x80-'f'-1)*swarOnes
+	lower = atLeastA &^ pastZ & ascii
+	return lower, pastF & lower
+}
+
+// lowerRun returns the length of the run of ASCII lowercase letters at the
+// start of s, and whether any of them is a letter past 'f', which cannot
+// occur in hex.
+func lowerRun(s string) (n int, nonHex bool) {
+	for ; n+8 <= len(s); n += 8 {
+		lower, hex := lowerMask(load64(s, n))
+		if lower != swarHighs {
+			// The run ends in this word: keep the bytes before the first
+			// byte that is not a lowercase letter.
+			k := bits.TrailingZeros64(^lower&swarHighs) / 8
+			keep := uint64(1)<<(k*8) - 1
+			return n + k, nonHex || hex&keep != 0
+		}
+		nonHex = nonHex || hex != 0
+	}
+	for ; n < len(s) && s[n] >= 'a' && s[n] <= 'z'; n++ {
+		nonHex = nonHex || s[n] > 'f'
+	}
+	return n, nonHex
+}
+
+// addLowerRun records run, a run of ASCII lowercase letters that directly
+// follows an ASCII letter. Such letters only extend the current word and
+// blob and count as Latin letters; nonHex tells whether the run contains
+// a letter past 'f'.
+func (a *analyzer) addLowerRun(run string, nonHex bool) {
+	n := len(run)
+	a.stats.LatinLetters += n
+	last := rune(run[n-1])
+	if n == 1 {
+		a.prev2 = a.prev
+	} else {
+		a.prev2 = rune(run[n-2])
+	}
+	a.prev = last
+	a.md.prev = last
+	if a.detectBlobs {
+		a.blob.length += n
+		a.blob.letters += n
+		a.blob.lower = true
+		a.blob.nonHex = a.blob.nonHex || nonHex
+	}
+}
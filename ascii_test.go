@@ -0,0 +1,108 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLowerRun(t *testing.T) {
+	for _, s := range []string{
+		"", "a", "abc", "abcdefgh", "abcdefghi", "abcdef", "abcdefabcdef0",
+		"hello world", "helloworldandmore Z", "abcdefgh{", "abcdefg`", "zzzzzzzzzzzz",
+		"aaaaaaaa\xe4\xb8\xadaaaa", "Abc", "abcdefgH", "abc" + strings.Repeat("d", 30) + "!",
+	} {
+		wantN, wantNonHex := 0, false
+		for wantN < len(s) && s[wantN] >= 'a' && s[wantN] <= 'z' {
+			wantNonHex = wantNonHex || s[wantN] > 'f'
+			wantN++
+		}
+		if n, nonHex := lowerRun(s); n != wantN || nonHex != wantNonHex {
+			t.Errorf("lowerRun(%q) = %d, %v, want %d, %v", s, n, nonHex, wantN, wantNonHex)
+		}
+	}
+}
+
+// analyzeRunes analyzes text one rune at a time, without the lowercase
+// fast path of analyzeFull.
+func analyzeRunes(e *Estimator, text string) Stats {
+	a := e.newAnalyzer()
+	skip := 0
+	for i, r := range text {
+		if i < skip {
+			continue
+		}
+		if r == '<' && a.special != nil {
+			if n := a.special.match(text[i:]); n > 0 {
+				a.addSpecial()
+				skip = i + n
+				continue
+			}
+		}
+		if r == utf8.RuneError && isInvalidAt(text, i) {
+			a.addInvalid()
+			continue
+		}
+		a.add(r)
+	}
+	a.finish()
+	return a.stats
+}
+
+func TestAnalyzeFullLowerRuns(t *testing.T) {
+	texts := append([]string(nil), referenceTexts...)
+	pieces := []string{
+		"hello", "World", "camelCase", "snake_case", "a.b", " ", "  ", "\n", "\t", "12", "deadbeef", "0deadbeef9", "0deadxyzw9",
+		"aGVsbG8gd29ybGQ", "## ", "- ", "](", "<|im_end|>", "中文", "é", "\xff", "[x](y)",
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 200 {
+		var b strings.Builder
+		for range rng.IntN(40) {
+			b.WriteString(pieces[rng.IntN(len(pieces))])
+		}
+		texts = append(texts, b.String())
+	}
+
+	for _, e := range []*Estimator{KimiK2Estimator, KimiK2CodeEstimator, &Estimator{}} {
+		for _, text := range texts {
+			if got, want := e.analyzeFull(text), analyzeRunes(e, text); got != want {
+				t.Errorf("%s: analyzeFull(%q) = %+v, want %+v", e.Name, text, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkEstimator_EnglishProse(b *testing.B) {
+	estimator := NewEstimator()
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog while the reviewers read the requests carefully. ", 100)
+
+	b.SetBytes(int64(len(text)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		estimator.Estimate(text)
+	}
+}
+
+// BenchmarkPresetVersions measures the throughput of every kimi-k2 version
+// on prose and on indented code, so that the cost of each feature a
+// revision adds shows up next to the version that added it.
+func BenchmarkPresetVersions(b *testing.B) {
+	texts := map[string]string{
+		"Prose": strings.Repeat("The quick brown fox jumps over the lazy dog while the reviewers read the requests carefully. ", 100),
+		"Code":  strings.Repeat("func main() {\n    for i := 0; i < 10; i++ {\n        fmt.Println(i)\n    }\n}\n", 100),
+	}
+	for _, kind := range []string{"Prose", "Code"} {
+		text := texts[kind]
+		for _, e := range kimiK2Versions {
+			b.Run(fmt.Sprintf("%s/%s", kind, e.Key()), func(b *testing.B) {
+				b.SetBytes(int64(len(text)))
+				for i := 0; i < b.N; i++ {
+					e.Estimate(text)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package tokenestimate
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// WithConcurrency returns a clone of the estimator that spreads batch
+// estimation across n goroutines. A value of 0 or less uses
+// runtime.GOMAXPROCS(0).
+func (e *Estimator) WithConcurrency(n int) *Estimator {
+	clone := e.Clone()
+	clone.Concurrency = n
+	return clone
+}
+
+// EstimateBatch estimates every text in texts concurrently and returns the
+// estimates in the same order. Each estimate equals Estimate(texts[i]).
+func (e *Estimator) EstimateBatch(texts []string) []int {
+	results, _ := e.EstimateBatchContext(context.Background(), texts)
+	return results
+}
+
+// EstimateBatchContext is like EstimateBatch but stops starting new texts
+// once ctx is done. It then returns the estimates made so far, with 0 for
+// the remaining texts, and ctx.Err().
+func (e *Estimator) EstimateBatchContext(ctx context.Context, texts []string) ([]int, error) {
+	results := make([]int, len(texts))
+	workers := e.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(texts))
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= len(texts) {
+					return
+				}
+				results[i] = e.Estimate(texts[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return results, ctx.Err()
+}
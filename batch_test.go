@@ -0,0 +1,48 @@
+package tokenestimate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestEstimateBatch(t *testing.T) {
+	texts := make([]string, 500)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("Chunk %d of the document: 你好 world %s", i, string(rune('a'+i%26)))
+	}
+	estimator := NewEstimator()
+
+	for _, workers := range []int{0, 1, 3, 1000} {
+		t.Run(fmt.Sprintf("Concurrency %d", workers), func(t *testing.T) {
+			results := estimator.WithConcurrency(workers).EstimateBatch(texts)
+			if len(results) != len(texts) {
+				t.Fatalf("Expected %d results, got %d", len(texts), len(results))
+			}
+			for i, text := range texts {
+				if want := estimator.Estimate(text); results[i] != want {
+					t.Errorf("results[%d] = %d, want %d", i, results[i], want)
+				}
+			}
+		})
+	}
+
+	t.Run("Empty batch", func(t *testing.T) {
+		if results := estimator.EstimateBatch(nil); len(results) != 0 {
+			t.Errorf("Expected no results, got %v", results)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := estimator.EstimateBatchContext(ctx, texts)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if len(results) != len(texts) {
+			t.Errorf("Expected %d results, got %d", len(texts), len(results))
+		}
+	})
+}
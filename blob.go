@@ -0,0 +1,93 @@
+package tokenestimate
+
+// minBlobLength is the minimum length of a run of base64 or hex characters
+// treated as an encoded blob. Shorter runs are ordinary words and numbers.
+const minBlobLength = 32
+
+// blobScanLimit bounds how far sampling mode looks around a sampled rune to
+// decide whether it lies inside a blob.
+const blobScanLimit = 128
+
+// blobRun accumulates a run of base64/hex alphabet characters so that its
+// counts can be moved to Stats.BlobChars once the run ends and turns out to
+// be a blob.
+type blobRun struct {
+	length    int
+	letters   int
+	digits    int
+	symbols   int
+	digitRuns int
+	idents    int  // identifier boundaries counted inside the run
+	upper     bool // contains A-Z
+	lower     bool // contains a-z
+	nonHex    bool // contains a letter outside a-f/A-F
+}
+
+// add extends the run with r, which must be in the blob alphabet, noting
+// whether r was counted as the start of a digit run or as an identifier
+// boundary.
+func (b *blobRun) add(r rune, digitRunStart, identBoundary bool) {
+	b.length++
+	if identBoundary {
+		b.idents++
+	}
+	switch {
+	case r >= '0' && r <= '9':
+		b.digits++
+		if digitRunStart {
+			b.digitRuns++
+		}
+	case r >= 'a' && r <= 'z':
+		b.letters++
+		b.lower = true
+		b.nonHex = b.nonHex || r > 'f'
+	case r >= 'A' && r <= 'Z':
+		b.letters++
+		b.upper = true
+		b.nonHex = b.nonHex || r > 'F'
+	default:
+		b.symbols++
+	}
+}
+
+// isBlob reports whether the run looks like base64 or hex encoded data:
+// long enough, containing digits, and either mixing upper and lower case
+// (base64) or using only hex letters.
+func (b *blobRun) isBlob() bool {
+	if b.length < minBlobLength || b.digits == 0 || b.letters == 0 {
+		return false
+	}
+	return (b.upper && b.lower) || !b.nonHex
+}
+
+// isBlobAlphabet checks if a rune can occur in base64 (standard or URL-safe)
+// or hex encoded data.
+func isBlobAlphabet(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		r == '+' || r == '/' || r == '=' || r == '-' || r == '_'
+}
+
+// inBlob reports whether the rune at byte offset i of text lies inside a
+// blob. Only the characters within blobScanLimit of i are examined, so the
+// cost per call is bounded. The blob alphabet is ASCII, so characters and
+// bytes coincide within a run.
+func inBlob(text string, i int) bool {
+	if !isBlobAlphabet(rune(text[i])) {
+		return false
+	}
+
+	start := i
+	for start > 0 && i-start < blobScanLimit && isBlobAlphabet(rune(text[start-1])) {
+		start--
+	}
+	end := i + 1
+	for end < len(text) && end-i < blobScanLimit && isBlobAlphabet(rune(text[end])) {
+		end++
+	}
+
+	var run blobRun
+	for _, r := range text[start:end] {
+		run.add(r, false, false)
+	}
+	return run.isBlob()
+}
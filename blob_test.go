@@ -0,0 +1,84 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlobDetection(t *testing.T) {
+	estimator := NewEstimator()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected Stats
+	}{
+		{
+			name: "Hex digest",
+			text: "sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+			expected: Stats{
+				LatinLetters: 3,
+				Digits:       3,
+				DigitRuns:    1,
+				Symbols:      1,
+				Spaces:       1,
+				Words:        2,
+				BlobChars:    64,
+			},
+		},
+		{
+			name: "Base64",
+			text: "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJ==",
+			expected: Stats{
+				Words:     1,
+				BlobChars: 46,
+			},
+		},
+		{
+			name: "Short hex is not a blob",
+			text: "deadbeef1234",
+			expected: Stats{
+				LatinLetters: 8,
+				Digits:       4,
+				DigitRuns:    1,
+				Words:        1,
+			},
+		},
+		{
+			name: "Long lowercase word is not a blob",
+			text: "pneumonoultramicroscopicsilicovolcanoconiosis",
+			expected: Stats{
+				LatinLetters: 45,
+				Words:        1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := estimator.Analyze(tt.text)
+			if result != tt.expected {
+				t.Errorf("Analyze(%q) = %+v, want %+v", tt.text, result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Older versions do not detect blobs", func(t *testing.T) {
+		older, err := lookupPreset("kimi-k2@6")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stats := older.Analyze(tests[0].text); stats.BlobChars != 0 {
+			t.Errorf("Expected no blob chars, got %d", stats.BlobChars)
+		}
+	})
+
+	t.Run("Sampling detects blobs", func(t *testing.T) {
+		sampled := estimator.WithSampling(1000, 100)
+		blob := strings.Repeat("iVBORw0KGgoAAAANSUhEUgAAAAEAAAAB", 100)
+		stats := sampled.Analyze("attachment: " + blob)
+		if stats.BlobChars < 3000 {
+			t.Errorf("Expected most of 3200 chars in blobs, got %+v", stats)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package tokenestimate
+
+// Builder constructs a custom estimator step by step, without reaching
+// into the fields of Estimator:
+//
+//	e, err := tokenestimate.NewBuilder("my-model").
+//		Coef("ChineseChars", 0.66).
+//		Intercept(1.2).
+//		Description("My model's tokenizer").
+//		Build()
+//
+// Coefficients are named like the Stats fields they multiply, as in
+// WithCoefficients. The first error, such as an unknown feature name, is
+// returned by Build, which also validates the estimator. A Builder is not
+// safe for concurrent use.
+type Builder struct {
+	e   *Estimator
+	err error
+}
+
+// NewBuilder returns a Builder of the named estimator, starting from the
+// coefficients and analysis settings of the default preset.
+func NewBuilder(name string) *Builder {
+	return (&Builder{}).Base(NewEstimator()).Name(name)
+}
+
+// Base starts over from the coefficients and analysis settings of base,
+// keeping the name, version and description set so far.
+func (b *Builder) Base(base *Estimator) *Builder {
+	e := base.Clone()
+	e.detach()
+	e.Deprecated = ""
+	if b.e != nil {
+		e.Name, e.Version, e.Description = b.e.Name, b.e.Version, b.e.Description
+	}
+	b.e = e
+	return b
+}
+
+// Name sets the name of the estimator.
+func (b *Builder) Name(name string) *Builder {
+	b.e.Name = name
+	return b
+}
+
+// Version sets the version of the estimator, under which RegisterPreset
+// registers it as "name@version".
+func (b *Builder) Version(version int) *Builder {
+	b.e.Version = version
+	return b
+}
+
+// Description sets the description of the estimator.
+func (b *Builder) Description(description string) *Builder {
+	b.e.Description = description
+	return b
+}
+
+// Coef sets the coefficient of the named Stats feature.
+func (b *Builder) Coef(feature string, v float64) *Builder {
+	if b.err != nil {
+		return b
+	}
+	p, err := b.e.coefficientOf(feature)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	*p = v
+	return b
+}
+
+// Coefs sets the coefficients of the named Stats features.
+func (b *Builder) Coefs(coefs map[string]float64) *Builder {
+	for feature, v := range coefs {
+		b.Coef(feature, v)
+	}
+	return b
+}
+
+// Intercept sets the intercept of the linear model.
+func (b *Builder) Intercept(v float64) *Builder {
+	b.e.intercept = v
+	return b
+}
+
+// Residuals sets the 10th and 90th percentiles of the ratio of actual to
+// estimated tokens, which EstimateRange scales estimates by.
+func (b *Builder) Residuals(p10, p90 float64) *Builder {
+	b.e.residualP10, b.e.residualP90 = p10, p90
+	return b
+}
+
+// Metadata sets the training metadata of the estimator.
+func (b *Builder) Metadata(m Metadata) *Builder {
+	b.e.Metadata = m.clone()
+	return b
+}
+
+// Build returns the estimator, or the first error of the steps. The
+// Builder can go on building others from it.
+func (b *Builder) Build() (*Estimator, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if err := b.e.Validate(); err != nil {
+		return nil, err
+	}
+	return b.e.Clone(), nil
+}
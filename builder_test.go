@@ -0,0 +1,62 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder("built").
+		Coef("ChineseChars", 0.9).
+		Coefs(map[string]float64{"Words": 0.3}).
+		Intercept(1.5).
+		Version(2).
+		Description("Built preset").
+		Residuals(0.8, 1.2).
+		Metadata(Metadata{Corpus: "Tests", Scripts: []string{"Chinese"}})
+	e, err := b.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want, _ := NewEstimator().WithCoefficients(1.5, map[string]float64{"ChineseChars": 0.9, "Words": 0.3})
+	for _, text := range []string{"Hello, world!", "你好，世界"} {
+		if got := e.Estimate(text); got != want.Estimate(text) {
+			t.Errorf("Estimate(%q): expected %d, got %d", text, want.Estimate(text), got)
+		}
+	}
+	if e.Key() != "built@2" || e.Description != "Built preset" || e.Metadata.Corpus != "Tests" {
+		t.Errorf("Unexpected estimator %+v", e)
+	}
+	if low, high := e.EstimateRange("Hello, world!"); low >= high {
+		t.Errorf("Expected a range, got %d..%d", low, high)
+	}
+
+	// Building again after a change leaves the first estimator alone.
+	other, err := b.Coef("ChineseChars", 2).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e.coefChinese != 0.9 || other.coefChinese != 2 {
+		t.Errorf("Expected independent estimators, got %v and %v", e.coefChinese, other.coefChinese)
+	}
+
+	base, err := NewBuilder("based").Description("kept").Base(KimiK2CodeEstimator).Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if base.coefIdentifierBoundaries != KimiK2CodeEstimator.coefIdentifierBoundaries || base.Description != "kept" || base.ContentType != ContentCode {
+		t.Errorf("Expected the code preset's model, got %+v", base)
+	}
+
+	tests := map[string]*Builder{
+		"unknown feature: Foo":                        NewBuilder("x").Coef("Foo", 1).Coef("Bar", 1),
+		"invalid residual quantiles":                  NewBuilder("x").Residuals(1.2, 0.8),
+		"estimator has no name":                       NewBuilder(""),
+		"feature DictionaryTokens has no coefficient": NewBuilder("x").Coef("DictionaryTokens", 1),
+	}
+	for want, b := range tests {
+		if _, err := b.Build(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an error containing %q, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package tokenestimate
+
+import "unsafe"
+
+// EstimateBytes is like Estimate but takes a byte slice, such as an HTTP
+// body or file contents, without copying it into a string. b must not be
+// modified during the call; it is not retained.
+func (e *Estimator) EstimateBytes(b []byte) int {
+	return e.Estimate(bytesToString(b))
+}
+
+// AnalyzeBytes is like Analyze but takes a byte slice without copying it
+// into a string. b must not be modified during the call; it is not
+// retained.
+func (e *Estimator) AnalyzeBytes(b []byte) Stats {
+	return e.Analyze(bytesToString(b))
+}
+
+// bytesToString returns a string sharing b's memory. The analysis only
+// reads its input and keeps no reference to it once it returns, so the
+// string never outlives the caller's use of b.
+func bytesToString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
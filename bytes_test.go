@@ -0,0 +1,33 @@
+package tokenestimate
+
+import "testing"
+
+func TestEstimateBytes(t *testing.T) {
+	estimator := NewEstimator()
+	texts := []string{
+		"",
+		"Hello, world!",
+		"你好，世界！ <|im_end|> \t\tindented",
+		"invalid \xff\xfe bytes",
+	}
+
+	for _, text := range texts {
+		b := []byte(text)
+		if got, want := estimator.EstimateBytes(b), estimator.Estimate(text); got != want {
+			t.Errorf("EstimateBytes(%q) = %d, want %d", text, got, want)
+		}
+		if got, want := estimator.AnalyzeBytes(b), estimator.Analyze(text); got != want {
+			t.Errorf("AnalyzeBytes(%q) = %+v, want %+v", text, got, want)
+		}
+	}
+
+	t.Run("No copy", func(t *testing.T) {
+		text := "The quick brown fox jumps over the lazy dog. "
+		b := []byte(text)
+		bytesAllocs := testing.AllocsPerRun(100, func() { estimator.EstimateBytes(b) })
+		stringAllocs := testing.AllocsPerRun(100, func() { estimator.Estimate(text) })
+		if bytesAllocs > stringAllocs {
+			t.Errorf("EstimateBytes allocated %.0f times, Estimate %.0f", bytesAllocs, stringAllocs)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package tokenestimate
+
+import "slices"
+
+// Sample is a text labeled with the number of tokens the target tokenizer
+// encodes it to, such as the prompt of an API request and the input token
+// count of its response. The JSON field names match the JSONL files of
+// the library's test data.
+type Sample struct {
+	Text   string `json:"text"`
+	Tokens int    `json:"token_count"`
+}
+
+// Calibrate returns a clone of the estimator adapted to samples from a
+// similar but different tokenizer. It keeps the ratios of the model's
+// coefficients and fits only a scale applied to all of them and an offset
+// added to the intercept, so a handful of samples is enough: with one
+// sample, or samples of the same estimate, only the scale is fitted.
+// Dictionary words keep their costs. The content-type variants of a
+// preset are calibrated alike.
+//
+// Without samples to fit, or if they cannot give a positive scale,
+// Calibrate returns an unchanged clone.
+func (e *Estimator) Calibrate(samples []Sample) *Estimator {
+	// Fit tokens - fixed = scale * (estimate - fixed) + offset, where
+	// fixed is the part of the estimate that is not scaled.
+	xs := make([]float64, len(samples))
+	ys := make([]float64, len(samples))
+	for i, s := range samples {
+		x := e.Explain(s.Text)
+		fixed := float64(x.Stats.DictionaryTokens)
+		xs[i] = x.Total - fixed
+		ys[i] = float64(s.Tokens) - fixed
+	}
+	scale, offset, ok := fitLine(xs, ys)
+	if !ok {
+		return e.Clone()
+	}
+
+	return e.withModels(func(m *Estimator) {
+		m.scaleModel(scale)
+		m.intercept += offset
+	})
+}
+
+// withModels returns a clone of the estimator with update applied to its
+// coefficients and to those of copies of its content-type variants, so
+// that a correction of the model survives WithContentType.
+func (e *Estimator) withModels(update func(*Estimator)) *Estimator {
+	clone := e.Clone()
+	update(clone)
+	if e.variants != nil {
+		variants := make(map[ContentType]*Estimator, len(e.variants))
+		for ct, v := range e.variants {
+			variants[ct] = v.Clone()
+			update(variants[ct])
+		}
+		linkContentVariants(variants)
+		clone.variants = variants
+	}
+	return clone
+}
+
+// fitLine returns the least squares fit y = scale*x + offset, or a fit
+// through the origin when x does not vary; ok is false if neither gives a
+// positive scale.
+func fitLine(xs, ys []float64) (scale, offset float64, ok bool) {
+	n := float64(len(xs))
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	if variance := n*sxx - sx*sx; len(xs) > 1 && variance > 1e-9*n*sxx {
+		scale = (n*sxy - sx*sy) / variance
+		if scale > 0 {
+			return scale, (sy - scale*sx) / n, true
+		}
+	}
+	if sxx <= 0 {
+		return 0, 0, false
+	}
+	scale = sxy / sxx
+	return scale, 0, scale > 0
+}
+
+// scaleModel multiplies every coefficient, the intercept and the nonlinear
+// terms of the estimator by scale. The terms are copied first, since
+// clones share them.
+func (e *Estimator) scaleModel(scale float64) {
+	for _, f := range features {
+		if p := e.coefficientField(f.name); p != nil {
+			*p *= scale
+		}
+	}
+	e.intercept *= scale
+	e.Terms = slices.Clone(e.Terms)
+	for i := range e.Terms {
+		term := &e.Terms[i]
+		term.Coef *= scale
+		term.Slopes = slices.Clone(term.Slopes)
+		for j := range term.Slopes {
+			term.Slopes[j] *= scale
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package tokenestimate
+
+import (
+	"math"
+	"testing"
+)
+
+// scaledTokenizer labels texts like a tokenizer that encodes to 1.3 times
+// the tokens of e plus 2.
+func scaledTokenizer(e *Estimator, texts ...string) []Sample {
+	samples := make([]Sample, len(texts))
+	for i, text := range texts {
+		samples[i] = Sample{Text: text, Tokens: int(math.Round(1.3*e.EstimateFloat(text) + 2))}
+	}
+	return samples
+}
+
+func TestCalibrate(t *testing.T) {
+	e := NewEstimator()
+	samples := scaledTokenizer(e,
+		"Hello, world!",
+		"The quick brown fox jumps over the lazy dog.",
+		"你好世界，今天天气很好。",
+		"Привет, как дела? Всё хорошо.",
+		"func main() { fmt.Println(42) }",
+		"Tokenization of mixed 中文 and English text, with numbers like 3.14159.",
+	)
+	calibrated := e.Calibrate(samples)
+
+	for _, s := range scaledTokenizer(e,
+		"A sentence the calibration has not seen before.",
+		"一段新的中文文本，用来检验校准。",
+		"Lorem ipsum dolor sit amet, consectetur adipiscing elit.",
+	) {
+		if got := calibrated.Estimate(s.Text); math.Abs(float64(got-s.Tokens)) > 1 {
+			t.Errorf("Estimate(%q) = %d, expected about %d", s.Text, got, s.Tokens)
+		}
+	}
+	if got, want := e.Estimate("Hello, world!"), NewEstimator().Estimate("Hello, world!"); got != want {
+		t.Errorf("Calibrate modified the estimator: %d instead of %d", got, want)
+	}
+
+	// The content-type variants of the preset are calibrated too.
+	code := "for i := 0; i < n; i++ { total += values[i] }"
+	want := 1.3*e.WithContentType(ContentCode).EstimateFloat(code) + 2
+	if got := calibrated.WithContentType(ContentCode).EstimateFloat(code); math.Abs(got-want) > 0.5 {
+		t.Errorf("Expected the code variant to give about %.1f, got %.1f", want, got)
+	}
+}
+
+func TestCalibrateFewSamples(t *testing.T) {
+	// Without dictionary words every token of the estimate scales.
+	e := NewEstimator().WithDictionary(false)
+	text := "The quick brown fox jumps over the lazy dog."
+	tokens := e.Estimate(text)
+
+	// A single sample determines only the scale.
+	calibrated := e.Calibrate([]Sample{{Text: text, Tokens: 2 * tokens}})
+	if got := calibrated.Estimate(text); got != 2*tokens {
+		t.Errorf("Expected %d tokens, got %d", 2*tokens, got)
+	}
+	scale := float64(2*tokens) / e.EstimateFloat(text)
+	if got, want := calibrated.EstimateFloat(text+" "+text), scale*e.EstimateFloat(text+" "+text); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Expected the scale to double longer texts to %.1f, got %.1f", want, got)
+	}
+
+	for _, samples := range [][]Sample{nil, {{Text: "", Tokens: 5}}, {{Text: text, Tokens: 0}}} {
+		if got := e.Calibrate(samples).Estimate(text); got != tokens {
+			t.Errorf("Calibrate(%v) changed the estimate from %d to %d", samples, tokens, got)
+		}
+	}
+}
+
+func TestCalibrateTerms(t *testing.T) {
+	e, err := NewEstimator().WithDictionary(false).WithTerms(Term{Feature: "Digits", Kind: TermSqrt, Coef: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "1234567890 and 42"
+	tokens := int(math.Round(3 * e.EstimateFloat(text)))
+	calibrated := e.Calibrate([]Sample{{Text: text, Tokens: tokens}})
+	scale := float64(tokens) / e.EstimateFloat(text)
+	got, want := calibrated.Explain(text).Terms[0].Tokens, scale*e.Explain(text).Terms[0].Tokens
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("Expected the term to scale to %.2f, got %.2f", want, got)
+	}
+	if e.Terms[0].Coef != 1 {
+		t.Errorf("Calibrate modified the terms of the estimator: %+v", e.Terms)
+	}
+}
@@ -0,0 +1,250 @@
+package tokenestimate
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Category is the character category a rune is counted under in Stats.
+type Category int
+
+const (
+	CategorySymbol        Category = iota // Stats.Symbols
+	CategoryLatin                         // Stats.LatinLetters
+	CategoryLatinExtended                 // Stats.LatinExtended
+	CategoryDigit                         // Stats.Digits
+	CategoryChinese                       // Stats.ChineseChars
+	CategoryJapanese                      // Stats.JapaneseKana
+	CategoryKorean                        // Stats.KoreanHangul
+	CategoryRussian                       // Stats.RussianChars
+	CategoryArabic                        // Stats.ArabicChars
+	CategoryKhmer                         // Stats.KhmerChars
+	CategoryLao                           // Stats.LaoChars
+	CategoryMyanmar                       // Stats.MyanmarChars
+	CategoryEthiopic                      // Stats.EthiopicChars
+	CategorySpace                         // Stats.Spaces
+	CategoryTab                           // Stats.Tabs
+	CategoryUnknown                       // Stats.Unknown
+	CategoryInvalid                       // Stats.InvalidBytes
+	CategorySpecial                       // Stats.SpecialTokens
+)
+
+// categoryNames are the names returned by Category.String.
+var categoryNames = [...]string{
+	CategorySymbol:        "symbol",
+	CategoryLatin:         "latin",
+	CategoryLatinExtended: "latin-extended",
+	CategoryDigit:         "digit",
+	CategoryChinese:       "chinese",
+	CategoryJapanese:      "japanese",
+	CategoryKorean:        "korean",
+	CategoryRussian:       "russian",
+	CategoryArabic:        "arabic",
+	CategoryKhmer:         "khmer",
+	CategoryLao:           "lao",
+	CategoryMyanmar:       "myanmar",
+	CategoryEthiopic:      "ethiopic",
+	CategorySpace:         "space",
+	CategoryTab:           "tab",
+	CategoryUnknown:       "unknown",
+	CategoryInvalid:       "invalid",
+	CategorySpecial:       "special",
+}
+
+// String returns the name of the category.
+func (c Category) String() string {
+	if c < 0 || int(c) >= len(categoryNames) {
+		return "unknown"
+	}
+	return categoryNames[c]
+}
+
+// categoryTable assigns a category to the code points of a set of range
+// tables.
+type categoryTable struct {
+	category Category
+	tables   []*unicode.RangeTable
+}
+
+// categoryTables lists the categories of non-ASCII code points in order
+// of precedence; a code point in none of them is a symbol if it is
+// assigned and unknown otherwise. Adding a script takes its ranges here,
+// a Category and a Stats field.
+var categoryTables = []categoryTable{
+	{CategoryLatinExtended, []*unicode.RangeTable{{R16: []unicode.Range16{
+		{Lo: 0x00C0, Hi: 0x024F, Stride: 1}, // Latin-1 Supplement letters, Latin Extended-A and -B
+		{Lo: 0x1E00, Hi: 0x1EFF, Stride: 1}, // Latin Extended Additional
+	}}}},
+	{CategoryDigit, []*unicode.RangeTable{unicode.Nd}},
+	{CategoryJapanese, []*unicode.RangeTable{{R16: []unicode.Range16{
+		{Lo: 0x3040, Hi: 0x30FF, Stride: 1}, // Hiragana, Katakana
+	}}}},
+	{CategoryKorean, []*unicode.RangeTable{{R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x11FF, Stride: 1}, // Hangul Jamo
+		{Lo: 0x3130, Hi: 0x318F, Stride: 1}, // Hangul Compatibility Jamo
+		{Lo: 0xA960, Hi: 0xA97F, Stride: 1}, // Hangul Jamo Extended-A
+		{Lo: 0xAC00, Hi: 0xD7FF, Stride: 1}, // Hangul Syllables, Hangul Jamo Extended-B
+	}}}},
+	{CategoryChinese, []*unicode.RangeTable{{
+		R16: []unicode.Range16{
+			{Lo: 0x3400, Hi: 0x4DBF, Stride: 1}, // CJK Extension A
+			{Lo: 0x4E00, Hi: 0x9FFF, Stride: 1}, // CJK Unified Ideographs
+		},
+		R32: []unicode.Range32{
+			{Lo: 0x20000, Hi: 0x2A6DF, Stride: 1}, // CJK Extension B
+			{Lo: 0x2A700, Hi: 0x2EBEF, Stride: 1}, // CJK Extensions C to F
+			{Lo: 0x30000, Hi: 0x3134F, Stride: 1}, // CJK Extension G
+		},
+	}}},
+	{CategoryRussian, []*unicode.RangeTable{{R16: []unicode.Range16{
+		{Lo: 0x0400, Hi: 0x052F, Stride: 1}, // Cyrillic, Cyrillic Supplement
+		{Lo: 0x1C80, Hi: 0x1C8F, Stride: 1}, // Cyrillic Extended-C
+		{Lo: 0x2DE0, Hi: 0x2DFF, Stride: 1}, // Cyrillic Extended-A
+		{Lo: 0xA640, Hi: 0xA69F, Stride: 1}, // Cyrillic Extended-B
+	}}}},
+	{CategoryArabic, []*unicode.RangeTable{{R16: []unicode.Range16{
+		{Lo: 0x0600, Hi: 0x06FF, Stride: 1}, // Arabic
+		{Lo: 0x0750, Hi: 0x077F, Stride: 1}, // Arabic Supplement
+		{Lo: 0x08A0, Hi: 0x08FF, Stride: 1}, // Arabic Extended-A
+		{Lo: 0xFB50, Hi: 0xFDFF, Stride: 1}, // Arabic Presentation Forms-A
+		{Lo: 0xFE70, Hi: 0xFEFF, Stride: 1}, // Arabic Presentation Forms-B
+	}}}},
+	{CategoryKhmer, []*unicode.RangeTable{{R16: []unicode.Range16{
+		{Lo: 0x1780, Hi: 0x17FF, Stride: 1}, // Khmer
+		{Lo: 0x19E0, Hi: 0x19FF, Stride: 1}, // Khmer Symbols
+	}}}},
+	{CategoryLao, []*unicode.RangeTable{{R16: []unicode.Range16{
+		{Lo: 0x0E80, Hi: 0x0EFF, Stride: 1}, // Lao
+	}}}},
+	{CategoryMyanmar, []*unicode.RangeTable{{R16: []unicode.Range16{
+		{Lo: 0x1000, Hi: 0x109F, Stride: 1}, // Myanmar
+		{Lo: 0xA9E0, Hi: 0xA9FF, Stride: 1}, // Myanmar Extended-B
+		{Lo: 0xAA60, Hi: 0xAA7F, Stride: 1}, // Myanmar Extended-A
+	}}}},
+	{CategoryEthiopic, []*unicode.RangeTable{{R16: []unicode.Range16{
+		{Lo: 0x1200, Hi: 0x139F, Stride: 1}, // Ethiopic, Ethiopic Supplement
+		{Lo: 0x2D80, Hi: 0x2DDF, Stride: 1}, // Ethiopic Extended
+		{Lo: 0xAB00, Hi: 0xAB2F, Stride: 1}, // Ethiopic Extended-A
+	}}}},
+	{CategorySpace, []*unicode.RangeTable{unicode.White_Space}},
+	{CategoryUnknown, []*unicode.RangeTable{unicode.Co, {R16: []unicode.Range16{
+		{Lo: unicode.ReplacementChar, Hi: unicode.ReplacementChar, Stride: 1},
+	}}}},
+}
+
+// assignedTables are the code points that are symbols when in none of
+// categoryTables: graphic, control and format characters. Unassigned code
+// points are unknown.
+var assignedTables = append([]*unicode.RangeTable{unicode.Cc, unicode.Cf}, unicode.GraphicRanges...)
+
+// asciiCategory returns the category of an ASCII rune.
+func asciiCategory(r rune) Category {
+	switch {
+	case isASCIILetter(r):
+		return CategoryLatin
+	case r >= '0' && r <= '9':
+		return CategoryDigit
+	case r == '\t':
+		return CategoryTab
+	case unicode.IsSpace(r):
+		return CategorySpace
+	default:
+		// punctuation, symbols and control characters
+		return CategorySymbol
+	}
+}
+
+// classifyTables classifies r with categoryTables, evaluating the tables
+// in turn. It is used for code points outside the Basic Multilingual
+// Plane, and to build bmpTrie.
+func classifyTables(r rune) Category {
+	for _, t := range categoryTables {
+		if unicode.In(r, t.tables...) {
+			return t.category
+		}
+	}
+	if unicode.In(r, assignedTables...) {
+		return CategorySymbol
+	}
+	return CategoryUnknown
+}
+
+// trieBlockBits is the number of low code point bits indexing a block of
+// bmpTrie.
+const trieBlockBits = 8
+
+// categoryTrie is a two-level lookup table for the categories of the Basic
+// Multilingual Plane. Most blocks of 256 code points are uniform or repeat
+// another block, so the distinct blocks fit in a few kilobytes.
+type categoryTrie struct {
+	index  [0x10000 >> trieBlockBits]uint8
+	blocks [][1 << trieBlockBits]uint8
+}
+
+// bmpTrie holds the categories of the Basic Multilingual Plane.
+var bmpTrie = buildCategoryTrie()
+
+// asciiCategories holds the category of every ASCII rune, so that the
+// common case takes a single lookup.
+var asciiCategories = func() (table [utf8.RuneSelf]Category) {
+	for r := range table {
+		table[r] = asciiCategory(rune(r))
+	}
+	return table
+}()
+
+// buildCategoryTrie evaluates categoryTables over the Basic Multilingual
+// Plane. The tables are applied from the lowest precedence up, range by
+// range, which is much faster than classifying every code point.
+func buildCategoryTrie() *categoryTrie {
+	flat := make([]uint8, 0x10000)
+	fill := func(table *unicode.RangeTable, c Category) {
+		for _, rg := range table.R16 {
+			for r := int(rg.Lo); r <= int(rg.Hi); r += int(rg.Stride) {
+				flat[r] = uint8(c)
+			}
+		}
+	}
+	for i := range flat {
+		flat[i] = uint8(CategoryUnknown)
+	}
+	for _, table := range assignedTables {
+		fill(table, CategorySymbol)
+	}
+	for i := len(categoryTables) - 1; i >= 0; i-- {
+		for _, table := range categoryTables[i].tables {
+			fill(table, categoryTables[i].category)
+		}
+	}
+	for r := range utf8.RuneSelf {
+		flat[r] = uint8(asciiCategory(rune(r)))
+	}
+
+	trie := &categoryTrie{}
+	seen := make(map[[1 << trieBlockBits]uint8]uint8)
+	for i := range trie.index {
+		var block [1 << trieBlockBits]uint8
+		copy(block[:], flat[i<<trieBlockBits:])
+		n, ok := seen[block]
+		if !ok {
+			n = uint8(len(trie.blocks))
+			seen[block] = n
+			trie.blocks = append(trie.blocks, block)
+		}
+		trie.index[i] = n
+	}
+	return trie
+}
+
+// classify returns the category of r. Invalid bytes and special tokens
+// depend on their context and are never returned.
+func classify(r rune) Category {
+	switch {
+	case uint32(r) < utf8.RuneSelf:
+		return asciiCategories[r]
+	case uint32(r) <= 0xFFFF:
+		return Category(bmpTrie.blocks[bmpTrie.index[r>>trieBlockBits]][r&(1<<trieBlockBits-1)])
+	default:
+		return classifyTables(r)
+	}
+}
@@ -0,0 +1,186 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestClassify(t *testing.T) {
+	for r := rune(-1); r <= unicode.MaxRune+1; r++ {
+		if got, want := classify(r), referenceClassify(r); got != want {
+			t.Errorf("classify(%U) = %v, want %v", r, got, want)
+		}
+	}
+	if n := len(bmpTrie.blocks); n > 128 {
+		t.Errorf("Category trie has %d distinct blocks", n)
+	}
+
+	tests := map[rune]Category{
+		'a': CategoryLatin, 'Z': CategoryLatin, '7': CategoryDigit, '_': CategorySymbol,
+		' ': CategorySpace, '\n': CategorySpace, '\t': CategoryTab, 0x7F: CategorySymbol,
+	}
+	for r, want := range tests {
+		if got := classify(r); got != want {
+			t.Errorf("classify(%q) = %v, want %v", r, got, want)
+		}
+	}
+}
+
+func BenchmarkClassify(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		text string
+	}{
+		{"English", strings.Repeat("The quick brown fox jumps over the lazy dog 42 times. ", 20)},
+		{"Chinese", strings.Repeat("今天天气很好，我们一起去公园散步吧。", 20)},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.SetBytes(int64(len(bench.text)))
+			for i := 0; i < b.N; i++ {
+				for _, r := range bench.text {
+					classify(r)
+				}
+			}
+		})
+	}
+}
+
+// referenceClassify is the original chain of range checks that the
+// table-driven classify replaces.
+func referenceClassify(r rune) Category {
+	switch {
+	case unicode.IsLetter(r) && r < 128:
+		return CategoryLatin
+	case isLatinExtended(r):
+		return CategoryLatinExtended
+	case unicode.IsDigit(r):
+		return CategoryDigit
+	case isJapaneseKana(r):
+		return CategoryJapanese
+	case isKoreanHangul(r):
+		return CategoryKorean
+	case isChinese(r):
+		return CategoryChinese
+	case isRussian(r):
+		return CategoryRussian
+	case isArabic(r):
+		return CategoryArabic
+	case isKhmer(r):
+		return CategoryKhmer
+	case isLao(r):
+		return CategoryLao
+	case isMyanmar(r):
+		return CategoryMyanmar
+	case isEthiopic(r):
+		return CategoryEthiopic
+	case isSymbol(r):
+		return CategorySymbol
+	case r == '\t':
+		return CategoryTab
+	case unicode.IsSpace(r):
+		return CategorySpace
+	case isUnknown(r):
+		return CategoryUnknown
+	default:
+		// treat other chars as symbols
+		return CategorySymbol
+	}
+}
+
+// isJapaneseKana checks if a rune is Japanese Hiragana or Katakana.
+func isJapaneseKana(r rune) bool {
+	return (r >= 0x3040 && r <= 0x309F) || // Hiragana
+		(r >= 0x30A0 && r <= 0x30FF) // Katakana
+}
+
+// isLatinExtended checks if a rune is a Latin extended letter (non-ASCII Latin).
+func isLatinExtended(r rune) bool {
+	return (r >= 0x00C0 && r <= 0x00FF) || // Latin-1 Supplement (à, ñ, ü, etc.)
+		(r >= 0x0100 && r <= 0x017F) || // Latin Extended-A (ā, ē, œ, etc.)
+		(r >= 0x0180 && r <= 0x024F) || // Latin Extended-B
+		(r >= 0x1E00 && r <= 0x1EFF) // Latin Extended Additional
+}
+
+// isKoreanHangul checks if a rune is Korean Hangul.
+func isKoreanHangul(r rune) bool {
+	return (r >= 0xAC00 && r <= 0xD7AF) || // Hangul Syllables
+		(r >= 0x1100 && r <= 0x11FF) || // Hangul Jamo
+		(r >= 0x3130 && r <= 0x318F) || // Hangul Compatibility Jamo
+		(r >= 0xA960 && r <= 0xA97F) || // Hangul Jamo Extended-A
+		(r >= 0xD7B0 && r <= 0xD7FF) // Hangul Jamo Extended-B
+}
+
+// isChinese checks if a rune is a CJK (Chinese) character,
+func isChinese(r rune) bool {
+	return (r >= 0x4E00 && r <= 0x9FFF) || // CJK Unified Ideographs
+		(r >= 0x3400 && r <= 0x4DBF) || // CJK Extension A
+		(r >= 0x20000 && r <= 0x2A6DF) || // CJK Extension B
+		(r >= 0x2A700 && r <= 0x2B73F) || // CJK Extension C
+		(r >= 0x2B740 && r <= 0x2B81F) || // CJK Extension D
+		(r >= 0x2B820 && r <= 0x2CEAF) || // CJK Extension E
+		(r >= 0x2CEB0 && r <= 0x2EBEF) || // CJK Extension F
+		(r >= 0x30000 && r <= 0x3134F) // CJK Extension G
+}
+
+// isSymbol checks if a rune is an ASCII punctuation or symbol.
+func isSymbol(r rune) bool {
+	return (r >= 0x21 && r <= 0x2F) || // !"#$%&'()*+,-./
+		(r >= 0x3A && r <= 0x40) || // :;<=>?@
+		(r >= 0x5B && r <= 0x60) || // [\]^_`
+		(r >= 0x7B && r <= 0x7E) // {|}~
+}
+
+// isArabic checks if a rune is an Arabic character.
+func isArabic(r rune) bool {
+	return (r >= 0x0600 && r <= 0x06FF) || // Arabic
+		(r >= 0x0750 && r <= 0x077F) || // Arabic Supplement
+		(r >= 0x08A0 && r <= 0x08FF) || // Arabic Extended-A
+		(r >= 0xFB50 && r <= 0xFDFF) || // Arabic Presentation Forms-A
+		(r >= 0xFE70 && r <= 0xFEFF) // Arabic Presentation Forms-B
+}
+
+// isRussian checks if a rune is a Russian Cyrillic character.
+func isRussian(r rune) bool {
+	return (r >= 0x0400 && r <= 0x04FF) || // Cyrillic
+		(r >= 0x0500 && r <= 0x052F) || // Cyrillic Supplement
+		(r >= 0x2DE0 && r <= 0x2DFF) || // Cyrillic Extended-A
+		(r >= 0xA640 && r <= 0xA69F) || // Cyrillic Extended-B
+		(r >= 0x1C80 && r <= 0x1C8F) // Cyrillic Extended-C
+}
+
+// isKhmer checks if a rune is a Khmer character.
+func isKhmer(r rune) bool {
+	return (r >= 0x1780 && r <= 0x17FF) || // Khmer
+		(r >= 0x19E0 && r <= 0x19FF) // Khmer Symbols
+}
+
+// isLao checks if a rune is a Lao character.
+func isLao(r rune) bool {
+	return r >= 0x0E80 && r <= 0x0EFF // Lao
+}
+
+// isMyanmar checks if a rune is a Myanmar character.
+func isMyanmar(r rune) bool {
+	return (r >= 0x1000 && r <= 0x109F) || // Myanmar
+		(r >= 0xA9E0 && r <= 0xA9FF) || // Myanmar Extended-B
+		(r >= 0xAA60 && r <= 0xAA7F) // Myanmar Extended-A
+}
+
+// isEthiopic checks if a rune is an Ethiopic character.
+func isEthiopic(r rune) bool {
+	return (r >= 0x1200 && r <= 0x137F) || // Ethiopic
+		(r >= 0x1380 && r <= 0x139F) || // Ethiopic Supplement
+		(r >= 0x2D80 && r <= 0x2DDF) || // Ethiopic Extended
+		(r >= 0xAB00 && r <= 0xAB2F) // Ethiopic Extended-A
+}
+
+// isUnknown checks if a rune is a private-use character, the U+FFFD
+// replacement character, or a code point unassigned in Go's Unicode tables.
+// Control and format characters are not unknown.
+func isUnknown(r rune) bool {
+	if r == unicode.ReplacementChar || unicode.Is(unicode.Co, r) {
+		return true
+	}
+	return !unicode.IsGraphic(r) && !unicode.IsControl(r) && !unicode.Is(unicode.Cf, r)
+}
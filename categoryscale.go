@@ -0,0 +1,44 @@
+package tokenestimate
+
+// categoryFeatures maps each category to the Stats field that counts it.
+var categoryFeatures = [...]string{
+	CategorySymbol:        "Symbols",
+	CategoryLatin:         "LatinLetters",
+	CategoryLatinExtended: "LatinExtended",
+	CategoryDigit:         "Digits",
+	CategoryChinese:       "ChineseChars",
+	CategoryJapanese:      "JapaneseKana",
+	CategoryKorean:        "KoreanHangul",
+	CategoryRussian:       "RussianChars",
+	CategoryArabic:        "ArabicChars",
+	CategoryKhmer:         "KhmerChars",
+	CategoryLao:           "LaoChars",
+	CategoryMyanmar:       "MyanmarChars",
+	CategoryEthiopic:      "EthiopicChars",
+	CategorySpace:         "Spaces",
+	CategoryTab:           "Tabs",
+	CategoryUnknown:       "Unknown",
+	CategoryInvalid:       "InvalidBytes",
+	CategorySpecial:       "SpecialTokens",
+}
+
+// WithCategoryScale returns a clone of the estimator with the coefficient
+// of the characters of category c multiplied by factor, to correct a
+// systematic bias observed on one language without refitting the model:
+// WithCategoryScale(CategoryChinese, 0.93) estimates Chinese characters 7%
+// lower. Scales compound when applied repeatedly, and carry over to the
+// preset's content-type variants. Word, run and markup coefficients are
+// not scaled.
+//
+// Under the default policies unknown characters and invalid bytes are
+// priced with the Symbols coefficient, so only the CategorySymbol scale
+// applies to them. An unknown category leaves the estimator unchanged.
+func (e *Estimator) WithCategoryScale(c Category, factor float64) *Estimator {
+	if c < 0 || int(c) >= len(categoryFeatures) {
+		return e.Clone()
+	}
+	name := categoryFeatures[c]
+	return e.withModels(func(m *Estimator) {
+		*m.coefficientField(name) *= factor
+	})
+}
@@ -0,0 +1,53 @@
+package tokenestimate
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCategoryFeatures(t *testing.T) {
+	e := NewEstimator()
+	for c, name := range categoryFeatures {
+		if e.coefficientField(name) == nil {
+			t.Errorf("Category %v maps to %s, which has no coefficient", Category(c), name)
+		}
+	}
+	if len(categoryFeatures) != len(categoryNames) {
+		t.Errorf("Expected a feature for each of the %d categories, got %d", len(categoryNames), len(categoryFeatures))
+	}
+}
+
+func TestWithCategoryScale(t *testing.T) {
+	e := NewEstimator()
+	scaled := e.WithCategoryScale(CategoryChinese, 0.5)
+
+	coef := func(e *Estimator, text, feature string) float64 {
+		for _, c := range e.Explain(text).Contributions {
+			if c.Feature == feature {
+				return c.Coefficient
+			}
+		}
+		return math.NaN()
+	}
+	chinese := "你好，世界！这是一个测试句子。"
+	if got, want := coef(scaled, chinese, "ChineseChars"), 0.5*coef(e, chinese, "ChineseChars"); got != want {
+		t.Errorf("Expected a Chinese coefficient of %v, got %v", want, got)
+	}
+	if got, want := scaled.Estimate("Hello, world!"), e.Estimate("Hello, world!"); got != want {
+		t.Errorf("Expected English to be unaffected, got %d instead of %d", got, want)
+	}
+	if got, want := coef(e, chinese, "ChineseChars"), coef(NewEstimator(), chinese, "ChineseChars"); got != want {
+		t.Errorf("WithCategoryScale modified the estimator: %v instead of %v", got, want)
+	}
+
+	// Scales carry over to content-type variants and compound.
+	code := NewEstimator().WithContentType(ContentCode)
+	got := coef(scaled.WithCategoryScale(CategoryChinese, 0.5).WithContentType(ContentCode), chinese, "ChineseChars")
+	if want := 0.25 * coef(code, chinese, "ChineseChars"); got != want {
+		t.Errorf("Expected the code variant's Chinese coefficient to be %v, got %v", want, got)
+	}
+
+	if got := e.WithCategoryScale(Category(-1), 2).Estimate(chinese); got != e.Estimate(chinese) {
+		t.Errorf("Expected an unknown category to change nothing, got %d", got)
+	}
+}
@@ -0,0 +1,119 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// kimiK2ChatTemplate renders every message as
+	// <|im_{role}|>{role}<|im_middle|>{content}<|im_end|>.
+	kimiK2ChatTemplate = &ChatTemplate{
+		Name:          "kimi-k2",
+		MessageTokens: 3,
+		RoleTokens:    map[string]int{"system": 1, "user": 1, "assistant": 1, "tool": 1},
+		ReplyTokens:   3,
+	}
+
+	// chatMLTemplate renders every message as
+	// <|im_start|>{role}\n{content}<|im_end|>\n.
+	chatMLTemplate = &ChatTemplate{
+		Name:          "chatml",
+		MessageTokens: 4,
+		RoleTokens:    map[string]int{"system": 1, "user": 1, "assistant": 1, "tool": 1},
+		ReplyTokens:   3,
+	}
+
+	// llama3ChatTemplate renders every message as
+	// <|start_header_id|>{role}<|end_header_id|>\n\n{content}<|eot_id|>,
+	// after a single <|begin_of_text|>.
+	llama3ChatTemplate = &ChatTemplate{
+		Name:          "llama-3",
+		MessageTokens: 4,
+		RoleTokens:    map[string]int{"system": 1, "user": 1, "assistant": 1, "ipython": 2, "tool": 1},
+		ReplyTokens:   5,
+	}
+
+	// mistralChatTemplate renders user messages as [INST] {content} [/INST]
+	// and assistant messages as {content}</s>, after a single <s>. Role
+	// names are not rendered, and the system prompt is joined to the first
+	// user message with a blank line.
+	mistralChatTemplate = &ChatTemplate{
+		Name:          "mistral",
+		MessageTokens: 0,
+		RoleTokens:    map[string]int{"system": 1, "user": 2, "assistant": 1, "tool": 2},
+		ReplyTokens:   1,
+	}
+
+	// gemmaChatTemplate renders every message as
+	// <start_of_turn>{role}\n{content}<end_of_turn>\n, after a single <bos>.
+	// The assistant role is named "model". The system prompt is joined to
+	// the first user turn, so its role entry cancels the framing and leaves
+	// only the blank line between the two.
+	gemmaChatTemplate = &ChatTemplate{
+		Name:          "gemma",
+		MessageTokens: 4,
+		RoleTokens:    map[string]int{"system": -3, "user": 1, "assistant": 1, "model": 1},
+		ReplyTokens:   4,
+	}
+)
+
+var (
+	// chatTemplates holds the templates selectable with WithChatTemplate.
+	chatTemplates = make(map[string]*ChatTemplate)
+
+	// chatTemplatesMu guards chatTemplates, which RegisterChatTemplate may
+	// update while templates are in use.
+	chatTemplatesMu sync.RWMutex
+)
+
+func init() {
+	for _, t := range []*ChatTemplate{
+		kimiK2ChatTemplate, chatMLTemplate, llama3ChatTemplate,
+		mistralChatTemplate, gemmaChatTemplate,
+	} {
+		RegisterChatTemplate(t)
+	}
+}
+
+// RegisterChatTemplate makes a chat template selectable by name with
+// WithChatTemplate. If a template with the same name already exists, it
+// will be overwritten.
+func RegisterChatTemplate(template *ChatTemplate) {
+	if template.Name == "" {
+		return
+	}
+	chatTemplatesMu.Lock()
+	defer chatTemplatesMu.Unlock()
+	chatTemplates[template.Name] = template
+}
+
+// ListChatTemplates returns the names of all registered chat templates in
+// sorted order.
+func ListChatTemplates() []string {
+	chatTemplatesMu.RLock()
+	defer chatTemplatesMu.RUnlock()
+	names := make([]string, 0, len(chatTemplates))
+	for name := range chatTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithChatTemplate returns a clone of the estimator that prices chat
+// messages with the named template, such as "chatml", "llama-3",
+// "mistral" or "gemma", for estimating requests to models other than the
+// preset's own.
+func (e *Estimator) WithChatTemplate(name string) (*Estimator, error) {
+	chatTemplatesMu.RLock()
+	template, ok := chatTemplates[name]
+	chatTemplatesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown chat template: %s", name)
+	}
+	clone := e.Clone()
+	clone.ChatTemplate = template
+	return clone, nil
+}
@@ -0,0 +1,107 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestChatTemplates(t *testing.T) {
+	estimator := NewEstimator()
+	msgs := []Message{
+		{Role: RoleSystem, Content: "You are a helpful assistant."},
+		{Role: "user", Content: "What is the capital of France?"},
+		{Role: "assistant", Content: "Paris."},
+		{Role: "user", Content: "And of Italy?"},
+	}
+	content := 0
+	for _, m := range msgs {
+		content += estimator.Estimate(m.Content)
+	}
+
+	tests := []struct {
+		template string
+		overhead int
+	}{
+		{"kimi-k2", 4*4 + 3},
+		{"chatml", 4*5 + 3},
+		{"llama-3", 4*5 + 5},
+		{"mistral", 1 + 2 + 1 + 2 + 1},
+		{"gemma", 1 + 5 + 5 + 5 + 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			withTemplate, err := estimator.WithChatTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got, want := withTemplate.EstimateMessages(msgs), content+tt.overhead; got != want {
+				t.Errorf("EstimateMessages = %d, want %d", got, want)
+			}
+		})
+	}
+
+	t.Run("Unknown template", func(t *testing.T) {
+		if _, err := estimator.WithChatTemplate("nonexistent"); err == nil {
+			t.Error("Expected error for unknown template")
+		}
+	})
+
+	t.Run("Does not modify original", func(t *testing.T) {
+		if _, err := estimator.WithChatTemplate("chatml"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if estimator.ChatTemplate.Name != "kimi-k2" {
+			t.Errorf("Expected the original to keep kimi-k2, got %s", estimator.ChatTemplate.Name)
+		}
+	})
+
+	t.Run("Register", func(t *testing.T) {
+		RegisterChatTemplate(&ChatTemplate{Name: "test-template", MessageTokens: 10})
+		defer func() {
+			chatTemplatesMu.Lock()
+			delete(chatTemplates, "test-template")
+			chatTemplatesMu.Unlock()
+		}()
+		withTemplate, err := estimator.WithChatTemplate("test-template")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want := content + 4*10
+		for _, m := range msgs {
+			want += estimator.Estimate(m.Role)
+		}
+		if got := withTemplate.EstimateMessages(msgs); got != want {
+			t.Errorf("EstimateMessages = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		names := ListChatTemplates()
+		if len(names) < 5 || names[0] != "chatml" {
+			t.Errorf("Unexpected template list %v", names)
+		}
+	})
+}
+
+func TestRegisterChatTemplateConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("concurrent-%d", g)
+			RegisterChatTemplate(&ChatTemplate{Name: name})
+			if _, err := NewEstimator().WithChatTemplate(name); err != nil {
+				t.Error(err)
+			}
+			ListChatTemplates()
+		}()
+	}
+	wg.Wait()
+	chatTemplatesMu.Lock()
+	for g := range 4 {
+		delete(chatTemplates, fmt.Sprintf("concurrent-%d", g))
+	}
+	chatTemplatesMu.Unlock()
+}
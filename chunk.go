@@ -0,0 +1,214 @@
+package tokenestimate
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// BoundaryMode selects the coarsest boundary SplitByTokens cuts text at.
+// Finer boundaries are used only for pieces that do not fit in a chunk.
+type BoundaryMode int
+
+const (
+	// BoundaryParagraph cuts at blank lines, then lines, sentences, words
+	// and finally between characters.
+	BoundaryParagraph BoundaryMode = iota
+	// BoundaryLine cuts at line breaks, then sentences, words and
+	// characters.
+	BoundaryLine
+	// BoundarySentence cuts after sentence-ending punctuation, then at
+	// words and characters.
+	BoundarySentence
+	// BoundaryWord cuts at spaces, then between characters.
+	BoundaryWord
+	// BoundaryRune cuts between any two characters.
+	BoundaryRune
+)
+
+// ChunkOptions configures SplitByTokens.
+type ChunkOptions struct {
+	MaxTokens     int          // Upper bound on the estimated tokens in a chunk
+	OverlapTokens int          // Estimated tokens repeated from the end of the previous chunk
+	BoundaryMode  BoundaryMode // Coarsest boundary to cut at
+}
+
+// validate checks that the options describe a possible split.
+func (o ChunkOptions) validate() error {
+	switch {
+	case o.MaxTokens <= 0:
+		return errors.New("chunk MaxTokens must be positive")
+	case o.OverlapTokens < 0:
+		return errors.New("chunk OverlapTokens must not be negative")
+	case o.OverlapTokens >= o.MaxTokens:
+		return errors.New("chunk OverlapTokens must be less than MaxTokens")
+	case o.BoundaryMode < BoundaryParagraph || o.BoundaryMode > BoundaryRune:
+		return errors.New("unknown chunk BoundaryMode")
+	}
+	return nil
+}
+
+// cutFunc returns the offsets in s after which s may be cut, in increasing
+// order. Cutting after a separator keeps it with the text it ends.
+type cutFunc func(s string) []int
+
+// chunkCuts lists the boundaries from coarsest to finest, indexed by
+// BoundaryMode.
+var chunkCuts = []cutFunc{
+	cutAfter("\n\n"),
+	cutAfter("\n"),
+	cutSentences,
+	cutAfter(" "),
+	cutRunes,
+}
+
+// cutAfter returns a cutFunc that cuts after every occurrence of sep.
+func cutAfter(sep string) cutFunc {
+	return func(s string) []int {
+		var cuts []int
+		for i := 0; ; {
+			j := strings.Index(s[i:], sep)
+			if j < 0 {
+				return cuts
+			}
+			i += j + len(sep)
+			cuts = append(cuts, i)
+		}
+	}
+}
+
+// cutSentences cuts after '.', '!' or '?' and the spaces that follow them,
+// and after the CJK full stop, exclamation and question marks.
+func cutSentences(s string) []int {
+	var cuts []int
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch r {
+		case '.', '!', '?':
+			if i < len(s) && s[i] == ' ' {
+				for i < len(s) && s[i] == ' ' {
+					i++
+				}
+				cuts = append(cuts, i)
+			}
+		case '。', '！', '？':
+			cuts = append(cuts, i)
+		}
+	}
+	return cuts
+}
+
+// cutRunes cuts between every two runes.
+func cutRunes(s string) []int {
+	cuts := make([]int, 0, len(s))
+	for i := range s {
+		if i > 0 {
+			cuts = append(cuts, i)
+		}
+	}
+	return cuts
+}
+
+// chunkPiece is a span of the text being split.
+type chunkPiece struct {
+	start, end int
+}
+
+// chunker holds the state of one SplitByTokens call.
+type chunker struct {
+	e    *Estimator
+	text string
+	opts ChunkOptions
+}
+
+// SplitByTokens splits text into chunks of at most opts.MaxTokens estimated
+// tokens, for example to index documents for retrieval. Like LangChain's
+// RecursiveCharacterTextSplitter, it cuts at the coarsest boundary that
+// yields pieces small enough to fit, starting from opts.BoundaryMode, and
+// only falls back to finer boundaries for pieces that are still too
+// large. The pieces are then packed greedily into chunks, and each chunk
+// after the first starts with trailing pieces of the previous chunk
+// totalling at most opts.OverlapTokens.
+//
+// Chunks are substrings of text, so with no overlap they concatenate back
+// to the original text. Every chunk is estimated as a whole before it is
+// accepted, since summing the statistics of its pieces misses features
+// spanning a cut, such as a whitespace run split at a space, and can
+// undercount. A single character that costs more than opts.MaxTokens
+// becomes a chunk of its own.
+func (e *Estimator) SplitByTokens(text string, opts ChunkOptions) ([]string, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	c := chunker{e: e, text: text, opts: opts}
+	pieces := c.split(nil, 0, len(text), chunkCuts[opts.BoundaryMode:])
+	return c.merge(pieces), nil
+}
+
+// split appends the pieces of text[start:end] to pieces, cutting with the
+// first of cuts that makes progress and recursing into pieces that are
+// still too large with the remaining cuts.
+func (c *chunker) split(pieces []chunkPiece, start, end int, cuts []cutFunc) []chunkPiece {
+	stats := c.e.Analyze(c.text[start:end])
+	if c.tokens(stats) <= c.opts.MaxTokens || len(cuts) == 0 {
+		return append(pieces, chunkPiece{start: start, end: end})
+	}
+
+	for i, cut := range cuts {
+		offsets := cut(c.text[start:end])
+		if len(offsets) > 0 && offsets[len(offsets)-1] == end-start {
+			offsets = offsets[:len(offsets)-1]
+		}
+		if len(offsets) == 0 {
+			continue
+		}
+		from := start
+		for _, offset := range offsets {
+			pieces = c.split(pieces, from, start+offset, cuts[i+1:])
+			from = start + offset
+		}
+		return c.split(pieces, from, end, cuts[i+1:])
+	}
+	return append(pieces, chunkPiece{start: start, end: end})
+}
+
+// tokens returns the estimate for stats. Unlike EstimateFromStats it never
+// logs, since a split estimates the same characters many times.
+func (c *chunker) tokens(stats Stats) int {
+	return c.e.roundTokens(c.e.calculateTokenCount(stats))
+}
+
+// spanTokens returns the estimate for text[start:end].
+func (c *chunker) spanTokens(start, end int) int {
+	return c.tokens(c.e.Analyze(c.text[start:end]))
+}
+
+// merge packs consecutive pieces into chunks with the configured overlap.
+func (c *chunker) merge(pieces []chunkPiece) []string {
+	var chunks []string
+	var window []chunkPiece
+
+	for _, p := range pieces {
+		if len(window) > 0 && c.spanTokens(window[0].start, p.end) > c.opts.MaxTokens {
+			chunks = append(chunks, c.text[window[0].start:window[len(window)-1].end])
+			if c.opts.OverlapTokens == 0 {
+				window = window[:0]
+			}
+			for len(window) > 0 &&
+				(c.spanTokens(window[0].start, window[len(window)-1].end) > c.opts.OverlapTokens ||
+					c.spanTokens(window[0].start, p.end) > c.opts.MaxTokens) {
+				window = window[1:]
+			}
+		}
+		window = append(window, p)
+	}
+	if len(window) > 0 {
+		chunks = append(chunks, c.text[window[0].start:window[len(window)-1].end])
+	}
+	return chunks
+}
@@ -0,0 +1,159 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSplitByTokens(t *testing.T) {
+	estimator := NewEstimator()
+	paragraph := "The quick brown fox jumps over the lazy dog. It was not amused. " +
+		"The dog went back to sleep and the fox ran off into the woods.\n\n"
+	text := strings.Repeat(paragraph, 20)
+
+	t.Run("Chunks fit and reassemble", func(t *testing.T) {
+		for mode := BoundaryParagraph; mode <= BoundaryRune; mode++ {
+			chunks, err := estimator.SplitByTokens(text, ChunkOptions{MaxTokens: 50, BoundaryMode: mode})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(chunks) < 2 {
+				t.Fatalf("Mode %d: expected several chunks, got %d", mode, len(chunks))
+			}
+			for _, chunk := range chunks {
+				if tokens := estimator.Estimate(chunk); tokens > 50 {
+					t.Errorf("Mode %d: chunk %q has %d tokens", mode, chunk, tokens)
+				}
+			}
+			if got := strings.Join(chunks, ""); got != text {
+				t.Errorf("Mode %d: chunks do not reassemble the text", mode)
+			}
+		}
+	})
+
+	t.Run("Prefers coarse boundaries", func(t *testing.T) {
+		chunks, err := estimator.SplitByTokens(text, ChunkOptions{MaxTokens: 50})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, chunk := range chunks {
+			if !strings.HasPrefix(chunk, "The quick") || !strings.HasSuffix(chunk, "woods.\n\n") {
+				t.Errorf("Expected whole paragraphs, got %q", chunk)
+			}
+		}
+	})
+
+	t.Run("Falls back to sentences", func(t *testing.T) {
+		chunks, err := estimator.SplitByTokens(paragraph, ChunkOptions{MaxTokens: 20})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(chunks) < 2 {
+			t.Fatalf("Expected several chunks, got %d", len(chunks))
+		}
+		if !strings.HasSuffix(chunks[0], ". ") {
+			t.Errorf("Expected the first chunk to end a sentence, got %q", chunks[0])
+		}
+	})
+
+	t.Run("Overlap", func(t *testing.T) {
+		var words []string
+		for i := range 300 {
+			words = append(words, fmt.Sprintf("word%d", i))
+		}
+		text := strings.Join(words, " ")
+		chunks, err := estimator.SplitByTokens(text, ChunkOptions{MaxTokens: 40, OverlapTokens: 15, BoundaryMode: BoundaryWord})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(chunks) < 2 {
+			t.Fatalf("Expected several chunks, got %d", len(chunks))
+		}
+		for i := 1; i < len(chunks); i++ {
+			prev, chunk := chunks[i-1], chunks[i]
+			overlap := 0
+			for n := min(len(prev), len(chunk)); n > 0; n-- {
+				if strings.HasSuffix(prev, chunk[:n]) {
+					overlap = n
+					break
+				}
+			}
+			if overlap == 0 {
+				t.Fatalf("Chunk %d does not overlap the previous one", i)
+			}
+			if tokens := estimator.Estimate(chunk[:overlap]); tokens > 15 {
+				t.Errorf("Chunk %d overlaps by %d tokens", i, tokens)
+			}
+			if tokens := estimator.Estimate(chunk); tokens > 40 {
+				t.Errorf("Chunk %d has %d tokens", i, tokens)
+			}
+		}
+	})
+
+	t.Run("Chunks fit across whitespace runs", func(t *testing.T) {
+		var table, code strings.Builder
+		table.WriteString("| id   | name       | value      |\n|------|------------|------------|\n")
+		for i := range 200 {
+			fmt.Fprintf(&table, "| %-4d | item %-5d | %-10d |\n", i, i, i*37)
+			fmt.Fprintf(&code, "        if x%d > %d {\n                return    %d\n        }\n", i, i, i)
+		}
+		for _, text := range []string{table.String(), code.String()} {
+			for _, mode := range []BoundaryMode{BoundarySentence, BoundaryWord} {
+				chunks, err := estimator.SplitByTokens(text, ChunkOptions{MaxTokens: 100, BoundaryMode: mode})
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				for i, chunk := range chunks {
+					if tokens := estimator.Estimate(chunk); tokens > 100 {
+						t.Errorf("Mode %d: chunk %d has %d tokens", mode, i, tokens)
+					}
+				}
+				if got := strings.Join(chunks, ""); got != text {
+					t.Errorf("Mode %d: chunks do not reassemble the text", mode)
+				}
+			}
+		}
+	})
+
+	t.Run("CJK text", func(t *testing.T) {
+		cjk := strings.Repeat("今天天气很好。我们去公园散步吧！", 30)
+		chunks, err := estimator.SplitByTokens(cjk, ChunkOptions{MaxTokens: 30, BoundaryMode: BoundarySentence})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, chunk := range chunks {
+			if !strings.HasSuffix(chunk, "。") && !strings.HasSuffix(chunk, "！") {
+				t.Errorf("Expected chunk to end a sentence, got %q", chunk)
+			}
+		}
+		if got := strings.Join(chunks, ""); got != cjk {
+			t.Error("Chunks do not reassemble the text")
+		}
+	})
+
+	t.Run("Short and empty text", func(t *testing.T) {
+		chunks, err := estimator.SplitByTokens("Hello, world!", ChunkOptions{MaxTokens: 100})
+		if err != nil || len(chunks) != 1 || chunks[0] != "Hello, world!" {
+			t.Errorf("Expected a single chunk, got %q, %v", chunks, err)
+		}
+		chunks, err = estimator.SplitByTokens("", ChunkOptions{MaxTokens: 100})
+		if err != nil || len(chunks) != 0 {
+			t.Errorf("Expected no chunks, got %q, %v", chunks, err)
+		}
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		invalid := []ChunkOptions{
+			{MaxTokens: 0},
+			{MaxTokens: 10, OverlapTokens: -1},
+			{MaxTokens: 10, OverlapTokens: 10},
+			{MaxTokens: 10, BoundaryMode: BoundaryMode(99)},
+		}
+		for _, opts := range invalid {
+			if _, err := estimator.SplitByTokens("text", opts); err == nil {
+				t.Errorf("Expected error for %+v", opts)
+			}
+		}
+	})
+}
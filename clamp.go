@@ -0,0 +1,79 @@
+package tokenestimate
+
+import "math"
+
+// charBounds gives the shortest and longest UTF-8 encoding of the
+// characters a Stats field counts, and the most tokens they take on
+// average.
+type charBounds struct {
+	feature  feature
+	min, max int
+	tokens   int
+}
+
+// charBytes lists the UTF-8 lengths of the characters counted by each
+// category of Stats, and their token ceilings. Characters of dictionary
+// words are not in any category.
+//
+// A ceiling is the worst average measured on texts of random characters
+// of the category with cl100k_base and o200k_base, rounded up. Rare
+// characters are split into their bytes, so most categories take nearly
+// a token per byte: random Chinese characters take 3.6 tokens each in
+// cl100k_base, random Latin Extended letters 2.1 and random kana 1.6.
+// Only ASCII letters, kana and spaces take fewer tokens than bytes.
+// Kimi-K2's vocabulary was not available to measure.
+var charBytes = []charBounds{
+	{featuresByName["Symbols"], 1, 4, 4},
+	{featuresByName["LatinLetters"], 1, 1, 1},
+	{featuresByName["LatinExtended"], 2, 3, 3},
+	{featuresByName["Digits"], 1, 4, 4},
+	{featuresByName["ChineseChars"], 3, 4, 4},
+	{featuresByName["JapaneseKana"], 3, 3, 2},
+	{featuresByName["KoreanHangul"], 3, 3, 3},
+	{featuresByName["RussianChars"], 2, 3, 3},
+	{featuresByName["ArabicChars"], 2, 3, 3},
+	{featuresByName["KhmerChars"], 3, 3, 3},
+	{featuresByName["LaoChars"], 3, 3, 3},
+	{featuresByName["MyanmarChars"], 3, 3, 3},
+	{featuresByName["EthiopicChars"], 3, 3, 3},
+	{featuresByName["Spaces"], 1, 3, 2},
+	{featuresByName["Tabs"], 1, 1, 1},
+	{featuresByName["Unknown"], 2, 4, 4},
+	{featuresByName["InvalidBytes"], 1, 1, 1},
+	{featuresByName["BlobChars"], 1, 1, 1},
+}
+
+// tokenBounds returns the fewest and most tokens the text described by
+// stats can encode to, whatever its exact characters. No byte-level or
+// byte-fallback tokenizer produces more tokens than the text has bytes,
+// nor more than the measured ceiling of charBytes per character; and a
+// non-empty text takes at least one token, and at least its bytes divided
+// by the length of the longest token in the vocabulary when MaxTokenBytes
+// is known. Dictionary words and special tokens are known exactly.
+func (e *Estimator) tokenBounds(stats *Stats) (low, high float64) {
+	minBytes, maxTokens := 0, 0
+	for _, c := range charBytes {
+		n := *c.feature.field(stats)
+		minBytes += c.min * n
+		maxTokens += c.tokens * n
+	}
+	exact := stats.DictionaryTokens + stats.SpecialTokens
+
+	if minBytes+exact <= 0 {
+		return 0, 0
+	}
+	high = float64(maxTokens + exact)
+	low = float64(exact)
+	if e.MaxTokenBytes > 0 {
+		low += math.Ceil(float64(minBytes) / float64(e.MaxTokenBytes))
+	}
+	return max(low, 1), high
+}
+
+// clampTokens limits a model output to the bounds of tokenBounds, so that
+// an extreme coefficient or an unusual input cannot produce an impossible
+// estimate.
+func (e *Estimator) clampTokens(stats *Stats, count float64) float64 {
+	low, high := e.tokenBounds(stats)
+	return min(max(count, low), high)
+}
@@ -0,0 +1,76 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenBounds(t *testing.T) {
+	t.Run("At most one token per byte", func(t *testing.T) {
+		estimator := &Estimator{coefLatinExt: 6}
+		text := strings.Repeat("é", 40)
+		if got := estimator.Estimate(text); got > len(text) {
+			t.Errorf("Expected at most %d tokens, got %d", len(text), got)
+		}
+	})
+
+	t.Run("Token ceilings per character", func(t *testing.T) {
+		estimator := &Estimator{coefJapanese: 6, coefEthiopic: 6, coefLatinLetters: 6}
+		if got := estimator.Estimate(strings.Repeat("の", 40)); got != 80 {
+			t.Errorf("Expected 80 tokens for 40 kana, got %d", got)
+		}
+		if got := estimator.Estimate(strings.Repeat("ሰ", 40)); got != 120 {
+			t.Errorf("Expected 120 tokens for 40 characters of 3 bytes, got %d", got)
+		}
+		if got := estimator.Estimate("abcde"); got != 5 {
+			t.Errorf("Expected 5 tokens for 5 letters, got %d", got)
+		}
+	})
+
+	t.Run("At least one token for non-empty text", func(t *testing.T) {
+		estimator := &Estimator{coefLatinLetters: -1}
+		if got := estimator.Estimate("abc"); got != 1 {
+			t.Errorf("Expected 1 token, got %d", got)
+		}
+		if got := estimator.Estimate(""); got != 0 {
+			t.Errorf("Expected 0 tokens for empty text, got %d", got)
+		}
+		if got := (&Estimator{intercept: 3}).Estimate(""); got != 0 {
+			t.Errorf("Expected 0 tokens for empty text with an intercept, got %d", got)
+		}
+	})
+
+	t.Run("Longest token", func(t *testing.T) {
+		estimator := &Estimator{coefLatinLetters: 0.01, MaxTokenBytes: 4}
+		if got := estimator.Estimate(strings.Repeat("a", 100)); got != 25 {
+			t.Errorf("Expected 25 tokens, got %d", got)
+		}
+	})
+
+	t.Run("Kimi-K2 presets know their longest token", func(t *testing.T) {
+		for _, estimator := range []*Estimator{KimiK2Estimator, KimiK2CodeEstimator, KimiK2MarkdownEstimator, KimiK2JSONEstimator} {
+			if got := estimator.Estimate(strings.Repeat(" ", 2560)); estimator.MaxTokenBytes == 0 || got < 10 {
+				t.Errorf("%s: Estimate of 2560 spaces = %d with MaxTokenBytes %d, want at least 10", estimator.Key(), got, estimator.MaxTokenBytes)
+			}
+		}
+	})
+
+	t.Run("Exact counts", func(t *testing.T) {
+		stats := Stats{SpecialTokens: 3, DictionaryWords: 2, DictionaryChars: 10, DictionaryTokens: 2}
+		if low, high := (&Estimator{}).tokenBounds(&stats); low != 5 || high != 5 {
+			t.Errorf("Expected bounds of 5 and 5, got %v and %v", low, high)
+		}
+	})
+
+	t.Run("Presets stay within bytes", func(t *testing.T) {
+		for _, versions := range presetVersions {
+			for _, estimator := range versions {
+				for _, text := range referenceTexts {
+					if got := estimator.Estimate(text); got > len(text) || (text != "" && got < 1) {
+						t.Errorf("%s: Estimate(%q) = %d, outside 1..%d", estimator.Key(), text, got, len(text))
+					}
+				}
+			}
+		}
+	})
+}
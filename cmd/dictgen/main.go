@@ -0,0 +1,248 @@
+// Command dictgen measures the token cost of every word of a word list
+// with a tokenizer's byte-pair encoding and writes the words and their
+// costs to a Go source file, such as the dictionary_o200k.go of the
+// tokenestimate package. The encoding is read from a file in tiktoken's
+// format, a base64 token and its rank per line, which is how Kimi-K2's
+// tiktoken.model and the cl100k_base and o200k_base encodings ship.
+//
+// A Latin word costs its tokens after a space, where most words of a text
+// are; a Chinese word costs its own tokens. Latin words whose cost differs
+// in another form the dictionary matches, without the space or with a
+// leading capital, have no exact cost and are left out with a report. The
+// length of the longest token of the encoding, the MaxTokenBytes of its
+// presets, is reported too:
+//
+//	go run ./cmd/dictgen -bpe o200k_base.tiktoken -var o200kDictionary -o dictionary_o200k.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+func main() {
+	var (
+		bpe     = flag.String("bpe", "", "tokenizer encoding in tiktoken's format (required)")
+		words   = flag.String("words", "testdata/dictionary/words.txt", "word list")
+		output  = flag.String("o", "", "output file (required)")
+		varName = flag.String("var", "", "name of the generated variable (required)")
+	)
+	flag.Parse()
+	if *bpe == "" || *output == "" || *varName == "" {
+		log.Fatal("dictgen: -bpe, -o and -var are required")
+	}
+
+	ranks, longest, err := loadRanks(*bpe)
+	if err != nil {
+		log.Fatal(err)
+	}
+	groups, err := loadWords(*words)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i := range groups {
+		g := &groups[i]
+		kept := g.entries[:0]
+		for _, w := range g.entries {
+			if w.cost = cost(ranks, w.word); w.cost > 0 {
+				kept = append(kept, w)
+			}
+		}
+		g.entries = kept
+	}
+	log.Printf("dictgen: the longest token of %s is %d bytes", filepath.Base(*bpe), longest)
+
+	encoding := strings.TrimSuffix(filepath.Base(*bpe), filepath.Ext(*bpe))
+	src, err := render(*varName, encoding, groups)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// group is a group of words of the word list, such as English.
+type group struct {
+	name    string
+	entries []entry
+}
+
+// entry is a word and its token cost.
+type entry struct {
+	word string
+	cost int
+}
+
+// loadRanks reads an encoding in tiktoken's format and returns the rank of
+// every token and the length of the longest one in bytes.
+func loadRanks(path string) (map[string]int, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	ranks := make(map[string]int)
+	longest := 0
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, 0, fmt.Errorf("%s:%d: want a token and a rank", path, line)
+		}
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		ranks[string(token)] = rank
+		longest = max(longest, len(token))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(ranks) == 0 {
+		return nil, 0, fmt.Errorf("%s: no tokens", path)
+	}
+	return ranks, longest, nil
+}
+
+// loadWords reads a word list: a word per line, in groups started by a
+// group name in brackets. Blank lines and lines starting with # are
+// skipped.
+func loadWords(path string) ([]group, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var groups []group
+	seen := make(map[string]bool)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			groups = append(groups, group{name: line[1 : len(line)-1]})
+			continue
+		case len(groups) == 0:
+			return nil, fmt.Errorf("%s:%d: word before the first group", path, i+1)
+		case seen[line]:
+			return nil, fmt.Errorf("%s:%d: duplicate word %q", path, i+1, line)
+		case isLatin(line) && line != strings.ToLower(line):
+			return nil, fmt.Errorf("%s:%d: Latin word %q is not in lower case", path, i+1, line)
+		}
+		seen[line] = true
+		g := &groups[len(groups)-1]
+		g.entries = append(g.entries, entry{word: line})
+	}
+	return groups, nil
+}
+
+// cost returns the token cost of word, or 0 if it has none because its
+// forms cost different numbers of tokens.
+func cost(ranks map[string]int, word string) int {
+	if !isLatin(word) {
+		return encode(ranks, word)
+	}
+	n := encode(ranks, " "+word)
+	capital := strings.ToUpper(word[:1]) + word[1:]
+	for _, form := range []string{word, " " + capital, capital} {
+		if c := encode(ranks, form); c != n {
+			log.Printf("dictgen: left out %q: %q is %d tokens but %q is %d", word, " "+word, n, form, c)
+			return 0
+		}
+	}
+	return n
+}
+
+// isLatin reports whether word is made of ASCII letters.
+func isLatin(word string) bool {
+	for i := 0; i < len(word); i++ {
+		if c := word[i]; c >= utf8.RuneSelf || !unicode.IsLetter(rune(c)) {
+			return false
+		}
+	}
+	return word != ""
+}
+
+// encode returns the number of tokens of piece under the byte-pair
+// encoding of ranks. Like tiktoken, it starts from single bytes and merges
+// the adjacent pair forming the token of the lowest rank until no pair
+// forms a token. Dictionary words are encoded whole: the split patterns of
+// the tokenizers keep a word together with the space before it.
+func encode(ranks map[string]int, piece string) int {
+	if _, ok := ranks[piece]; ok {
+		return 1
+	}
+	parts := make([]string, len(piece))
+	for i := 0; i < len(piece); i++ {
+		parts[i] = piece[i : i+1]
+	}
+	for len(parts) > 1 {
+		best, at := 0, -1
+		for i := 0; i+1 < len(parts); i++ {
+			if rank, ok := ranks[parts[i]+parts[i+1]]; ok && (at < 0 || rank < best) {
+				best, at = rank, i
+			}
+		}
+		if at < 0 {
+			break
+		}
+		parts[at] += parts[at+1]
+		parts = slices.Delete(parts, at+1, at+2)
+	}
+	return len(parts)
+}
+
+// render returns the source of the dictionary variable varName.
+func render(varName, encoding string, groups []group) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by dictgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package tokenestimate\n\n")
+	fmt.Fprintf(&buf, "// %s holds frequent words with their token costs\n", varName)
+	fmt.Fprintf(&buf, "// under %s, as measured by dictgen.\n", encoding)
+	fmt.Fprintf(&buf, "var %s = Dictionary{\n", varName)
+	for _, g := range groups {
+		fmt.Fprintf(&buf, "// %s\n", g.name)
+		width := 0
+		for _, e := range g.entries {
+			item := fmt.Sprintf("%q: %d,", e.word, e.cost)
+			n := utf8.RuneCountInString(item)
+			if width > 0 && width+1+n > 72 {
+				buf.WriteByte('\n')
+				width = 0
+			}
+			if width > 0 {
+				buf.WriteByte(' ')
+				width++
+			}
+			buf.WriteString(item)
+			width += n
+		}
+		buf.WriteByte('\n')
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,89 @@
+// Command goldengen writes a Go source file containing a snapshot of the
+// estimates produced by tokenestimate presets for the library's reference
+// texts. Pass the generated variable to tokenestimate.VerifyGolden in a test
+// to detect silently changed coefficients when upgrading the library.
+//
+// Usage:
+//
+//	//go:generate go run github.com/infinigence/tokenestimate/cmd/goldengen -pkg mypkg -o tokenestimate_golden_test.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+func main() {
+	var (
+		output  = flag.String("o", "tokenestimate_golden_test.go", "output file")
+		pkg     = flag.String("pkg", "main", "package name of the generated file")
+		varName = flag.String("var", "tokenestimateGolden", "name of the generated variable")
+		names   = flag.String("presets", "", "comma-separated preset names (default: all registered presets)")
+	)
+	flag.Parse()
+
+	var presets []string
+	if *names != "" {
+		presets = strings.Split(*names, ",")
+	}
+
+	golden, err := tokenestimate.GenerateGolden(presets...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := render(*pkg, *varName, golden)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// render produces gofmt-formatted source declaring varName as golden.
+func render(pkg, varName string, golden tokenestimate.Golden) ([]byte, error) {
+	qualifier := "tokenestimate."
+	if pkg == "tokenestimate" {
+		qualifier = ""
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by goldengen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	if qualifier != "" {
+		fmt.Fprintf(&buf, "import \"github.com/infinigence/tokenestimate\"\n\n")
+	}
+	fmt.Fprintf(&buf, "var %s = %sGolden{\n", varName, qualifier)
+
+	names := make([]string, 0, len(golden))
+	for name := range golden {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%q: {\n", name)
+		texts := make([]string, 0, len(golden[name]))
+		for text := range golden[name] {
+			texts = append(texts, text)
+		}
+		sort.Strings(texts)
+		for _, text := range texts {
+			fmt.Fprintf(&buf, "%q: %d,\n", text, golden[name][text])
+		}
+		fmt.Fprintf(&buf, "},\n")
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	return format.Source(buf.Bytes())
+}
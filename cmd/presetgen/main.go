@@ -0,0 +1,253 @@
+// Command presetgen fits a preset to each training dataset of a directory
+// and writes the presets with their accuracy to a Go source file, the
+// presets_gen.go of the tokenestimate package. A dataset is a JSONL, CSV
+// or TSV file the dataset package loads, and the preset is named after the
+// file: testdata/presets/cl100k-base.jsonl fits the preset cl100k-base.
+//
+// Each preset is fitted on top of a built-in preset to 80% of its samples,
+// and its accuracy and the residual quantiles of EstimateRange are
+// measured on the other 20%, so the same datasets always produce the same
+// presets. Run it with go generate after changing a dataset:
+//
+//	go generate github.com/infinigence/tokenestimate
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/infinigence/tokenestimate/dataset"
+	"github.com/infinigence/tokenestimate/eval"
+	"github.com/infinigence/tokenestimate/fit"
+)
+
+func main() {
+	var (
+		dir    = flag.String("dir", "testdata/presets", "directory of training datasets")
+		output = flag.String("o", "presets_gen.go", "output file")
+		base   = flag.String("base", "", "preset the presets are fitted on top of (default: the latest version of the default preset)")
+		date   = flag.String("date", "", "training date of the presets, as 2006-01-02 (default: today for presets whose coefficients change)")
+	)
+	flag.Parse()
+
+	if *base == "" {
+		e := tokenestimate.NewEstimator()
+		*base = fmt.Sprintf("%s@%d", e.Name, e.Version)
+	}
+	b, err := tokenestimate.GetPresetByName(*base)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if *date != "" {
+		if today, err = time.Parse(time.DateOnly, *date); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	paths, err := datasets(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var presets []preset
+	for _, path := range paths {
+		p, err := fitPreset(path, *base, b)
+		if err != nil {
+			log.Fatal(err)
+		}
+		p.trained = today
+		if *date == "" {
+			if current, err := tokenestimate.GetPresetByName(p.name); err == nil && p.sameModel(current) {
+				p.trained = current.Metadata.TrainingDate
+			}
+		}
+		presets = append(presets, p)
+	}
+
+	src, err := render(presets)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// preset is a fitted preset as it is written to the generated file.
+type preset struct {
+	name, description, base string
+	intercept               float64
+	coefficients            map[string]float64
+	residualP10             float64
+	residualP90             float64
+	accuracy                tokenestimate.Accuracy
+	trained                 time.Time
+	corpus                  string
+}
+
+// sameModel reports whether the preset has the coefficients of e, the
+// preset currently generated, so that regenerating unchanged datasets
+// keeps the training date.
+func (p *preset) sameModel(e *tokenestimate.Estimator) bool {
+	if e.Metadata.TrainingDate.IsZero() || e.Intercept() != p.intercept {
+		return false
+	}
+	coefs := e.Coefficients()
+	for feature, coef := range p.coefficients {
+		if coefs[feature] != coef {
+			return false
+		}
+	}
+	return true
+}
+
+// datasets returns the paths of the datasets in dir, sorted.
+func datasets(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%s has no datasets", dir)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// fitPreset fits a preset to the dataset at path on top of base, whose
+// registry key is baseKey.
+func fitPreset(path, baseKey string, base *tokenestimate.Estimator) (preset, error) {
+	samples, err := dataset.Load(path)
+	if err != nil {
+		return preset{}, err
+	}
+	file := filepath.Base(path)
+	name, _, _ := strings.Cut(file, ".")
+	train, test := fit.Holdout(samples, 0.2, 1)
+	if len(test) == 0 {
+		return preset{}, fmt.Errorf("%s: too few samples to hold any out", path)
+	}
+	e, report, err := fit.Fit(train, fit.FitOptions{
+		Base:     base,
+		Method:   fit.NNLS,
+		Relative: true,
+		Robust:   true,
+		Name:     name,
+	})
+	if err != nil {
+		return preset{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	r := eval.Evaluate(e, test)
+	accuracy := tokenestimate.Accuracy{
+		Dataset:  file,
+		Samples:  r.Samples,
+		MAPE:     round(r.MAPE),
+		P90:      round(r.P90),
+		PassRate: round(r.PassRate),
+		ByScript: make(map[string]float64, len(r.ByScript)),
+	}
+	for script, m := range r.ByScript {
+		accuracy.ByScript[script] = round(m.MAPE)
+	}
+	p10, p90 := residualQuantiles(e, test)
+	return preset{
+		name:         name,
+		description:  fmt.Sprintf("Preset fitted to %d samples of %s (~%.1f%% avg error)", report.Samples, file, 100*accuracy.MAPE),
+		base:         baseKey,
+		intercept:    report.Intercept,
+		coefficients: report.Coefficients,
+		residualP10:  p10,
+		residualP90:  p90,
+		accuracy:     accuracy,
+		corpus:       fmt.Sprintf("%d labeled texts of %s", len(samples), file),
+	}, nil
+}
+
+// residualQuantiles returns the 10th and 90th percentiles of the ratio of
+// actual to estimated tokens of samples, rounded outwards to three
+// decimals.
+func residualQuantiles(e *tokenestimate.Estimator, samples []fit.Sample) (p10, p90 float64) {
+	var ratios []float64
+	for _, s := range samples {
+		if estimate := e.Estimate(s.Text); s.Tokens > 0 && estimate > 0 {
+			ratios = append(ratios, float64(s.Tokens)/float64(estimate))
+		}
+	}
+	if len(ratios) == 0 {
+		return 0, 0
+	}
+	sort.Float64s(ratios)
+	at := func(q float64) float64 {
+		return ratios[int(math.Round(q*float64(len(ratios)-1)))]
+	}
+	return math.Floor(at(0.1)*1000) / 1000, math.Ceil(at(0.9)*1000) / 1000
+}
+
+// round rounds a metric to four decimals.
+func round(x float64) float64 {
+	return math.Round(x*1e4) / 1e4
+}
+
+// render produces gofmt-formatted source declaring generatedPresets.
+func render(presets []preset) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by presetgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package tokenestimate\n\n")
+	fmt.Fprintf(&buf, "import \"time\"\n\n")
+	fmt.Fprintf(&buf, "var generatedPresets = []fittedPreset{\n")
+	for _, p := range presets {
+		fmt.Fprintf(&buf, "{\n")
+		fmt.Fprintf(&buf, "name: %q,\n", p.name)
+		fmt.Fprintf(&buf, "description: %q,\n", p.description)
+		fmt.Fprintf(&buf, "base: %q,\n", p.base)
+		fmt.Fprintf(&buf, "intercept: %v,\n", p.intercept)
+		fmt.Fprintf(&buf, "coefficients: map[string]float64{\n")
+		for _, feature := range slices.Sorted(maps.Keys(p.coefficients)) {
+			fmt.Fprintf(&buf, "%q: %v,\n", feature, p.coefficients[feature])
+		}
+		fmt.Fprintf(&buf, "},\n")
+		fmt.Fprintf(&buf, "residualP10: %v,\n", p.residualP10)
+		fmt.Fprintf(&buf, "residualP90: %v,\n", p.residualP90)
+		a := p.accuracy
+		fmt.Fprintf(&buf, "accuracy: Accuracy{\n")
+		fmt.Fprintf(&buf, "Dataset: %q,\n", a.Dataset)
+		fmt.Fprintf(&buf, "Samples: %d,\n", a.Samples)
+		fmt.Fprintf(&buf, "MAPE: %v,\n", a.MAPE)
+		fmt.Fprintf(&buf, "P90: %v,\n", a.P90)
+		fmt.Fprintf(&buf, "PassRate: %v,\n", a.PassRate)
+		fmt.Fprintf(&buf, "ByScript: map[string]float64{\n")
+		for _, script := range slices.Sorted(maps.Keys(a.ByScript)) {
+			fmt.Fprintf(&buf, "%q: %v,\n", script, a.ByScript[script])
+		}
+		fmt.Fprintf(&buf, "},\n")
+		fmt.Fprintf(&buf, "},\n")
+		y, m, d := p.trained.Date()
+		fmt.Fprintf(&buf, "trained: time.Date(%d, %d, %d, 0, 0, 0, 0, time.UTC),\n", y, m, d)
+		fmt.Fprintf(&buf, "corpus: %q,\n", p.corpus)
+		fmt.Fprintf(&buf, "},\n")
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	return format.Source(buf.Bytes())
+}
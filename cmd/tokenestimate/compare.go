@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/infinigence/tokenestimate/dataset"
+	"github.com/infinigence/tokenestimate/eval"
+)
+
+// comparison is the accuracy and speed of a preset on a dataset.
+type comparison struct {
+	preset string
+	report eval.Report
+	perOp  time.Duration // time to estimate a sample, on average
+	mbps   float64       // megabytes of text estimated per second
+}
+
+// runCompare measures several presets on a dataset and writes them side
+// by side.
+func runCompare(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("tokenestimate compare", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: tokenestimate compare -dataset file [-presets a,b,...] [-format text|json|csv] [-o file]")
+		fs.PrintDefaults()
+	}
+	path := fs.String("dataset", "", "JSONL, CSV or TSV file of texts labeled with their token counts")
+	names := fs.String("presets", "", "comma-separated presets to compare (default: the latest version of every preset)")
+	format := fs.String("format", "text", "report format: text, json or csv")
+	output := fs.String("o", "", "file to write the report to (default: standard output)")
+	benchtime := fs.Duration("benchtime", 200*time.Millisecond, "time to spend timing each preset")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch {
+	case *path == "":
+		return errors.New("compare: -dataset is required")
+	case fs.NArg() > 0:
+		return fmt.Errorf("compare: unexpected arguments: %q", fs.Args())
+	case *format != "text" && *format != "json" && *format != "csv":
+		return fmt.Errorf("compare: unknown format: %s", *format)
+	}
+
+	var estimators []*tokenestimate.Estimator
+	if *names == "" {
+		for _, info := range tokenestimate.ListPresetsDetailed() {
+			if info.Latest {
+				e, err := tokenestimate.NewEstimatorWithName(info.Name)
+				if err != nil {
+					return err
+				}
+				estimators = append(estimators, e)
+			}
+		}
+	} else {
+		for _, name := range strings.Split(*names, ",") {
+			e, err := tokenestimate.NewEstimatorWithName(strings.TrimSpace(name))
+			if err != nil {
+				return err
+			}
+			estimators = append(estimators, e)
+		}
+	}
+	samples, err := dataset.Load(*path)
+	if err != nil {
+		return err
+	}
+	if len(samples) == 0 {
+		return fmt.Errorf("%s has no samples", *path)
+	}
+
+	comparisons := make([]comparison, len(estimators))
+	for i, e := range estimators {
+		comparisons[i] = compare(e, samples, *benchtime)
+	}
+	return writeOutput(*output, stdout, func(w io.Writer) error {
+		switch *format {
+		case "json":
+			return writeCompareJSON(w, *path, comparisons)
+		case "csv":
+			return writeCompareCSV(w, comparisons)
+		default:
+			return writeCompareText(w, *path, len(samples), comparisons)
+		}
+	})
+}
+
+// compare evaluates e on samples, and times passes over the samples until
+// benchtime has passed, at least one.
+func compare(e *tokenestimate.Estimator, samples []tokenestimate.Sample, benchtime time.Duration) comparison {
+	var size int
+	for _, s := range samples {
+		size += len(s.Text)
+	}
+	estimates := make([]int, len(samples))
+	passes := 0
+	start := time.Now()
+	for passes == 0 || time.Since(start) < benchtime {
+		for i, s := range samples {
+			estimates[i] = e.Estimate(s.Text)
+		}
+		passes++
+	}
+	elapsed := time.Since(start)
+
+	c := comparison{
+		preset: e.Key(),
+		report: eval.EvaluateEstimates(samples, estimates, eval.Options{}),
+		perOp:  elapsed / time.Duration(passes*len(samples)),
+	}
+	if elapsed > 0 {
+		c.mbps = float64(size*passes) / 1e6 / elapsed.Seconds()
+	}
+	return c
+}
+
+// writeCompareText writes a table of the comparisons.
+func writeCompareText(w io.Writer, path string, samples int, comparisons []comparison) error {
+	fmt.Fprintf(w, "%d samples of %s\n\n", samples, path)
+	fmt.Fprintf(w, "%-24s %8s %9s %9s %9s %12s %10s\n", "preset", "MAPE", "bias", "p90", "pass", "time/sample", "MB/s")
+	for _, c := range comparisons {
+		r := c.report
+		fmt.Fprintf(w, "%-24s %8.1f%% %+8.1f%% %8.1f%% %8.1f%% %12s %10.1f\n",
+			c.preset, 100*r.MAPE, 100*r.Bias, 100*r.P90, 100*r.PassRate, c.perOp, c.mbps)
+	}
+	return nil
+}
+
+// writeCompareJSON writes the comparisons as a JSON object.
+func writeCompareJSON(w io.Writer, path string, comparisons []comparison) error {
+	type presetJSON struct {
+		Preset string `json:"preset"`
+		metricsJSON
+		NsPerSample int64   `json:"ns_per_sample"`
+		MBPerSecond float64 `json:"mb_per_second"`
+	}
+	out := struct {
+		Dataset string       `json:"dataset"`
+		Presets []presetJSON `json:"presets"`
+	}{Dataset: path, Presets: []presetJSON{}}
+	for _, c := range comparisons {
+		out.Presets = append(out.Presets, presetJSON{c.preset, toMetricsJSON(c.report.Metrics), c.perOp.Nanoseconds(), c.mbps})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// writeCompareCSV writes the metrics of the comparisons as CSV, in the
+// columns of the eval subcommand followed by the speed.
+func writeCompareCSV(w io.Writer, comparisons []comparison) error {
+	cw := csv.NewWriter(w)
+	cw.Write(slices.Concat(csvHeader, []string{"ns_per_sample", "mb_per_second"}))
+	for _, c := range comparisons {
+		speed := []string{strconv.FormatInt(c.perOp.Nanoseconds(), 10), strconv.FormatFloat(c.mbps, 'f', 2, 64)}
+		for _, row := range evalRows(c.preset, c.report) {
+			cw.Write(append(row, speed...))
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
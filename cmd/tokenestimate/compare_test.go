@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunCompare(t *testing.T) {
+	path := writeDataset(t)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"compare", "-dataset", path, "-presets", "kimi-k2, cl100k-base", "-benchtime", "1ms"}
+	if err := run(args, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[3], "kimi-k2@12 ") || !strings.HasPrefix(lines[4], "cl100k-base ") {
+		t.Errorf("Unexpected table:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := run(append(args, "-format", "json"), nil, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var report struct {
+		Presets []struct {
+			Preset      string
+			Samples     int
+			NsPerSample int64 `json:"ns_per_sample"`
+		}
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(report.Presets) != 2 || report.Presets[1].Preset != "cl100k-base" || report.Presets[1].Samples != 3 || report.Presets[0].NsPerSample <= 0 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	stdout.Reset()
+	if err := run(append(args, "-format", "csv"), nil, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rows, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if header := rows[0]; header[len(header)-1] != "mb_per_second" || rows[1][0] != "kimi-k2@12" || rows[len(rows)-1][0] != "cl100k-base" {
+		t.Errorf("Unexpected CSV %q", rows)
+	}
+
+	stdout.Reset()
+	if err := run([]string{"compare", "-dataset", path, "-benchtime", "1ms"}, nil, &stdout, &stderr); err != nil || !strings.Contains(stdout.String(), "o200k-base") {
+		t.Errorf("Expected every preset, got %v:\n%s", err, stdout.String())
+	}
+
+	for _, args := range [][]string{
+		{"compare"},
+		{"compare", "-dataset", path, "-presets", "kimi-k2,nope"},
+		{"compare", "-dataset", path, "-format", "xml"},
+	} {
+		if err := run(args, nil, &stdout, &stderr); err == nil {
+			t.Errorf("run(%q): expected an error", args)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/infinigence/tokenestimate/dataset"
+	"github.com/infinigence/tokenestimate/eval"
+)
+
+// runEval measures a preset on a dataset and writes the report.
+func runEval(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("tokenestimate eval", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: tokenestimate eval -dataset file [-preset name] [-format text|json|csv] [-o file]")
+		fs.PrintDefaults()
+	}
+	path := fs.String("dataset", "", "JSONL, CSV or TSV file of texts labeled with their token counts")
+	preset := fs.String("preset", "kimi-k2", "preset to evaluate")
+	format := fs.String("format", "text", "report format: text, json or csv")
+	output := fs.String("o", "", "file to write the report to (default: standard output)")
+	relative := fs.Float64("relative", eval.DefaultThresholds.Relative, "largest relative error that passes")
+	absolute := fs.Int("absolute", eval.DefaultThresholds.Absolute, "largest error in tokens that passes")
+	failures := fs.Int("failures", 10, "number of failures to list in text and JSON reports")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch {
+	case *path == "":
+		return errors.New("eval: -dataset is required")
+	case fs.NArg() > 0:
+		return fmt.Errorf("eval: unexpected arguments: %q", fs.Args())
+	case *format != "text" && *format != "json" && *format != "csv":
+		return fmt.Errorf("eval: unknown format: %s", *format)
+	}
+	e, err := tokenestimate.NewEstimatorWithName(*preset)
+	if err != nil {
+		return err
+	}
+	samples, err := dataset.Load(*path)
+	if err != nil {
+		return err
+	}
+	if len(samples) == 0 {
+		return fmt.Errorf("%s has no samples", *path)
+	}
+	report := eval.EvaluateWithOptions(e, samples, eval.Options{
+		Thresholds: eval.Thresholds{Relative: *relative, Absolute: *absolute},
+	})
+	if len(report.Failures) > *failures {
+		report.Failures = report.Failures[:max(*failures, 0)]
+	}
+
+	return writeOutput(*output, stdout, func(w io.Writer) error {
+		switch *format {
+		case "json":
+			return writeEvalJSON(w, e.Key(), *path, report)
+		case "csv":
+			return writeEvalCSV(w, e.Key(), report)
+		default:
+			return writeEvalText(w, e.Key(), *path, report)
+		}
+	})
+}
+
+// writeOutput calls write with a buffered writer to the file path, or to
+// stdout if path is empty.
+func writeOutput(path string, stdout io.Writer, write func(io.Writer) error) error {
+	var file *os.File
+	if path != "" {
+		var err error
+		if file, err = os.Create(path); err != nil {
+			return err
+		}
+		defer file.Close()
+		stdout = file
+	}
+	bw := bufio.NewWriter(stdout)
+	if err := write(bw); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if file != nil {
+		return file.Close()
+	}
+	return nil
+}
+
+// writeEvalText writes the report as eval.Report formats it, followed by
+// the failures.
+func writeEvalText(w io.Writer, preset, path string, r eval.Report) error {
+	fmt.Fprintf(w, "%s on %s\n\n%s", preset, path, r)
+	if len(r.Failures) > 0 {
+		fmt.Fprintf(w, "\n%-8s %8s %8s %8s  %s\n", "sample", "tokens", "estimate", "error", "text")
+	}
+	for _, f := range r.Failures {
+		text := []rune(f.Sample.Text)
+		if len(text) > 60 {
+			text = append(text[:60], '…')
+		}
+		fmt.Fprintf(w, "%-8d %8d %8d %+7.1f%%  %q\n", f.Index+1, f.Sample.Tokens, f.Estimate, 100*f.RelError, string(text))
+	}
+	return nil
+}
+
+// metricsJSON is eval.Metrics as the JSON and CSV reports write it.
+type metricsJSON struct {
+	Samples  int     `json:"samples"`
+	MAE      float64 `json:"mae"`
+	MAPE     float64 `json:"mape"`
+	RMSE     float64 `json:"rmse"`
+	Bias     float64 `json:"bias"`
+	P50      float64 `json:"p50"`
+	P90      float64 `json:"p90"`
+	P99      float64 `json:"p99"`
+	Coverage float64 `json:"coverage"`
+	PassRate float64 `json:"pass_rate"`
+}
+
+func toMetricsJSON(m eval.Metrics) metricsJSON {
+	return metricsJSON{
+		Samples: m.Samples, MAE: m.MAE, MAPE: m.MAPE, RMSE: m.RMSE, Bias: m.Bias,
+		P50: m.P50, P90: m.P90, P99: m.P99, Coverage: m.Coverage, PassRate: m.PassRate,
+	}
+}
+
+// writeEvalJSON writes the report as a JSON object.
+func writeEvalJSON(w io.Writer, preset, path string, r eval.Report) error {
+	type failure struct {
+		Sample   int     `json:"sample"` // 1-based position in the dataset
+		Tokens   int     `json:"tokens"`
+		Estimate int     `json:"estimate"`
+		RelError float64 `json:"rel_error"`
+		Text     string  `json:"text"`
+	}
+	out := struct {
+		Preset  string `json:"preset"`
+		Dataset string `json:"dataset"`
+		metricsJSON
+		Thresholds struct {
+			Relative float64 `json:"relative"`
+			Absolute int     `json:"absolute"`
+		} `json:"thresholds"`
+		Failures      []failure              `json:"failures"`
+		ByScript      map[string]metricsJSON `json:"by_script"`
+		ByContentType map[string]metricsJSON `json:"by_content_type"`
+	}{
+		Preset:        preset,
+		Dataset:       path,
+		metricsJSON:   toMetricsJSON(r.Metrics),
+		Failures:      []failure{},
+		ByScript:      make(map[string]metricsJSON),
+		ByContentType: make(map[string]metricsJSON),
+	}
+	out.Thresholds.Relative, out.Thresholds.Absolute = r.Thresholds.Relative, r.Thresholds.Absolute
+	for _, f := range r.Failures {
+		out.Failures = append(out.Failures, failure{f.Index + 1, f.Sample.Tokens, f.Estimate, f.RelError, f.Sample.Text})
+	}
+	for script, m := range r.ByScript {
+		out.ByScript[script] = toMetricsJSON(m)
+	}
+	for ct, m := range r.ByContentType {
+		out.ByContentType[ct.String()] = toMetricsJSON(m)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// csvHeader is the header of the CSV reports.
+var csvHeader = []string{"preset", "group", "key", "samples", "mae", "mape", "rmse", "bias", "p50", "p90", "p99", "coverage", "pass_rate"}
+
+// csvRow returns the CSV row of metrics of a group of samples.
+func csvRow(preset, group, key string, m eval.Metrics) []string {
+	row := []string{preset, group, key, strconv.Itoa(m.Samples)}
+	for _, v := range []float64{m.MAE, m.MAPE, m.RMSE, m.Bias, m.P50, m.P90, m.P99, m.Coverage, m.PassRate} {
+		row = append(row, strconv.FormatFloat(v, 'f', 4, 64))
+	}
+	return row
+}
+
+// evalRows returns the CSV rows of the report: all samples, then each
+// script and each content type.
+func evalRows(preset string, r eval.Report) [][]string {
+	rows := [][]string{csvRow(preset, "all", "", r.Metrics)}
+	for _, script := range slices.Sorted(maps.Keys(r.ByScript)) {
+		rows = append(rows, csvRow(preset, "script", script, r.ByScript[script]))
+	}
+	for _, ct := range slices.Sorted(maps.Keys(r.ByContentType)) {
+		rows = append(rows, csvRow(preset, "content", ct.String(), r.ByContentType[ct]))
+	}
+	return rows
+}
+
+// writeEvalCSV writes the metrics of the report as CSV.
+func writeEvalCSV(w io.Writer, preset string, r eval.Report) error {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	cw.WriteAll(evalRows(preset, r))
+	return cw.Error()
+}
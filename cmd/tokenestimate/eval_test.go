@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// writeDataset writes a dataset whose second sample is labeled far off its
+// estimate, and returns its path.
+func writeDataset(t *testing.T) string {
+	t.Helper()
+	e := tokenestimate.NewEstimator()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, s := range []tokenestimate.Sample{
+		{Text: "Hello, world!", Tokens: e.Estimate("Hello, world!")},
+		{Text: strings.Repeat("The quick brown fox. ", 20), Tokens: 1000},
+		{Text: "你好，世界", Tokens: e.Estimate("你好，世界")},
+	} {
+		enc.Encode(s)
+	}
+	path := filepath.Join(t.TempDir(), "data.jsonl")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunEval(t *testing.T) {
+	path := writeDataset(t)
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"eval", "-dataset", path}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"kimi-k2@12 on " + path, "samples  3", "1 failures", "\n2 ", `"The quick brown fox.`} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected the text report to contain %q:\n%s", want, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if err := run([]string{"eval", "-dataset", path, "-format", "json"}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var report struct {
+		Preset   string
+		Samples  int
+		PassRate float64 `json:"pass_rate"`
+		Failures []struct{ Sample, Tokens int }
+		ByScript map[string]struct{ Samples int } `json:"by_script"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if report.Preset != "kimi-k2@12" || report.Samples != 3 || len(report.Failures) != 1 ||
+		report.Failures[0].Sample != 2 || report.Failures[0].Tokens != 1000 || report.ByScript["Chinese"].Samples != 1 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	out := filepath.Join(t.TempDir(), "report.csv")
+	if err := run([]string{"eval", "-dataset", path, "-format", "csv", "-o", out}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f, _ := os.Open(out)
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(rows) < 4 || strings.Join(rows[0][:4], ",") != "preset,group,key,samples" || strings.Join(rows[1][:4], ",") != "kimi-k2@12,all,,3" {
+		t.Errorf("Unexpected CSV %q", rows)
+	}
+
+	for _, args := range [][]string{
+		{"eval"},
+		{"eval", "-dataset", path, "-format", "xml"},
+		{"eval", "-dataset", path, "-preset", "nope"},
+		{"eval", "-dataset", filepath.Join(t.TempDir(), "missing.jsonl")},
+	} {
+		if err := run(args, nil, &stdout, &stderr); err == nil {
+			t.Errorf("run(%q): expected an error", args)
+		}
+	}
+}
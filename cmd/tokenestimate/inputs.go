@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// input is a file to estimate.
+type input struct {
+	path   string // "-" for the standard input
+	walked bool   // found by walking a directory, and skipped if binary
+}
+
+// expand returns the inputs the arguments name: files, the files matching
+// glob patterns, and with recursive the files under directories. No
+// arguments is the standard input.
+func expand(args []string, recursive bool) ([]input, error) {
+	if len(args) == 0 {
+		return []input{{path: "-"}}, nil
+	}
+	var inputs []input
+	for _, arg := range args {
+		paths := []string{arg}
+		if _, err := os.Stat(arg); err != nil && strings.ContainsAny(arg, "*?[") {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("%s: no files match", arg)
+			}
+			paths = matches
+		}
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			switch {
+			case path == "-":
+				inputs = append(inputs, input{path: path})
+			case err != nil:
+				return nil, err
+			case !info.IsDir():
+				inputs = append(inputs, input{path: path})
+			case !recursive:
+				return nil, fmt.Errorf("%s is a directory (use -r to walk it)", path)
+			default:
+				walked, err := walk(path)
+				if err != nil {
+					return nil, err
+				}
+				inputs = append(inputs, walked...)
+			}
+		}
+	}
+	return inputs, nil
+}
+
+// walk returns the regular files under dir in lexical order, skipping
+// hidden directories such as .git.
+func walk(dir string) ([]input, error) {
+	var inputs []input
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case d.IsDir() && path != dir && strings.HasPrefix(d.Name(), "."):
+			return filepath.SkipDir
+		case d.Type().IsRegular():
+			inputs = append(inputs, input{path: path, walked: true})
+		}
+		return nil
+	})
+	return inputs, err
+}
@@ -0,0 +1,236 @@
+// Command tokenestimate prints the estimated token counts of files, or of
+// its standard input, for scripts, shell pipelines and corpus audits.
+//
+// Usage:
+//
+//	tokenestimate [-preset kimi-k2] [-stats] [-json | -jsonl] [-r] [file | pattern | dir...]
+//
+// With one input it prints its estimate; with several, the estimate and
+// name of each, separated by a tab, and their total, like wc. -stats adds
+// the character breakdown of each input. A file named "-" is the standard
+// input.
+//
+// Arguments may be glob patterns such as "docs/*.md", for shells that do
+// not expand them. With -r, directories are walked recursively, skipping
+// hidden directories and binary files, so that
+//
+//	tokenestimate -r -json .
+//
+// reports the tokens of every text file of a repository and their total.
+// -json writes one JSON object with the files and the total, and -jsonl
+// one line per file as it is estimated, followed by the total.
+//
+// The eval subcommand measures a preset on a dataset of texts labeled with
+// their token counts, in the formats the dataset package reads, and
+// writes the error report as text, JSON or CSV:
+//
+//	tokenestimate eval -dataset data.jsonl [-preset kimi-k2] [-format text|json|csv] [-o report.csv]
+//
+// The compare subcommand measures several presets on a dataset, writing a
+// table of their accuracy and speed side by side, to pick the preset that
+// fits the dataset's traffic best:
+//
+//	tokenestimate compare -dataset data.jsonl [-presets kimi-k2,cl100k-base] [-format text|json|csv]
+//
+// The serve subcommand serves estimates over HTTP, with the endpoints of
+// the server package, including a /tokenize endpoint shaped like those of
+// inference servers:
+//
+//	tokenestimate serve [-addr localhost:8080] [-preset kimi-k2]
+//
+// Use ./eval, ./compare or ./serve for a file of that name.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "tokenestimate:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// fileResult is the estimate of an input, as written by -json and -jsonl.
+type fileResult struct {
+	Path   string         `json:"path"`
+	Bytes  int64          `json:"bytes"`
+	Tokens int            `json:"tokens"`
+	Stats  map[string]int `json:"stats,omitempty"` // nonzero Stats fields, with -stats
+}
+
+// summary is the total of the inputs.
+type summary struct {
+	Preset string `json:"preset"`
+	Files  int    `json:"files"`
+	Bytes  int64  `json:"bytes"`
+	Tokens int    `json:"tokens"`
+}
+
+// run runs the command with the arguments args.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "eval":
+			return runEval(args[1:], stdout, stderr)
+		case "compare":
+			return runCompare(args[1:], stdout, stderr)
+		case "serve":
+			return runServe(args[1:], stderr)
+		}
+	}
+	return runCount(args, stdin, stdout, stderr)
+}
+
+// runCount estimates the inputs args name.
+func runCount(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("tokenestimate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: tokenestimate [-preset name] [-stats] [-json | -jsonl] [-r] [file | pattern | dir...]")
+		fs.PrintDefaults()
+	}
+	preset := fs.String("preset", "kimi-k2", "preset estimating the tokens")
+	stats := fs.Bool("stats", false, "print the character breakdown of each input")
+	jsonOut := fs.Bool("json", false, "write a JSON object with the files and the total")
+	jsonl := fs.Bool("jsonl", false, "write a JSON line per file, then one with the total")
+	recursive := fs.Bool("r", false, "walk directories recursively")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *jsonOut && *jsonl {
+		return errors.New("-json and -jsonl are exclusive")
+	}
+	e, err := tokenestimate.NewEstimatorWithName(*preset)
+	if err != nil {
+		return err
+	}
+	inputs, err := expand(fs.Args(), *recursive)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	enc := json.NewEncoder(w)
+	total := summary{Preset: e.Key()}
+	var results []fileResult
+	for _, in := range inputs {
+		res, s, err := estimate(e, in, stdin)
+		if errors.Is(err, errBinary) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if *stats {
+			res.Stats = statsMap(s)
+		}
+		total.Files++
+		total.Bytes += res.Bytes
+		total.Tokens += res.Tokens
+
+		switch {
+		case *jsonOut:
+			results = append(results, res)
+		case *jsonl:
+			if err := enc.Encode(res); err != nil {
+				return err
+			}
+		case len(inputs) == 1:
+			fmt.Fprintln(w, res.Tokens)
+		default:
+			fmt.Fprintf(w, "%d\t%s\n", res.Tokens, res.Path)
+		}
+		if *stats && !*jsonOut && !*jsonl {
+			writeStats(w, s)
+		}
+	}
+
+	switch {
+	case *jsonOut:
+		return enc.Encode(struct {
+			Files []fileResult `json:"files"`
+			Total summary      `json:"total"`
+		}{append([]fileResult{}, results...), total})
+	case *jsonl:
+		return enc.Encode(struct {
+			Total summary `json:"total"`
+		}{total})
+	case len(inputs) > 1:
+		fmt.Fprintf(w, "%d\ttotal\n", total.Tokens)
+	}
+	return nil
+}
+
+// errBinary reports a walked file that is not text.
+var errBinary = errors.New("binary file")
+
+// binaryPeek is the length of the prefix of a walked file searched for a
+// NUL byte, which marks binary files as it does for git.
+const binaryPeek = 8000
+
+// estimate estimates the input in a single streaming pass. It returns
+// errBinary for a walked binary file.
+func estimate(e *tokenestimate.Estimator, in input, stdin io.Reader) (fileResult, tokenestimate.Stats, error) {
+	res := fileResult{Path: in.path}
+	r := stdin
+	if in.path != "-" {
+		f, err := os.Open(in.path)
+		if err != nil {
+			return res, tokenestimate.Stats{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+	br := bufio.NewReader(r)
+	if in.walked {
+		prefix, _ := br.Peek(binaryPeek)
+		for _, b := range prefix {
+			if b == 0 {
+				return res, tokenestimate.Stats{}, errBinary
+			}
+		}
+	}
+	se := tokenestimate.NewStreamEstimator(e)
+	n, err := io.Copy(se, br)
+	if err != nil {
+		return res, tokenestimate.Stats{}, fmt.Errorf("%s: %w", in.path, err)
+	}
+	res.Bytes, res.Tokens = n, se.Tokens()
+	return res, se.Stats(), nil
+}
+
+// statsMap returns the nonzero fields of s by name.
+func statsMap(s tokenestimate.Stats) map[string]int {
+	m := make(map[string]int)
+	v := reflect.ValueOf(s)
+	for i := range v.NumField() {
+		if n := int(v.Field(i).Int()); n != 0 {
+			m[v.Type().Field(i).Name] = n
+		}
+	}
+	return m
+}
+
+// writeStats writes the nonzero fields of s, indented.
+func writeStats(w io.Writer, s tokenestimate.Stats) {
+	v := reflect.ValueOf(s)
+	for i := range v.NumField() {
+		if n := v.Field(i).Int(); n != 0 {
+			fmt.Fprintf(w, "  %-22s %d\n", v.Type().Field(i).Name, n)
+		}
+	}
+}
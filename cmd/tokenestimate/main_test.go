@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")
+	os.WriteFile(a, []byte("Hello, world!"), 0o644)
+	os.WriteFile(b, []byte("你好，世界"), 0o644)
+	e := tokenestimate.NewEstimator()
+	na, nb := e.Estimate("Hello, world!"), e.Estimate("你好，世界")
+
+	tests := []struct {
+		args  []string
+		stdin string
+		want  string
+	}{
+		{nil, "Hello, world!", strconv.Itoa(na) + "\n"},
+		{[]string{a}, "", strconv.Itoa(na) + "\n"},
+		{[]string{a, "-"}, "你好，世界", strings.Join([]string{
+			strconv.Itoa(na) + "\t" + a,
+			strconv.Itoa(nb) + "\t-",
+			strconv.Itoa(na+nb) + "\ttotal",
+		}, "\n") + "\n"},
+		{[]string{"-stats", b}, "", strconv.Itoa(nb) + "\n" +
+			"  Symbols                1\n  ChineseChars           4\n  Words                  1\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if err := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr); err != nil {
+			t.Errorf("run(%q): %v", tt.args, err)
+			continue
+		}
+		if got := stdout.String(); got != tt.want {
+			t.Errorf("run(%q): expected\n%s\ngot\n%s", tt.args, tt.want, got)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-preset", "nope"}, nil, &stdout, &stderr); err == nil || err.Error() != "unknown preset: nope" {
+		t.Errorf("Expected an unknown preset, got %v", err)
+	}
+	if err := run([]string{filepath.Join(dir, "missing")}, nil, &stdout, &stderr); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range map[string]string{
+		"a.md":         "# Title",
+		"sub/b.txt":    "Hello, world!",
+		"sub/c.bin":    "\x00\x01binary",
+		".git/config":  "[core]",
+		"sub/.hidden":  "hidden files are walked",
+		"sub/d/e.json": `{"a": 1}`,
+	} {
+		path = filepath.Join(dir, path)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte(content), 0o644)
+	}
+	e := tokenestimate.NewEstimator()
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-r", "-json", dir}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var got struct {
+		Files []fileResult
+		Total summary
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", stdout.String(), err)
+	}
+	var paths []string
+	tokens := 0
+	for _, f := range got.Files {
+		paths = append(paths, strings.TrimPrefix(filepath.ToSlash(f.Path), filepath.ToSlash(dir)+"/"))
+		tokens += f.Tokens
+	}
+	if want := "a.md sub/.hidden sub/b.txt sub/d/e.json"; strings.Join(paths, " ") != want {
+		t.Errorf("Expected the files %s, got %v", want, paths)
+	}
+	if got.Total.Files != 4 || got.Total.Tokens != tokens || got.Total.Preset != "kimi-k2@12" || got.Files[0].Bytes != 7 {
+		t.Errorf("Unexpected total %+v", got.Total)
+	}
+	if got.Files[0].Tokens != e.Estimate("# Title") {
+		t.Errorf("Expected %d tokens for a.md, got %d", e.Estimate("# Title"), got.Files[0].Tokens)
+	}
+
+	stdout.Reset()
+	if err := run([]string{"-jsonl", "-stats", filepath.Join(dir, "sub", "*.txt")}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"stats":{"LatinLetters":10,`) || !strings.HasPrefix(lines[1], `{"total":{`) {
+		t.Errorf("Unexpected JSON lines %q", lines)
+	}
+
+	for _, args := range [][]string{{dir}, {filepath.Join(dir, "*.nope")}, {"-json", "-jsonl", dir}} {
+		if err := run(args, nil, &stdout, &stderr); err == nil {
+			t.Errorf("run(%q): expected an error", args)
+		}
+	}
+}
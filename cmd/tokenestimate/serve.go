@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/infinigence/tokenestimate/server"
+)
+
+// runServe serves estimates over HTTP until the server fails.
+func runServe(args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("tokenestimate serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: tokenestimate serve [-addr host:port] [-preset name] [-max-body bytes]")
+		fs.PrintDefaults()
+	}
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	preset := fs.String("preset", "kimi-k2", "preset estimating requests for no preset or an unknown model")
+	maxBody := fs.Int64("max-body", 32<<20, "largest request body in bytes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("serve: unexpected arguments: %q", fs.Args())
+	}
+	h, err := server.New(server.Options{Preset: *preset, MaxBodyBytes: *maxBody})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "tokenestimate: serving on %s\n", *addr)
+	srv := &http.Server{Addr: *addr, Handler: h, ReadHeaderTimeout: 10 * time.Second}
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRunServe(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"serve", "-preset", "nope"}, nil, &stdout, &stderr); err == nil || err.Error() != "unknown preset: nope" {
+		t.Errorf("Expected an unknown preset, got %v", err)
+	}
+	if err := run([]string{"serve", "extra"}, nil, &stdout, &stderr); err == nil {
+		t.Error("Expected an error for unexpected arguments")
+	}
+	if err := run([]string{"serve", "-addr", "localhost:-1"}, nil, &stdout, &stderr); err == nil {
+		t.Error("Expected an error for an invalid address")
+	}
+}
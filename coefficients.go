@@ -0,0 +1,94 @@
+package tokenestimate
+
+import "fmt"
+
+// WithCoefficients returns a clone of the estimator with the intercept and
+// the coefficients of the named features of its linear model replaced, for
+// example by coefficients trained for another tokenizer with the fit
+// package. Features not in coefs keep their coefficients. It returns an
+// error if a name is not a Stats field or is a field without a
+// coefficient of its own, such as DictionaryTokens.
+//
+// The residual quantiles of EstimateRange, the Accuracy and the training
+// Metadata describe the original model, so the clone has none of them
+// except the supported scripts, and it is no longer linked to the
+// content-type variants of a preset.
+func (e *Estimator) WithCoefficients(intercept float64, coefs map[string]float64) (*Estimator, error) {
+	clone := e.Clone()
+	for name, coef := range coefs {
+		p, err := clone.coefficientOf(name)
+		if err != nil {
+			return nil, err
+		}
+		*p = coef
+	}
+	clone.intercept = intercept
+	clone.detach()
+	return clone, nil
+}
+
+// Coefficients returns the coefficients of the linear model by the name of
+// the Stats feature they multiply, for every feature with a coefficient of
+// its own. Unknown and InvalidBytes are the coefficients of the policies
+// that price them separately. The map is a copy; change coefficients with
+// SetCoefficient or WithCoefficients.
+func (e *Estimator) Coefficients() map[string]float64 {
+	coefs := make(map[string]float64, len(features))
+	for _, f := range features {
+		if p := e.coefficientField(f.name); p != nil {
+			coefs[f.name] = *p
+		}
+	}
+	return coefs
+}
+
+// Intercept returns the intercept of the linear model, the tokens added to
+// every estimate before rounding.
+func (e *Estimator) Intercept() float64 {
+	return e.intercept
+}
+
+// SetCoefficient sets the coefficient of the named Stats feature, with the
+// names and errors of WithCoefficients. Unlike WithCoefficients it
+// modifies e, so call it on a Clone rather than on a registered preset.
+// Like WithCoefficients, it drops the residual quantiles, the Accuracy,
+// the training Metadata and the link to the content-type variants.
+func (e *Estimator) SetCoefficient(name string, v float64) error {
+	p, err := e.coefficientOf(name)
+	if err != nil {
+		return err
+	}
+	*p = v
+	e.detach()
+	return nil
+}
+
+// SetIntercept sets the intercept of the linear model. Like SetCoefficient
+// it modifies e.
+func (e *Estimator) SetIntercept(v float64) {
+	e.intercept = v
+	e.detach()
+}
+
+// coefficientOf returns a pointer to the coefficient of the named feature,
+// or an error if it has none.
+func (e *Estimator) coefficientOf(name string) (*float64, error) {
+	if _, ok := featuresByName[name]; !ok {
+		return nil, fmt.Errorf("unknown feature: %s", name)
+	}
+	p := e.coefficientField(name)
+	if p == nil {
+		return nil, fmt.Errorf("feature %s has no coefficient", name)
+	}
+	return p, nil
+}
+
+// detach drops what describes the model before its coefficients changed.
+// The supported scripts are kept: they depend on the features priced,
+// not on the values of the coefficients.
+func (e *Estimator) detach() {
+	e.residualP10, e.residualP90 = 0, 0
+	e.Accuracy = nil
+	e.Metadata = Metadata{Scripts: e.Metadata.Scripts}
+	e.variants = nil
+}
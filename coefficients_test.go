@@ -0,0 +1,76 @@
+package tokenestimate
+
+import "testing"
+
+func TestWithCoefficients(t *testing.T) {
+	estimator, err := NewEstimator().WithCoefficients(1, map[string]float64{"LatinLetters": 0.5, "Words": 0})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if estimator.coefLatinLetters != 0.5 || estimator.coefWords != 0 || estimator.intercept != 1 {
+		t.Errorf("Coefficients not set: %v, %v, %v", estimator.coefLatinLetters, estimator.coefWords, estimator.intercept)
+	}
+	if estimator.coefSymbols != NewEstimator().coefSymbols {
+		t.Errorf("Expected other coefficients to be kept")
+	}
+	if low, high := estimator.EstimateRange("hello world"); low != high {
+		t.Errorf("Expected no range without residual quantiles, got %d..%d", low, high)
+	}
+	if NewEstimator().coefLatinLetters == 0.5 {
+		t.Error("Expected the preset to be unchanged")
+	}
+
+	code := estimator.WithContentType(ContentCode)
+	if code.coefLatinLetters != 0.5 {
+		t.Errorf("Expected the fitted model for code, got coefficient %v", code.coefLatinLetters)
+	}
+
+	for _, name := range []string{"Letters", "DictionaryTokens"} {
+		if _, err := NewEstimator().WithCoefficients(0, map[string]float64{name: 1}); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
+
+func TestCoefficientAccessors(t *testing.T) {
+	coefs := NewEstimator().Coefficients()
+	if coefs["LatinLetters"] != NewEstimator().coefLatinLetters || coefs["Tabs"] != NewEstimator().coefTabs {
+		t.Errorf("Unexpected coefficients %v", coefs)
+	}
+	if _, ok := coefs["DictionaryTokens"]; ok {
+		t.Error("Expected no coefficient for DictionaryTokens")
+	}
+	rebuilt, err := NewEstimator().WithCoefficients(NewEstimator().Intercept(), coefs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, text := range []string{"Hello, world!", "你好，世界", "func main() {\n\treturn\n}"} {
+		if got, want := rebuilt.Estimate(text), NewEstimator().Estimate(text); got != want {
+			t.Errorf("Estimate(%q): expected %d from the coefficients, got %d", text, want, got)
+		}
+	}
+	coefs["LatinLetters"] = 100
+	if NewEstimator().coefLatinLetters == 100 {
+		t.Error("Expected Coefficients to return a copy")
+	}
+
+	e := NewEstimator().Clone()
+	if err := e.SetCoefficient("LatinLetters", 0.5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	e.SetIntercept(2)
+	if e.Coefficients()["LatinLetters"] != 0.5 || e.Intercept() != 2 {
+		t.Errorf("Coefficients not set: %v, %v", e.coefLatinLetters, e.intercept)
+	}
+	if low, high := e.EstimateRange("hello world"); low != high {
+		t.Errorf("Expected no range after changing coefficients, got %d..%d", low, high)
+	}
+	if code := e.WithContentType(ContentCode); code.coefLatinLetters != 0.5 {
+		t.Errorf("Expected the changed model for code, got coefficient %v", code.coefLatinLetters)
+	}
+	for _, name := range []string{"Letters", "DictionaryTokens"} {
+		if err := e.SetCoefficient(name, 1); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
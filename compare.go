@@ -0,0 +1,78 @@
+package tokenestimate
+
+import (
+	"math"
+	"sort"
+)
+
+// CompareEstimates returns the estimates of text by the named presets,
+// keyed by the names given, so that operators can see how much the choice
+// of model matters for their texts. Names that are not registered are left
+// out. With no names, it compares the latest version of every preset, by
+// bare name. Summarize the result with SpreadOf.
+func CompareEstimates(text string, presets ...string) map[string]int {
+	if len(presets) == 0 {
+		for _, info := range ListPresetsDetailed() {
+			if info.Latest {
+				presets = append(presets, info.Preset)
+			}
+		}
+	}
+	estimates := make(map[string]int, len(presets))
+	for _, name := range presets {
+		e, err := GetPresetByName(name)
+		if err != nil {
+			continue
+		}
+		estimates[name] = e.Estimate(text)
+	}
+	return estimates
+}
+
+// Spread summarizes how far the estimates of several presets diverge.
+type Spread struct {
+	Min, Max             int
+	MinPreset, MaxPreset string  // presets with the lowest and highest estimate
+	Mean                 float64 // mean of the estimates
+	StdDev               float64 // population standard deviation of the estimates
+
+	// Range is Max-Min relative to Mean, the fraction by which choosing
+	// the wrong preset can miss; 0 if Mean is 0.
+	Range float64
+}
+
+// SpreadOf summarizes estimates as returned by CompareEstimates. Ties for
+// the lowest or highest estimate go to the first preset in name order. It
+// returns the zero Spread for no estimates.
+func SpreadOf(estimates map[string]int) Spread {
+	names := make([]string, 0, len(estimates))
+	for name := range estimates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var s Spread
+	for i, name := range names {
+		n := estimates[name]
+		if i == 0 || n < s.Min {
+			s.Min, s.MinPreset = n, name
+		}
+		if i == 0 || n > s.Max {
+			s.Max, s.MaxPreset = n, name
+		}
+		s.Mean += float64(n)
+	}
+	if len(names) == 0 {
+		return s
+	}
+	s.Mean /= float64(len(names))
+	for _, name := range names {
+		d := float64(estimates[name]) - s.Mean
+		s.StdDev += d * d
+	}
+	s.StdDev = math.Sqrt(s.StdDev / float64(len(names)))
+	if s.Mean > 0 {
+		s.Range = float64(s.Max-s.Min) / s.Mean
+	}
+	return s
+}
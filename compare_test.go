@@ -0,0 +1,40 @@
+package tokenestimate
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompareEstimates(t *testing.T) {
+	text := "Hello, world! 你好，世界"
+	got := CompareEstimates(text, "kimi-k2", "cl100k-base", "nope")
+	cl100k, _ := GetPresetByName("cl100k-base")
+	want := map[string]int{"kimi-k2": KimiK2Estimator.Estimate(text), "cl100k-base": cl100k.Estimate(text)}
+	if len(got) != len(want) || got["kimi-k2"] != want["kimi-k2"] || got["cl100k-base"] != want["cl100k-base"] {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	all := CompareEstimates(text)
+	if _, ok := all["kimi-k2"]; !ok {
+		t.Errorf("Expected every latest preset, got %v", all)
+	}
+	if _, ok := all["kimi-k2@1"]; ok {
+		t.Errorf("Expected no pinned versions, got %v", all)
+	}
+}
+
+func TestSpreadOf(t *testing.T) {
+	s := SpreadOf(map[string]int{"a": 8, "b": 12, "c": 10, "d": 12})
+	if s.Min != 8 || s.MinPreset != "a" || s.Max != 12 || s.MaxPreset != "b" || s.Mean != 10.5 {
+		t.Errorf("Unexpected spread %+v", s)
+	}
+	if math.Abs(s.StdDev-math.Sqrt(2.75)) > 1e-9 || math.Abs(s.Range-4/10.5) > 1e-9 {
+		t.Errorf("Unexpected deviation %+v", s)
+	}
+	if s := SpreadOf(nil); s != (Spread{}) {
+		t.Errorf("Expected the zero spread, got %+v", s)
+	}
+	if s := SpreadOf(map[string]int{"a": 0}); s.Range != 0 {
+		t.Errorf("Expected no range for zero estimates, got %+v", s)
+	}
+}
@@ -0,0 +1,79 @@
+package tokenestimate
+
+// reliability describes how well the presets model one character category.
+type reliability struct {
+	feature feature
+	weight  float64 // 1 for categories fitted on plenty of data, near 0 for guesses
+	script  bool    // whether the category is a writing system for mixing purposes
+}
+
+// reliabilities lists every character category with its reliability. The
+// scripts added after the original fit are priced like symbols and have
+// not been measured, and unknown characters and invalid bytes can cost
+// anywhere from one to several tokens each.
+var reliabilities = []reliability{
+	{featuresByName["Symbols"], 1.0, false},
+	{featuresByName["LatinLetters"], 1.0, true},
+	{featuresByName["DictionaryChars"], 1.0, false},
+	{featuresByName["Digits"], 0.95, false},
+	{featuresByName["LatinExtended"], 0.7, false},
+	{featuresByName["ChineseChars"], 0.9, true},
+	{featuresByName["JapaneseKana"], 0.9, true},
+	{featuresByName["KoreanHangul"], 0.85, true},
+	{featuresByName["RussianChars"], 0.85, true},
+	{featuresByName["ArabicChars"], 0.85, true},
+	{featuresByName["BlobChars"], 0.8, false},
+	{featuresByName["KhmerChars"], 0.4, true},
+	{featuresByName["LaoChars"], 0.4, true},
+	{featuresByName["MyanmarChars"], 0.4, true},
+	{featuresByName["EthiopicChars"], 0.4, true},
+	{featuresByName["Unknown"], 0.1, false},
+	{featuresByName["InvalidBytes"], 0.2, false},
+}
+
+// mixedScriptShare is the share of non-space characters a script needs
+// before it counts towards the mixed-script penalty.
+const mixedScriptShare = 0.1
+
+// mixedScriptPenalty scales the confidence for every significant script
+// beyond the first, since tokens rarely span a script change and the
+// linear model does not see where those changes fall.
+const mixedScriptPenalty = 0.9
+
+// EstimateWithConfidence returns the estimate for text together with a
+// confidence between 0 and 1. The confidence is the average reliability
+// of the text's characters, so it is close to 1 for English or Chinese
+// prose and drops towards 0 as categories the presets barely model, such
+// as unassigned code points, invalid UTF-8 or scripts priced by fallback,
+// dominate the text. Texts mixing several scripts score lower still.
+// Callers can run an exact tokenizer when the confidence is below a
+// threshold of their choosing.
+func (e *Estimator) EstimateWithConfidence(text string) (tokens int, confidence float64) {
+	stats := e.Analyze(text)
+	return e.EstimateFromStats(stats), stats.confidence()
+}
+
+// confidence computes the confidence of an estimate made from s.
+func (s Stats) confidence() float64 {
+	var total, weighted float64
+	for _, r := range reliabilities {
+		n := float64(*r.feature.field(&s))
+		total += n
+		weighted += n * r.weight
+	}
+	if total == 0 {
+		return 1
+	}
+
+	confidence := weighted / total
+	scripts := 0
+	for _, r := range reliabilities {
+		if r.script && float64(*r.feature.field(&s)) >= mixedScriptShare*total {
+			scripts++
+		}
+	}
+	for ; scripts > 1; scripts-- {
+		confidence *= mixedScriptPenalty
+	}
+	return confidence
+}
@@ -0,0 +1,58 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateWithConfidence(t *testing.T) {
+	estimator := NewEstimator()
+
+	t.Run("Matches Estimate", func(t *testing.T) {
+		for _, text := range referenceTexts {
+			tokens, confidence := estimator.EstimateWithConfidence(text)
+			if want := estimator.Estimate(text); tokens != want {
+				t.Errorf("EstimateWithConfidence(%q) = %d tokens, Estimate = %d", text, tokens, want)
+			}
+			if confidence < 0 || confidence > 1 {
+				t.Errorf("EstimateWithConfidence(%q) confidence %v out of range", text, confidence)
+			}
+		}
+	})
+
+	t.Run("Empty text", func(t *testing.T) {
+		if _, confidence := estimator.EstimateWithConfidence(""); confidence != 1 {
+			t.Errorf("Expected confidence 1, got %v", confidence)
+		}
+	})
+
+	tests := []struct {
+		name string
+		text string
+		min  float64
+		max  float64
+	}{
+		{"English", "The quick brown fox jumps over the lazy dog.", 0.95, 1},
+		{"Chinese", "你好，世界！这是一个用于估算词元数量的测试句子。", 0.85, 1},
+		{"Mixed scripts", "Hello world Привет мир 你好世界", 0.6, 0.85},
+		{"Khmer", "ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា", 0, 0.5},
+		{"Unknown dominates", strings.Repeat("\uE000", 20) + "ok", 0, 0.3},
+		{"Invalid UTF-8", strings.Repeat("\xff", 30), 0, 0.3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, confidence := estimator.EstimateWithConfidence(tt.text)
+			if confidence < tt.min || confidence > tt.max {
+				t.Errorf("Expected confidence in [%v, %v], got %v", tt.min, tt.max, confidence)
+			}
+		})
+	}
+
+	t.Run("Unsupported share lowers confidence", func(t *testing.T) {
+		_, clean := estimator.EstimateWithConfidence("plain ascii text here")
+		_, dirty := estimator.EstimateWithConfidence("plain ascii text here \xff\xfe\xfd")
+		if dirty >= clean {
+			t.Errorf("Expected invalid bytes to lower confidence: %v >= %v", dirty, clean)
+		}
+	})
+}
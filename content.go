@@ -0,0 +1,77 @@
+package tokenestimate
+
+// ContentType describes the kind of text an Estimator is tuned for.
+// Presets may provide separate coefficients per content type, because
+// source code, for example, tokenizes very differently from prose.
+type ContentType int
+
+const (
+	// ContentText is natural-language prose (the default).
+	ContentText ContentType = iota
+	// ContentCode is program source code.
+	ContentCode
+	// ContentMarkdown is markdown-formatted text such as READMEs.
+	ContentMarkdown
+	// ContentJSON is JSON data such as tool-call arguments and API payloads.
+	ContentJSON
+)
+
+// String returns the name of the content type.
+func (ct ContentType) String() string {
+	switch ct {
+	case ContentText:
+		return "text"
+	case ContentCode:
+		return "code"
+	case ContentMarkdown:
+		return "markdown"
+	case ContentJSON:
+		return "json"
+	default:
+		return "unknown"
+	}
+}
+
+// WithContentType returns a clone of the estimator using the preset's
+// coefficients for the given content type, keeping the estimator's sampling
+// and character-handling configuration. If the preset has no model for ct,
+// the clone keeps the estimator's own coefficients.
+func (e *Estimator) WithContentType(ct ContentType) *Estimator {
+	model, ok := e.variants[ct]
+	if !ok {
+		model = e
+	}
+
+	clone := model.Clone()
+	clone.ContentType = ct
+	clone.UnknownPolicy = e.UnknownPolicy
+	clone.UTF8Mode = e.UTF8Mode
+	clone.HTMLHandling = e.HTMLHandling
+	clone.UseDictionary = e.UseDictionary
+	clone.Concurrency = e.Concurrency
+	clone.ItemOverhead = e.ItemOverhead
+	clone.ChatTemplate = e.ChatTemplate
+	clone.EnableSampling = e.EnableSampling
+	clone.SamplingThreshold = e.SamplingThreshold
+	clone.SamplingSize = e.SamplingSize
+	clone.SamplingBlockSize = e.SamplingBlockSize
+	clone.SamplingStrategy = e.SamplingStrategy
+	clone.SamplingSeed = e.SamplingSeed
+	clone.SamplingTargetError = e.SamplingTargetError
+	clone.MaxInputBytes = e.MaxInputBytes
+	clone.InputLimit = e.InputLimit
+	clone.Rounding = e.Rounding
+	clone.SafetyMargin = e.SafetyMargin
+	clone.SafetyMinTokens = e.SafetyMinTokens
+	clone.Anomalies = e.Anomalies
+	return clone
+}
+
+// linkContentVariants makes each estimator in variants aware of the others,
+// so WithContentType can switch between them.
+func linkContentVariants(variants map[ContentType]*Estimator) {
+	for ct, estimator := range variants {
+		estimator.ContentType = ct
+		estimator.variants = variants
+	}
+}
@@ -0,0 +1,67 @@
+package tokenestimate
+
+import "testing"
+
+func TestContentType(t *testing.T) {
+	code := "func main() {\n\tfor i := 0; i < 10; i++ {\n\t\tfmt.Println(i)\n\t}\n}\n"
+
+	t.Run("Default content type is text", func(t *testing.T) {
+		if ct := NewEstimator().ContentType; ct != ContentText {
+			t.Errorf("Expected ContentText, got %v", ct)
+		}
+	})
+
+	t.Run("Code variant matches code preset", func(t *testing.T) {
+		estimator := NewEstimator().WithContentType(ContentCode)
+		if estimator.ContentType != ContentCode {
+			t.Errorf("Expected ContentCode, got %v", estimator.ContentType)
+		}
+		if got, want := estimator.Estimate(code), KimiK2CodeEstimator.Estimate(code); got != want {
+			t.Errorf("Expected %d tokens like kimi-k2-code, got %d", want, got)
+		}
+		if estimator.Name != "kimi-k2-code" {
+			t.Errorf("Expected name kimi-k2-code, got %q", estimator.Name)
+		}
+	})
+
+	t.Run("Switching back to text", func(t *testing.T) {
+		estimator := KimiK2CodeEstimator.WithContentType(ContentText)
+		if got, want := estimator.Estimate(code), KimiK2Estimator.Estimate(code); got != want {
+			t.Errorf("Expected %d tokens like kimi-k2, got %d", want, got)
+		}
+	})
+
+	t.Run("Configuration is kept", func(t *testing.T) {
+		estimator := NewEstimator().WithSampling(1000, 100).WithContentType(ContentCode)
+		if !estimator.EnableSampling || estimator.SamplingThreshold != 1000 || estimator.SamplingSize != 100 {
+			t.Errorf("Expected sampling configuration to be kept, got %+v", estimator)
+		}
+	})
+
+	t.Run("Preset without variants keeps its coefficients", func(t *testing.T) {
+		custom := &Estimator{Name: "no-variants", coefLatinLetters: 1}
+		estimator := custom.WithContentType(ContentCode)
+		if got := estimator.Estimate("abc"); got != 3 {
+			t.Errorf("Expected 3 tokens, got %d", got)
+		}
+	})
+
+	t.Run("Variants derive from the latest version", func(t *testing.T) {
+		for _, variant := range []*Estimator{KimiK2CodeEstimator, KimiK2MarkdownEstimator, KimiK2JSONEstimator} {
+			if variant.base != KimiK2Estimator.Key() {
+				t.Errorf("%s is derived from %q, want %s", variant.Key(), variant.base, KimiK2Estimator.Key())
+			}
+		}
+		for ct, bound := range KimiK2P90Estimator.variants {
+			if want := KimiK2Estimator.WithContentType(ct).Key(); bound.base != want {
+				t.Errorf("%s is derived from %q, want %s", bound.Key(), bound.base, want)
+			}
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		if ContentCode.String() != "code" || ContentText.String() != "text" {
+			t.Errorf("Unexpected names %q, %q", ContentText, ContentCode)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+// Package corpus bundles a multilingual corpus of texts for fitting presets
+// to exact tokenizers: prose in two dozen languages, from one-word replies
+// to articles of several paragraphs, along with source code, JSON and
+// markdown. Label the texts with a tokenizer's counts and fit a preset to
+// them:
+//
+//	samples, err := fit.Label(counter, corpus.Texts())
+//	estimator, report, err := fit.Fit(samples, fit.FitOptions{Method: fit.NNLS})
+//
+// The corpus is small, about 34,000 characters, so that fitting a preset
+// takes seconds. It covers the kinds of text presets are tuned for rather
+// than any one domain; fit presets to your own traffic when you can.
+package corpus
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+//go:embed corpus.jsonl
+var data []byte
+
+// Passage is a text of the corpus.
+type Passage struct {
+	// Language is the BCP 47 tag of the text's language, such as "en" or
+	// "zh", or several tags joined by "+" for text mixing languages.
+	Language string
+	// Content is the kind of text.
+	Content tokenestimate.ContentType
+	// Text is the text itself.
+	Text string
+}
+
+// passages parses the embedded corpus once.
+var passages = sync.OnceValue(func() []Passage {
+	var out []Passage
+	for i, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		var record struct {
+			Language string `json:"language"`
+			Content  string `json:"content"`
+			Text     string `json:"text"`
+		}
+		if err := json.Unmarshal(line, &record); err != nil {
+			panic(fmt.Sprintf("corpus: line %d: %v", i+1, err))
+		}
+		p := Passage{Language: record.Language, Text: record.Text}
+		switch record.Content {
+		case "text":
+			p.Content = tokenestimate.ContentText
+		case "code":
+			p.Content = tokenestimate.ContentCode
+		case "markdown":
+			p.Content = tokenestimate.ContentMarkdown
+		case "json":
+			p.Content = tokenestimate.ContentJSON
+		default:
+			panic(fmt.Sprintf("corpus: line %d: unknown content type: %s", i+1, record.Content))
+		}
+		out = append(out, p)
+	}
+	return out
+})
+
+// Passages returns the passages of the corpus.
+func Passages() []Passage {
+	return slices.Clone(passages())
+}
+
+// Texts returns the texts of the corpus.
+func Texts() []string {
+	p := passages()
+	texts := make([]string, len(p))
+	for i := range p {
+		texts[i] = p[i].Text
+	}
+	return texts
+}
@@ -0,0 +1,42 @@
+package corpus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+func TestPassages(t *testing.T) {
+	passages := Passages()
+	if len(passages) < 100 {
+		t.Fatalf("Expected at least 100 passages, got %d", len(passages))
+	}
+	scripts := map[string]bool{}
+	contents := map[tokenestimate.ContentType]bool{}
+	for i, p := range passages {
+		if p.Language == "" || strings.TrimSpace(p.Text) == "" {
+			t.Errorf("Passage %d: expected a language and a text, got %+v", i, p)
+		}
+		scripts[tokenestimate.ProfileOf(p.Text).Dominant()] = true
+		contents[p.Content] = true
+	}
+	for _, script := range []string{"Latin", "Chinese", "Japanese", "Korean", "Russian", "Arabic"} {
+		if !scripts[script] {
+			t.Errorf("Expected passages in %s, got %v", script, scripts)
+		}
+	}
+	if len(contents) != 4 {
+		t.Errorf("Expected passages of every content type, got %v", contents)
+	}
+
+	texts := Texts()
+	if len(texts) != len(passages) || texts[0] != passages[0].Text {
+		t.Errorf("Expected the texts of the passages, got %d texts", len(texts))
+	}
+	// Callers may modify the results.
+	passages[0].Text = ""
+	if Passages()[0].Text == "" {
+		t.Error("Expected Passages to return a copy")
+	}
+}
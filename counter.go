@@ -0,0 +1,56 @@
+package tokenestimate
+
+// TokenCounter counts the tokens of texts. It is implemented by exact
+// tokenizers, such as the tiktoken sub-module, which serve as the ground
+// truth for labeling texts to fit and evaluate presets, and by Estimator
+// and Hybrid, so that code counting tokens can take any of them.
+type TokenCounter interface {
+	// Count returns the number of tokens text encodes to.
+	Count(text string) (int, error)
+}
+
+var (
+	_ TokenCounter = (*Estimator)(nil)
+	_ TokenCounter = (*Hybrid)(nil)
+)
+
+// Count returns the estimate of text, implementing TokenCounter. It
+// returns an *InputTooLargeError if the text is rejected by
+// WithMaxInputBytes.
+func (e *Estimator) Count(text string) (int, error) {
+	if err := e.checkInput(len(text)); err != nil {
+		return 0, err
+	}
+	return e.Estimate(text), nil
+}
+
+// Hybrid counts texts up to a size with an exact tokenizer and estimates
+// longer ones, so that short texts, where an estimate's error matters most
+// against a limit, get exact counts, while long texts do not pay the cost
+// of tokenizing them. It is a TokenCounter, and safe for concurrent use if
+// its counters are.
+type Hybrid struct {
+	exact     TokenCounter
+	fallback  TokenCounter
+	threshold int
+}
+
+// NewHybrid returns a Hybrid counting texts of at most threshold bytes with
+// exact and longer texts with fallback. If fallback is nil, the default
+// estimator is used.
+func NewHybrid(exact TokenCounter, threshold int, fallback TokenCounter) *Hybrid {
+	if fallback == nil {
+		fallback = NewEstimator()
+	}
+	return &Hybrid{exact: exact, fallback: fallback, threshold: threshold}
+}
+
+// Count returns the count of text by the exact tokenizer if text is at most
+// the threshold long, and by the fallback otherwise. Errors of the exact
+// tokenizer are returned, not covered up by an estimate.
+func (h *Hybrid) Count(text string) (int, error) {
+	if len(text) <= h.threshold {
+		return h.exact.Count(text)
+	}
+	return h.fallback.Count(text)
+}
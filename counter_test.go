@@ -0,0 +1,51 @@
+package tokenestimate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// runeCounter counts runes as tokens, and fails on texts containing
+// "fail".
+type runeCounter struct{}
+
+func (runeCounter) Count(text string) (int, error) {
+	if strings.Contains(text, "fail") {
+		return 0, errors.New("cannot count")
+	}
+	return len([]rune(text)), nil
+}
+
+func TestEstimatorCount(t *testing.T) {
+	e := NewEstimator()
+	if n, err := e.Count("Hello, world!"); err != nil || n != e.Estimate("Hello, world!") {
+		t.Errorf("Expected the estimate, got %d, %v", n, err)
+	}
+	var tooLarge *InputTooLargeError
+	if _, err := e.WithMaxInputBytes(4, InputLimitError).Count("Hello, world!"); !errors.As(err, &tooLarge) {
+		t.Errorf("Expected an *InputTooLargeError, got %v", err)
+	}
+}
+
+func TestHybrid(t *testing.T) {
+	h := NewHybrid(runeCounter{}, 16, nil)
+	long := strings.Repeat("Hello, world! ", 10)
+	tests := map[string]int{
+		"":                 0,
+		"你好，世界":            5,
+		"0123456789abcdef": 16,
+		long:               NewEstimator().Estimate(long),
+	}
+	for text, want := range tests {
+		if got, err := h.Count(text); err != nil || got != want {
+			t.Errorf("Count(%q): expected %d, got %d, %v", text, want, got, err)
+		}
+	}
+	if _, err := h.Count("fail"); err == nil {
+		t.Error("Expected the error of the exact counter")
+	}
+	if got, err := NewHybrid(runeCounter{}, 4, runeCounter{}).Count("fail now"); err == nil {
+		t.Errorf("Expected the error of the fallback, got %d", got)
+	}
+}
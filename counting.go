@@ -0,0 +1,75 @@
+package tokenestimate
+
+import "io"
+
+// CountingWriter is an io.Writer that passes writes through to an
+// underlying writer while estimating the tokens of everything written.
+// It is not safe for concurrent use.
+type CountingWriter struct {
+	w      io.Writer
+	stream *StreamEstimator
+}
+
+// NewCountingWriter returns a CountingWriter writing to w and estimating
+// with the default estimator.
+func NewCountingWriter(w io.Writer) *CountingWriter {
+	return NewEstimator().CountingWriter(w)
+}
+
+// CountingWriter returns a CountingWriter writing to w and estimating with e.
+func (e *Estimator) CountingWriter(w io.Writer) *CountingWriter {
+	return &CountingWriter{w: w, stream: NewStreamEstimator(e)}
+}
+
+// Write writes p to the underlying writer and counts the bytes it accepted.
+func (cw *CountingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.stream.Write(p[:n])
+	return n, err
+}
+
+// Tokens returns the estimated number of tokens written so far.
+func (cw *CountingWriter) Tokens() int {
+	return cw.stream.Tokens()
+}
+
+// Stats returns the statistics of the text written so far.
+func (cw *CountingWriter) Stats() Stats {
+	return cw.stream.Stats()
+}
+
+// CountingReader is an io.Reader that passes reads through from an
+// underlying reader while estimating the tokens of everything read.
+// It is not safe for concurrent use.
+type CountingReader struct {
+	r      io.Reader
+	stream *StreamEstimator
+}
+
+// NewCountingReader returns a CountingReader reading from r and estimating
+// with the default estimator.
+func NewCountingReader(r io.Reader) *CountingReader {
+	return NewEstimator().CountingReader(r)
+}
+
+// CountingReader returns a CountingReader reading from r and estimating with e.
+func (e *Estimator) CountingReader(r io.Reader) *CountingReader {
+	return &CountingReader{r: r, stream: NewStreamEstimator(e)}
+}
+
+// Read reads from the underlying reader and counts the bytes read.
+func (cr *CountingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.stream.Write(p[:n])
+	return n, err
+}
+
+// Tokens returns the estimated number of tokens read so far.
+func (cr *CountingReader) Tokens() int {
+	return cr.stream.Tokens()
+}
+
+// Stats returns the statistics of the text read so far.
+func (cr *CountingReader) Stats() Stats {
+	return cr.stream.Stats()
+}
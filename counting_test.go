@@ -0,0 +1,72 @@
+package tokenestimate
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCountingWriter(t *testing.T) {
+	text := "Proxied response body: 你好，世界！ 12345"
+
+	t.Run("Passes through and counts", func(t *testing.T) {
+		var buf bytes.Buffer
+		cw := NewCountingWriter(&buf)
+		if _, err := io.Copy(cw, iotest.HalfReader(strings.NewReader(text))); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if buf.String() != text {
+			t.Errorf("Expected %q written, got %q", text, buf.String())
+		}
+		if got, want := cw.Tokens(), NewEstimator().Estimate(text); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Counts only accepted bytes", func(t *testing.T) {
+		cw := KimiK2CodeEstimator.CountingWriter(&limitedWriter{n: 5})
+		n, err := cw.Write([]byte("hello world"))
+		if n != 5 || err == nil {
+			t.Errorf("Expected short write, got %d, %v", n, err)
+		}
+		if got := cw.Stats().LatinLetters; got != 5 {
+			t.Errorf("Expected 5 letters counted, got %d", got)
+		}
+	})
+}
+
+func TestCountingReader(t *testing.T) {
+	text := strings.Repeat("Request body line with 世界 and numbers 2024.\n", 3000)
+
+	cr := NewCountingReader(strings.NewReader(text))
+	data, err := io.ReadAll(iotest.OneByteReader(cr))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(data) != text {
+		t.Error("Expected data to pass through unchanged")
+	}
+	want := NewEstimator().Analyze(text)
+	if got := cr.Stats(); got.Words != want.Words || got.ChineseChars != want.ChineseChars {
+		t.Errorf("Expected stats %+v, got %+v", want, got)
+	}
+	if cr.Tokens() == 0 {
+		t.Error("Expected a non-zero estimate")
+	}
+}
+
+// limitedWriter accepts n bytes, then fails.
+type limitedWriter struct{ n int }
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) <= w.n {
+		w.n -= len(p)
+		return len(p), nil
+	}
+	n := w.n
+	w.n = 0
+	return n, errors.New("short write")
+}
@@ -0,0 +1,266 @@
+// Package dataset reads texts labeled with their token counts, for
+// training presets with the fit package and measuring them with the eval
+// package. It reads JSON Lines, CSV and TSV files, optionally
+// gzip-compressed, such as the JSONL format of the library's test set:
+//
+//	{"text": "Hello, world!", "token_count": 4}
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// Sample is a text labeled with the number of tokens the target tokenizer
+// encodes it to.
+type Sample = tokenestimate.Sample
+
+// Format is the file format of a dataset.
+type Format int
+
+const (
+	// FormatAuto chooses the format from the file extension: .jsonl or
+	// .ndjson, .csv or .tsv, each optionally followed by .gz.
+	FormatAuto Format = iota
+	// FormatJSONL has a JSON object per line.
+	FormatJSONL
+	// FormatCSV has comma-separated values with a header row naming the
+	// columns.
+	FormatCSV
+	// FormatTSV has tab-separated values with a header row naming the
+	// columns.
+	FormatTSV
+)
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatAuto:
+		return "auto"
+	case FormatJSONL:
+		return "jsonl"
+	case FormatCSV:
+		return "csv"
+	case FormatTSV:
+		return "tsv"
+	default:
+		return "unknown"
+	}
+}
+
+// Options configures LoadWithOptions and Read.
+type Options struct {
+	Format      Format // File format (default: FormatAuto)
+	TextField   string // Field or column of the text (default: "text")
+	TokensField string // Field or column of the token count (default: "token_count")
+}
+
+// Load reads the samples of the dataset at path, in the format its
+// extension names, with the text in the "text" field and the token count
+// in "token_count".
+func Load(path string) ([]Sample, error) {
+	return LoadWithOptions(path, Options{})
+}
+
+// LoadWithOptions is like Load with the given options.
+func LoadWithOptions(path string, opts Options) ([]Sample, error) {
+	if opts.Format == FormatAuto {
+		format, err := formatOf(path)
+		if err != nil {
+			return nil, err
+		}
+		opts.Format = format
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	samples, err := Read(f, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return samples, nil
+}
+
+// formatOf returns the format named by the extension of path.
+func formatOf(path string) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".gz" {
+		ext = strings.ToLower(filepath.Ext(strings.TrimSuffix(path, filepath.Ext(path))))
+	}
+	switch ext {
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, nil
+	case ".csv":
+		return FormatCSV, nil
+	case ".tsv":
+		return FormatTSV, nil
+	}
+	return FormatAuto, fmt.Errorf("unknown dataset format: %s", path)
+}
+
+// Read reads samples in opts.Format from r, which must not be FormatAuto.
+// Gzip-compressed input is decompressed.
+func Read(r io.Reader, opts Options) ([]Sample, error) {
+	opts.TextField = cmp.Or(opts.TextField, "text")
+	opts.TokensField = cmp.Or(opts.TokensField, "token_count")
+
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+
+	switch opts.Format {
+	case FormatJSONL:
+		return readJSONL(br, opts)
+	case FormatCSV:
+		return readDelimited(br, ',', opts)
+	case FormatTSV:
+		return readDelimited(br, '\t', opts)
+	case FormatAuto:
+		return nil, errors.New("dataset format not specified")
+	}
+	return nil, fmt.Errorf("unknown dataset format: %d", opts.Format)
+}
+
+// readJSONL reads a JSON object per line. Blank lines are skipped.
+func readJSONL(r *bufio.Reader, opts Options) ([]Sample, error) {
+	var samples []Sample
+	for line := 1; ; line++ {
+		b, readErr := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(b)) > 0 {
+			var record map[string]json.RawMessage
+			if err := json.Unmarshal(b, &record); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			s, err := jsonSample(record, opts)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			samples = append(samples, s)
+		}
+		if readErr == io.EOF {
+			return samples, nil
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+}
+
+// jsonSample returns the sample of a JSON record.
+func jsonSample(record map[string]json.RawMessage, opts Options) (Sample, error) {
+	var s Sample
+	text, ok := record[opts.TextField]
+	if !ok {
+		return s, fmt.Errorf("no field %q", opts.TextField)
+	}
+	if err := json.Unmarshal(text, &s.Text); err != nil {
+		return s, fmt.Errorf("field %q: %w", opts.TextField, err)
+	}
+	tokens, ok := record[opts.TokensField]
+	if !ok {
+		return s, fmt.Errorf("no field %q", opts.TokensField)
+	}
+	// Token counts may be numbers or strings of digits.
+	var value string
+	if err := json.Unmarshal(tokens, &value); err != nil {
+		value = string(tokens)
+	}
+	n, err := parseTokens(value)
+	if err != nil {
+		return s, fmt.Errorf("field %q: %w", opts.TokensField, err)
+	}
+	s.Tokens = n
+	return s, nil
+}
+
+// readDelimited reads records separated by sep, with a header row naming
+// the columns.
+func readDelimited(r io.Reader, sep rune, opts Options) ([]Sample, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = sep
+	cr.ReuseRecord = true
+	if sep == '\t' {
+		// Tab-separated exports rarely quote fields, so stray quotes in
+		// the text are kept rather than rejected.
+		cr.LazyQuotes = true
+	}
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	textCol, tokensCol := -1, -1
+	for i, name := range header {
+		// Spreadsheets may start the file with a byte order mark.
+		switch strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")) {
+		case opts.TextField:
+			textCol = i
+		case opts.TokensField:
+			tokensCol = i
+		}
+	}
+	if textCol < 0 {
+		return nil, fmt.Errorf("no column %q", opts.TextField)
+	}
+	if tokensCol < 0 {
+		return nil, fmt.Errorf("no column %q", opts.TokensField)
+	}
+
+	var samples []Sample
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		n, err := parseTokens(record[tokensCol])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: column %q: %w", line, opts.TokensField, err)
+		}
+		samples = append(samples, Sample{Text: record[textCol], Tokens: n})
+	}
+}
+
+// parseTokens parses a token count, which may be written as a whole
+// floating-point number such as "12.0".
+func parseTokens(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("negative token count %d", n)
+		}
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || f < 0 || f > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid token count %q", s)
+	}
+	return int(f), nil
+}
@@ -0,0 +1,134 @@
+package dataset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var want = []Sample{
+	{Text: "Hello, world!", Tokens: 4},
+	{Text: "你好，\"世界\"\n第二行", Tokens: 6},
+	{Text: "", Tokens: 0},
+}
+
+// write writes content to a file named name in a temporary directory,
+// compressed if name ends in .gz, and returns its path.
+func write(t *testing.T, name, content string) string {
+	t.Helper()
+	data := []byte(content)
+	if strings.HasSuffix(name, ".gz") {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(data)
+		zw.Close()
+		data = buf.Bytes()
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	jsonl := `{"text": "Hello, world!", "token_count": 4}
+
+{"token_count": 6, "text": "你好，\"世界\"\n第二行", "source": "chat"}
+{"text": "", "token_count": 0}
+`
+	csvData := "\ufefftext,token_count\n\"Hello, world!\",4\n\"你好，\"\"世界\"\"\n第二行\",6\n,0\n"
+	tsv := "id\ttoken_count\ttext\n1\t4\tHello, world!\n2\t6\t\"你好，\"\"世界\"\"\n第二行\"\n3\t0\t\n"
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"data.jsonl", jsonl},
+		{"data.jsonl.gz", jsonl},
+		{"data.NDJSON", jsonl},
+		{"data.csv", csvData},
+		{"data.csv.gz", csvData},
+		{"data.tsv", tsv},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(write(t, tt.name, tt.content))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("Expected %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestLoadWithOptions(t *testing.T) {
+	// Custom fields, token counts as strings or whole floats, and a format
+	// the extension does not name.
+	path := write(t, "data.txt", `{"prompt": "Hello, world!", "usage": "4"}
+{"prompt": "你好，\"世界\"\n第二行", "usage": 6.0}
+{"prompt": "", "usage": 0}
+`)
+	got, err := LoadWithOptions(path, Options{Format: FormatJSONL, TextField: "prompt", TokensField: "usage"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	path = write(t, "data.csv", "input,tokens\nHello,2\n")
+	got, err = LoadWithOptions(path, Options{TextField: "input", TokensField: "tokens"})
+	if err != nil || !slices.Equal(got, []Sample{{Text: "Hello", Tokens: 2}}) {
+		t.Errorf("Unexpected result %q, %v", got, err)
+	}
+}
+
+func TestLoadTestSet(t *testing.T) {
+	samples, err := Load(filepath.Join("..", "testset-sample.jsonl"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(samples) == 0 || samples[0].Text == "" || samples[0].Tokens == 0 {
+		t.Errorf("Expected labeled samples, got %q", samples)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errText string
+	}{
+		{"data.json", `{"text": "a", "token_count": 1}`, "unknown dataset format"},
+		{"data.jsonl", `{"text": "a", "token_count": 1}` + "\n{\"text\": \"b\"}\n", `line 2: no field "token_count"`},
+		{"data.jsonl", "{\"text\": \"a\", \"token_count\": 1}\nnot json\n", "line 2"},
+		{"data.jsonl", `{"text": 5, "token_count": 1}`, `field "text"`},
+		{"data.jsonl", `{"text": "a", "token_count": -1}`, "negative token count"},
+		{"data.jsonl", `{"text": "a", "token_count": 1.5}`, "invalid token count"},
+		{"data.csv", "text,count\na,1\n", `no column "token_count"`},
+		{"data.csv", "text,token_count\na,1\nb,x\n", `line 3: column "token_count"`},
+		{"data.csv", "text,token_count\na,1,2\n", "wrong number of fields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.errText, func(t *testing.T) {
+			_, err := Load(write(t, tt.name, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("Expected an error containing %q, got %v", tt.errText, err)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("Expected a missing file to be reported, got %v", err)
+	}
+	if _, err := Read(strings.NewReader(""), Options{}); err == nil {
+		t.Error("Expected Read without a format to fail")
+	}
+}
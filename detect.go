@@ -0,0 +1,80 @@
+package tokenestimate
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// codeKeywords are words that start lines of source code far more often
+// than lines of prose.
+var codeKeywords = []string{
+	"func ", "def ", "class ", "import ", "from ", "package ", "return",
+	"var ", "let ", "const ", "if (", "if ", "for (", "for ", "while ",
+	"#include", "#define", "public ", "private ", "static ", "fn ", "use ",
+	"//", "/*", "} else",
+}
+
+// DetectContentType guesses the content type of text from its structure:
+// ContentJSON for a valid JSON object or array, ContentMarkdown for text
+// with headings, fences, lists, tables or links on a good share of its
+// lines, ContentCode for text most of whose lines look like statements,
+// and ContentText otherwise. It is a heuristic for choosing a model with
+// WithContentType when the caller does not know what the text is.
+func DetectContentType(text string) ContentType {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return ContentText
+	}
+	if (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid([]byte(trimmed)) {
+		return ContentJSON
+	}
+
+	var md markdownLine
+	markdown := 0
+	for _, r := range text {
+		if md.step(r) != mdNone {
+			markdown++
+		}
+	}
+	lines, code := 0, 0
+	for _, line := range strings.Split(trimmed, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines++
+		if looksLikeCode(line) {
+			code++
+		}
+	}
+
+	// Markdown documents mark a line in four or so; code is mostly
+	// statements. Python comments pass for headings, so whichever
+	// structure is denser wins.
+	mdShare, codeShare := float64(markdown)/float64(lines), float64(code)/float64(lines)
+	switch {
+	case codeShare >= 0.5 && codeShare > mdShare:
+		return ContentCode
+	case mdShare >= 0.2 || markdown >= 3:
+		return ContentMarkdown
+	}
+	return ContentText
+}
+
+// looksLikeCode reports whether a trimmed, nonempty line looks like a
+// statement of source code rather than a line of prose.
+func looksLikeCode(line string) bool {
+	switch line[len(line)-1] {
+	case ';', '{', '}', '(', ')', '[', ']':
+		return true
+	case ':':
+		// Python blocks, but not prose introducing a list.
+		return strings.ContainsAny(line, "()")
+	}
+	for _, kw := range codeKeywords {
+		if strings.HasPrefix(line, kw) {
+			return true
+		}
+	}
+	return strings.Contains(line, " = ") || strings.Contains(line, " := ")
+}
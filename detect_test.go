@@ -0,0 +1,29 @@
+package tokenestimate
+
+import "testing"
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want ContentType
+	}{
+		{"empty", "", ContentText},
+		{"prose", "The quick brown fox jumps over the lazy dog.\nIt was not amused.", ContentText},
+		{"chinese", "今天天气很好，我们去公园散步吧。", ContentText},
+		{"json object", `{"name": "fox", "tags": ["quick", "brown"]}`, ContentJSON},
+		{"json array", " [1, 2, 3]\n", ContentJSON},
+		{"invalid json", `{"name": "fox",`, ContentText},
+		{"go", "package main\n\nfunc main() {\n\tx := 42\n\tfmt.Println(x)\n}\n", ContentCode},
+		{"python", "# Sum the values\ndef total(values):\n    s = 0\n    for v in values:\n        s += v\n    return s\n", ContentCode},
+		{"markdown", "# Title\n\nSome introduction.\n\n- first item\n- second item\n\nSee [the docs](https://example.com).\n", ContentMarkdown},
+		{"readme with code", "## Usage\n\nRun it:\n\n```go\nx := f()\n```\n\nThat is all.\n", ContentMarkdown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectContentType(tt.text); got != tt.want {
+				t.Errorf("DetectContentType(%q) = %s, expected %s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package tokenestimate
+
+import "unicode/utf8"
+
+// Dictionary maps common words to their exact token cost under a preset's
+// tokenizer. Latin words are stored in lower case and are matched in lower
+// case or with a leading capital; the cost includes the space before the
+// word, which the tokenizer merges into the word's token. The costs are
+// measured with cmd/dictgen from the encoding of the tokenizer.
+type Dictionary map[string]int
+
+// maxDictionaryHanRunes is the length of the longest Chinese entry.
+const maxDictionaryHanRunes = 4
+
+// maxDictionaryWordBytes is the length of the longest Latin entry.
+const maxDictionaryWordBytes = 16
+
+// WithDictionary returns a clone of the estimator that prices words found
+// in the preset's dictionary at their exact token cost and runs the
+// regression on the remaining text only. Only cl100k-base and o200k-base
+// have a dictionary; presets without one, such as kimi-k2, whose costs
+// have not been measured, are unaffected. The dictionary is not used when sampling.
+func (e *Estimator) WithDictionary(enabled bool) *Estimator {
+	clone := e.Clone()
+	clone.UseDictionary = enabled
+	return clone
+}
+
+// applyDictionary moves the dictionary words of text out of their
+// character categories into the Dictionary fields of stats.
+func applyDictionary(text string, dict Dictionary, stats *Stats) {
+	var buf [maxDictionaryWordBytes]byte
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case isASCIILetter(rune(c)):
+			end := i + 1
+			for end < len(text) && isASCIILetter(rune(text[end])) {
+				end++
+			}
+			if end-i <= maxDictionaryWordBytes && isDictionaryBoundary(text, i-1) && isDictionaryBoundary(text, end) {
+				if cost, ok := lookupLatin(dict, text[i:end], buf[:0]); ok {
+					stats.LatinLetters -= end - i
+					stats.DictionaryChars += end - i
+					stats.DictionaryTokens += cost
+					stats.DictionaryWords++
+					if i == 0 || isASCIISpace(text[i-1]) {
+						stats.Words--
+					}
+				}
+			}
+			i = end
+
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRuneInString(text[i:])
+			if classify(r) != CategoryChinese {
+				i += size
+				continue
+			}
+			n, length, cost := matchHan(dict, text[i:])
+			if n == 0 {
+				i += size
+				continue
+			}
+			stats.ChineseChars -= n
+			stats.DictionaryChars += n
+			stats.DictionaryTokens += cost
+			stats.DictionaryWords++
+			i += length
+
+		default:
+			i++
+		}
+	}
+}
+
+// isDictionaryBoundary reports whether the byte at text[i] may delimit a
+// dictionary word. Letters, digits, and characters that join words in
+// identifiers, paths and base64 are not boundaries.
+func isDictionaryBoundary(text string, i int) bool {
+	if i < 0 || i >= len(text) {
+		return true
+	}
+	switch c := text[i]; c {
+	case '.', ',', ';', ':', '!', '?', '"', '\'', '(', ')', '[', ']', '{', '}':
+		return true
+	default:
+		return isASCIISpace(c)
+	}
+}
+
+// lookupLatin looks up word in lower case, accepting only all-lower-case
+// words and words with a single leading capital.
+func lookupLatin(dict Dictionary, word string, buf []byte) (int, bool) {
+	for j := 0; j < len(word); j++ {
+		c := word[j]
+		if c >= 'A' && c <= 'Z' {
+			if j > 0 {
+				return 0, false
+			}
+			c += 'a' - 'A'
+		}
+		buf = append(buf, c)
+	}
+	cost, ok := dict[string(buf)]
+	return cost, ok
+}
+
+// matchHan returns the longest dictionary entry at the start of text, as
+// its length in runes and bytes and its cost. It returns n == 0 if there
+// is no match.
+func matchHan(dict Dictionary, text string) (n, length, cost int) {
+	end := 0
+	for runes := 1; runes <= maxDictionaryHanRunes && end < len(text); runes++ {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if classify(r) != CategoryChinese {
+			break
+		}
+		end += size
+		if c, ok := dict[text[:end]]; ok {
+			n, length, cost = runes, end, c
+		}
+	}
+	return n, length, cost
+}
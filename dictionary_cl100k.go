@@ -0,0 +1,40 @@
+// Code generated by dictgen; DO NOT EDIT.
+
+package tokenestimate
+
+// cl100kDictionary holds frequent words with their token costs
+// under cl100k_base, as measured by dictgen.
+var cl100kDictionary = Dictionary{
+	// English
+	"the": 1, "of": 1, "and": 1, "to": 1, "in": 1, "a": 1, "is": 1,
+	"that": 1, "for": 1, "it": 1, "as": 1, "was": 1, "with": 1, "be": 1,
+	"by": 1, "on": 1, "not": 1, "he": 1, "this": 1, "are": 1, "or": 1,
+	"his": 1, "from": 1, "at": 1, "which": 1, "but": 1, "have": 1, "an": 1,
+	"they": 1, "you": 1, "were": 1, "her": 1, "she": 1, "there": 1,
+	"been": 1, "one": 1, "all": 1, "we": 1, "their": 1, "has": 1,
+	"would": 1, "when": 1, "if": 1, "so": 1, "no": 1, "more": 1, "can": 1,
+	"will": 1, "who": 1, "what": 1, "up": 1, "out": 1, "about": 1,
+	"into": 1, "some": 1, "my": 1, "than": 1, "them": 1, "only": 1,
+	"its": 1, "do": 1, "like": 1, "your": 1, "me": 1, "our": 1, "may": 1,
+	"time": 1, "new": 1, "just": 1, "first": 1, "also": 1, "two": 1,
+	"these": 1, "other": 1, "after": 1, "any": 1, "very": 1, "how": 1,
+	"most": 1, "over": 1, "such": 1, "people": 1, "years": 1, "could": 1,
+	"well": 1, "now": 1, "even": 1, "many": 1, "much": 1, "where": 1,
+	"made": 1, "before": 1, "should": 1, "because": 1, "those": 1,
+	"through": 1, "work": 1, "way": 1, "between": 1, "world": 1, "state": 1,
+	"still": 1, "here": 1, "must": 1, "own": 1, "life": 1, "same": 1,
+	"year": 1, "last": 1, "few": 1, "good": 1, "part": 1,
+	// Chinese
+	"我们": 1, "他们": 2, "中国": 1, "一个": 1, "没有": 1, "什么": 3, "自己": 3, "这个": 2,
+	"可以": 1, "就是": 2, "因为": 2, "所以": 2, "已经": 2, "如果": 1, "时候": 3, "现在": 2,
+	"知道": 2, "这些": 2, "还是": 2, "问题": 1, "进行": 1, "发展": 2, "工作": 2, "社会": 2,
+	"经济": 3, "国家": 2, "人民": 2, "我的": 1, "你的": 2, "但是": 2, "然后": 2, "而且": 3,
+	"或者": 2, "这样": 2, "那么": 3, "怎么": 3, "今天": 2, "大家": 2, "孩子": 3, "朋友": 3,
+	"公司": 1, "市场": 2, "技术": 3, "世界": 3, "时间": 1, "政府": 3, "学生": 2, "老师": 3,
+	"生活": 2, "情况": 3, "方面": 2, "重要": 2, "需要": 1, "通过": 1, "关于": 2, "根据": 3,
+	"不是": 2, "非常": 2, "一些": 2, "可能": 1, "应该": 2, "这种": 2, "以及": 2, "以后": 2,
+	"之后": 2, "之前": 2, "开始": 1, "同时": 1, "其中": 1, "目前": 2, "主要": 2, "一样": 2,
+	"这里": 2, "那里": 3, "一起": 2, "东西": 2, "地方": 2, "事情": 2, "认为": 2, "觉得": 3,
+	"希望": 4, "喜欢": 4, "研究": 5, "系统": 1, "数据": 1, "信息": 1, "服务": 1, "管理": 1,
+	"用户": 1, "企业": 2, "产品": 1, "网络": 1,
+}
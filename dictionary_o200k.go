@@ -0,0 +1,40 @@
+// Code generated by dictgen; DO NOT EDIT.
+
+package tokenestimate
+
+// o200kDictionary holds frequent words with their token costs
+// under o200k_base, as measured by dictgen.
+var o200kDictionary = Dictionary{
+	// English
+	"the": 1, "of": 1, "and": 1, "to": 1, "in": 1, "a": 1, "is": 1,
+	"that": 1, "for": 1, "it": 1, "as": 1, "was": 1, "with": 1, "be": 1,
+	"by": 1, "on": 1, "not": 1, "he": 1, "this": 1, "are": 1, "or": 1,
+	"his": 1, "from": 1, "at": 1, "which": 1, "but": 1, "have": 1, "an": 1,
+	"they": 1, "you": 1, "were": 1, "her": 1, "she": 1, "there": 1,
+	"been": 1, "one": 1, "all": 1, "we": 1, "their": 1, "has": 1,
+	"would": 1, "when": 1, "if": 1, "so": 1, "no": 1, "more": 1, "can": 1,
+	"will": 1, "who": 1, "what": 1, "up": 1, "out": 1, "about": 1,
+	"into": 1, "some": 1, "my": 1, "than": 1, "them": 1, "only": 1,
+	"its": 1, "do": 1, "like": 1, "your": 1, "me": 1, "our": 1, "may": 1,
+	"time": 1, "new": 1, "just": 1, "first": 1, "also": 1, "two": 1,
+	"these": 1, "other": 1, "after": 1, "any": 1, "very": 1, "how": 1,
+	"most": 1, "over": 1, "such": 1, "people": 1, "years": 1, "could": 1,
+	"well": 1, "now": 1, "even": 1, "many": 1, "much": 1, "where": 1,
+	"made": 1, "before": 1, "should": 1, "because": 1, "those": 1,
+	"through": 1, "work": 1, "way": 1, "between": 1, "world": 1, "state": 1,
+	"still": 1, "here": 1, "must": 1, "own": 1, "life": 1, "same": 1,
+	"year": 1, "last": 1, "few": 1, "good": 1, "part": 1,
+	// Chinese
+	"我们": 1, "他们": 1, "中国": 1, "一个": 1, "没有": 1, "什么": 1, "自己": 1, "这个": 1,
+	"可以": 1, "就是": 1, "因为": 1, "所以": 1, "已经": 1, "如果": 1, "时候": 1, "现在": 1,
+	"知道": 1, "这些": 1, "还是": 1, "问题": 1, "进行": 1, "发展": 1, "工作": 1, "社会": 1,
+	"经济": 1, "国家": 1, "人民": 1, "我的": 1, "你的": 1, "但是": 1, "然后": 1, "而且": 2,
+	"或者": 1, "这样": 1, "那么": 1, "怎么": 1, "今天": 1, "大家": 1, "孩子": 1, "朋友": 1,
+	"公司": 1, "市场": 1, "技术": 1, "世界": 1, "时间": 1, "政府": 1, "学生": 1, "老师": 1,
+	"生活": 1, "情况": 1, "方面": 1, "重要": 1, "需要": 1, "通过": 1, "关于": 1, "根据": 1,
+	"不是": 1, "非常": 1, "一些": 1, "可能": 1, "应该": 1, "这种": 1, "以及": 1, "以后": 1,
+	"之后": 1, "之前": 1, "开始": 1, "同时": 1, "其中": 1, "目前": 1, "主要": 1, "一样": 1,
+	"这里": 1, "那里": 1, "一起": 1, "东西": 1, "地方": 1, "事情": 1, "认为": 1, "觉得": 1,
+	"希望": 1, "喜欢": 1, "研究": 1, "系统": 1, "数据": 1, "信息": 1, "服务": 1, "管理": 1,
+	"用户": 1, "企业": 1, "产品": 1, "网络": 1,
+}
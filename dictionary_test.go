@@ -0,0 +1,111 @@
+package tokenestimate
+
+import "testing"
+
+// dictionaryEstimator returns the o200k-base preset with its dictionary,
+// in which the words of the tests are a token each.
+func dictionaryEstimator(t *testing.T) *Estimator {
+	t.Helper()
+	e, err := GetPresetByName("o200k-base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e.WithDictionary(true)
+}
+
+func TestApplyDictionary(t *testing.T) {
+	estimator := dictionaryEstimator(t)
+
+	tests := []struct {
+		name      string
+		text      string
+		words     int
+		chars     int
+		letters   int
+		chinese   int
+		wordsLeft int
+	}{
+		{name: "English", text: "The cat and the hat.", words: 3, chars: 9, letters: 6, wordsLeft: 2},
+		{name: "Capitalization", text: "THE The tHe the", words: 2, chars: 6, letters: 6, wordsLeft: 2},
+		{name: "Punctuation boundaries", text: "(the) \"and\"", words: 2, chars: 6, wordsLeft: 2},
+		{name: "Identifiers and paths", text: "the_end a1 /the/ x-and", letters: 14, wordsLeft: 4},
+		{name: "Chinese longest match", text: "我们的工作", words: 2, chars: 4, chinese: 1, wordsLeft: 1},
+		{name: "Chinese without entries", text: "龙凤", chinese: 2, wordsLeft: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := estimator.Analyze(tt.text)
+			if stats.DictionaryWords != tt.words || stats.DictionaryChars != tt.chars ||
+				stats.DictionaryTokens != tt.words || stats.LatinLetters != tt.letters ||
+				stats.ChineseChars != tt.chinese || stats.Words != tt.wordsLeft {
+				t.Errorf("Analyze(%q) = dictionary %d words, %d chars, %d tokens; letters %d, chinese %d, words %d",
+					tt.text, stats.DictionaryWords, stats.DictionaryChars, stats.DictionaryTokens,
+					stats.LatinLetters, stats.ChineseChars, stats.Words)
+			}
+		})
+	}
+}
+
+func TestWithDictionary(t *testing.T) {
+	text := "It is one of the best of all the things that we have seen in the world."
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		if stats := NewEstimator().Analyze(text); stats.DictionaryWords != 0 {
+			t.Errorf("Expected no dictionary words, got %d", stats.DictionaryWords)
+		}
+	})
+
+	t.Run("Exact costs replace the regression", func(t *testing.T) {
+		estimator := dictionaryEstimator(t)
+		stats := estimator.Analyze(text)
+		if stats.DictionaryWords != 14 {
+			t.Errorf("Expected 14 dictionary words, got %d", stats.DictionaryWords)
+		}
+		rest := stats
+		rest.DictionaryTokens = 0
+		if got, want := estimator.Estimate(text), int(estimator.calculateTokenCount(rest)+0.5)+14; got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Measured costs", func(t *testing.T) {
+		// The costs of the words in cl100k_base, which encodes them as
+		// several tokens.
+		for word, want := range map[string]int{"经济": 3, "技术": 3, "朋友": 3, "这里": 2, "发展": 2, "我们": 1} {
+			if got := cl100kDictionary[word]; got != want {
+				t.Errorf("cl100k-base prices %q at %d tokens, want %d", word, got, want)
+			}
+		}
+	})
+
+	t.Run("Kimi-K2 has no measured costs", func(t *testing.T) {
+		if stats := NewEstimator().WithDictionary(true).Analyze(text); stats.DictionaryWords != 0 {
+			t.Errorf("Expected no dictionary words, got %d", stats.DictionaryWords)
+		}
+	})
+
+	t.Run("Preset without dictionary", func(t *testing.T) {
+		custom := &Estimator{Name: "no-dictionary", coefLatinLetters: 1}
+		if got := custom.WithDictionary(true).Estimate("the"); got != 3 {
+			t.Errorf("Expected 3 tokens, got %d", got)
+		}
+	})
+
+	t.Run("Kept across content types", func(t *testing.T) {
+		if !dictionaryEstimator(t).WithContentType(ContentCode).UseDictionary {
+			t.Error("Expected UseDictionary to be kept")
+		}
+	})
+
+	t.Run("Not used when sampling", func(t *testing.T) {
+		long := ""
+		for len(long) < 2000 {
+			long += text + " "
+		}
+		stats := dictionaryEstimator(t).WithSampling(1000, 100).Analyze(long)
+		if stats.DictionaryWords != 0 {
+			t.Errorf("Expected no dictionary words when sampling, got %d", stats.DictionaryWords)
+		}
+	})
+}
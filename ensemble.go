@@ -0,0 +1,269 @@
+package tokenestimate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Ensemble combines the estimates of several presets into their weighted
+// mean, the expected token count of a text when traffic is split across
+// backends with different tokenizers, for capacity planning and cost
+// forecasts. An Ensemble is safe for concurrent use.
+type Ensemble struct {
+	members []*Estimator
+	weights []float64 // normalized to sum to 1
+}
+
+// NewEnsemble returns an Ensemble of the named presets, each weighted by
+// its share of the traffic:
+//
+//	e, err := tokenestimate.NewEnsemble(map[string]float64{
+//		"kimi-k2":     0.7,
+//		"cl100k-base": 0.3,
+//	})
+//
+// Weights need not sum to 1; they are normalized. A preset with a weight
+// of zero takes no part. The presets are resolved when the ensemble is
+// created, so versions registered later do not change it. It returns an
+// error if a preset is not registered, a weight is negative or not finite,
+// or no weight is positive.
+func NewEnsemble(weights map[string]float64) (*Ensemble, error) {
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		names = append(names, name)
+	}
+	// Sum in a fixed order, so that estimates do not depend on map order.
+	sort.Strings(names)
+
+	m := &Ensemble{}
+	var total float64
+	for _, name := range names {
+		w := weights[name]
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("invalid weight of preset %s: %v", name, w)
+		}
+		e, err := GetPresetByName(name)
+		if err != nil {
+			return nil, err
+		}
+		if w == 0 {
+			continue
+		}
+		m.members = append(m.members, e)
+		m.weights = append(m.weights, w)
+		total += w
+	}
+	if total == 0 {
+		return nil, errors.New("ensemble has no preset with a positive weight")
+	}
+	for i := range m.weights {
+		m.weights[i] /= total
+	}
+	return m, nil
+}
+
+// Estimate returns the weighted mean of the presets' estimates of text,
+// rounded to the nearest token.
+func (m *Ensemble) Estimate(text string) int {
+	var sum float64
+	for i, e := range m.members {
+		sum += m.weights[i] * float64(e.Estimate(text))
+	}
+	return int(math.Round(sum))
+}
+
+// EstimateFloat returns the weighted mean of the presets' EstimateFloat of
+// text, for analytics that aggregate estimates over many texts.
+func (m *Ensemble) EstimateFloat(text string) float64 {
+	var sum float64
+	for i, e := range m.members {
+		sum += m.weights[i] * e.EstimateFloat(text)
+	}
+	return sum
+}
+
+// MaxOf estimates texts by the largest estimate of several presets, the
+// most a text may cost in a multi-model router where a request may land on
+// any of their tokenizers, for enforcing budgets that no backend may
+// exceed. A MaxOf is safe for concurrent use.
+type MaxOf struct {
+	members []*Estimator
+}
+
+// NewMaxOf returns a MaxOf of clones of the presets, so that changing them
+// later does not affect it. With no presets, it uses the default estimator.
+func NewMaxOf(presets ...*Estimator) *MaxOf {
+	m := &MaxOf{}
+	for _, e := range presets {
+		m.members = append(m.members, e.Clone())
+	}
+	if len(m.members) == 0 {
+		m.members = append(m.members, NewEstimator())
+	}
+	return m
+}
+
+// Estimate returns the largest of the presets' estimates of text.
+func (m *MaxOf) Estimate(text string) int {
+	tokens := 0
+	for _, e := range m.members {
+		tokens = max(tokens, e.Estimate(text))
+	}
+	return tokens
+}
+
+// ExceedsLimit reports whether any preset's estimate of text is greater
+// than limit. It stops at the first preset whose estimate is, and each
+// preset stops scanning a long text as Estimator.ExceedsLimit does.
+func (m *MaxOf) ExceedsLimit(text string, limit int) bool {
+	for _, e := range m.members {
+		if e.ExceedsLimit(text, limit) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScriptRouter estimates each part of a text with the preset most accurate
+// for the part's dominant script, such as a preset for English prose and
+// one fitted to Chinese for the Chinese paragraphs of a mixed document,
+// which fits mixed-language text better than any single linear model. A
+// ScriptRouter is safe for concurrent use.
+type ScriptRouter struct {
+	fallback *Estimator
+	routes   map[string]*Estimator // preset of each script, keyed like LanguageProfile.Dominant
+}
+
+// NewScriptRouter returns a ScriptRouter choosing among clones of the
+// presets. Each script goes to the preset with the lowest measured error
+// for it: the error of the script in the preset's Accuracy.ByScript, or
+// else the Metadata.MAPE of a preset whose Metadata supports the script.
+// Ties go to the earlier preset. Scripts without a measured error, and
+// text without letters, go to the first preset. With no presets, it uses
+// the default estimator.
+func NewScriptRouter(presets ...*Estimator) *ScriptRouter {
+	r := &ScriptRouter{routes: make(map[string]*Estimator)}
+	best := make(map[string]float64)
+	for _, p := range presets {
+		e := p.Clone()
+		if r.fallback == nil {
+			r.fallback = e
+		}
+		for script, mape := range e.scriptErrors() {
+			if current, ok := best[script]; !ok || mape < current {
+				best[script] = mape
+				r.routes[script] = e
+			}
+		}
+	}
+	if r.fallback == nil {
+		r.fallback = NewEstimator()
+	}
+	return r
+}
+
+// scriptErrors returns the measured error of the estimator for each script
+// it has one for.
+func (e *Estimator) scriptErrors() map[string]float64 {
+	errs := make(map[string]float64)
+	if e.Metadata.MAPE > 0 {
+		for _, script := range e.Metadata.Scripts {
+			errs[script] = e.Metadata.MAPE
+		}
+	}
+	if e.Accuracy != nil {
+		for script, mape := range e.Accuracy.ByScript {
+			errs[script] = mape
+		}
+	}
+	return errs
+}
+
+// PresetFor returns the registry name of the preset the router estimates
+// text of script with, such as "kimi-k2@12" for "Latin".
+func (r *ScriptRouter) PresetFor(script string) string {
+	return r.route(script).Key()
+}
+
+func (r *ScriptRouter) route(script string) *Estimator {
+	if e, ok := r.routes[script]; ok {
+		return e
+	}
+	return r.fallback
+}
+
+// Estimate returns the estimate of text, summing the estimates of its
+// parts by their presets and rounding the sum with the first preset's
+// rounding mode. Text whose parts all go to one preset gets that preset's
+// estimate.
+func (r *ScriptRouter) Estimate(text string) int {
+	parts := r.split(text)
+	if len(parts) == 1 {
+		return parts[0].e.Estimate(text)
+	}
+	return r.fallback.Rounding.round(r.sum(parts))
+}
+
+// EstimateFloat returns the sum of the EstimateFloat of the parts of text
+// by their presets, for analytics that aggregate estimates over many
+// texts.
+func (r *ScriptRouter) EstimateFloat(text string) float64 {
+	return r.sum(r.split(text))
+}
+
+// routedPart is a part of a text with the preset estimating it.
+type routedPart struct {
+	e    *Estimator
+	text string
+}
+
+func (r *ScriptRouter) sum(parts []routedPart) float64 {
+	var total float64
+	for _, part := range parts {
+		total += part.e.EstimateFloat(part.text)
+	}
+	return total
+}
+
+// split splits text at line breaks into parts estimated by one preset
+// each. The dominant script of each line selects its preset, and
+// consecutive lines with the same preset form one part. Lines without
+// letters, such as blank lines or numbers, belong to the part before them,
+// or the first part if there is none.
+func (r *ScriptRouter) split(text string) []routedPart {
+	var parts []routedPart
+	var current *Estimator
+	start := 0
+	for i := 0; i < len(text); {
+		end := len(text)
+		if n := strings.IndexByte(text[i:], '\n'); n >= 0 {
+			end = i + n + 1
+		}
+		if script := ProfileOf(text[i:end]).Dominant(); isScript(script) {
+			e := r.route(script)
+			if current != nil && e != current {
+				parts = append(parts, routedPart{current, text[start:i]})
+				start = i
+			}
+			current = e
+		}
+		i = end
+	}
+	if current == nil {
+		current = r.fallback
+	}
+	return append(parts, routedPart{current, text[start:]})
+}
+
+// isScript reports whether a share named by LanguageProfile.Dominant is a
+// script of letters.
+func isScript(name string) bool {
+	switch name {
+	case "", "Digits", "Symbols", "Whitespace", "Unknown":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package tokenestimate
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEnsemble(t *testing.T) {
+	m, err := NewEnsemble(map[string]float64{"kimi-k2": 7, "cl100k-base": 3, "o200k-base": 0})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cl100k, _ := GetPresetByName("cl100k-base")
+	for _, text := range []string{"", "Hello, world!", "你好，世界", strings.Repeat("func main() {}\n", 50)} {
+		want := 0.7*float64(KimiK2Estimator.Estimate(text)) + 0.3*float64(cl100k.Estimate(text))
+		if got := m.Estimate(text); got != int(math.Round(want)) {
+			t.Errorf("Estimate(%q): expected %v rounded, got %d", text, want, got)
+		}
+		wantFloat := 0.7*KimiK2Estimator.EstimateFloat(text) + 0.3*cl100k.EstimateFloat(text)
+		if got := m.EstimateFloat(text); math.Abs(got-wantFloat) > 1e-9 {
+			t.Errorf("EstimateFloat(%q): expected %v, got %v", text, wantFloat, got)
+		}
+	}
+
+	tests := map[string]map[string]float64{
+		"unknown preset: nope":                          {"kimi-k2": 1, "nope": 1},
+		"invalid weight of preset kimi-k2: -1":          {"kimi-k2": -1},
+		"invalid weight of preset kimi-k2: NaN":         {"kimi-k2": math.NaN()},
+		"ensemble has no preset with a positive weight": {"kimi-k2": 0},
+	}
+	for want, weights := range tests {
+		if _, err := NewEnsemble(weights); err == nil || err.Error() != want {
+			t.Errorf("NewEnsemble(%v): expected %q, got %v", weights, want, err)
+		}
+	}
+	if _, err := NewEnsemble(nil); err == nil {
+		t.Error("Expected an error for an empty ensemble")
+	}
+}
+
+func TestMaxOf(t *testing.T) {
+	cl100k, _ := GetPresetByName("cl100k-base")
+	o200k, _ := GetPresetByName("o200k-base")
+	m := NewMaxOf(KimiK2Estimator, cl100k, o200k)
+	for _, text := range []string{"", "Hello, world!", "你好，世界", strings.Repeat("func main() {}\n", 50)} {
+		want := max(KimiK2Estimator.Estimate(text), cl100k.Estimate(text), o200k.Estimate(text))
+		if got := m.Estimate(text); got != want {
+			t.Errorf("Estimate(%q): expected %d, got %d", text, want, got)
+		}
+		if want > 0 && (!m.ExceedsLimit(text, want-1) || m.ExceedsLimit(text, want)) {
+			t.Errorf("ExceedsLimit(%q): expected the limit to be %d", text, want)
+		}
+	}
+
+	e := KimiK2Estimator.Clone()
+	m = NewMaxOf(e)
+	e.coefChinese *= 10
+	if got, want := m.Estimate("你好，世界"), KimiK2Estimator.Estimate("你好，世界"); got != want {
+		t.Errorf("Expected the MaxOf to keep its own copy, got %d instead of %d", got, want)
+	}
+	if got, want := NewMaxOf().Estimate("Hello, world!"), NewEstimator().Estimate("Hello, world!"); got != want {
+		t.Errorf("Expected the default estimator, got %d instead of %d", got, want)
+	}
+}
+
+func TestScriptRouter(t *testing.T) {
+	chinese := KimiK2Estimator.Clone()
+	chinese.Name, chinese.Version = "chinese-test", 1
+	chinese.coefChinese *= 1.5
+	chinese.Accuracy = &Accuracy{MAPE: 0.05, ByScript: map[string]float64{"Chinese": 0.02, "Korean": 0.5}}
+	r := NewScriptRouter(KimiK2Estimator, chinese)
+
+	routes := map[string]string{
+		"Chinese":  "chinese-test@1",
+		"Latin":    KimiK2Estimator.Key(), // tie on the metadata MAPE
+		"Korean":   KimiK2Estimator.Key(),
+		"Ethiopic": KimiK2Estimator.Key(),
+		"Elvish":   KimiK2Estimator.Key(),
+	}
+	for script, want := range routes {
+		if got := r.PresetFor(script); got != want {
+			t.Errorf("PresetFor(%q): expected %s, got %s", script, want, got)
+		}
+	}
+
+	english, han := "The quick brown fox jumps over the lazy dog.\n\n", "敏捷的棕色狐狸跳过了懒狗。\n2024\n"
+	for _, tt := range []struct {
+		text string
+		want int
+	}{
+		{"", KimiK2Estimator.Estimate("")},
+		{english, KimiK2Estimator.Estimate(english)},
+		{"12345\n" + han, chinese.Estimate("12345\n" + han)},
+		{english + han, KimiK2Estimator.Rounding.round(KimiK2Estimator.EstimateFloat(english) + chinese.EstimateFloat(han))},
+	} {
+		if got := r.Estimate(tt.text); got != tt.want {
+			t.Errorf("Estimate(%q): expected %d, got %d", tt.text, tt.want, got)
+		}
+	}
+	wantFloat := KimiK2Estimator.EstimateFloat(english) + chinese.EstimateFloat(han)
+	if got := r.EstimateFloat(english + han); math.Abs(got-wantFloat) > 1e-9 {
+		t.Errorf("EstimateFloat: expected %v, got %v", wantFloat, got)
+	}
+
+	if got := NewScriptRouter().PresetFor("Latin"); got != NewEstimator().Key() {
+		t.Errorf("Expected the default estimator, got %s", got)
+	}
+}
@@ -4,135 +4,333 @@
 package tokenestimate
 
 import (
-	"fmt"
+	"sync"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Estimator estimates token counts for text strings using a trained
 // linear regression model based on character classification.
 type Estimator struct {
-	Name             string  // Name of the preset (e.g., "kimi-k2")
-	Description      string  // Description of the preset
-	intercept        float64 // Regression coefficients
-	coefSymbols      float64
-	coefLatinLetters float64
-	coefLatinExt     float64
-	coefDigits       float64
-	coefChinese      float64
-	coefJapanese     float64
-	coefKorean       float64
-	coefRussian      float64
-	coefArabic       float64
-	coefSpaces       float64
+	Name                     string  // Name of the preset (e.g., "kimi-k2")
+	Version                  int     // Version of the preset; 0 means unversioned
+	Description              string  // Description of the preset
+	Deprecated               string  // If non-empty, why the preset is deprecated and what replaces it
+	intercept                float64 // Regression coefficients
+	coefSymbols              float64
+	coefLatinLetters         float64
+	coefLatinExt             float64
+	coefDigits               float64
+	coefChinese              float64
+	coefJapanese             float64
+	coefKorean               float64
+	coefRussian              float64
+	coefArabic               float64
+	coefKhmer                float64
+	coefLao                  float64
+	coefMyanmar              float64
+	coefEthiopic             float64
+	coefSpaces               float64
+	coefTabs                 float64
+	coefWhitespaceRuns       float64
+	coefUnknown              float64
+	coefInvalidBytes         float64
+	coefWords                float64
+	coefDigitRuns            float64
+	coefBlobChars            float64
+	detectBlobs              bool // Whether the model moves base64/hex blobs into Stats.BlobChars
+	coefIdentifierBoundaries float64
+	coefMarkdownHeadings     float64
+	coefMarkdownFences       float64
+	coefMarkdownListItems    float64
+	coefMarkdownTableRows    float64
+	coefMarkdownLinks        float64
+	coefHTMLTags             float64
+	coefHTMLEntities         float64
+	coefJSONStructure        float64
+	coefJSONStructureRuns    float64
+	coefJSONRepeatedKeys     float64
+	coefRepeatedShingles     float64
+	detectRepetition         bool // Whether the model counts word shingles to measure repetitiveness
+	dictionary               Dictionary
+	coefSpecialTokens        float64
+	specialTokens            *specialTokenSet // Special tokens of the model's vocabulary, or nil
+
+	// Residual quantiles: the 10th and 90th percentile of the ratio of
+	// actual to estimated tokens over the preset's evaluation data, or 0
+	// if unknown; see EstimateRange. Those of the kimi-k2 presets are
+	// heuristics rather than measurements.
+	residualP10 float64
+	residualP90 float64
+
+	// Accuracy is the accuracy of a preset fitted by presetgen on
+	// held-out samples of its dataset, or nil if unknown. It is shared
+	// with the clones of the preset and must not be modified.
+	Accuracy *Accuracy
+
+	// Metadata describes how the preset was trained; see
+	// ListPresetsDetailed
+	Metadata Metadata
+
+	// Terms are nonlinear contributions added to the linear model; see
+	// WithTerms. The built-in presets are purely linear.
+	Terms []Term
+
+	// ContentType is the kind of text the coefficients are tuned for
+	// (default: ContentText); see WithContentType
+	ContentType ContentType
+	variants    map[ContentType]*Estimator // Models of the same preset for other content types
+	base        string                     // Registry key of the preset a content-type variant is derived from
+
+	// UnknownPolicy controls how private-use, unassigned and U+FFFD
+	// replacement characters are priced (default: UnknownAsSymbol)
+	UnknownPolicy UnknownPolicy
+
+	// UTF8Mode controls how bytes that are not valid UTF-8 are priced
+	// (default: UTF8Lenient)
+	UTF8Mode UTF8Mode
+
+	// HTMLHandling controls whether HTML markup is stripped, counted or
+	// treated as text (default: HTMLAsText)
+	HTMLHandling HTMLHandling
+
+	// UseDictionary prices common words at their exact token cost from the
+	// preset's dictionary (default: false); see WithDictionary
+	UseDictionary bool
+
+	// Concurrency is the number of goroutines EstimateBatch uses
+	// (default: runtime.GOMAXPROCS(0)); see WithConcurrency
+	Concurrency int
+
+	// ItemOverhead is the number of tokens FitsWithin adds for each text,
+	// such as a separator or per-document framing (default: 0); see
+	// WithItemOverhead
+	ItemOverhead int
+
+	// ChatTemplate gives the tokens EstimateMessages and TrimMessages add
+	// for the framing of chat messages (default: the preset's template)
+	ChatTemplate *ChatTemplate
+
+	// Anomalies logs estimates of unusual texts (default: the hooks of
+	// SetAnomalyHooks); see WithAnomalyHooks
+	Anomalies *AnomalyHooks
 
 	// Sampling configuration
-	EnableSampling    bool // Enable sampling mode for long texts
-	SamplingThreshold int  // Minimum text length to trigger sampling (default: 10000)
-	SamplingSize      int  // Number of characters to sample (default: 1000)
-}
-
-// Predefined estimator presets
-var (
-	// KimiK2Estimator is an estimator trained on Kimi-K2 tokenizer data.
-	// Achieves ~8.5% average relative error.
-	KimiK2Estimator = &Estimator{
-		Name:             "kimi-k2",
-		Description:      "Kimi-K2 tokenizer preset (~8.5% avg error)",
-		intercept:        0.0,
-		coefSymbols:      0.5671194745036742,
-		coefLatinLetters: 0.20601617930567592,
-		coefLatinExt:     5.87908499852652,
-		coefDigits:       0.8030572147361226,
-		coefChinese:      0.6627122076124944,
-		coefJapanese:     1.0879350533022305,
-		coefKorean:       1.0509515625240804,
-		coefRussian:      0.5306900990158002,
-		coefArabic:       0.6352704975749803,
-		coefSpaces:       0.02578661842488973,
-	}
-
-	// presets maps preset names to their estimator instances
-	presets = map[string]*Estimator{
-		"kimi-k2": KimiK2Estimator,
-	}
-)
+	EnableSampling    bool // Enable sampling mode for long texts (default: true in the built-in presets)
+	SamplingThreshold int  // Minimum text length to trigger sampling (default: 1048576)
+	SamplingSize      int  // Number of characters to sample (default: 16384)
+	SamplingBlockSize int  // Length of the contiguous blocks sampled; 0 samples isolated characters (default: 0)
+	SamplingStrategy  SamplingStrategy
+	SamplingSeed      uint64 // Seed of the random sampling strategies
+
+	// SamplingTargetError is the standard error relative to the estimate
+	// at which adaptive sampling stops growing the sample; 0 samples
+	// exactly SamplingSize characters (default: 0). See WithAdaptiveSampling
+	SamplingTargetError float64
+
+	// MaxInputBytes is the length above which input is oversized: it is
+	// sampled, or rejected by the methods that return an error, depending
+	// on InputLimit; 0 means no limit (default: 0). See WithMaxInputBytes
+	MaxInputBytes int
+	InputLimit    InputLimitPolicy
+
+	// MaxTokenBytes is the length in bytes of the longest token in the
+	// vocabulary, which bounds how few tokens a text can take; 0 if
+	// unknown (default: 0)
+	MaxTokenBytes int
+
+	// Rounding controls how the model output is rounded to a token count
+	// (default: RoundNearest); see WithRounding
+	Rounding RoundingMode
+
+	// SafetyMargin and SafetyMinTokens inflate every non-empty estimate by
+	// SafetyMargin times the model output, and at least SafetyMinTokens
+	// tokens (default: 0). See WithSafetyMargin
+	SafetyMargin    float64
+	SafetyMinTokens int
+}
 
 // Stats contains detailed character statistics for a text string.
 type Stats struct {
-	Symbols       int // Count of punctuation and symbols
-	LatinLetters  int // Count of ASCII Latin letters (a-z, A-Z)
-	LatinExtended int // Count of Latin extended letters (à, ñ, ü, etc.)
-	Digits        int // Count of numeric digits (0-9)
-	ChineseChars  int // Count of Chinese (CJK) characters
-	JapaneseKana  int // Count of Japanese Hiragana and Katakana
-	KoreanHangul  int // Count of Korean Hangul
-	RussianChars  int // Count of Russian Cyrillic letters
-	ArabicChars   int // Count of Arabic characters
-	Spaces        int // Count of whitespace characters
+	Symbols        int // Count of punctuation and symbols
+	LatinLetters   int // Count of ASCII Latin letters (a-z, A-Z)
+	LatinExtended  int // Count of Latin extended letters (à, ñ, ü, etc.)
+	Digits         int // Count of numeric digits (0-9)
+	ChineseChars   int // Count of Chinese (CJK) characters
+	JapaneseKana   int // Count of Japanese Hiragana and Katakana
+	KoreanHangul   int // Count of Korean Hangul
+	RussianChars   int // Count of Russian Cyrillic letters
+	ArabicChars    int // Count of Arabic characters
+	KhmerChars     int // Count of Khmer characters
+	LaoChars       int // Count of Lao characters
+	MyanmarChars   int // Count of Myanmar (Burmese) characters
+	EthiopicChars  int // Count of Ethiopic (Ge'ez) characters
+	Spaces         int // Count of whitespace characters other than tabs
+	Tabs           int // Count of tab characters
+	WhitespaceRuns int // Count of runs of two or more consecutive whitespace characters
+	Unknown        int // Count of private-use, unassigned and U+FFFD replacement characters
+	InvalidBytes   int // Count of bytes that are not valid UTF-8
+	Words          int // Count of whitespace-delimited words
+	DigitRuns      int // Count of maximal runs of consecutive digits (numbers)
+	BlobChars      int // Count of characters in base64 or hex encoded blobs
+	// Count of sub-word boundaries inside identifiers: camelCase
+	// transitions and letters following "_" or "." (foo_bar, foo.bar)
+	IdentifierBoundaries int
+	MarkdownHeadings     int // Count of markdown headings ("# Title")
+	MarkdownFences       int // Count of markdown code fence lines ("```")
+	MarkdownListItems    int // Count of markdown list items ("- item", "1. item")
+	MarkdownTableRows    int // Count of markdown table rows ("| a | b |")
+	MarkdownLinks        int // Count of markdown links and images ("[text](url)")
+	HTMLTags             int // Count of HTML tags and comments (only with HTMLCountTags)
+	HTMLEntities         int // Count of HTML character references such as "&amp;" (only with HTMLCountTags)
+	JSONStructure        int // Count of JSON braces, brackets, colons, commas and string quotes (only with ContentJSON)
+	JSONStructureRuns    int // Count of runs of adjacent JSON structural characters such as `":"` (only with ContentJSON)
+	JSONKeys             int // Count of JSON object keys (only with ContentJSON)
+	JSONRepeatedKeys     int // Count of JSON object keys repeating an earlier key (only with ContentJSON)
+	Shingles             int // Count of four-word shingles (only for presets that measure repetition)
+	RepeatedShingles     int // Count of shingles repeating an earlier shingle (only for presets that measure repetition)
+	DictionaryWords      int // Count of words priced from the preset's dictionary (only with UseDictionary)
+	DictionaryChars      int // Count of characters in DictionaryWords
+	DictionaryTokens     int // Exact token cost of DictionaryWords
+	SpecialTokens        int // Count of literal special tokens such as "<|im_end|>"
 }
 
-// NewEstimator creates a new token count estimator with pre-trained coefficients.
-// By default, it returns the Kimi-K2 estimator which achieves ~11% average relative error.
-func NewEstimator() *Estimator {
-	return KimiK2Estimator
+// AvgWordLength returns the average number of non-whitespace characters
+// per word, or 0 if there are no words.
+func (s Stats) AvgWordLength() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return float64(s.chars()-s.Spaces-s.Tabs) / float64(s.Words)
 }
 
-// NewEstimatorWithName creates a new estimator using a preset name.
-// Returns an error if the preset name is not found.
-func NewEstimatorWithName(name string) (*Estimator, error) {
-	estimator, ok := presets[name]
-	if !ok {
-		return nil, fmt.Errorf("unknown preset: %s", name)
+// AvgDigitRunLength returns the average number of digits per run of
+// digits, or 0 if there are no digits.
+func (s Stats) AvgDigitRunLength() float64 {
+	if s.DigitRuns == 0 {
+		return 0
 	}
-	return estimator, nil
+	return float64(s.Digits) / float64(s.DigitRuns)
 }
 
-// ListPresets returns a list of all available preset names.
-func ListPresets() []string {
-	names := make([]string, 0, len(presets))
-	for name := range presets {
-		names = append(names, name)
+// DuplicationRate returns the fraction of shingles that repeat an earlier
+// shingle, or 0 if repetition was not measured.
+func (s Stats) DuplicationRate() float64 {
+	if s.Shingles == 0 {
+		return 0
 	}
-	return names
+	return float64(s.RepeatedShingles) / float64(s.Shingles)
 }
 
-// GetPresetByName returns an estimator preset by name, or an error if not found.
-func GetPresetByName(name string) (*Estimator, error) {
-	estimator, ok := presets[name]
-	if !ok {
-		return nil, fmt.Errorf("unknown preset: %s", name)
-	}
-	return estimator, nil
+// chars returns the number of characters counted in s, with each invalid
+// byte counted as one character. Characters of dictionary words are not
+// included.
+func (s Stats) chars() int {
+	return s.Symbols + s.LatinLetters + s.LatinExtended + s.Digits +
+		s.ChineseChars + s.JapaneseKana + s.KoreanHangul + s.RussianChars +
+		s.ArabicChars + s.KhmerChars + s.LaoChars + s.MyanmarChars +
+		s.EthiopicChars + s.Spaces + s.Tabs + s.Unknown + s.InvalidBytes + s.BlobChars
 }
 
-// RegisterPreset allows users to register custom estimator presets.
-// If an estimator with the same name already exists, it will be overwritten.
-func RegisterPreset(estimator *Estimator) {
-	if estimator.Name != "" {
-		presets[estimator.Name] = estimator
-	}
+// UnknownPolicy selects how an Estimator prices Stats.Unknown characters.
+type UnknownPolicy int
+
+const (
+	// UnknownAsSymbol prices unknown characters like symbols.
+	UnknownAsSymbol UnknownPolicy = iota
+	// UnknownAsCategory prices unknown characters with the preset's
+	// dedicated unknown coefficient.
+	UnknownAsCategory
+	// UnknownWarn prices unknown characters like symbols and logs a warning
+	// the first time a text estimated with the preset contains any.
+	UnknownWarn
+)
+
+// NewEstimator creates a new token count estimator with pre-trained coefficients.
+// By default, it returns the Kimi-K2 estimator which achieves ~11% average relative error;
+// SetDefaultPreset selects another preset.
+// The estimator is a clone of the preset, so changing its fields does not
+// affect other callers.
+func NewEstimator() *Estimator {
+	return defaultEstimator().Clone()
 }
 
 // Clone creates a deep copy of the estimator.
 // This is useful when you want to modify a preset without affecting the original.
 func (e *Estimator) Clone() *Estimator {
 	return &Estimator{
-		Name:              e.Name,
-		Description:       e.Description,
-		intercept:         e.intercept,
-		coefSymbols:       e.coefSymbols,
-		coefLatinLetters:  e.coefLatinLetters,
-		coefLatinExt:      e.coefLatinExt,
-		coefDigits:        e.coefDigits,
-		coefChinese:       e.coefChinese,
-		coefJapanese:      e.coefJapanese,
-		coefKorean:        e.coefKorean,
-		coefRussian:       e.coefRussian,
-		coefArabic:        e.coefArabic,
-		coefSpaces:        e.coefSpaces,
-		EnableSampling:    e.EnableSampling,
-		SamplingThreshold: e.SamplingThreshold,
-		SamplingSize:      e.SamplingSize,
+		Name:                     e.Name,
+		Version:                  e.Version,
+		Description:              e.Description,
+		Deprecated:               e.Deprecated,
+		intercept:                e.intercept,
+		coefSymbols:              e.coefSymbols,
+		coefLatinLetters:         e.coefLatinLetters,
+		coefLatinExt:             e.coefLatinExt,
+		coefDigits:               e.coefDigits,
+		coefChinese:              e.coefChinese,
+		coefJapanese:             e.coefJapanese,
+		coefKorean:               e.coefKorean,
+		coefRussian:              e.coefRussian,
+		coefArabic:               e.coefArabic,
+		coefKhmer:                e.coefKhmer,
+		coefLao:                  e.coefLao,
+		coefMyanmar:              e.coefMyanmar,
+		coefEthiopic:             e.coefEthiopic,
+		coefSpaces:               e.coefSpaces,
+		coefTabs:                 e.coefTabs,
+		coefWhitespaceRuns:       e.coefWhitespaceRuns,
+		coefUnknown:              e.coefUnknown,
+		coefInvalidBytes:         e.coefInvalidBytes,
+		coefWords:                e.coefWords,
+		coefDigitRuns:            e.coefDigitRuns,
+		coefBlobChars:            e.coefBlobChars,
+		detectBlobs:              e.detectBlobs,
+		coefIdentifierBoundaries: e.coefIdentifierBoundaries,
+		coefMarkdownHeadings:     e.coefMarkdownHeadings,
+		coefMarkdownFences:       e.coefMarkdownFences,
+		coefMarkdownListItems:    e.coefMarkdownListItems,
+		coefMarkdownTableRows:    e.coefMarkdownTableRows,
+		coefMarkdownLinks:        e.coefMarkdownLinks,
+		coefHTMLTags:             e.coefHTMLTags,
+		coefHTMLEntities:         e.coefHTMLEntities,
+		coefJSONStructure:        e.coefJSONStructure,
+		coefJSONStructureRuns:    e.coefJSONStructureRuns,
+		coefJSONRepeatedKeys:     e.coefJSONRepeatedKeys,
+		coefRepeatedShingles:     e.coefRepeatedShingles,
+		detectRepetition:         e.detectRepetition,
+		dictionary:               e.dictionary,
+		coefSpecialTokens:        e.coefSpecialTokens,
+		specialTokens:            e.specialTokens,
+		residualP10:              e.residualP10,
+		residualP90:              e.residualP90,
+		Accuracy:                 e.Accuracy,
+		Metadata:                 e.Metadata.clone(),
+		Terms:                    append([]Term(nil), e.Terms...),
+		ContentType:              e.ContentType,
+		variants:                 e.variants,
+		base:                     e.base,
+		UnknownPolicy:            e.UnknownPolicy,
+		UTF8Mode:                 e.UTF8Mode,
+		HTMLHandling:             e.HTMLHandling,
+		UseDictionary:            e.UseDictionary,
+		Concurrency:              e.Concurrency,
+		ItemOverhead:             e.ItemOverhead,
+		ChatTemplate:             e.ChatTemplate,
+		Anomalies:                e.Anomalies,
+		EnableSampling:           e.EnableSampling,
+		SamplingThreshold:        e.SamplingThreshold,
+		SamplingSize:             e.SamplingSize,
+		SamplingBlockSize:        e.SamplingBlockSize,
+		SamplingStrategy:         e.SamplingStrategy,
+		SamplingSeed:             e.SamplingSeed,
+		SamplingTargetError:      e.SamplingTargetError,
+		MaxInputBytes:            e.MaxInputBytes,
+		InputLimit:               e.InputLimit,
+		MaxTokenBytes:            e.MaxTokenBytes,
+		Rounding:                 e.Rounding,
+		SafetyMargin:             e.SafetyMargin,
+		SafetyMinTokens:          e.SafetyMinTokens,
 	}
 }
 
@@ -147,11 +345,66 @@ func (e *Estimator) WithSampling(threshold, sampleSize int) *Estimator {
 	return clone
 }
 
+// WithBlockSampling returns a clone of the estimator with block sampling
+// enabled. Instead of isolated characters, sampleSize characters are taken
+// in contiguous blocks of blockSize spread evenly across the text, which
+// keeps words, numbers and runs intact and represents texts made of large
+// homogeneous sections, such as a code listing followed by Chinese prose,
+// with less variance. sampleSize should be several times blockSize.
+func (e *Estimator) WithBlockSampling(threshold, sampleSize, blockSize int) *Estimator {
+	clone := e.WithSampling(threshold, sampleSize)
+	clone.SamplingBlockSize = blockSize
+	return clone
+}
+
+// WithRounding returns a clone of the estimator that rounds the model
+// output to a token count with the given mode, for example RoundUp for
+// budget enforcement that must not undercount.
+func (e *Estimator) WithRounding(mode RoundingMode) *Estimator {
+	clone := e.Clone()
+	clone.Rounding = mode
+	return clone
+}
+
+// WithUnknownPolicy returns a clone of the estimator using the given policy
+// for unknown characters.
+func (e *Estimator) WithUnknownPolicy(policy UnknownPolicy) *Estimator {
+	clone := e.Clone()
+	clone.UnknownPolicy = policy
+	return clone
+}
+
 // Estimate returns the estimated token count for the given text.
 // This is the main method for quick token estimation.
 func (e *Estimator) Estimate(text string) int {
+	if h := e.anomalyHooks(); h != nil {
+		stats, sampled := e.analyzeSampled(text)
+		tokens := e.EstimateFromStats(stats)
+		h.check(e, text, stats, sampled, float64(tokens))
+		return tokens
+	}
 	stats := e.Analyze(text)
-	return e.estimateFromStats(stats)
+	return e.EstimateFromStats(stats)
+}
+
+// EstimateFloat returns the model output for text before it is rounded to
+// a token count, for analytics that aggregate estimates over many texts.
+// Negative outputs are clamped to zero.
+func (e *Estimator) EstimateFloat(text string) float64 {
+	h := e.anomalyHooks()
+	var stats Stats
+	sampled := 0
+	if h != nil {
+		stats, sampled = e.analyzeSampled(text)
+	} else {
+		stats = e.Analyze(text)
+	}
+	e.warnUnknown(stats)
+	tokens := max(e.withMargin(e.calculateTokenCount(stats)), 0)
+	if h != nil {
+		h.check(e, text, stats, sampled, tokens)
+	}
+	return tokens
 }
 
 // Analyze analyzes the text and returns detailed character statistics.
@@ -159,141 +412,366 @@ func (e *Estimator) Estimate(text string) int {
 // If EnableSampling is true and text length exceeds SamplingThreshold,
 // it will use sampling mode for better performance.
 func (e *Estimator) Analyze(text string) Stats {
-	// Check if we should use sampling mode
-	textLen := len([]rune(text))
-	if e.EnableSampling && e.SamplingThreshold > 0 && e.SamplingSize > 0 && textLen > e.SamplingThreshold {
-		return e.analyzeSampling(text, textLen)
+	var stats Stats
+	e.AnalyzeInto(text, &stats)
+	return stats
+}
+
+// AnalyzeInto is like Analyze but stores the statistics in out, for hot
+// paths that reuse a Stats value. It does not allocate, except when
+// HTMLHandling is HTMLStripTags, which builds a stripped copy of the text,
+// or when the text is long enough to be sampled, which allocates the
+// sample positions.
+func (e *Estimator) AnalyzeInto(text string, out *Stats) {
+	e.analyze(text, out, nil, nil)
+}
+
+// analyze implements AnalyzeInto. When the text is sampled and detail is
+// not nil, it also stores the sampling result in detail. Streams pass the
+// JSON keys of their earlier chunks in keys; other callers pass nil.
+func (e *Estimator) analyze(text string, out *Stats, detail *sampleResult, keys *jsonKeys) {
+	oversized := e.oversized(len(text))
+	if e.HTMLHandling == HTMLStripTags {
+		text = stripHTML(text)
+	}
+
+	// Check if we should use sampling mode; counting the runes is only
+	// needed when it is enabled or the input is oversized, which is
+	// sampled regardless.
+	sampling := false
+	textLen := 0
+	if e.EnableSampling && e.SamplingThreshold > 0 && e.SamplingSize > 0 && len(text) > e.SamplingThreshold {
+		textLen = utf8.RuneCountInString(text)
+		sampling = textLen > e.SamplingThreshold
+	}
+	if !sampling && oversized {
+		textLen = utf8.RuneCountInString(text)
+		sampling = textLen > e.samplingSize()
+	}
+
+	var stats Stats
+	if sampling {
+		r := e.analyzeSampling(text, textLen)
+		stats = r.stats
+		if detail != nil {
+			*detail = r
+		}
+	} else {
+		// Full analysis mode
+		stats = e.analyzeFull(text)
+		if e.UseDictionary && e.dictionary != nil {
+			applyDictionary(text, e.dictionary, &stats)
+		}
 	}
 
-	// Full analysis mode
-	return e.analyzeFull(text)
+	if e.HTMLHandling == HTMLCountTags {
+		stats.HTMLTags, stats.HTMLEntities = countHTML(text)
+	}
+	if e.ContentType == ContentJSON {
+		js := scanJSON(text, keys)
+		stats.JSONStructure = js.structure
+		stats.JSONStructureRuns = js.runs
+		stats.JSONKeys = js.keys
+		stats.JSONRepeatedKeys = js.repeatedKeys
+	}
+	if e.detectRepetition {
+		stats.Shingles, stats.RepeatedShingles = scanRepetition(text)
+	}
+	*out = stats
 }
 
 // analyzeFull performs full character-by-character analysis
 func (e *Estimator) analyzeFull(text string) Stats {
-	stats := Stats{}
-
-	for _, r := range text {
-		switch {
-		case unicode.IsLetter(r) && r < 128:
-			// Latin letters (ASCII)
-			stats.LatinLetters++
-		case isLatinExtended(r):
-			stats.LatinExtended++
-		case unicode.IsDigit(r):
-			stats.Digits++
-		case isJapaneseKana(r):
-			stats.JapaneseKana++
-		case isKoreanHangul(r):
-			stats.KoreanHangul++
-		case isChinese(r):
-			stats.ChineseChars++
-		case isRussian(r):
-			stats.RussianChars++
-		case isArabic(r):
-			stats.ArabicChars++
-		case isSymbol(r):
-			stats.Symbols++
-		case unicode.IsSpace(r):
-			stats.Spaces++
-		default:
-			// treat other chars as symbols
-			stats.Symbols++
+	a := e.newAnalyzer()
+	for i := 0; i < len(text); {
+		// Lowercase letters inside a word are skipped a word at a time.
+		if isASCIILetter(a.prev) {
+			if n, nonHex := lowerRun(text[i:]); n > 0 {
+				a.addLowerRun(text[i:i+n], nonHex)
+				i += n
+				continue
+			}
 		}
+
+		r, size := rune(text[i]), 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRuneInString(text[i:])
+		}
+		if r == '<' && a.special != nil {
+			if n := a.special.match(text[i:]); n > 0 {
+				a.addSpecial()
+				i += n
+				continue
+			}
+		}
+		if r == utf8.RuneError && size == 1 {
+			a.addInvalid()
+			i++
+			continue
+		}
+		a.add(r)
+		i += size
 	}
+	a.finish()
+	return a.stats
+}
 
-	// prevent too many latin ext
-	if adj := (stats.LatinExtended - stats.LatinLetters/15); adj > 0 {
-		stats.Symbols += adj
-		stats.LatinExtended -= adj
+// analyzeSampling performs sampling-based analysis for long texts of
+// textLen characters
+func (e *Estimator) analyzeSampling(text string, textLen int) sampleResult {
+	sampleSize := min(e.samplingSize(), textLen)
+
+	// With a target error, keep doubling the sample until the estimate is
+	// precise enough or covers the whole text
+	for {
+		r := e.sample(text, textLen, sampleSize)
+		if e.SamplingTargetError <= 0 || sampleSize == textLen || r.relErr() <= e.SamplingTargetError {
+			return r
+		}
+		sampleSize = min(2*sampleSize, textLen)
 	}
+}
 
-	return stats
+// analyzer accumulates Stats one rune at a time. Besides the per-rune
+// category counts it keeps a little context about the preceding runes so
+// that run-based features can be counted in a single pass.
+type analyzer struct {
+	stats     Stats
+	wsRun     int  // length of the whitespace run ending at the previous rune
+	inWord    bool // whether the previous rune belongs to a word
+	prevDigit bool // whether the previous rune is a digit
+	prev      rune // the previous rune, or 0 at the start of the text
+	prev2     rune // the rune before prev, or 0
+	md        markdownLine
+
+	detectBlobs bool    // whether to move base64/hex blobs into BlobChars
+	blob        blobRun // the run of blob alphabet characters ending at the previous rune
+
+	special *specialTokenSet // special tokens to count as single tokens, or nil
 }
 
-// analyzeSampling performs sampling-based analysis for long texts
-func (e *Estimator) analyzeSampling(text string, textLen int) Stats {
-	runes := []rune(text)
-	sampleSize := e.SamplingSize
-	if sampleSize > textLen {
-		sampleSize = textLen
-	}
-
-	// Calculate sampling interval
-	interval := textLen / sampleSize
-	if interval < 1 {
-		interval = 1
-	}
-
-	// Sample characters evenly distributed across the text
-	sampledStats := Stats{}
-	for i := 0; i < sampleSize && i*interval < textLen; i++ {
-		r := runes[i*interval]
-
-		switch {
-		case unicode.IsLetter(r) && r < 128:
-			sampledStats.LatinLetters++
-		case isLatinExtended(r):
-			sampledStats.LatinExtended++
-		case unicode.IsDigit(r):
-			sampledStats.Digits++
-		case isJapaneseKana(r):
-			sampledStats.JapaneseKana++
-		case isKoreanHangul(r):
-			sampledStats.KoreanHangul++
-		case isChinese(r):
-			sampledStats.ChineseChars++
-		case isRussian(r):
-			sampledStats.RussianChars++
-		case isArabic(r):
-			sampledStats.ArabicChars++
-		case isSymbol(r):
-			sampledStats.Symbols++
-		case unicode.IsSpace(r):
-			sampledStats.Spaces++
-		default:
-			sampledStats.Symbols++
+// newAnalyzer returns an analyzer configured for the estimator's model.
+func (e *Estimator) newAnalyzer() analyzer {
+	return analyzer{detectBlobs: e.detectBlobs, special: e.specialTokens}
+}
+
+// add classifies r and updates the statistics.
+func (a *analyzer) add(r rune) {
+	stats := &a.stats
+	digit, digitRunStart := false, false
+
+	if unicode.IsSpace(r) {
+		a.wsRun++
+		// A run is counted once, when it reaches two characters; single
+		// spaces between words merge into the following word's token.
+		if a.wsRun == 2 {
+			stats.WhitespaceRuns++
+		}
+		a.inWord = false
+	} else {
+		a.nonSpace()
+	}
+
+	switch classify(r) {
+	case CategoryLatin:
+		stats.LatinLetters++
+	case CategoryLatinExtended:
+		stats.LatinExtended++
+	case CategoryDigit:
+		stats.Digits++
+		if !a.prevDigit {
+			stats.DigitRuns++
+			digitRunStart = true
+		}
+		digit = true
+	case CategoryJapanese:
+		stats.JapaneseKana++
+	case CategoryKorean:
+		stats.KoreanHangul++
+	case CategoryChinese:
+		stats.ChineseChars++
+	case CategoryRussian:
+		stats.RussianChars++
+	case CategoryArabic:
+		stats.ArabicChars++
+	case CategoryKhmer:
+		stats.KhmerChars++
+	case CategoryLao:
+		stats.LaoChars++
+	case CategoryMyanmar:
+		stats.MyanmarChars++
+	case CategoryEthiopic:
+		stats.EthiopicChars++
+	case CategoryTab:
+		stats.Tabs++
+	case CategorySpace:
+		stats.Spaces++
+	case CategoryUnknown:
+		stats.Unknown++
+	default:
+		stats.Symbols++
+	}
+	a.prevDigit = digit
+
+	switch a.md.step(r) {
+	case mdHeading:
+		stats.MarkdownHeadings++
+	case mdFence:
+		stats.MarkdownFences++
+	case mdListItem:
+		stats.MarkdownListItems++
+	case mdTableRow:
+		stats.MarkdownTableRows++
+	case mdLink:
+		stats.MarkdownLinks++
+	}
+
+	identBoundary := isIdentifierBoundary(a.prev2, a.prev, r)
+	if identBoundary {
+		stats.IdentifierBoundaries++
+	}
+	a.prev2, a.prev = a.prev, r
+
+	if a.detectBlobs {
+		if isBlobAlphabet(r) {
+			a.blob.add(r, digitRunStart, identBoundary)
+		} else {
+			a.flushBlob()
 		}
 	}
+}
+
+// addInvalid records a byte that is not valid UTF-8.
+func (a *analyzer) addInvalid() {
+	a.nonSpace()
+	a.prevDigit = false
+	a.prev2, a.prev = a.prev, utf8.RuneError
+	a.md.step(utf8.RuneError)
+	a.flushBlob()
+	a.stats.InvalidBytes++
+}
+
+// addSpecial records a literal special token.
+func (a *analyzer) addSpecial() {
+	a.nonSpace()
+	a.prevDigit = false
+	a.prev2, a.prev = '|', '>'
+	a.md.step('>')
+	a.flushBlob()
+	a.stats.SpecialTokens++
+}
 
-	// Scale up the sampled statistics to the full text length
-	scaleFactor := float64(textLen) / float64(sampleSize)
+// addBlob records a rune known to lie inside a blob. It is used by sampling
+// mode, which decides blob membership by looking around the sampled rune.
+func (a *analyzer) addBlob() {
+	a.nonSpace()
+	a.prevDigit = false
+	a.prev2, a.prev = 0, 0
+	a.stats.BlobChars++
+}
+
+// flushBlob ends the current run of blob alphabet characters, moving its
+// counts to BlobChars if it is a blob.
+func (a *analyzer) flushBlob() {
+	if a.blob.isBlob() {
+		stats := &a.stats
+		stats.LatinLetters -= a.blob.letters
+		stats.Digits -= a.blob.digits
+		stats.Symbols -= a.blob.symbols
+		stats.DigitRuns -= a.blob.digitRuns
+		stats.IdentifierBoundaries -= a.blob.idents
+		stats.BlobChars += a.blob.length
+	}
+	a.blob = blobRun{}
+}
+
+// nonSpace updates the run context for a rune that is not whitespace.
+func (a *analyzer) nonSpace() {
+	a.wsRun = 0
+	if !a.inWord {
+		a.stats.Words++
+		a.inWord = true
+	}
+}
 
-	stats := Stats{
-		Symbols:       int(float64(sampledStats.Symbols)*scaleFactor + 0.5),
-		LatinLetters:  int(float64(sampledStats.LatinLetters)*scaleFactor + 0.5),
-		LatinExtended: int(float64(sampledStats.LatinExtended)*scaleFactor + 0.5),
-		Digits:        int(float64(sampledStats.Digits)*scaleFactor + 0.5),
-		ChineseChars:  int(float64(sampledStats.ChineseChars)*scaleFactor + 0.5),
-		JapaneseKana:  int(float64(sampledStats.JapaneseKana)*scaleFactor + 0.5),
-		KoreanHangul:  int(float64(sampledStats.KoreanHangul)*scaleFactor + 0.5),
-		RussianChars:  int(float64(sampledStats.RussianChars)*scaleFactor + 0.5),
-		ArabicChars:   int(float64(sampledStats.ArabicChars)*scaleFactor + 0.5),
-		Spaces:        int(float64(sampledStats.Spaces)*scaleFactor + 0.5),
+// seek restores the context the analyzer would have after reading
+// text[:i], looking back only as far as the run-based features need.
+// It is used by sampling mode, where runes are visited out of sequence.
+func (a *analyzer) seek(text string, i int) {
+	a.blob = blobRun{} // blob membership is decided by inBlob instead
+	a.prev, a.prev2 = 0, 0
+	if i > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:i])
+		a.prev = r
+		if i > size {
+			a.prev2, _ = utf8.DecodeLastRuneInString(text[:i-size])
+		}
+	}
+	a.inWord = i > 0 && !unicode.IsSpace(a.prev)
+	a.prevDigit = i > 0 && unicode.IsDigit(a.prev)
+	a.md = seekMarkdown(text, i)
+	a.wsRun = 0
+	for j := i; j > 0 && a.wsRun < 2; {
+		r, size := utf8.DecodeLastRuneInString(text[:j])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		a.wsRun++
+		j -= size
 	}
+}
+
+// finish applies corrections that depend on the complete statistics.
+func (a *analyzer) finish() {
+	a.flushBlob()
+	adjustLatinExtended(&a.stats)
+}
 
-	// prevent too many latin ext
+// adjustLatinExtended prevents too many latin ext: accented letters far in
+// excess of plain Latin letters are almost always symbol-like noise rather
+// than European prose.
+func adjustLatinExtended(stats *Stats) {
 	if adj := (stats.LatinExtended - stats.LatinLetters/15); adj > 0 {
 		stats.Symbols += adj
 		stats.LatinExtended -= adj
 	}
+}
 
-	return stats
+// EstimateFromStats calculates the estimated token count from pre-computed statistics.
+// This is useful when you already have the character statistics, for example
+// running totals maintained with Stats.Add and Stats.Sub.
+func (e *Estimator) EstimateFromStats(stats Stats) int {
+	e.warnUnknown(stats)
+	return e.roundTokens(e.calculateTokenCount(stats))
 }
 
-// estimateFromStats calculates the estimated token count from pre-computed statistics.
-// This is useful when you already have the character statistics.
-func (e *Estimator) estimateFromStats(stats Stats) int {
-	count := e.calculateTokenCount(stats)
-	if count < 0 {
-		return 0
+// unknownWarned records which presets have already reported unknown
+// characters, so each is logged only once per process.
+var unknownWarned sync.Map
+
+// warnUnknown logs the unknown characters of stats under UnknownWarn.
+func (e *Estimator) warnUnknown(stats Stats) {
+	if e.UnknownPolicy == UnknownWarn && stats.Unknown > 0 {
+		key := e.Key()
+		if _, warned := unknownWarned.LoadOrStore(key, true); !warned {
+			getLogger().Warn("tokenestimate: unknown characters",
+				"preset", key, "count", stats.Unknown)
+		}
 	}
-	return int(count + 0.5) // Round to nearest integer
 }
 
-// calculateTokenCount applies the linear regression formula to compute token count.
+// roundTokens adds the safety margin to a model output and rounds it to a
+// token count with the estimator's rounding mode, clamping negative
+// outputs to zero.
+func (e *Estimator) roundTokens(count float64) int {
+	return e.Rounding.round(e.withMargin(count))
+}
+
+// calculateTokenCount applies the linear regression formula, plus any
+// nonlinear Terms, to compute token count, clamped to the counts the text
+// could possibly take.
 func (e *Estimator) calculateTokenCount(stats Stats) float64 {
-	return e.intercept +
+	total := e.intercept +
 		e.coefSymbols*float64(stats.Symbols) +
 		e.coefLatinLetters*float64(stats.LatinLetters) +
 		e.coefLatinExt*float64(stats.LatinExtended) +
@@ -303,66 +781,71 @@ func (e *Estimator) calculateTokenCount(stats Stats) float64 {
 		e.coefKorean*float64(stats.KoreanHangul) +
 		e.coefRussian*float64(stats.RussianChars) +
 		e.coefArabic*float64(stats.ArabicChars) +
-		e.coefSpaces*float64(stats.Spaces)
-}
-
-// isJapaneseKana checks if a rune is Japanese Hiragana or Katakana.
-func isJapaneseKana(r rune) bool {
-	return (r >= 0x3040 && r <= 0x309F) || // Hiragana
-		(r >= 0x30A0 && r <= 0x30FF) // Katakana
-}
-
-// isLatinExtended checks if a rune is a Latin extended letter (non-ASCII Latin).
-func isLatinExtended(r rune) bool {
-	return (r >= 0x00C0 && r <= 0x00FF) || // Latin-1 Supplement (à, ñ, ü, etc.)
-		(r >= 0x0100 && r <= 0x017F) || // Latin Extended-A (ā, ē, œ, etc.)
-		(r >= 0x0180 && r <= 0x024F) || // Latin Extended-B
-		(r >= 0x1E00 && r <= 0x1EFF) // Latin Extended Additional
-}
-
-// isKoreanHangul checks if a rune is Korean Hangul.
-func isKoreanHangul(r rune) bool {
-	return (r >= 0xAC00 && r <= 0xD7AF) || // Hangul Syllables
-		(r >= 0x1100 && r <= 0x11FF) || // Hangul Jamo
-		(r >= 0x3130 && r <= 0x318F) || // Hangul Compatibility Jamo
-		(r >= 0xA960 && r <= 0xA97F) || // Hangul Jamo Extended-A
-		(r >= 0xD7B0 && r <= 0xD7FF) // Hangul Jamo Extended-B
-}
-
-// isChinese checks if a rune is a CJK (Chinese) character,
-func isChinese(r rune) bool {
-	return (r >= 0x4E00 && r <= 0x9FFF) || // CJK Unified Ideographs
-		(r >= 0x3400 && r <= 0x4DBF) || // CJK Extension A
-		(r >= 0x20000 && r <= 0x2A6DF) || // CJK Extension B
-		(r >= 0x2A700 && r <= 0x2B73F) || // CJK Extension C
-		(r >= 0x2B740 && r <= 0x2B81F) || // CJK Extension D
-		(r >= 0x2B820 && r <= 0x2CEAF) || // CJK Extension E
-		(r >= 0x2CEB0 && r <= 0x2EBEF) || // CJK Extension F
-		(r >= 0x30000 && r <= 0x3134F) // CJK Extension G
-}
-
-// isSymbol checks if a rune is an ASCII punctuation or symbol.
-func isSymbol(r rune) bool {
-	return (r >= 0x21 && r <= 0x2F) || // !"#$%&'()*+,-./
-		(r >= 0x3A && r <= 0x40) || // :;<=>?@
-		(r >= 0x5B && r <= 0x60) || // [\]^_`
-		(r >= 0x7B && r <= 0x7E) // {|}~
-}
-
-// isArabic checks if a rune is an Arabic character.
-func isArabic(r rune) bool {
-	return (r >= 0x0600 && r <= 0x06FF) || // Arabic
-		(r >= 0x0750 && r <= 0x077F) || // Arabic Supplement
-		(r >= 0x08A0 && r <= 0x08FF) || // Arabic Extended-A
-		(r >= 0xFB50 && r <= 0xFDFF) || // Arabic Presentation Forms-A
-		(r >= 0xFE70 && r <= 0xFEFF) // Arabic Presentation Forms-B
-}
-
-// isRussian checks if a rune is a Russian Cyrillic character.
-func isRussian(r rune) bool {
-	return (r >= 0x0400 && r <= 0x04FF) || // Cyrillic
-		(r >= 0x0500 && r <= 0x052F) || // Cyrillic Supplement
-		(r >= 0x2DE0 && r <= 0x2DFF) || // Cyrillic Extended-A
-		(r >= 0xA640 && r <= 0xA69F) || // Cyrillic Extended-B
-		(r >= 0x1C80 && r <= 0x1C8F) // Cyrillic Extended-C
+		e.coefKhmer*float64(stats.KhmerChars) +
+		e.coefLao*float64(stats.LaoChars) +
+		e.coefMyanmar*float64(stats.MyanmarChars) +
+		e.coefEthiopic*float64(stats.EthiopicChars) +
+		e.coefSpaces*float64(stats.Spaces) +
+		e.coefTabs*float64(stats.Tabs) +
+		e.coefWhitespaceRuns*float64(stats.WhitespaceRuns) +
+		e.unknownCoef()*float64(stats.Unknown) +
+		e.invalidBytesCoef()*float64(stats.InvalidBytes) +
+		e.coefWords*float64(stats.Words) +
+		e.coefDigitRuns*float64(stats.DigitRuns) +
+		e.coefBlobChars*float64(stats.BlobChars) +
+		e.coefIdentifierBoundaries*float64(stats.IdentifierBoundaries) +
+		e.coefMarkdownHeadings*float64(stats.MarkdownHeadings) +
+		e.coefMarkdownFences*float64(stats.MarkdownFences) +
+		e.coefMarkdownListItems*float64(stats.MarkdownListItems) +
+		e.coefMarkdownTableRows*float64(stats.MarkdownTableRows) +
+		e.coefMarkdownLinks*float64(stats.MarkdownLinks) +
+		e.coefHTMLTags*float64(stats.HTMLTags) +
+		e.coefHTMLEntities*float64(stats.HTMLEntities) +
+		e.coefJSONStructure*float64(stats.JSONStructure) +
+		e.coefJSONStructureRuns*float64(stats.JSONStructureRuns) +
+		e.coefJSONRepeatedKeys*float64(stats.JSONRepeatedKeys) +
+		e.coefRepeatedShingles*float64(stats.RepeatedShingles) +
+		float64(stats.DictionaryTokens) +
+		e.coefSpecialTokens*float64(stats.SpecialTokens)
+
+	for _, term := range e.Terms {
+		total += term.value(&stats)
+	}
+	return e.clampTokens(&stats, total)
+}
+
+// unknownCoef returns the coefficient applied to Stats.Unknown under the
+// estimator's UnknownPolicy.
+func (e *Estimator) unknownCoef() float64 {
+	if e.UnknownPolicy == UnknownAsCategory {
+		return e.coefUnknown
+	}
+	return e.coefSymbols
+}
+
+// invalidBytesCoef returns the coefficient applied to Stats.InvalidBytes
+// under the estimator's UTF8Mode.
+func (e *Estimator) invalidBytesCoef() float64 {
+	if e.UTF8Mode == UTF8ByteFallback {
+		return e.coefInvalidBytes
+	}
+	return e.unknownCoef()
+}
+
+// isIdentifierBoundary reports whether r starts a new sub-word inside an
+// identifier, given the two runes before it: a lower-to-upper case
+// transition, or a letter after "_" or "." that follows a letter or digit.
+func isIdentifierBoundary(prev2, prev, r rune) bool {
+	if !isASCIILetter(r) {
+		return false
+	}
+	if prev >= 'a' && prev <= 'z' && r >= 'A' && r <= 'Z' {
+		return true
+	}
+	return (prev == '_' || prev == '.') && (isASCIILetter(prev2) || (prev2 >= '0' && prev2 <= '9'))
+}
+
+// isASCIILetter checks if a rune is an ASCII letter.
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
 }
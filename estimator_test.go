@@ -2,10 +2,14 @@ package tokenestimate
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"log/slog"
 	"math"
 	"os"
+	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 const (
@@ -88,6 +92,7 @@ func TestEstimator_Analyze(t *testing.T) {
 			text: "Hello",
 			expected: Stats{
 				LatinLetters: 5,
+				Words:        1,
 			},
 		},
 		{
@@ -99,6 +104,8 @@ func TestEstimator_Analyze(t *testing.T) {
 				ChineseChars: 2,
 				Digits:       3,
 				Spaces:       2,
+				Words:        3,
+				DigitRuns:    1,
 			},
 		},
 		{
@@ -107,6 +114,83 @@ func TestEstimator_Analyze(t *testing.T) {
 			expected: Stats{
 				Symbols: 6,
 				Spaces:  1,
+				Words:   2,
+			},
+		},
+		{
+			name: "Southeast Asian scripts",
+			text: "ខ្មែរ ລາວ မြန်မာ",
+			expected: Stats{
+				KhmerChars:   5,
+				LaoChars:     3,
+				MyanmarChars: 6,
+				Spaces:       2,
+				Words:        3,
+			},
+		},
+		{
+			name: "Ethiopic",
+			text: "ሰላም ለዓለም።",
+			expected: Stats{
+				EthiopicChars: 8,
+				Spaces:        1,
+				Words:         2,
+			},
+		},
+		{
+			name: "Unknown characters",
+			text: "a\uE000\uFFFD\U0010FFFD",
+			expected: Stats{
+				LatinLetters: 1,
+				Unknown:      3,
+				Words:        1,
+			},
+		},
+		{
+			name: "Numbers",
+			text: "1234567890123456 2024-01-15",
+			expected: Stats{
+				Digits:    24,
+				DigitRuns: 4,
+				Symbols:   2,
+				Spaces:    1,
+				Words:     2,
+			},
+		},
+		{
+			name: "Identifiers",
+			text: "foo_bar.baz.QuxHandler() e.g. 3.14",
+			expected: Stats{
+				LatinLetters:         21,
+				Symbols:              8,
+				Digits:               3,
+				DigitRuns:            2,
+				Spaces:               2,
+				Words:                3,
+				IdentifierBoundaries: 5, // _b .b .Q xH .g
+			},
+		},
+		{
+			name: "Indented code",
+			text: "if x:\n    return x\n",
+			expected: Stats{
+				LatinLetters:   10,
+				Symbols:        1,
+				Spaces:         8,
+				WhitespaceRuns: 1, // "\n    "; the single space and trailing newline don't count
+				Words:          4,
+			},
+		},
+		{
+			name: "Tab indented code",
+			text: "if x {\n\t\treturn x\n}",
+			expected: Stats{
+				LatinLetters:   10,
+				Symbols:        2,
+				Spaces:         5,
+				Tabs:           2,
+				WhitespaceRuns: 1, // "\n\t\t"
+				Words:          6,
 			},
 		},
 	}
@@ -121,6 +205,39 @@ func TestEstimator_Analyze(t *testing.T) {
 	}
 }
 
+func TestStats_AvgWordLength(t *testing.T) {
+	estimator := NewEstimator()
+
+	tests := []struct {
+		text     string
+		expected float64
+	}{
+		{"", 0},
+		{"   ", 0},
+		{"a bb ccc", 2},
+		{"internationalization is hard", 26.0 / 3},
+		{"你好 世界！", 2.5},
+	}
+
+	for _, tt := range tests {
+		if got := estimator.Analyze(tt.text).AvgWordLength(); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("AvgWordLength(%q) = %v, want %v", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestEstimator_LongNumbers(t *testing.T) {
+	estimator := NewEstimator()
+
+	// A 16-digit number is split into chunks of up to three digits
+	if got := estimator.Estimate("1234567890123456"); got != 6 {
+		t.Errorf("Estimate of a 16-digit number = %d, want 6", got)
+	}
+	if got := estimator.Analyze("12 345 6").AvgDigitRunLength(); got != 2 {
+		t.Errorf("AvgDigitRunLength = %v, want 2", got)
+	}
+}
+
 func TestEstimator_EstimateFromStats(t *testing.T) {
 	estimator := NewEstimator()
 
@@ -130,7 +247,7 @@ func TestEstimator_EstimateFromStats(t *testing.T) {
 		Symbols:      1,
 	}
 
-	result := estimator.estimateFromStats(stats)
+	result := estimator.EstimateFromStats(stats)
 	if result < 0 {
 		t.Errorf("EstimateFromStats should not return negative values, got %d", result)
 	}
@@ -259,13 +376,17 @@ func TestEstimator_TestDataset(t *testing.T) {
 
 // TestPresetSystem tests the preset system functionality
 func TestPresetSystem(t *testing.T) {
-	t.Run("NewEstimator returns KimiK2Estimator", func(t *testing.T) {
+	t.Run("NewEstimator returns a clone of KimiK2Estimator", func(t *testing.T) {
 		estimator := NewEstimator()
 		if estimator.Name != "kimi-k2" {
 			t.Errorf("Expected default estimator name 'kimi-k2', got %q", estimator.Name)
 		}
-		if estimator != KimiK2Estimator {
-			t.Error("Expected NewEstimator to return KimiK2Estimator")
+		if estimator == KimiK2Estimator {
+			t.Error("Expected NewEstimator to return a clone of KimiK2Estimator")
+		}
+		estimator.EnableSampling = false
+		if !KimiK2Estimator.EnableSampling || !NewEstimator().EnableSampling {
+			t.Error("Expected changes to the estimator not to affect the preset")
 		}
 	})
 
@@ -286,8 +407,8 @@ func TestPresetSystem(t *testing.T) {
 		if estimator == nil {
 			t.Fatal("Expected non-nil estimator")
 		}
-		if estimator != KimiK2Estimator {
-			t.Error("Expected to get KimiK2Estimator")
+		if estimator == KimiK2Estimator || estimator.Key() != KimiK2Estimator.Key() {
+			t.Error("Expected to get a clone of KimiK2Estimator")
 		}
 	})
 
@@ -349,7 +470,9 @@ func TestPresetSystem(t *testing.T) {
 			coefChinese:      0.6,
 			coefSpaces:       0.1,
 		}
-		RegisterPreset(customEstimator)
+		if err := RegisterPreset(customEstimator); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
 
 		// Verify it was registered
 		estimator, err := GetPresetByName("custom-test")
@@ -368,8 +491,8 @@ func TestPresetSystem(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
-		if estimator2 != customEstimator {
-			t.Error("Expected to get the same estimator instance")
+		if estimator2 == customEstimator || estimator2.Name != "custom-test" {
+			t.Error("Expected to get a clone of the estimator")
 		}
 	})
 
@@ -413,6 +536,53 @@ func TestPresetSystem(t *testing.T) {
 	})
 }
 
+// TestUnknownPolicy tests the pricing of private-use and replacement characters
+func TestUnknownPolicy(t *testing.T) {
+	text := strings.Repeat("\uE000\uFFFD", 10)
+
+	t.Run("Default prices unknown like symbols", func(t *testing.T) {
+		estimator := NewEstimator()
+		if estimator.UnknownPolicy != UnknownAsSymbol {
+			t.Errorf("Expected default policy UnknownAsSymbol, got %v", estimator.UnknownPolicy)
+		}
+		symbols := estimator.Estimate(strings.Repeat("!", 20))
+		if got := estimator.Estimate(text); got != symbols {
+			t.Errorf("Expected %d tokens like 20 symbols, got %d", symbols, got)
+		}
+	})
+
+	t.Run("Dedicated category", func(t *testing.T) {
+		estimator := NewEstimator().WithUnknownPolicy(UnknownAsCategory)
+		want := int(estimator.coefUnknown*20 + 0.5)
+		if got := estimator.Estimate(text); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Warn logs unknown count once", func(t *testing.T) {
+		var buf bytes.Buffer
+		SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+		defer logger.Store(nil)
+
+		unknownWarned.Clear()
+		defer unknownWarned.Clear()
+
+		estimator := NewEstimator().WithUnknownPolicy(UnknownWarn)
+		estimator.Estimate("plain text")
+		if buf.Len() != 0 {
+			t.Errorf("Expected no warning for plain text, got %s", buf.String())
+		}
+		estimator.Estimate(text)
+		if !strings.Contains(buf.String(), "count=20") {
+			t.Errorf("Expected warning with count=20, got %s", buf.String())
+		}
+		estimator.Estimate(text)
+		if n := strings.Count(buf.String(), "unknown characters"); n != 1 {
+			t.Errorf("Expected one warning per preset, got %d:\n%s", n, buf.String())
+		}
+	})
+}
+
 // TestSamplingMode tests the sampling mode for long texts
 func TestSamplingMode(t *testing.T) {
 	t.Run("Short text doesn't trigger sampling", func(t *testing.T) {
@@ -427,6 +597,8 @@ func TestSamplingMode(t *testing.T) {
 			Spaces:       3,
 			ChineseChars: 4,
 			Digits:       3,
+			Words:        4,
+			DigitRuns:    1,
 		}
 
 		if stats != expectedStats {
@@ -490,10 +662,80 @@ func TestSamplingMode(t *testing.T) {
 		}
 	})
 
-	t.Run("Sampling disabled by default", func(t *testing.T) {
+	t.Run("Sampling counts whitespace runs", func(t *testing.T) {
+		estimator := NewEstimator().WithSampling(100, 70)
+
+		// 100 words each followed by three spaces of indentation (500 chars);
+		// the sampling interval of 7 is coprime to the period of 5
+		longText := ""
+		for i := 0; i < 100; i++ {
+			longText += "xy   "
+		}
+
+		stats := estimator.Analyze(longText)
+		if stats.WhitespaceRuns < 80 || stats.WhitespaceRuns > 120 {
+			t.Errorf("Expected around 100 whitespace runs, got %d", stats.WhitespaceRuns)
+		}
+	})
+
+	t.Run("Blocks covering the text match full analysis", func(t *testing.T) {
+		text := strings.Repeat("func main() {\n\tx := 42 // answer\n}\n", 20) +
+			"<|im_end|>" + strings.Repeat("你好，世界。Hello 123!  ", 30)
+		for utf8.RuneCountInString(text)%4 != 0 {
+			text += "x"
+		}
+		n := utf8.RuneCountInString(text)
+
+		sampled := NewEstimator().WithBlockSampling(100, n, n/4).Analyze(text)
+		if full := NewEstimator().Analyze(text); sampled != full {
+			t.Errorf("Block sampling of every character = %+v, want %+v", sampled, full)
+		}
+	})
+
+	t.Run("Block sampling on sectioned text", func(t *testing.T) {
+		text := strings.Repeat("for i := 0; i < n; i++ {\n\tsum += values[i]\n}\n", 200) +
+			strings.Repeat("今天天气很好，我们一起去公园散步吧。", 500)
+		full := NewEstimator().Estimate(text)
+		sampled := NewEstimator().WithBlockSampling(1000, 1000, 50).Estimate(text)
+		if diff := math.Abs(float64(sampled-full)) / float64(full); diff > 0.05 {
+			t.Errorf("Block sampling error %.1f%% (sampled=%d, full=%d)", diff*100, sampled, full)
+		}
+	})
+
+	t.Run("Sampling giant texts by default", func(t *testing.T) {
 		estimator := NewEstimator()
-		if estimator.EnableSampling {
-			t.Error("Expected sampling to be disabled by default")
+		if !estimator.EnableSampling || estimator.SamplingThreshold != 1<<20 || estimator.SamplingSize != 1<<14 {
+			t.Errorf("Expected sampling of texts over 1M characters by default, got %v, %d, %d",
+				estimator.EnableSampling, estimator.SamplingThreshold, estimator.SamplingSize)
+		}
+		// Versions released before sampling became the default keep
+		// analyzing giant texts in full.
+		unsampled := map[*Estimator]bool{kimiK2MarkdownV1: true, kimiK2JSONV1: true}
+		for _, e := range kimiK2Versions[:11] {
+			unsampled[e] = true
+		}
+		for _, e := range kimiK2CodeVersions[:3] {
+			unsampled[e] = true
+		}
+		for _, versions := range presetVersions {
+			for _, preset := range versions {
+				if preset.EnableSampling == unsampled[preset] {
+					t.Errorf("Preset %s samples by default: %v", preset.Key(), preset.EnableSampling)
+				}
+			}
+		}
+	})
+
+	t.Run("WithoutSampling", func(t *testing.T) {
+		text := strings.Repeat("Hello, world! 你好世界 123 ", 60000)
+		full := NewEstimator().WithoutSampling()
+		if full.EnableSampling || !NewEstimator().EnableSampling {
+			t.Fatal("WithoutSampling should disable sampling on a clone only")
+		}
+		// A threshold of the byte length is never reached
+		want := NewEstimator().WithSampling(len(text), 1).Analyze(text)
+		if got := full.Analyze(text); got != want {
+			t.Errorf("WithoutSampling analysis = %+v, want the full scan %+v", got, want)
 		}
 	})
 }
@@ -764,3 +1006,55 @@ func BenchmarkEstimator_VaryingTextSizes(b *testing.B) {
 		})
 	}
 }
+
+func TestTabIndentation(t *testing.T) {
+	tabs := strings.Repeat("\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n", 20)
+	spaces := strings.ReplaceAll(tabs, "\t", "    ")
+
+	pinned, _ := NewEstimatorWithName("kimi-k2-code@2")
+	if got, prev := kimiK2CodeV3.Estimate(tabs), pinned.Estimate(tabs); got <= prev {
+		t.Errorf("Expected more than kimi-k2-code@2's %d tokens for tab indentation, got %d", prev, got)
+	}
+	if got, prev := kimiK2CodeV3.Estimate(spaces), pinned.Estimate(spaces); got != prev {
+		t.Errorf("Expected space indentation to be unchanged at %d tokens, got %d", prev, got)
+	}
+}
+
+func TestAnalyzeInto(t *testing.T) {
+	estimators := map[string]*Estimator{
+		"text":       NewEstimator(),
+		"code":       NewEstimator().WithContentType(ContentCode),
+		"json":       NewEstimator().WithContentType(ContentJSON),
+		"html count": NewEstimator().WithHTMLHandling(HTMLCountTags),
+	}
+	for name, estimator := range estimators {
+		t.Run(name, func(t *testing.T) {
+			var stats Stats
+			for _, text := range referenceTexts {
+				estimator.AnalyzeInto(text, &stats)
+				if want := estimator.Analyze(text); stats != want {
+					t.Errorf("AnalyzeInto(%q) = %+v, Analyze = %+v", text, stats, want)
+				}
+				allocs := testing.AllocsPerRun(10, func() {
+					estimator.AnalyzeInto(text, &stats)
+					estimator.EstimateFromStats(stats)
+				})
+				if allocs != 0 {
+					t.Errorf("AnalyzeInto(%q) allocates %v times", text, allocs)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEstimator_AnalyzeInto(b *testing.B) {
+	estimator := NewEstimator()
+	text := "This is a benchmark test for character analysis. 这是一个基准测试。"
+	var stats Stats
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		estimator.AnalyzeInto(text, &stats)
+	}
+}
@@ -0,0 +1,261 @@
+// Package eval measures how well a tokenestimate estimator predicts the
+// actual token counts of a labeled dataset, with the metrics and the pass
+// rule the library's own tests use, so that preset authors and users
+// evaluating a preset on their traffic report comparable numbers.
+package eval
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// Sample is a text labeled with the number of tokens the target tokenizer
+// encodes it to.
+type Sample = tokenestimate.Sample
+
+// Thresholds is the rule a sample passes by: its estimate is off by at most
+// Relative of its token count, or by at most Absolute tokens.
+type Thresholds struct {
+	Relative float64 // Largest relative error that passes, as a fraction
+	Absolute int     // Largest error in tokens that passes
+}
+
+// DefaultThresholds is the rule of the library's tests: an error of at most
+// 15% or 20 tokens, whichever is larger.
+var DefaultThresholds = Thresholds{Relative: 0.15, Absolute: 20}
+
+// passes reports whether an estimate with the given errors passes.
+func (t Thresholds) passes(absErr int, relErr float64) bool {
+	return absErr <= t.Absolute || relErr <= t.Relative
+}
+
+// Options configures EvaluateWithOptions.
+type Options struct {
+	Thresholds Thresholds // Pass rule (default: DefaultThresholds)
+}
+
+// Metrics measures estimates against the actual token counts of samples.
+// Relative errors are fractions of the actual token count, so 0.05 is 5%,
+// and are computed over the samples with tokens.
+type Metrics struct {
+	Samples int     // Number of samples measured
+	MAE     float64 // Mean absolute error, in tokens
+	MAPE    float64 // Mean absolute relative error
+	RMSE    float64 // Root mean squared error, in tokens
+	Bias    float64 // Mean signed relative error; positive if the estimator overestimates
+
+	P50 float64 // Median absolute relative error
+	P90 float64 // 90th percentile of the absolute relative error
+	P99 float64 // 99th percentile of the absolute relative error
+
+	// Coverage is the fraction of samples whose estimate is at least their
+	// token count, which an upper-bound preset should keep near its
+	// quantile.
+	Coverage float64
+
+	PassRate float64 // Fraction of samples that pass the Thresholds
+}
+
+// Report is the Metrics of an estimator on a dataset, overall and broken
+// down by the kind of text.
+type Report struct {
+	Metrics
+
+	Thresholds Thresholds // Pass rule applied
+	Failures   []Failure  // Samples that fail, in dataset order
+
+	// ByScript has the Metrics of the samples of each dominant script,
+	// keyed by the LanguageProfile.Dominant of their texts, such as
+	// "Latin" or "Japanese", and ByContentType those of each content type
+	// DetectContentType finds. An estimator that is accurate overall can
+	// still be poor for a kind of text that is rare in the dataset.
+	ByScript      map[string]Metrics
+	ByContentType map[tokenestimate.ContentType]Metrics
+}
+
+// Failure is a sample that fails the pass rule.
+type Failure struct {
+	Index    int // Position of the sample in the dataset
+	Sample   Sample
+	Estimate int
+	RelError float64 // Signed relative error of the estimate
+}
+
+// Evaluate estimates every sample of dataset with e and reports the
+// errors, judging samples by DefaultThresholds.
+func Evaluate(e *tokenestimate.Estimator, dataset []Sample) Report {
+	return EvaluateWithOptions(e, dataset, Options{})
+}
+
+// EvaluateWithOptions is like Evaluate with the given options.
+func EvaluateWithOptions(e *tokenestimate.Estimator, dataset []Sample, opts Options) Report {
+	estimates := make([]int, len(dataset))
+	for i, s := range dataset {
+		estimates[i] = e.Estimate(s.Text)
+	}
+	return EvaluateEstimates(dataset, estimates, opts)
+}
+
+// EvaluateEstimates reports the errors of estimates made by other means,
+// such as a tokenizer service or several models, where estimates[i] is the
+// estimate of dataset[i]. It panics if the lengths differ.
+func EvaluateEstimates(dataset []Sample, estimates []int, opts Options) Report {
+	if len(estimates) != len(dataset) {
+		panic("eval: estimates and dataset differ in length")
+	}
+	thresholds := opts.Thresholds
+	if thresholds == (Thresholds{}) {
+		thresholds = DefaultThresholds
+	}
+
+	r := Report{
+		Thresholds:    thresholds,
+		ByScript:      make(map[string]Metrics),
+		ByContentType: make(map[tokenestimate.ContentType]Metrics),
+	}
+	results := make([]result, len(dataset))
+	byScript := make(map[string][]result)
+	byContentType := make(map[tokenestimate.ContentType][]result)
+	for i, s := range dataset {
+		res := result{diff: estimates[i] - s.Tokens, tokens: s.Tokens, rel: math.Inf(1)}
+		if s.Tokens > 0 {
+			res.rel = float64(res.diff) / float64(s.Tokens)
+		} else if res.diff == 0 {
+			res.rel = 0
+		}
+		res.pass = thresholds.passes(absInt(res.diff), math.Abs(res.rel))
+		if !res.pass {
+			r.Failures = append(r.Failures, Failure{Index: i, Sample: s, Estimate: estimates[i], RelError: res.rel})
+		}
+		results[i] = res
+
+		script := tokenestimate.ProfileOf(s.Text).Dominant()
+		byScript[script] = append(byScript[script], res)
+		ct := tokenestimate.DetectContentType(s.Text)
+		byContentType[ct] = append(byContentType[ct], res)
+	}
+
+	r.Metrics = metrics(results)
+	for script, results := range byScript {
+		r.ByScript[script] = metrics(results)
+	}
+	for ct, results := range byContentType {
+		r.ByContentType[ct] = metrics(results)
+	}
+	return r
+}
+
+// result is the error of the estimate of one sample.
+type result struct {
+	diff, tokens int
+	rel          float64 // signed relative error; infinite for an error on no tokens
+	pass         bool
+}
+
+// metrics summarizes results.
+func metrics(results []result) Metrics {
+	m := Metrics{Samples: len(results)}
+	if len(results) == 0 {
+		return m
+	}
+	var absSum, squares, signed float64
+	relErrs := make([]float64, 0, len(results))
+	covered, passed := 0, 0
+	for _, res := range results {
+		diff := float64(res.diff)
+		absSum += math.Abs(diff)
+		squares += diff * diff
+		if res.diff >= 0 {
+			covered++
+		}
+		if res.pass {
+			passed++
+		}
+		if res.tokens > 0 {
+			signed += res.rel
+			relErrs = append(relErrs, math.Abs(res.rel))
+		}
+	}
+
+	n := float64(len(results))
+	m.MAE = absSum / n
+	m.RMSE = math.Sqrt(squares / n)
+	m.Coverage = float64(covered) / n
+	m.PassRate = float64(passed) / n
+	if len(relErrs) > 0 {
+		m.Bias = signed / float64(len(relErrs))
+		sum := 0.0
+		for _, v := range relErrs {
+			sum += v
+		}
+		m.MAPE = sum / float64(len(relErrs))
+		slices.Sort(relErrs)
+		m.P50 = quantile(relErrs, 0.5)
+		m.P90 = quantile(relErrs, 0.9)
+		m.P99 = quantile(relErrs, 0.99)
+	}
+	return m
+}
+
+// absInt returns the absolute value of n.
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// quantile returns the q quantile of sorted, interpolating linearly
+// between the closest ranks.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lo] + (pos-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
+
+// String formats the report as a short summary followed by tables of the
+// metrics of each script and content type, when there is more than one.
+func (r Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "samples  %d\n", r.Samples)
+	fmt.Fprintf(&sb, "MAE      %.2f tokens\n", r.MAE)
+	fmt.Fprintf(&sb, "RMSE     %.2f tokens\n", r.RMSE)
+	fmt.Fprintf(&sb, "MAPE     %.1f%%\n", 100*r.MAPE)
+	fmt.Fprintf(&sb, "bias     %+.1f%%\n", 100*r.Bias)
+	fmt.Fprintf(&sb, "p50/p90/p99  %.1f%% / %.1f%% / %.1f%%\n", 100*r.P50, 100*r.P90, 100*r.P99)
+	fmt.Fprintf(&sb, "pass     %.1f%% (error ≤ %.0f%% or ≤ %d tokens; %d failures)\n",
+		100*r.PassRate, 100*r.Thresholds.Relative, r.Thresholds.Absolute, len(r.Failures))
+
+	row := func(name string, m Metrics) {
+		fmt.Fprintf(&sb, "%-12s %8d %8.1f%% %+8.1f%% %8.1f%% %8.1f%%\n",
+			name, m.Samples, 100*m.MAPE, 100*m.Bias, 100*m.P90, 100*m.PassRate)
+	}
+	header := func(name string) {
+		fmt.Fprintf(&sb, "\n%-12s %8s %9s %9s %9s %9s\n", name, "samples", "MAPE", "bias", "p90", "pass")
+	}
+	if len(r.ByScript) > 1 {
+		header("script")
+		for _, script := range slices.Sorted(maps.Keys(r.ByScript)) {
+			name := script
+			if name == "" {
+				name = "(empty)"
+			}
+			row(name, r.ByScript[script])
+		}
+	}
+	if len(r.ByContentType) > 1 {
+		header("content")
+		for _, ct := range slices.Sorted(maps.Keys(r.ByContentType)) {
+			row(ct.String(), r.ByContentType[ct])
+		}
+	}
+	return sb.String()
+}
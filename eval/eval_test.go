@@ -0,0 +1,144 @@
+package eval
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// labeled returns samples whose token counts are the estimates of e
+// multiplied by the given factors.
+func labeled(e *tokenestimate.Estimator, factors ...float64) []Sample {
+	samples := make([]Sample, len(factors))
+	for i, f := range factors {
+		text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", i+1)
+		samples[i] = Sample{Text: text, Tokens: int(math.Round(float64(e.Estimate(text)) / f))}
+	}
+	return samples
+}
+
+func TestEvaluate(t *testing.T) {
+	e := tokenestimate.NewEstimator()
+	samples := labeled(e, 1, 1, 1, 1)
+	r := Evaluate(e, samples)
+	if r.Samples != 4 || r.MAE != 0 || r.RMSE != 0 || r.MAPE != 0 || r.P99 != 0 {
+		t.Errorf("Expected exact labels to give no error, got %+v", r)
+	}
+	if r.PassRate != 1 || len(r.Failures) != 0 {
+		t.Errorf("Expected every sample to pass, got a pass rate of %v", r.PassRate)
+	}
+	if r.Thresholds != DefaultThresholds {
+		t.Errorf("Expected the default thresholds, got %+v", r.Thresholds)
+	}
+
+	// Doubling the labels halves the estimates relative to them.
+	for i := range samples {
+		samples[i].Tokens *= 2
+	}
+	r = Evaluate(e, samples)
+	if math.Abs(r.MAPE-0.5) > 0.01 || math.Abs(r.Bias+0.5) > 0.01 || math.Abs(r.P50-0.5) > 0.01 {
+		t.Errorf("Expected a relative error of about -50%%, got MAPE %.3f, bias %.3f, p50 %.3f", r.MAPE, r.Bias, r.P50)
+	}
+	if r.RMSE < r.MAE {
+		t.Errorf("Expected RMSE %.2f to be at least MAE %.2f", r.RMSE, r.MAE)
+	}
+}
+
+func TestEvaluatePercentiles(t *testing.T) {
+	e := tokenestimate.NewEstimator()
+	factors := make([]float64, 100)
+	for i := range factors {
+		factors[i] = 1
+	}
+	factors[99] = 2
+	r := Evaluate(e, labeled(e, factors...))
+	if r.P50 != 0 || r.P90 != 0 {
+		t.Errorf("Expected the p50 and p90 to ignore a single outlier, got %.3f and %.3f", r.P50, r.P90)
+	}
+	if r.P99 <= 0 || r.P99 >= 1 {
+		t.Errorf("Expected the p99 to interpolate towards the outlier, got %.3f", r.P99)
+	}
+}
+
+func TestEvaluateThresholds(t *testing.T) {
+	e := tokenestimate.NewEstimator()
+	short := "Hello, world!"
+	long := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+	samples := []Sample{
+		// Far off relatively, but by few tokens.
+		{Text: short, Tokens: 2 * e.Estimate(short)},
+		// Off by 30% and by far more than 20 tokens.
+		{Text: long, Tokens: int(float64(e.Estimate(long)) / 1.3)},
+		{Text: long, Tokens: e.Estimate(long)},
+		// Samples without tokens pass only if estimated exactly.
+		{Text: "", Tokens: 0},
+	}
+
+	r := Evaluate(e, samples)
+	if r.PassRate != 0.75 || len(r.Failures) != 1 {
+		t.Fatalf("Expected three of four samples to pass, got %v with failures %+v", r.PassRate, r.Failures)
+	}
+	if f := r.Failures[0]; f.Index != 1 || f.Estimate != e.Estimate(long) || math.Abs(f.RelError-0.3) > 0.01 {
+		t.Errorf("Expected the second sample to fail by 30%%, got %+v", f)
+	}
+
+	r = EvaluateWithOptions(e, samples, Options{Thresholds: Thresholds{Relative: 0.5, Absolute: 0}})
+	if r.PassRate != 1 {
+		t.Errorf("Expected every sample to pass within 50%%, got failures %+v", r.Failures)
+	}
+	r = EvaluateWithOptions(e, samples, Options{Thresholds: Thresholds{Relative: 0.01}})
+	if r.PassRate != 0.5 {
+		t.Errorf("Expected two of four samples to pass within 1%%, got %v", r.PassRate)
+	}
+}
+
+func TestEvaluateEmpty(t *testing.T) {
+	r := Evaluate(tokenestimate.NewEstimator(), nil)
+	if r.Samples != 0 || r.PassRate != 0 || !strings.Contains(r.String(), "samples  0") {
+		t.Errorf("Unexpected report for no samples: %+v", r)
+	}
+}
+
+func TestEvaluateBreakdown(t *testing.T) {
+	e := tokenestimate.NewEstimator()
+	japanese := []string{"今日はいい天気ですね。", "ありがとうございます、また明日。", "東京へ行きたいです。"}
+	code := []string{
+		"func main() {\n\tx := 42\n\tfmt.Println(x)\n}\n",
+		"for (int i = 0; i < n; i++) {\n\ttotal += values[i];\n}\n",
+	}
+	var samples []Sample
+	for _, text := range japanese {
+		samples = append(samples, Sample{Text: text, Tokens: e.Estimate(text)})
+	}
+	for _, text := range code {
+		samples = append(samples, Sample{Text: text, Tokens: int(math.Round(float64(e.Estimate(text)) / 1.3))})
+	}
+
+	r := Evaluate(e, samples)
+	if m := r.ByScript["Japanese"]; m.Samples != 3 || m.MAPE != 0 {
+		t.Errorf("Expected 3 exact Japanese samples, got %+v", m)
+	}
+	m := r.ByContentType[tokenestimate.ContentCode]
+	if m.Samples != 2 || math.Abs(m.Bias-0.3) > 0.05 {
+		t.Errorf("Expected 2 code samples about 30%% off, got %+v", m)
+	}
+	if m := r.ByContentType[tokenestimate.ContentText]; m.Samples != 3 || m.MAPE != 0 {
+		t.Errorf("Expected 3 exact text samples, got %+v", m)
+	}
+	if s := r.String(); !strings.Contains(s, "Japanese") || !strings.Contains(s, "code") {
+		t.Errorf("Expected the summary to list scripts and content types, got:\n%s", s)
+	}
+}
+
+func TestEvaluateEstimates(t *testing.T) {
+	samples := []Sample{{Text: "a", Tokens: 10}, {Text: "b", Tokens: 20}}
+	r := EvaluateEstimates(samples, []int{12, 20}, Options{Thresholds: Thresholds{Relative: 0.1}})
+	if r.MAE != 1 || math.Abs(r.MAPE-0.1) > 1e-9 || r.Coverage != 1 {
+		t.Errorf("Unexpected metrics: %+v", r.Metrics)
+	}
+	if len(r.Failures) != 1 || r.Failures[0].Index != 0 {
+		t.Errorf("Expected the first sample to fail, got %+v", r.Failures)
+	}
+}
@@ -0,0 +1,185 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Contribution is the share of an estimate due to one Stats feature.
+type Contribution struct {
+	Feature     string  // Name of the Stats field
+	Count       int     // Value of the field
+	Coefficient float64 // Tokens per unit of the field
+	Tokens      float64 // Count * Coefficient
+}
+
+// TermContribution is the share of an estimate due to one nonlinear Term.
+type TermContribution struct {
+	Term   Term
+	Tokens float64
+}
+
+// Explanation breaks an estimate down into the contributions that make it
+// up; see Explain.
+type Explanation struct {
+	Stats         Stats              // Statistics of the text
+	Intercept     float64            // Constant term of the model
+	Contributions []Contribution     // Priced features with a non-zero count, in Stats order
+	Terms         []TermContribution // Nonlinear terms of the model
+	Total         float64            // Sum of all contributions before rounding
+	Clamped       float64            // Total limited to the counts the text can possibly take
+	Tokens        int                // The estimate, as returned by Estimate
+}
+
+// Explain returns the breakdown of the estimate for text: the count,
+// coefficient and token contribution of every feature, such as Latin
+// letters or digit runs, plus the intercept and any nonlinear terms. The
+// contributions sum to Total; Clamped is Total limited to the bounds every
+// estimate is kept within, and rounds to Tokens after any safety margin.
+func (e *Estimator) Explain(text string) Explanation {
+	stats := e.Analyze(text)
+	x := Explanation{
+		Stats:     stats,
+		Intercept: e.intercept,
+		Total:     e.intercept,
+		Tokens:    e.EstimateFromStats(stats),
+	}
+	for _, f := range features {
+		count := *f.field(&stats)
+		coef, priced := e.coefficient(f.name)
+		if !priced || count == 0 {
+			continue
+		}
+		tokens := coef * float64(count)
+		x.Contributions = append(x.Contributions, Contribution{
+			Feature:     f.name,
+			Count:       count,
+			Coefficient: coef,
+			Tokens:      tokens,
+		})
+		x.Total += tokens
+	}
+	for _, term := range e.Terms {
+		tokens := term.value(&stats)
+		x.Terms = append(x.Terms, TermContribution{Term: term, Tokens: tokens})
+		x.Total += tokens
+	}
+	x.Clamped = e.clampTokens(&stats, x.Total)
+	return x
+}
+
+// String formats the explanation as a table, one contribution per line.
+func (x Explanation) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-22s %8s %10s %10s\n", "feature", "count", "coef", "tokens")
+	if x.Intercept != 0 {
+		fmt.Fprintf(&sb, "%-22s %8s %10s %10.2f\n", "intercept", "", "", x.Intercept)
+	}
+	for _, c := range x.Contributions {
+		fmt.Fprintf(&sb, "%-22s %8d %10.4f %10.2f\n", c.Feature, c.Count, c.Coefficient, c.Tokens)
+	}
+	for _, t := range x.Terms {
+		fmt.Fprintf(&sb, "%-22s %8s %10s %10.2f\n", t.Term.Feature+" (term)", "", "", t.Tokens)
+	}
+	if x.Clamped != x.Total {
+		fmt.Fprintf(&sb, "%-22s %8s %10s %10.2f\n", "total", "", "", x.Total)
+		fmt.Fprintf(&sb, "%-22s %8s %10s %10.2f = %d tokens\n", "clamped", "", "", x.Clamped, x.Tokens)
+	} else {
+		fmt.Fprintf(&sb, "%-22s %8s %10s %10.2f = %d tokens\n", "total", "", "", x.Total, x.Tokens)
+	}
+	return sb.String()
+}
+
+// coefficient returns the coefficient the linear model applies to the
+// named feature under the estimator's configuration, and false for
+// features the model does not price.
+func (e *Estimator) coefficient(name string) (float64, bool) {
+	switch name {
+	case "Unknown":
+		return e.unknownCoef(), true
+	case "InvalidBytes":
+		return e.invalidBytesCoef(), true
+	case "DictionaryTokens":
+		return 1, true
+	}
+	if p := e.coefficientField(name); p != nil {
+		return *p, true
+	}
+	return 0, false
+}
+
+// coefficientField returns a pointer to the coefficient of the named
+// feature, or nil if the feature has no coefficient of its own.
+func (e *Estimator) coefficientField(name string) *float64 {
+	switch name {
+	case "Symbols":
+		return &e.coefSymbols
+	case "LatinLetters":
+		return &e.coefLatinLetters
+	case "LatinExtended":
+		return &e.coefLatinExt
+	case "Digits":
+		return &e.coefDigits
+	case "ChineseChars":
+		return &e.coefChinese
+	case "JapaneseKana":
+		return &e.coefJapanese
+	case "KoreanHangul":
+		return &e.coefKorean
+	case "RussianChars":
+		return &e.coefRussian
+	case "ArabicChars":
+		return &e.coefArabic
+	case "KhmerChars":
+		return &e.coefKhmer
+	case "LaoChars":
+		return &e.coefLao
+	case "MyanmarChars":
+		return &e.coefMyanmar
+	case "EthiopicChars":
+		return &e.coefEthiopic
+	case "Spaces":
+		return &e.coefSpaces
+	case "Tabs":
+		return &e.coefTabs
+	case "WhitespaceRuns":
+		return &e.coefWhitespaceRuns
+	case "Unknown":
+		return &e.coefUnknown
+	case "InvalidBytes":
+		return &e.coefInvalidBytes
+	case "Words":
+		return &e.coefWords
+	case "DigitRuns":
+		return &e.coefDigitRuns
+	case "BlobChars":
+		return &e.coefBlobChars
+	case "IdentifierBoundaries":
+		return &e.coefIdentifierBoundaries
+	case "MarkdownHeadings":
+		return &e.coefMarkdownHeadings
+	case "MarkdownFences":
+		return &e.coefMarkdownFences
+	case "MarkdownListItems":
+		return &e.coefMarkdownListItems
+	case "MarkdownTableRows":
+		return &e.coefMarkdownTableRows
+	case "MarkdownLinks":
+		return &e.coefMarkdownLinks
+	case "HTMLTags":
+		return &e.coefHTMLTags
+	case "HTMLEntities":
+		return &e.coefHTMLEntities
+	case "JSONStructure":
+		return &e.coefJSONStructure
+	case "JSONStructureRuns":
+		return &e.coefJSONStructureRuns
+	case "JSONRepeatedKeys":
+		return &e.coefJSONRepeatedKeys
+	case "RepeatedShingles":
+		return &e.coefRepeatedShingles
+	case "SpecialTokens":
+		return &e.coefSpecialTokens
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package tokenestimate
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	estimators := map[string]*Estimator{
+		"text":    NewEstimator(),
+		"code":    NewEstimator().WithContentType(ContentCode),
+		"json":    NewEstimator().WithContentType(ContentJSON),
+		"unknown": NewEstimator().WithUnknownPolicy(UnknownAsCategory),
+	}
+	withTerms, err := NewEstimator().WithTerms(Term{Feature: "Digits", Kind: TermSqrt, Coef: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	estimators["terms"] = withTerms
+
+	for name, estimator := range estimators {
+		t.Run(name, func(t *testing.T) {
+			for _, text := range referenceTexts {
+				x := estimator.Explain(text)
+				if want := estimator.calculateTokenCount(estimator.Analyze(text)); math.Abs(x.Clamped-want) > 1e-9 {
+					t.Errorf("Explain(%q).Clamped = %v, model gives %v", text, x.Clamped, want)
+				}
+				if want := estimator.Estimate(text); x.Tokens != want {
+					t.Errorf("Explain(%q).Tokens = %d, Estimate = %d", text, x.Tokens, want)
+				}
+			}
+		})
+	}
+
+	t.Run("Contributions", func(t *testing.T) {
+		x := NewEstimator().Explain("Hello 123")
+		byFeature := make(map[string]Contribution)
+		for _, c := range x.Contributions {
+			byFeature[c.Feature] = c
+		}
+		latin := byFeature["LatinLetters"]
+		if latin.Count != 5 || latin.Tokens != latin.Coefficient*5 {
+			t.Errorf("Unexpected Latin contribution %+v", latin)
+		}
+		if _, ok := byFeature["Shingles"]; ok {
+			t.Error("Unpriced features should not contribute")
+		}
+		if !strings.Contains(x.String(), "LatinLetters") {
+			t.Errorf("Expected the table to list LatinLetters:\n%s", x)
+		}
+	})
+}
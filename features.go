@@ -0,0 +1,109 @@
+package tokenestimate
+
+import "unsafe"
+
+// feature describes a Stats field that models can price. Fields are
+// located by offset rather than through accessor functions so that
+// reading them does not force a Stats onto the heap.
+type feature struct {
+	name   string
+	offset uintptr
+}
+
+// field returns a pointer to the feature's field in s.
+func (f feature) field(s *Stats) *int {
+	return (*int)(unsafe.Add(unsafe.Pointer(s), f.offset))
+}
+
+// features lists every Stats field in declaration order.
+var features = []feature{
+	{"Symbols", unsafe.Offsetof(Stats{}.Symbols)},
+	{"LatinLetters", unsafe.Offsetof(Stats{}.LatinLetters)},
+	{"LatinExtended", unsafe.Offsetof(Stats{}.LatinExtended)},
+	{"Digits", unsafe.Offsetof(Stats{}.Digits)},
+	{"ChineseChars", unsafe.Offsetof(Stats{}.ChineseChars)},
+	{"JapaneseKana", unsafe.Offsetof(Stats{}.JapaneseKana)},
+	{"KoreanHangul", unsafe.Offsetof(Stats{}.KoreanHangul)},
+	{"RussianChars", unsafe.Offsetof(Stats{}.RussianChars)},
+	{"ArabicChars", unsafe.Offsetof(Stats{}.ArabicChars)},
+	{"KhmerChars", unsafe.Offsetof(Stats{}.KhmerChars)},
+	{"LaoChars", unsafe.Offsetof(Stats{}.LaoChars)},
+	{"MyanmarChars", unsafe.Offsetof(Stats{}.MyanmarChars)},
+	{"EthiopicChars", unsafe.Offsetof(Stats{}.EthiopicChars)},
+	{"Spaces", unsafe.Offsetof(Stats{}.Spaces)},
+	{"Tabs", unsafe.Offsetof(Stats{}.Tabs)},
+	{"WhitespaceRuns", unsafe.Offsetof(Stats{}.WhitespaceRuns)},
+	{"Unknown", unsafe.Offsetof(Stats{}.Unknown)},
+	{"InvalidBytes", unsafe.Offsetof(Stats{}.InvalidBytes)},
+	{"Words", unsafe.Offsetof(Stats{}.Words)},
+	{"DigitRuns", unsafe.Offsetof(Stats{}.DigitRuns)},
+	{"BlobChars", unsafe.Offsetof(Stats{}.BlobChars)},
+	{"IdentifierBoundaries", unsafe.Offsetof(Stats{}.IdentifierBoundaries)},
+	{"MarkdownHeadings", unsafe.Offsetof(Stats{}.MarkdownHeadings)},
+	{"MarkdownFences", unsafe.Offsetof(Stats{}.MarkdownFences)},
+	{"MarkdownListItems", unsafe.Offsetof(Stats{}.MarkdownListItems)},
+	{"MarkdownTableRows", unsafe.Offsetof(Stats{}.MarkdownTableRows)},
+	{"MarkdownLinks", unsafe.Offsetof(Stats{}.MarkdownLinks)},
+	{"HTMLTags", unsafe.Offsetof(Stats{}.HTMLTags)},
+	{"HTMLEntities", unsafe.Offsetof(Stats{}.HTMLEntities)},
+	{"JSONStructure", unsafe.Offsetof(Stats{}.JSONStructure)},
+	{"JSONStructureRuns", unsafe.Offsetof(Stats{}.JSONStructureRuns)},
+	{"JSONKeys", unsafe.Offsetof(Stats{}.JSONKeys)},
+	{"JSONRepeatedKeys", unsafe.Offsetof(Stats{}.JSONRepeatedKeys)},
+	{"Shingles", unsafe.Offsetof(Stats{}.Shingles)},
+	{"RepeatedShingles", unsafe.Offsetof(Stats{}.RepeatedShingles)},
+	{"DictionaryWords", unsafe.Offsetof(Stats{}.DictionaryWords)},
+	{"DictionaryChars", unsafe.Offsetof(Stats{}.DictionaryChars)},
+	{"DictionaryTokens", unsafe.Offsetof(Stats{}.DictionaryTokens)},
+	{"SpecialTokens", unsafe.Offsetof(Stats{}.SpecialTokens)},
+}
+
+// featuresByName indexes features by name.
+var featuresByName = func() map[string]feature {
+	m := make(map[string]feature, len(features))
+	for _, f := range features {
+		m[f.name] = f
+	}
+	return m
+}()
+
+// Add returns the field-wise sum of s and other: the statistics of two
+// texts analyzed separately. EstimateFromStats of the sum can differ
+// slightly from estimating the concatenated text, because words and
+// repetition spanning the join are counted differently.
+func (s Stats) Add(other Stats) Stats {
+	for _, f := range features {
+		*f.field(&s) += *f.field(&other)
+	}
+	return s
+}
+
+// Merge returns the field-wise sum of s and others, the statistics of
+// several texts analyzed separately, such as the segments kept by an
+// incremental pipeline. It is Add applied to each of others in turn.
+func (s Stats) Merge(others ...Stats) Stats {
+	for _, other := range others {
+		s = s.Add(other)
+	}
+	return s
+}
+
+// Sub returns the field-wise difference of s and other, removing the
+// statistics of a text previously added with Add.
+func (s Stats) Sub(other Stats) Stats {
+	for _, f := range features {
+		*f.field(&s) -= *f.field(&other)
+	}
+	return s
+}
+
+// Scale returns s with every field multiplied by factor and rounded to the
+// nearest integer, for example to extrapolate the statistics of a sample
+// to a whole text.
+func (s Stats) Scale(factor float64) Stats {
+	for _, f := range features {
+		field := f.field(&s)
+		*field = int(float64(*field)*factor + 0.5)
+	}
+	return s
+}
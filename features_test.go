@@ -0,0 +1,47 @@
+package tokenestimate
+
+import "testing"
+
+func TestStatsArithmetic(t *testing.T) {
+	estimator := NewEstimator()
+	a := estimator.Analyze("The quick brown fox jumps over the lazy dog. ")
+	b := estimator.Analyze("你好，世界！123 Привет")
+
+	t.Run("Add and Sub", func(t *testing.T) {
+		sum := a.Add(b)
+		if sum.LatinLetters != a.LatinLetters+b.LatinLetters || sum.ChineseChars != b.ChineseChars {
+			t.Errorf("Unexpected sum %+v", sum)
+		}
+		if got := sum.Sub(b); got != a {
+			t.Errorf("Expected (a + b) - b == a, got %+v", got)
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		c := estimator.Analyze("func main() {}\n")
+		if got, want := a.Merge(b, c), a.Add(b).Add(c); got != want {
+			t.Errorf("Merge = %+v, want %+v", got, want)
+		}
+		if got := a.Merge(); got != a {
+			t.Errorf("Expected Merge() to be the identity, got %+v", got)
+		}
+	})
+
+	t.Run("Matches concatenated text", func(t *testing.T) {
+		text1, text2 := "First paragraph of a document.\n", "Second paragraph, 第二段。\n"
+		sum := estimator.Analyze(text1).Add(estimator.Analyze(text2))
+		if got, want := estimator.EstimateFromStats(sum), estimator.Estimate(text1+text2); got != want {
+			t.Errorf("EstimateFromStats(sum) = %d, Estimate = %d", got, want)
+		}
+	})
+
+	t.Run("Scale", func(t *testing.T) {
+		scaled := a.Scale(2.5)
+		if scaled.LatinLetters != int(float64(a.LatinLetters)*2.5+0.5) || scaled.Spaces != int(float64(a.Spaces)*2.5+0.5) {
+			t.Errorf("Unexpected scaled stats %+v", scaled)
+		}
+		if got := a.Scale(1); got != a {
+			t.Errorf("Expected Scale(1) to be the identity, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package tokenestimate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FetchOptions configures FetchPresetsWithOptions and WatchPresets.
+type FetchOptions struct {
+	// SHA256 is the hex-encoded SHA-256 checksum the bundle must have.
+	// When neither it nor PublicKey is set, the checksum is downloaded
+	// from the bundle's URL with ".sha256" appended, in the format of
+	// sha256sum, which detects corrupted downloads but not a compromised
+	// server.
+	SHA256 string
+
+	// PublicKey verifies the Ed25519 signature of the bundle, downloaded
+	// base64-encoded from the bundle's URL with ".sig" appended.
+	PublicKey ed25519.PublicKey
+
+	// CacheDir keeps the last bundle verified, which is registered
+	// instead when the bundle cannot be downloaded or verified, so that
+	// services start with the presets they last had while the server is
+	// down (default: no cache)
+	CacheDir string
+
+	// RefreshInterval is how often WatchPresets downloads the bundle
+	// again (default: 1 hour)
+	RefreshInterval time.Duration
+
+	Client *http.Client // Client making the requests (default: http.DefaultClient)
+}
+
+// defaultRefreshInterval is the default FetchOptions.RefreshInterval.
+const defaultRefreshInterval = time.Hour
+
+// maxBundleSize is the size beyond which a download is rejected.
+const maxBundleSize = 32 << 20
+
+// errNotModified reports that the bundle has not changed since the last
+// download.
+var errNotModified = errors.New("not modified")
+
+// presetBundle is the format of the bundles FetchPresets downloads: a JSON
+// object whose presets are in the format of LoadPreset.
+type presetBundle struct {
+	Presets []json.RawMessage `json:"presets"`
+}
+
+// FetchPresets downloads the preset bundle at url, verifies its checksum
+// and registers its presets, so that re-calibrated coefficients reach
+// services without a redeploy. A bundle is a JSON object listing presets
+// in the format of LoadPreset:
+//
+//	{"presets": [{"name": "my-model", "version": 3, "coefficients": {"LatinLetters": 0.21}}]}
+//
+// The checksum is downloaded from url with ".sha256" appended; use
+// FetchPresetsWithOptions to pin the checksum, verify a signature or cache
+// the bundle, and WatchPresets to refresh it periodically. Either every
+// preset of the bundle is registered or none is.
+func FetchPresets(url string) ([]*Estimator, error) {
+	return FetchPresetsWithOptions(context.Background(), url, FetchOptions{})
+}
+
+// FetchPresetsWithOptions is FetchPresets with options.
+func FetchPresetsWithOptions(ctx context.Context, url string, opts FetchOptions) ([]*Estimator, error) {
+	w := &presetWatch{url: url, opts: opts}
+	return w.fetch(ctx)
+}
+
+// WatchPresets fetches the preset bundle at url like
+// FetchPresetsWithOptions, then downloads it again every
+// opts.RefreshInterval in the background, registering its presets
+// whenever it changes, until ctx is canceled. Failed refreshes are logged
+// and keep the presets registered before.
+func WatchPresets(ctx context.Context, url string, opts FetchOptions) ([]*Estimator, error) {
+	w := &presetWatch{url: url, opts: opts}
+	loaded, err := w.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	go w.run(ctx)
+	return loaded, nil
+}
+
+// presetWatch downloads a bundle, remembering its ETag to skip the
+// downloads of bundles that have not changed.
+type presetWatch struct {
+	url  string
+	opts FetchOptions
+	etag string
+}
+
+// fetch downloads, verifies and registers the bundle, or the cached one if
+// that fails.
+func (w *presetWatch) fetch(ctx context.Context) ([]*Estimator, error) {
+	loaded, data, err := w.download(ctx)
+	if err == nil {
+		w.store(data)
+		registerPresets(loaded)
+		return loaded, nil
+	}
+	if w.opts.CacheDir == "" {
+		return nil, err
+	}
+	data, cacheErr := os.ReadFile(w.cachePath())
+	if cacheErr != nil {
+		return nil, err
+	}
+	loaded, cacheErr = readBundle(data)
+	if cacheErr != nil {
+		return nil, err
+	}
+	getLogger().Warn("tokenestimate: using cached presets", "url", w.url, "error", err)
+	registerPresets(loaded)
+	return loaded, nil
+}
+
+// run refreshes the bundle every RefreshInterval until ctx is canceled.
+func (w *presetWatch) run(ctx context.Context) {
+	interval := w.opts.RefreshInterval
+	if interval <= 0 {
+		interval = defaultRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		loaded, data, err := w.download(ctx)
+		switch {
+		case errors.Is(err, errNotModified):
+		case err != nil:
+			if ctx.Err() == nil {
+				getLogger().Warn("tokenestimate: preset refresh failed", "url", w.url, "error", err)
+			}
+		default:
+			w.store(data)
+			registerPresets(loaded)
+		}
+	}
+}
+
+// download downloads and verifies the bundle and reads its presets.
+func (w *presetWatch) download(ctx context.Context) ([]*Estimator, []byte, error) {
+	data, etag, err := w.get(ctx, w.url, w.etag)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := w.verify(ctx, data); err != nil {
+		return nil, nil, err
+	}
+	loaded, err := readBundle(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", w.url, err)
+	}
+	w.etag = etag
+	return loaded, data, nil
+}
+
+// verify checks the checksum and the signature of the bundle data.
+func (w *presetWatch) verify(ctx context.Context, data []byte) error {
+	want := w.opts.SHA256
+	if want == "" && w.opts.PublicKey == nil {
+		sum, _, err := w.get(ctx, w.url+".sha256", "")
+		if err != nil {
+			return err
+		}
+		fields := strings.Fields(string(sum))
+		if len(fields) == 0 {
+			return fmt.Errorf("%s.sha256: empty checksum", w.url)
+		}
+		want = fields[0]
+	}
+	if want != "" {
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, want) {
+			return fmt.Errorf("%s: checksum mismatch: expected %s, got %s", w.url, want, got)
+		}
+	}
+	if w.opts.PublicKey != nil {
+		encoded, _, err := w.get(ctx, w.url+".sig", "")
+		if err != nil {
+			return err
+		}
+		sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+		if err != nil {
+			return fmt.Errorf("%s.sig: invalid signature: %w", w.url, err)
+		}
+		if !ed25519.Verify(w.opts.PublicKey, data, sig) {
+			return fmt.Errorf("%s: signature mismatch", w.url)
+		}
+	}
+	return nil
+}
+
+// get downloads url, unless its ETag is etag.
+func (w *presetWatch) get(ctx context.Context, url, etag string) (data []byte, newETag string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	client := w.opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && etag != "":
+		return nil, "", errNotModified
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	data, err = io.ReadAll(io.LimitReader(resp.Body, maxBundleSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", url, err)
+	}
+	if len(data) > maxBundleSize {
+		return nil, "", fmt.Errorf("%s: larger than %d bytes", url, maxBundleSize)
+	}
+	return data, resp.Header.Get("ETag"), nil
+}
+
+// store writes verified bundle data to the cache. A failure is logged:
+// the presets are still registered.
+func (w *presetWatch) store(data []byte) {
+	if w.opts.CacheDir == "" {
+		return
+	}
+	if err := writeFileAtomic(w.cachePath(), data); err != nil {
+		getLogger().Warn("tokenestimate: caching presets failed", "url", w.url, "error", err)
+	}
+}
+
+// writeFileAtomic writes data to a temporary file renamed to path, so that
+// readers never see a partly written file.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// cachePath returns the path of the cached bundle of the URL.
+func (w *presetWatch) cachePath() string {
+	sum := sha256.Sum256([]byte(w.url))
+	return filepath.Join(w.opts.CacheDir, "presets-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// readBundle reads the presets of bundle data.
+func readBundle(data []byte) ([]*Estimator, error) {
+	var b presetBundle
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&b); err != nil {
+		return nil, err
+	}
+	if len(b.Presets) == 0 {
+		return nil, errors.New("bundle has no presets")
+	}
+	loaded := make([]*Estimator, len(b.Presets))
+	for i, raw := range b.Presets {
+		e, err := readPreset(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("preset %d: %w", i, err)
+		}
+		loaded[i] = e
+	}
+	return loaded, nil
+}
@@ -0,0 +1,181 @@
+package tokenestimate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// bundleServer serves a preset bundle at /presets.json with its checksum
+// and signature, counting the bundles sent in full.
+type bundleServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	bundle   string
+	checksum string // served checksum, or that of bundle if empty
+	key      ed25519.PrivateKey
+	sent     atomic.Int32
+}
+
+func newBundleServer(t *testing.T, bundle string) *bundleServer {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &bundleServer{bundle: bundle, key: key}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		sum := sha256.Sum256([]byte(s.bundle))
+		etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+		switch r.URL.Path {
+		case "/presets.json":
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			s.sent.Add(1)
+			w.Header().Set("ETag", etag)
+			w.Write([]byte(s.bundle))
+		case "/presets.json.sha256":
+			checksum := s.checksum
+			if checksum == "" {
+				checksum = hex.EncodeToString(sum[:])
+			}
+			w.Write([]byte(checksum + "  presets.json\n"))
+		case "/presets.json.sig":
+			w.Write([]byte(base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, []byte(s.bundle)))))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *bundleServer) set(bundle string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bundle = bundle
+}
+
+func TestFetchPresets(t *testing.T) {
+	SetLogger(nil)
+	defer logger.Store(nil)
+	unregister(t, "fetched", "fetched@1", "fetched@2", "fetched-2")
+
+	s := newBundleServer(t, `{"presets": [
+		{"name": "fetched", "version": 1, "coefficients": {"Words": 0.5}},
+		{"name": "fetched-2", "base": "kimi-k2@11"}
+	]}`)
+
+	t.Run("Downloaded checksum", func(t *testing.T) {
+		loaded, err := FetchPresets(s.URL + "/presets.json")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(loaded) != 2 {
+			t.Fatalf("Expected 2 presets, got %d", len(loaded))
+		}
+		if e, err := GetPresetByName("fetched@1"); err != nil || e != loaded[0] {
+			t.Errorf("Expected fetched@1 to be registered, got %v", err)
+		}
+	})
+
+	t.Run("Signature", func(t *testing.T) {
+		opts := FetchOptions{PublicKey: s.key.Public().(ed25519.PublicKey)}
+		if _, err := FetchPresetsWithOptions(context.Background(), s.URL+"/presets.json", opts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		other, _, _ := ed25519.GenerateKey(nil)
+		opts.PublicKey = other
+		_, err := FetchPresetsWithOptions(context.Background(), s.URL+"/presets.json", opts)
+		if err == nil || !strings.Contains(err.Error(), "signature mismatch") {
+			t.Errorf("Expected a signature mismatch, got %v", err)
+		}
+	})
+
+	t.Run("Checksum mismatch falls back to the cache", func(t *testing.T) {
+		cache := t.TempDir()
+		opts := FetchOptions{CacheDir: cache}
+		if _, err := FetchPresetsWithOptions(context.Background(), s.URL+"/presets.json", opts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		opts.SHA256 = strings.Repeat("0", 64)
+		loaded, err := FetchPresetsWithOptions(context.Background(), s.URL+"/presets.json", opts)
+		if err != nil || len(loaded) != 2 {
+			t.Fatalf("Expected the cached presets, got %d, %v", len(loaded), err)
+		}
+
+		opts.CacheDir = t.TempDir()
+		_, err = FetchPresetsWithOptions(context.Background(), s.URL+"/presets.json", opts)
+		if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+			t.Errorf("Expected a checksum mismatch without a cache, got %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := map[string]string{
+			"/missing.json": "404 Not Found",
+		}
+		for path, want := range tests {
+			if _, err := FetchPresets(s.URL + path); err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: expected an error containing %q, got %v", path, want, err)
+			}
+		}
+
+		bad := newBundleServer(t, `{"presets": [{"name": "ok"}, {"name": "bad", "coefficients": {"Foo": 1}}]}`)
+		_, err := FetchPresets(bad.URL + "/presets.json")
+		if err == nil || !strings.Contains(err.Error(), "preset 1: unknown feature: Foo") {
+			t.Errorf("Expected an error for preset 1, got %v", err)
+		}
+		if _, err := lookupPreset("ok"); err == nil {
+			t.Error("Expected no preset of a bad bundle to be registered")
+		}
+	})
+}
+
+func TestWatchPresets(t *testing.T) {
+	SetLogger(nil)
+	defer logger.Store(nil)
+	unregister(t, "watched")
+
+	s := newBundleServer(t, `{"presets": [{"name": "watched", "description": "first"}]}`)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := WatchPresets(ctx, s.URL+"/presets.json", FetchOptions{RefreshInterval: 10 * time.Millisecond}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	s.set(`{"presets": [{"name": "watched", "description": "second"}]}`)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if e, err := lookupPreset("watched"); err == nil && e.Description == "second" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the refreshed preset to be registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Unchanged bundles are not downloaded again.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if n := s.sent.Load(); n != 2 {
+		t.Errorf("Expected the bundle to be sent twice, got %d", n)
+	}
+	if e, _ := lookupPreset("watched"); e.Description != "second" {
+		t.Errorf("Expected the refreshed preset to stay registered, got %q", e.Description)
+	}
+}
@@ -0,0 +1,478 @@
+// Package fit trains tokenestimate models from texts labeled with their
+// actual token counts, so that presets can be built for any tokenizer
+// without an offline pipeline: collect (text, token_count) pairs, call Fit
+// and register the result with tokenestimate.RegisterPreset.
+package fit
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// Sample is a text labeled with the number of tokens the target tokenizer
+// encodes it to.
+type Sample = tokenestimate.Sample
+
+// Method selects the solver Fit uses.
+type Method int
+
+const (
+	// OLS fits ordinary least squares.
+	OLS Method = iota
+	// NNLS fits least squares with every coefficient, and the intercept if
+	// fitted, constrained to be non-negative, which keeps a feature that
+	// is rare in the samples from taking a coefficient that rewards it.
+	NNLS
+)
+
+// FitOptions configures Fit.
+type FitOptions struct {
+	// Base provides the analysis settings, such as the content type and
+	// blob detection, and the coefficients of the features that are not
+	// fitted (default: tokenestimate.NewEstimator())
+	Base *tokenestimate.Estimator
+
+	// Features are the Stats features to fit (default: every feature of
+	// Features that occurs in at least one sample)
+	Features []string
+
+	// MinSupport is the number of samples a feature must occur in to be
+	// fitted; features with less support keep Base's coefficients and are
+	// listed in Report.Dropped (default: 1 for the default Features, and
+	// Features that never occur are an error otherwise)
+	MinSupport int
+
+	// Ridge and Lasso penalize the squared and the absolute values of the
+	// coefficients, relative to the mean squared residual, to keep
+	// coefficients of features that are rare or correlated in the samples
+	// small; Lasso also sets the least useful ones to zero. The intercept
+	// is not penalized. The residual is in tokens, or relative with
+	// Relative, so suitable values differ: with Relative, start around
+	// 0.01 for Ridge and 0.001 for Lasso (default: 0)
+	Ridge float64
+	Lasso float64
+
+	// Quantile fits the given quantile of the token counts instead of
+	// their mean, such as 0.9 for a model whose estimates are at least the
+	// actual count for nine texts in ten, by quantile regression. With
+	// Relative it is the quantile of the ratio of actual to estimated
+	// tokens (default: 0, the mean)
+	Quantile float64
+
+	// Robust fits the coefficients by robust regression, which gives the
+	// samples the model reproduces far worse than the others little or no
+	// weight, so that a few mislabeled samples, such as token counts from
+	// the wrong tokenizer, do not skew the coefficients. Robust does not
+	// apply to quantile fits, which are robust already (default: false)
+	Robust bool
+
+	// OutlierThreshold is the residual, in robust standard deviations,
+	// beyond which a sample is listed in Report.Outliers, whether or not
+	// the fit is Robust; a negative threshold lists none (default: 3.5)
+	OutlierThreshold float64
+
+	Method    Method // Solver (default: OLS)
+	Intercept bool   // Whether to fit an intercept; otherwise Base's intercept is kept
+	Relative  bool   // Minimize the squared relative error instead of the squared error in tokens
+	Name      string // Name of the fitted estimator (default: "fitted")
+}
+
+// Report describes a fit and how well the fitted model reproduces the
+// training samples.
+type Report struct {
+	Samples      int                // Number of samples fitted
+	Features     []string           // Features fitted
+	Dropped      []string           // Features not fitted for lack of support
+	Support      map[string]int     // Number of samples each feature occurs in
+	Coefficients map[string]float64 // Fitted coefficient of each feature
+	Intercept    float64            // Intercept of the fitted model
+
+	MeanRelError float64 // Mean of |estimate - tokens| / tokens over samples with tokens
+	RMSE         float64 // Root mean squared error, in tokens
+	R2           float64 // Coefficient of determination
+	Coverage     float64 // Fraction of samples whose estimate is at least their token count
+
+	// Outliers lists the samples the fitted model reproduces far worse
+	// than the others, in sample order. Check them for labeling errors.
+	Outliers []Outlier
+}
+
+// fittable lists the features with a coefficient of their own, in Stats
+// order. Unknown and InvalidBytes are left out: under the default
+// policies they are priced with the Symbols coefficient.
+var fittable = []string{
+	"Symbols", "LatinLetters", "LatinExtended", "Digits",
+	"ChineseChars", "JapaneseKana", "KoreanHangul", "RussianChars", "ArabicChars",
+	"KhmerChars", "LaoChars", "MyanmarChars", "EthiopicChars",
+	"Spaces", "Tabs", "WhitespaceRuns", "Words", "DigitRuns", "BlobChars", "IdentifierBoundaries",
+	"MarkdownHeadings", "MarkdownFences", "MarkdownListItems", "MarkdownTableRows", "MarkdownLinks",
+	"HTMLTags", "HTMLEntities",
+	"JSONStructure", "JSONStructureRuns", "JSONRepeatedKeys",
+	"RepeatedShingles", "SpecialTokens",
+}
+
+// Features returns the names of the features Fit can fit.
+func Features() []string {
+	return slices.Clone(fittable)
+}
+
+// design is the least squares problem of a fit: the counts of the fitted
+// features, then a column of ones if the intercept is fitted, and the
+// targets the model has to reproduce after the contributions of the
+// features that are not fitted.
+type design struct {
+	x       *matrix
+	y       []float64
+	weights []float64
+	names   []string // names of the columns; the intercept is ""
+}
+
+// Fit trains the coefficients of a linear model over the Stats features
+// from samples and returns an estimator using them, derived from
+// opts.Base, together with a report of the fit. Features that are not
+// fitted, nonlinear terms and dictionary words keep the pricing of
+// opts.Base, and the fit accounts for their contributions.
+//
+// Fit returns an error if there are fewer samples than coefficients to
+// fit, or if a requested feature never occurs or is a combination of
+// the others in the samples, which leaves its coefficient undetermined.
+// A Ridge or Lasso penalty determines every coefficient.
+func Fit(samples []Sample, opts FitOptions) (*tokenestimate.Estimator, Report, error) {
+	base := cmp.Or(opts.Base, tokenestimate.NewEstimator())
+	if len(samples) == 0 {
+		return nil, Report{}, errors.New("no samples")
+	}
+	if opts.Quantile < 0 || opts.Quantile >= 1 {
+		return nil, Report{}, fmt.Errorf("quantile %v is not between 0 and 1", opts.Quantile)
+	}
+	if opts.Robust && opts.Quantile != 0 {
+		return nil, Report{}, errors.New("robust fitting does not apply to quantile fits")
+	}
+	explanations := make([]tokenestimate.Explanation, len(samples))
+	for i, s := range samples {
+		if s.Tokens < 0 {
+			return nil, Report{}, fmt.Errorf("sample %d: negative token count %d", i, s.Tokens)
+		}
+		explanations[i] = base.Explain(s.Text)
+	}
+
+	support := supportOf(explanations)
+	names, dropped, err := selectFeatures(opts, support)
+	if err != nil {
+		return nil, Report{}, err
+	}
+
+	d := newDesign(base, samples, explanations, names, opts)
+	if d.x.rows < d.x.cols && opts.Ridge <= 0 && opts.Lasso <= 0 {
+		return nil, Report{}, fmt.Errorf("%d samples cannot determine %d coefficients", d.x.rows, d.x.cols)
+	}
+	coefs, err := d.solve(opts)
+	if err != nil {
+		return nil, Report{}, err
+	}
+
+	intercept := explanations[0].Intercept
+	fitted := make(map[string]float64, len(names))
+	for j, name := range d.names {
+		if name == "" {
+			intercept = coefs[j]
+		} else {
+			fitted[name] = coefs[j]
+		}
+	}
+	estimator, err := base.WithCoefficients(intercept, fitted)
+	if err != nil {
+		return nil, Report{}, err
+	}
+	estimator.Name = cmp.Or(opts.Name, "fitted")
+	estimator.Version = 0
+	estimator.Description = fmt.Sprintf("Fitted to %d samples", len(samples))
+	estimator.Deprecated = ""
+
+	report := Report{
+		Samples:      len(samples),
+		Features:     slices.Clone(names),
+		Dropped:      dropped,
+		Support:      support,
+		Coefficients: fitted,
+		Intercept:    intercept,
+	}
+	report.measure(estimator, samples, explanations)
+	report.Outliers = d.outliers(coefs, cmp.Or(opts.OutlierThreshold, defaultOutlierThreshold), opts.Robust)
+	for i := range report.Outliers {
+		o := &report.Outliers[i]
+		o.Tokens = samples[o.Index].Tokens
+		o.Estimate = estimator.EstimateFromStats(explanations[o.Index].Stats)
+	}
+	return estimator, report, nil
+}
+
+// supportOf returns the number of explained samples each fittable
+// feature occurs in.
+func supportOf(explanations []tokenestimate.Explanation) map[string]int {
+	support := make(map[string]int, len(fittable))
+	for _, name := range fittable {
+		support[name] = 0
+	}
+	for _, x := range explanations {
+		for _, c := range x.Contributions {
+			if _, ok := support[c.Feature]; ok {
+				support[c.Feature]++
+			}
+		}
+	}
+	return support
+}
+
+// selectFeatures returns the features to fit and the features dropped for
+// lack of support.
+func selectFeatures(opts FitOptions, support map[string]int) (names, dropped []string, err error) {
+	candidates, minSupport := opts.Features, opts.MinSupport
+	if candidates == nil {
+		candidates, minSupport = fittable, max(minSupport, 1)
+	}
+	for _, name := range candidates {
+		n, ok := support[name]
+		switch {
+		case !ok:
+			return nil, nil, fmt.Errorf("unknown feature: %s", name)
+		case n == 0 && minSupport == 0:
+			return nil, nil, fmt.Errorf("feature %s does not occur in the samples", name)
+		case n >= minSupport:
+			names = append(names, name)
+		case n > 0 || opts.Features != nil:
+			dropped = append(dropped, name)
+		}
+	}
+	return names, dropped, nil
+}
+
+// newDesign builds the least squares problem for fitting names.
+func newDesign(base *tokenestimate.Estimator, samples []Sample, explanations []tokenestimate.Explanation,
+	names []string, opts FitOptions) *design {
+	d := &design{names: slices.Clone(names)}
+	if opts.Intercept {
+		d.names = append(d.names, "")
+	}
+	column := make(map[string]int, len(d.names))
+	for j, name := range d.names {
+		column[name] = j
+	}
+
+	// Unknown characters and invalid bytes priced like symbols move with
+	// the Symbols coefficient.
+	if _, ok := column["Symbols"]; ok && base.UnknownPolicy != tokenestimate.UnknownAsCategory {
+		column["Unknown"] = column["Symbols"]
+		if base.UTF8Mode != tokenestimate.UTF8ByteFallback {
+			column["InvalidBytes"] = column["Symbols"]
+		}
+	}
+
+	d.x = newMatrix(len(samples), len(d.names))
+	d.y = make([]float64, len(samples))
+	d.weights = make([]float64, len(samples))
+	for i, x := range explanations {
+		offset := x.Total
+		if opts.Intercept {
+			offset -= x.Intercept
+			d.x.col(column[""])[i] = 1
+		}
+		for _, c := range x.Contributions {
+			if j, ok := column[c.Feature]; ok {
+				d.x.col(j)[i] += float64(c.Count)
+				offset -= c.Tokens
+			}
+		}
+		d.y[i] = float64(samples[i].Tokens) - offset
+		d.weights[i] = 1
+		if opts.Relative {
+			d.weights[i] = 1 / float64(max(samples[i].Tokens, 1))
+		}
+	}
+	return d
+}
+
+// solve returns the coefficients of the columns of d.
+func (d *design) solve(opts FitOptions) ([]float64, error) {
+	switch {
+	case opts.Quantile != 0:
+		return d.solveQuantile(opts)
+	case opts.Robust:
+		return d.solveRobust(opts)
+	}
+	return d.solveWeighted(opts, d.weights)
+}
+
+// quantileIterations bounds the reweighting rounds of solveQuantile.
+const quantileIterations = 200
+
+// solveQuantile returns the coefficients that minimize the pinball loss
+// of opts.Quantile, which prices a sample below the fit at the quantile
+// and one above it at one minus the quantile, by iteratively reweighted
+// least squares: each round weights a sample by its share of the loss
+// over its squared residual in the previous fit.
+func (d *design) solveQuantile(opts FitOptions) ([]float64, error) {
+	weights := slices.Clone(d.weights)
+	coefs, err := d.solveWeighted(opts, weights)
+	if err != nil {
+		return nil, err
+	}
+	for range quantileIterations {
+		residuals := d.residuals(coefs)
+		scale := 0.0
+		for _, r := range residuals {
+			scale += math.Abs(r)
+		}
+		// Residuals near zero would get unbounded weights.
+		floor := 1e-6 * (1 + scale/float64(len(residuals)))
+		for i, r := range residuals {
+			share := opts.Quantile
+			if r < 0 {
+				share = 1 - opts.Quantile
+			}
+			weights[i] = d.weights[i] * math.Sqrt(share/max(math.Abs(r), floor))
+		}
+
+		next, err := d.solveWeighted(opts, weights)
+		if err != nil {
+			return nil, err
+		}
+		change, size := 0.0, 0.0
+		for j := range coefs {
+			change = max(change, math.Abs(next[j]-coefs[j]))
+			size = max(size, math.Abs(next[j]))
+		}
+		coefs = next
+		if change <= 1e-9*(1+size) {
+			break
+		}
+	}
+	return coefs, nil
+}
+
+// solveWeighted returns the coefficients of the columns of d with each
+// sample weighted by weights.
+func (d *design) solveWeighted(opts FitOptions, weights []float64) ([]float64, error) {
+	x := newMatrix(d.x.rows, d.x.cols)
+	y := make([]float64, len(d.y))
+	for i, w := range weights {
+		y[i] = w * d.y[i]
+	}
+	for j := range d.x.cols {
+		for i, v := range d.x.col(j) {
+			x.col(j)[i] = weights[i] * v
+		}
+	}
+	if opts.Ridge > 0 {
+		x, y = d.withRidge(x, y, opts.Ridge)
+	}
+
+	var coefs []float64
+	var err error
+	if opts.Method == NNLS {
+		coefs, err = nonNegativeLeastSquares(x, y)
+	} else {
+		coefs, err = leastSquares(x, y, columns(x.cols))
+	}
+	if opts.Lasso > 0 {
+		// The least squares solution is only a starting point, and a
+		// rank-deficient design has none.
+		if err != nil {
+			coefs = make([]float64, x.cols)
+		}
+		return coordinateDescent(x, y, d.x.rows, coefs, d.penalized(), opts.Lasso, opts.Method == NNLS)
+	}
+	if rank := (*errRankDeficient)(nil); errors.As(err, &rank) {
+		return nil, fmt.Errorf("feature %s is a linear combination of the other features in the samples", d.name(rank.column))
+	}
+	return coefs, err
+}
+
+// penalized reports for each column whether its coefficient is
+// penalized: all but the intercept.
+func (d *design) penalized() []bool {
+	penalized := make([]bool, len(d.names))
+	for j, name := range d.names {
+		penalized[j] = name != ""
+	}
+	return penalized
+}
+
+// withRidge returns the weighted design x, y extended with a row per
+// penalized coefficient, so that least squares on the result minimizes
+// the mean squared residual plus ridge times the sum of the squared
+// coefficients.
+func (d *design) withRidge(x *matrix, y []float64, ridge float64) (*matrix, []float64) {
+	n := x.rows
+	penalized := d.penalized()
+	extra := 0
+	for _, p := range penalized {
+		if p {
+			extra++
+		}
+	}
+
+	ext := newMatrix(n+extra, x.cols)
+	row := n
+	scale := math.Sqrt(ridge * float64(n))
+	for j := range x.cols {
+		copy(ext.col(j), x.col(j))
+		if penalized[j] {
+			ext.col(j)[row] = scale
+			row++
+		}
+	}
+	return ext, append(slices.Clone(y), make([]float64, extra)...)
+}
+
+// name returns the name of column j for error messages.
+func (d *design) name(j int) string {
+	return cmp.Or(d.names[j], "intercept")
+}
+
+// columns returns the indices 0 to n-1.
+func columns(n int) []int {
+	cols := make([]int, n)
+	for j := range cols {
+		cols[j] = j
+	}
+	return cols
+}
+
+// measure fills in the error statistics of r for estimator on samples.
+func (r *Report) measure(estimator *tokenestimate.Estimator, samples []Sample, explanations []tokenestimate.Explanation) {
+	var relErr, squares, mean float64
+	labeled := 0
+	for _, s := range samples {
+		mean += float64(s.Tokens)
+	}
+	mean /= float64(len(samples))
+
+	var total float64
+	covered := 0
+	for i, s := range samples {
+		diff := float64(estimator.EstimateFromStats(explanations[i].Stats) - s.Tokens)
+		if diff >= 0 {
+			covered++
+		}
+		squares += diff * diff
+		total += (float64(s.Tokens) - mean) * (float64(s.Tokens) - mean)
+		if s.Tokens > 0 {
+			relErr += math.Abs(diff) / float64(s.Tokens)
+			labeled++
+		}
+	}
+	if labeled > 0 {
+		r.MeanRelError = relErr / float64(labeled)
+	}
+	r.RMSE = math.Sqrt(squares / float64(len(samples)))
+	r.Coverage = float64(covered) / float64(len(samples))
+	if total > 0 {
+		r.R2 = 1 - squares/total
+	}
+}
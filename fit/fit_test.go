@@ -0,0 +1,348 @@
+package fit
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/infinigence/tokenestimate/eval"
+)
+
+// teacher labels the synthetic samples, standing in for a tokenizer whose
+// token counts are linear in the features.
+func teacher(t *testing.T) *tokenestimate.Estimator {
+	e, err := tokenestimate.NewEstimatorWithName("kimi-k2@6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// syntheticTexts returns n random texts mixing English, Chinese, Russian,
+// numbers and punctuation.
+func syntheticTexts(n int, seed uint64) []string {
+	pieces := []string{
+		"the", "quick", "brown", "fox", "tokenization", "a", "of", "estimate",
+		"你好", "世界", "测试句子", "привет", "мир", "42", "2024", "3.14159",
+		"!", ",", "?", "(x)", "  ",
+	}
+	rng := rand.New(rand.NewPCG(seed, 0))
+	texts := make([]string, n)
+	for i := range texts {
+		words := make([]string, 5+rng.IntN(60))
+		for j := range words {
+			words[j] = pieces[rng.IntN(len(pieces))]
+		}
+		texts[i] = strings.Join(words, " ")
+	}
+	return texts
+}
+
+// label returns samples of texts labeled by e.
+func label(e *tokenestimate.Estimator, texts []string) []Sample {
+	samples := make([]Sample, len(texts))
+	for i, text := range texts {
+		samples[i] = Sample{Text: text, Tokens: e.Estimate(text)}
+	}
+	return samples
+}
+
+func TestFit(t *testing.T) {
+	e := teacher(t)
+	samples := label(e, syntheticTexts(300, 1))
+
+	for _, method := range []Method{OLS, NNLS} {
+		fitted, report, err := Fit(samples, FitOptions{Base: e, Method: method, Name: "mine"})
+		if err != nil {
+			t.Fatalf("Method %d: unexpected error: %v", method, err)
+		}
+		if fitted.Name != "mine" || report.Samples != len(samples) {
+			t.Errorf("Method %d: unexpected name %q or sample count %d", method, fitted.Name, report.Samples)
+		}
+		if report.MeanRelError > 0.03 || report.R2 < 0.99 {
+			t.Errorf("Method %d: poor fit: %+v", method, report)
+		}
+
+		// The fitted model generalizes to texts it was not trained on.
+		for _, text := range syntheticTexts(50, 2) {
+			want := e.Estimate(text)
+			if got := fitted.Estimate(text); math.Abs(float64(got-want)) > math.Max(2, 0.05*float64(want)) {
+				t.Errorf("Method %d: Estimate(%q) = %d, teacher %d", method, text, got, want)
+			}
+		}
+
+		if method == NNLS {
+			for name, coef := range report.Coefficients {
+				if coef < 0 {
+					t.Errorf("NNLS gave %s a negative coefficient %v", name, coef)
+				}
+			}
+		}
+	}
+}
+
+func TestFitFeatures(t *testing.T) {
+	e := teacher(t)
+	samples := label(e, syntheticTexts(100, 3))
+
+	t.Run("Subset keeps the other coefficients", func(t *testing.T) {
+		fitted, report, err := Fit(samples, FitOptions{Base: e, Features: []string{"LatinLetters", "Words"}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(report.Coefficients) != 2 {
+			t.Errorf("Expected 2 coefficients, got %v", report.Coefficients)
+		}
+		// The teacher's other coefficients account for the rest exactly.
+		if report.MeanRelError > 0.03 {
+			t.Errorf("Poor fit: %+v", report)
+		}
+		if got, want := fitted.Estimate("你好世界"), e.Estimate("你好世界"); got != want {
+			t.Errorf("Expected Chinese to be priced as before, got %d instead of %d", got, want)
+		}
+	})
+
+	t.Run("Intercept", func(t *testing.T) {
+		shifted := make([]Sample, len(samples))
+		for i, s := range samples {
+			shifted[i] = Sample{Text: s.Text, Tokens: s.Tokens + 5}
+		}
+		_, report, err := Fit(shifted, FitOptions{Base: e, Intercept: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if math.Abs(report.Intercept-5) > 1 {
+			t.Errorf("Expected an intercept near 5, got %v", report.Intercept)
+		}
+	})
+
+	t.Run("Relative", func(t *testing.T) {
+		if _, report, err := Fit(samples, FitOptions{Base: e, Relative: true}); err != nil || report.MeanRelError > 0.03 {
+			t.Errorf("Unexpected result %+v, %v", report, err)
+		}
+	})
+}
+
+// skewed returns samples labeled by e in which LatinExtended occurs in only
+// two texts, whose labels are off by several tokens, as happens with small
+// or unrepresentative training sets.
+func skewed(e *tokenestimate.Estimator) []Sample {
+	samples := label(e, syntheticTexts(200, 5))
+	for i, text := range []string{"the café of the quick brown fox", "a naïve fox, quick and brown"} {
+		samples = append(samples, Sample{Text: text, Tokens: e.Estimate(text) + 4 + 2*i})
+	}
+	return samples
+}
+
+func TestFitSupport(t *testing.T) {
+	e := teacher(t)
+	samples := skewed(e)
+
+	_, report, err := Fit(samples, FitOptions{Base: e})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Support["LatinExtended"] != 2 || report.Support["KhmerChars"] != 0 {
+		t.Errorf("Unexpected support %v", report.Support)
+	}
+	wild := report.Coefficients["LatinExtended"]
+
+	fitted, report, err := Fit(samples, FitOptions{Base: e, MinSupport: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(report.Dropped, []string{"LatinExtended"}) {
+		t.Errorf("Expected LatinExtended to be dropped, got %v", report.Dropped)
+	}
+	if _, ok := report.Coefficients["LatinExtended"]; ok {
+		t.Errorf("Expected no coefficient for LatinExtended, got %v", report.Coefficients)
+	}
+	// The base prices the dropped feature, unlike the fit to two samples.
+	text := "a résumé of the naïve café estimate"
+	if got, want := fitted.Estimate(text), e.Estimate(text); math.Abs(float64(got-want)) > 1 {
+		t.Errorf("Expected about %d tokens, got %d (fitted coefficient without support %v)", want, got, wild)
+	}
+
+	// Requested features are dropped, not rejected, with a MinSupport.
+	_, report, err = Fit(samples, FitOptions{Base: e, Features: []string{"LatinLetters", "KhmerChars"}, MinSupport: 1})
+	if err != nil || !slices.Equal(report.Dropped, []string{"KhmerChars"}) {
+		t.Errorf("Expected KhmerChars to be dropped, got %v, %v", report.Dropped, err)
+	}
+}
+
+func TestFitRegularization(t *testing.T) {
+	e := teacher(t)
+	samples := skewed(e)
+	_, ols, err := Fit(samples, FitOptions{Base: e, Relative: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts FitOptions
+	}{
+		{"Ridge", FitOptions{Ridge: 0.01}},
+		{"Lasso", FitOptions{Lasso: 0.001}},
+		{"Elastic net", FitOptions{Ridge: 0.005, Lasso: 0.0005}},
+		{"Non-negative lasso", FitOptions{Lasso: 0.001, Method: NNLS}},
+		{"Intercept", FitOptions{Ridge: 0.01, Intercept: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Base, tt.opts.Relative = e, true
+			_, report, err := Fit(samples, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			// The penalty tames the coefficient fitted to two noisy
+			// samples at little cost elsewhere.
+			if got, wild := report.Coefficients["LatinExtended"], ols.Coefficients["LatinExtended"]; math.Abs(got) >= math.Abs(wild)/2 {
+				t.Errorf("Expected LatinExtended to shrink from %v, got %v", wild, got)
+			}
+			if report.MeanRelError > 0.05 {
+				t.Errorf("Poor fit: %+v", report)
+			}
+			if tt.opts.Method == NNLS {
+				for name, coef := range report.Coefficients {
+					if coef < 0 {
+						t.Errorf("NNLS gave %s a negative coefficient %v", name, coef)
+					}
+				}
+			}
+		})
+	}
+
+	// A penalty determines coefficients that the samples alone do not.
+	dependent := []Sample{{Text: "ab", Tokens: 1}, {Text: "cd", Tokens: 1}, {Text: "ef", Tokens: 1}}
+	for _, opts := range []FitOptions{{Ridge: 0.1}, {Lasso: 0.1}} {
+		opts.Features = []string{"LatinLetters", "Words"}
+		if _, _, err := Fit(dependent, opts); err != nil {
+			t.Errorf("%+v: unexpected error: %v", opts, err)
+		}
+	}
+}
+
+func TestFitQuantile(t *testing.T) {
+	// Labels scattered up to 20% around the teacher, like a tokenizer that
+	// the linear model only approximates.
+	e := teacher(t)
+	noisy := func(n int, seed uint64) []Sample {
+		rng := rand.New(rand.NewPCG(seed, 1))
+		samples := label(e, syntheticTexts(n, seed))
+		for i := range samples {
+			samples[i].Tokens = int(math.Round(float64(samples[i].Tokens) * (0.8 + 0.4*rng.Float64())))
+		}
+		return samples
+	}
+	train, test := noisy(400, 8), noisy(200, 9)
+
+	_, mean, err := Fit(train, FitOptions{Base: e, Relative: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fitted, report, err := Fit(train, FitOptions{Base: e, Relative: true, Quantile: 0.9})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Coverage < 0.85 || report.Coverage > 0.95 {
+		t.Errorf("Expected 90%% of the training samples to be covered, got %.3f (mean fit %.3f)", report.Coverage, mean.Coverage)
+	}
+	if got := eval.Evaluate(fitted, test).Coverage; got < 0.8 || got > 0.97 {
+		t.Errorf("Expected about 90%% of the held-out samples to be covered, got %.3f", got)
+	}
+	if mean.Coverage > 0.7 {
+		t.Errorf("Expected the mean fit to cover far fewer samples, got %.3f", mean.Coverage)
+	}
+}
+
+func TestFitRobust(t *testing.T) {
+	// One sample in ten is labeled by a tokenizer encoding to 1.8 times the
+	// tokens.
+	e := teacher(t)
+	samples := label(e, syntheticTexts(300, 10))
+	mislabeled := map[int]bool{}
+	for i := 5; i < len(samples); i += 10 {
+		samples[i].Tokens = int(math.Round(1.8 * float64(samples[i].Tokens)))
+		mislabeled[i] = true
+	}
+	held := label(e, syntheticTexts(100, 11))
+
+	plain, plainReport, err := Fit(samples, FitOptions{Base: e, Relative: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	robust, report, err := Fit(samples, FitOptions{Base: e, Relative: true, Robust: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, skewed := eval.Evaluate(robust, held).MAPE, eval.Evaluate(plain, held).MAPE; got > 0.02 || got > skewed/3 {
+		t.Errorf("Expected the robust fit to ignore the mislabeled samples, got a MAPE of %.3f against %.3f", got, skewed)
+	}
+
+	if len(report.Outliers) != len(mislabeled) {
+		t.Errorf("Expected %d outliers, got %d: %+v", len(mislabeled), len(report.Outliers), report.Outliers)
+	}
+	for _, o := range report.Outliers {
+		if !mislabeled[o.Index] || o.Score <= 0 || o.Weight != 0 || o.Tokens != samples[o.Index].Tokens {
+			t.Errorf("Unexpected outlier %+v", o)
+		}
+	}
+	// Outliers are reported without Robust too, at full weight.
+	if len(plainReport.Outliers) == 0 || plainReport.Outliers[0].Weight != 1 {
+		t.Errorf("Expected the plain fit to report outliers at full weight, got %+v", plainReport.Outliers)
+	}
+	if _, r, _ := Fit(samples, FitOptions{Base: e, Relative: true, OutlierThreshold: -1}); r.Outliers != nil {
+		t.Errorf("Expected a negative threshold to report no outliers, got %+v", r.Outliers)
+	}
+
+	// Without outliers the robust fit is as good as least squares.
+	clean := label(e, syntheticTexts(300, 12))
+	fitted, _, err := Fit(clean, FitOptions{Base: e, Robust: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := eval.Evaluate(fitted, held).MAPE; got > 0.03 {
+		t.Errorf("Expected a robust fit to clean samples to generalize, got a MAPE of %.3f", got)
+	}
+}
+
+func TestFitErrors(t *testing.T) {
+	e := teacher(t)
+	samples := label(e, syntheticTexts(20, 4))
+
+	tests := []struct {
+		name    string
+		samples []Sample
+		opts    FitOptions
+	}{
+		{"No samples", nil, FitOptions{}},
+		{"Unknown feature", samples, FitOptions{Features: []string{"Letters"}}},
+		{"Feature without coefficient", samples, FitOptions{Features: []string{"DictionaryTokens"}}},
+		{"Absent feature", samples, FitOptions{Features: []string{"KhmerChars"}}},
+		{"Too few samples", samples[:1], FitOptions{Features: []string{"LatinLetters", "Words"}}},
+		{"Negative count", []Sample{{Text: "a", Tokens: -1}}, FitOptions{}},
+		{"Quantile out of range", samples, FitOptions{Quantile: 1}},
+		{"Robust quantile", samples, FitOptions{Quantile: 0.9, Robust: true}},
+		{"Dependent features", []Sample{{Text: "ab", Tokens: 1}, {Text: "cd", Tokens: 1}, {Text: "ef", Tokens: 1}}, FitOptions{Features: []string{"LatinLetters", "Words"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Fit(tt.samples, tt.opts); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	e := tokenestimate.NewEstimator()
+	for _, name := range Features() {
+		if _, err := e.WithCoefficients(0, map[string]float64{name: 1}); err != nil {
+			t.Errorf("Feature %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package fit
+
+import (
+	"fmt"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// Label returns texts labeled with their token counts by counter, such as
+// an exact tokenizer, as samples to fit or evaluate a preset on.
+func Label(counter tokenestimate.TokenCounter, texts []string) ([]Sample, error) {
+	samples := make([]Sample, len(texts))
+	for i, text := range texts {
+		n, err := counter.Count(text)
+		if err != nil {
+			return nil, fmt.Errorf("text %d: %w", i, err)
+		}
+		samples[i] = Sample{Text: text, Tokens: n}
+	}
+	return samples, nil
+}
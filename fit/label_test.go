@@ -0,0 +1,34 @@
+package fit
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// wordCounter counts whitespace-separated words as tokens, and fails on
+// texts containing "fail".
+type wordCounter struct{}
+
+func (wordCounter) Count(text string) (int, error) {
+	if strings.Contains(text, "fail") {
+		return 0, errors.New("cannot count")
+	}
+	return len(strings.Fields(text)), nil
+}
+
+func TestLabel(t *testing.T) {
+	samples, err := Label(wordCounter{}, []string{"one two", "", "three four five"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []Sample{{Text: "one two", Tokens: 2}, {Text: "", Tokens: 0}, {Text: "three four five", Tokens: 3}}
+	if !slices.Equal(samples, want) {
+		t.Errorf("Expected %v, got %v", want, samples)
+	}
+
+	if _, err := Label(wordCounter{}, []string{"ok", "fail"}); err == nil || !strings.Contains(err.Error(), "text 1") {
+		t.Errorf("Expected the error of the second text, got %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package fit
+
+import (
+	"errors"
+	"math"
+)
+
+// errRankDeficient is returned by leastSquares when the columns of the
+// design are linearly dependent.
+type errRankDeficient struct {
+	column int // a column that depends on the ones before it
+}
+
+func (err *errRankDeficient) Error() string {
+	return "columns are linearly dependent"
+}
+
+// rankTolerance is the size, relative to the largest column, below which
+// a column left after orthogonalizing against the earlier ones counts as
+// linearly dependent.
+const rankTolerance = 1e-10
+
+// matrix is a dense matrix stored by column, which suits the column
+// operations of Householder QR.
+type matrix struct {
+	rows, cols int
+	data       []float64 // column j is data[j*rows : (j+1)*rows]
+}
+
+// newMatrix returns a rows×cols matrix of zeros.
+func newMatrix(rows, cols int) *matrix {
+	return &matrix{rows: rows, cols: cols, data: make([]float64, rows*cols)}
+}
+
+// col returns column j.
+func (m *matrix) col(j int) []float64 {
+	return m.data[j*m.rows : (j+1)*m.rows]
+}
+
+// leastSquares returns the x that minimizes |a[:, cols]·x - b|, using the
+// Householder QR decomposition of the selected columns. a and b are not
+// modified.
+func leastSquares(a *matrix, b []float64, cols []int) ([]float64, error) {
+	n, p := a.rows, len(cols)
+	if p == 0 {
+		return nil, nil
+	}
+	if n < p {
+		return nil, errors.New("fewer rows than columns")
+	}
+
+	q := newMatrix(n, p)
+	norms := 0.0
+	for k, j := range cols {
+		copy(q.col(k), a.col(j))
+		norms = max(norms, norm(q.col(k)))
+	}
+	y := append([]float64(nil), b...)
+	diag := make([]float64, p)
+
+	for k := range p {
+		v := q.col(k)[k:]
+		alpha := norm(v)
+		if alpha <= rankTolerance*norms {
+			return nil, &errRankDeficient{column: cols[k]}
+		}
+		if v[0] > 0 {
+			alpha = -alpha
+		}
+		// Reflect v onto alpha·e1, storing the Householder vector in v.
+		v[0] -= alpha
+		vv := dot(v, v)
+		for j := k + 1; j < p; j++ {
+			reflect(v, q.col(j)[k:], vv)
+		}
+		reflect(v, y[k:], vv)
+		diag[k] = alpha
+	}
+
+	// Back substitution with the upper triangle R.
+	x := make([]float64, p)
+	for k := p - 1; k >= 0; k-- {
+		s := y[k]
+		for j := k + 1; j < p; j++ {
+			s -= q.col(j)[k] * x[j]
+		}
+		x[k] = s / diag[k]
+	}
+	return x, nil
+}
+
+// reflect applies the Householder reflection I - 2vvᵀ/(vᵀv) to u.
+func reflect(v, u []float64, vv float64) {
+	f := 2 * dot(v, u) / vv
+	for i := range u {
+		u[i] -= f * v[i]
+	}
+}
+
+// nonNegativeLeastSquares returns the x >= 0 that minimizes |a·x - b|,
+// with the active set method of Lawson and Hanson.
+func nonNegativeLeastSquares(a *matrix, b []float64) ([]float64, error) {
+	p := a.cols
+	x := make([]float64, p)
+	passive := make([]bool, p)
+	const tolerance = 1e-10
+
+	for iter := 0; iter < 3*p+10; iter++ {
+		// The gradient of the objective points to the column that would
+		// reduce it most; stop when no inactive column helps.
+		w := gradient(a, b, x)
+		best, bestW := -1, tolerance*(1+norm(b))
+		for j := range p {
+			if !passive[j] && w[j] > bestW {
+				best, bestW = j, w[j]
+			}
+		}
+		if best < 0 {
+			return x, nil
+		}
+		passive[best] = true
+
+		for {
+			cols := activeColumns(passive)
+			s, err := leastSquares(a, b, cols)
+			if err != nil {
+				return nil, err
+			}
+			if allPositive(s) {
+				clear(x)
+				for k, j := range cols {
+					x[j] = s[k]
+				}
+				break
+			}
+			// Move from x towards s until a coefficient reaches zero,
+			// and drop the columns whose coefficients did.
+			alpha := 1.0
+			for k, j := range cols {
+				if s[k] <= 0 && x[j] > s[k] {
+					alpha = min(alpha, x[j]/(x[j]-s[k]))
+				}
+			}
+			for k, j := range cols {
+				x[j] += alpha * (s[k] - x[j])
+				if x[j] <= tolerance {
+					x[j] = 0
+					passive[j] = false
+				}
+			}
+		}
+	}
+	return nil, errors.New("non-negative least squares did not converge")
+}
+
+// gradient returns aᵀ(b - a·x), the negative gradient of |a·x - b|²/2.
+func gradient(a *matrix, b, x []float64) []float64 {
+	r := append([]float64(nil), b...)
+	for j := range a.cols {
+		if x[j] != 0 {
+			for i, v := range a.col(j) {
+				r[i] -= v * x[j]
+			}
+		}
+	}
+	w := make([]float64, a.cols)
+	for j := range a.cols {
+		w[j] = dot(a.col(j), r)
+	}
+	return w
+}
+
+// activeColumns returns the indices where passive is true.
+func activeColumns(passive []bool) []int {
+	var cols []int
+	for j, p := range passive {
+		if p {
+			cols = append(cols, j)
+		}
+	}
+	return cols
+}
+
+// allPositive reports whether every element of s is positive.
+func allPositive(s []float64) bool {
+	for _, v := range s {
+		if v <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func dot(u, v []float64) float64 {
+	s := 0.0
+	for i := range u {
+		s += u[i] * v[i]
+	}
+	return s
+}
+
+func norm(v []float64) float64 {
+	return math.Sqrt(dot(v, v))
+}
+
+// coordinateDescent returns the x that minimizes |a·x - b|²/n plus lasso
+// times the sum of |x[j]| over the penalized columns, by cyclic coordinate
+// descent from x0, optionally constrained to x >= 0. n is the number of
+// rows the mean is over, which excludes the rows that add a ridge
+// penalty. x0 is not modified.
+func coordinateDescent(a *matrix, b []float64, n int, x0 []float64, penalized []bool, lasso float64, nonNegative bool) ([]float64, error) {
+	x := append([]float64(nil), x0...)
+	if nonNegative {
+		for j := range x {
+			x[j] = max(x[j], 0)
+		}
+	}
+	r := append([]float64(nil), b...)
+	for j := range a.cols {
+		for i, v := range a.col(j) {
+			r[i] -= v * x[j]
+		}
+	}
+	squares := make([]float64, a.cols)
+	for j := range a.cols {
+		squares[j] = dot(a.col(j), a.col(j)) / float64(n)
+	}
+	tolerance := 1e-12 * (1 + norm(b))
+
+	for range 10000 {
+		change := 0.0
+		for j := range a.cols {
+			if squares[j] == 0 {
+				continue
+			}
+			// Minimize over x[j] alone: soft-threshold the least squares
+			// update by the penalty.
+			c := dot(a.col(j), r)/float64(n) + squares[j]*x[j]
+			if penalized[j] {
+				c = math.Copysign(max(math.Abs(c)-lasso/2, 0), c)
+			}
+			next := c / squares[j]
+			if nonNegative {
+				next = max(next, 0)
+			}
+			if delta := next - x[j]; delta != 0 {
+				for i, v := range a.col(j) {
+					r[i] -= v * delta
+				}
+				x[j] = next
+				change = max(change, math.Abs(delta)*math.Sqrt(squares[j]*float64(n)))
+			}
+		}
+		if change <= tolerance {
+			return x, nil
+		}
+	}
+	return nil, errors.New("coordinate descent did not converge")
+}
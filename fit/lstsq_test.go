@@ -0,0 +1,111 @@
+package fit
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// newMatrixRows returns the matrix with the given rows.
+func newMatrixRows(rows [][]float64) *matrix {
+	m := newMatrix(len(rows), len(rows[0]))
+	for i, row := range rows {
+		for j, v := range row {
+			m.col(j)[i] = v
+		}
+	}
+	return m
+}
+
+func closeTo(got, want []float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLeastSquares(t *testing.T) {
+	a := newMatrixRows([][]float64{{1, 0}, {0, 1}, {1, 1}, {2, 1}})
+	b := []float64{1, 2, 3, 4}
+	x, err := leastSquares(a, b, []int{0, 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []float64{1, 2}; !closeTo(x, want) {
+		t.Errorf("Expected %v, got %v", want, x)
+	}
+
+	// Inconsistent: the normal equations 6x+3y = 10, 3x+3y = 8 give
+	// (2/3, 2).
+	x, err = leastSquares(a, []float64{1, 2, 3, 3}, []int{0, 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []float64{2.0 / 3, 2}; !closeTo(x, want) {
+		t.Errorf("Expected %v, got %v", want, x)
+	}
+
+	dependent := newMatrixRows([][]float64{{1, 2}, {2, 4}, {3, 6}})
+	var rank *errRankDeficient
+	if _, err := leastSquares(dependent, []float64{1, 2, 3}, []int{0, 1}); !errors.As(err, &rank) || rank.column != 1 {
+		t.Errorf("Expected column 1 to be dependent, got %v", err)
+	}
+}
+
+func TestNonNegativeLeastSquares(t *testing.T) {
+	tests := []struct {
+		a    [][]float64
+		b    []float64
+		want []float64
+	}{
+		{[][]float64{{1, 0}, {0, 1}}, []float64{1, -1}, []float64{1, 0}},
+		{[][]float64{{1, 0}, {0, 1}, {1, 1}}, []float64{1, 2, 3}, []float64{1, 2}},
+		{[][]float64{{1, 1}, {1, 2}, {1, 3}}, []float64{3, 2, 1}, []float64{2, 0}},
+		{[][]float64{{1}, {1}}, []float64{-1, -2}, []float64{0}},
+	}
+	for _, tt := range tests {
+		x, err := nonNegativeLeastSquares(newMatrixRows(tt.a), tt.b)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !closeTo(x, tt.want) {
+			t.Errorf("NNLS(%v, %v) = %v, want %v", tt.a, tt.b, x, tt.want)
+		}
+	}
+}
+
+func TestCoordinateDescent(t *testing.T) {
+	identity := [][]float64{{1, 0}, {0, 1}}
+	tests := []struct {
+		name        string
+		a           [][]float64
+		b           []float64
+		penalized   []bool
+		lasso       float64
+		nonNegative bool
+		want        []float64
+	}{
+		{"Least squares", [][]float64{{1, 0}, {0, 1}, {1, 1}, {2, 1}}, []float64{1, 2, 3, 4}, []bool{true, true}, 0, false, []float64{1, 2}},
+		// With orthonormal columns the solution soft-thresholds b by the
+		// penalty.
+		{"Soft threshold", identity, []float64{3, -0.5}, []bool{true, true}, 1, false, []float64{2, 0}},
+		{"Negative", identity, []float64{3, -2}, []bool{true, true}, 1, false, []float64{2, -1}},
+		{"Non-negative", identity, []float64{3, -2}, []bool{true, true}, 1, true, []float64{2, 0}},
+		{"Unpenalized", identity, []float64{3, 4}, []bool{true, false}, 10, false, []float64{0, 4}},
+	}
+	for _, tt := range tests {
+		a := newMatrixRows(tt.a)
+		x, err := coordinateDescent(a, tt.b, a.rows, make([]float64, a.cols), tt.penalized, tt.lasso, tt.nonNegative)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !closeTo(x, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, x, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package fit
+
+import (
+	"math"
+	"slices"
+)
+
+// Tuning of robust fits.
+const (
+	// robustIterations bounds the reweighting rounds of solveRobust.
+	robustIterations = 50
+
+	// biweightTuning is the residual, in robust standard deviations, at
+	// which Tukey's biweight gives a sample no weight. 4.685 keeps 95% of
+	// the efficiency of least squares when there are no outliers.
+	biweightTuning = 4.685
+
+	// defaultOutlierThreshold is the default OutlierThreshold.
+	defaultOutlierThreshold = 3.5
+)
+
+// Outlier is a training sample the fitted model reproduces far worse than
+// the others, typically because it is mislabeled, such as with the token
+// count of another tokenizer.
+type Outlier struct {
+	Index    int     // Position of the sample in the samples fitted
+	Tokens   int     // Token count of the sample
+	Estimate int     // Estimate of the fitted model
+	Score    float64 // Residual in robust standard deviations; positive if the sample has more tokens than estimated
+	Weight   float64 // Weight of the sample in the fit, from 0 to 1; 1 unless Robust
+}
+
+// solveRobust returns the coefficients that minimize Tukey's biweight loss
+// of the residuals, by iteratively reweighted least squares starting from
+// the least squares fit: each round weights a sample by the biweight of
+// its residual in the previous fit, so that samples far off the others
+// lose their weight, while the residuals of the rest are fitted by least
+// squares.
+func (d *design) solveRobust(opts FitOptions) ([]float64, error) {
+	coefs, err := d.solveWeighted(opts, d.weights)
+	if err != nil {
+		return nil, err
+	}
+	weights := make([]float64, len(d.weights))
+	for range robustIterations {
+		for i, z := range d.scores(coefs) {
+			weights[i] = d.weights[i] * math.Sqrt(biweight(z))
+		}
+
+		next, err := d.solveWeighted(opts, weights)
+		if err != nil {
+			return nil, err
+		}
+		change, size := 0.0, 0.0
+		for j := range coefs {
+			change = max(change, math.Abs(next[j]-coefs[j]))
+			size = max(size, math.Abs(next[j]))
+		}
+		coefs = next
+		if change <= 1e-9*(1+size) {
+			break
+		}
+	}
+	return coefs, nil
+}
+
+// residuals returns the weighted residuals of the samples of d under
+// coefs: in tokens, or relative with FitOptions.Relative.
+func (d *design) residuals(coefs []float64) []float64 {
+	residuals := make([]float64, len(d.y))
+	for i := range residuals {
+		r := d.y[i]
+		for j := range d.x.cols {
+			r -= d.x.col(j)[i] * coefs[j]
+		}
+		residuals[i] = d.weights[i] * r
+	}
+	return residuals
+}
+
+// scores returns the residuals of d under coefs in robust standard
+// deviations. Integer token counts differ from any real-valued model by up
+// to half a token, which dominates the relative residuals of short texts,
+// so each residual is measured against the spread of the residuals
+// combined with its own rounding.
+func (d *design) scores(coefs []float64) []float64 {
+	residuals := d.residuals(coefs)
+	scale := robustScale(residuals)
+	for i, r := range residuals {
+		residuals[i] = r / math.Hypot(scale, 0.5*d.weights[i])
+	}
+	return residuals
+}
+
+// robustScale estimates the standard deviation of the residuals from their
+// median absolute deviation, which outliers barely move. Residuals that
+// are mostly zero would give a zero scale and make every other one
+// infinitely far off, so the scale has a small floor.
+func robustScale(residuals []float64) float64 {
+	median := medianOf(residuals)
+	deviations := make([]float64, len(residuals))
+	mean := 0.0
+	for i, r := range residuals {
+		deviations[i] = math.Abs(r - median)
+		mean += math.Abs(r)
+	}
+	mean /= float64(len(residuals))
+	return max(1.4826*medianOf(deviations), 1e-6*(1+mean))
+}
+
+// biweight returns the weight Tukey's biweight gives a residual of z
+// robust standard deviations.
+func biweight(z float64) float64 {
+	u := z / biweightTuning
+	if math.Abs(u) >= 1 {
+		return 0
+	}
+	return (1 - u*u) * (1 - u*u)
+}
+
+// medianOf returns the median of values, which must not be empty.
+func medianOf(values []float64) float64 {
+	sorted := slices.Sorted(slices.Values(values))
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// outliers returns the samples whose residual under coefs is more than
+// threshold robust standard deviations, with their Index, Score and
+// Weight, or none if threshold is negative.
+func (d *design) outliers(coefs []float64, threshold float64, robust bool) []Outlier {
+	if threshold < 0 {
+		return nil
+	}
+	var outliers []Outlier
+	for i, score := range d.scores(coefs) {
+		if math.Abs(score) <= threshold {
+			continue
+		}
+		weight := 1.0
+		if robust {
+			weight = biweight(score)
+		}
+		outliers = append(outliers, Outlier{Index: i, Score: score, Weight: weight})
+	}
+	return outliers
+}
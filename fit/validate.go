@@ -0,0 +1,93 @@
+package fit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/infinigence/tokenestimate/eval"
+)
+
+// CrossValidation is the result of CrossValidate.
+type CrossValidation struct {
+	// Folds has the evaluation of each fold's model on the samples held
+	// out of its training.
+	Folds []eval.Report
+
+	// Report pools the held-out estimates of all folds, so that every
+	// sample is measured once by a model that was not trained on it.
+	eval.Report
+}
+
+// Holdout splits samples at random into a training set and a test set
+// holding the given fraction of them, for measuring a fitted model with
+// eval.Evaluate on samples it was not trained on. The same seed gives the same
+// split. samples is not modified.
+func Holdout(samples []Sample, fraction float64, seed uint64) (train, test []Sample) {
+	order := shuffled(len(samples), seed)
+	n := int(math.Round(fraction * float64(len(samples))))
+	n = min(max(n, 0), len(samples))
+	for i, j := range order {
+		if i < n {
+			test = append(test, samples[j])
+		} else {
+			train = append(train, samples[j])
+		}
+	}
+	return train, test
+}
+
+// CrossValidate estimates how well models fitted with opts generalize, by
+// k-fold cross-validation: it splits samples at random into k folds of
+// about equal size, fits a model to every k-1 of them and evaluates it on
+// the remaining one. The same seed gives the same folds.
+//
+// CrossValidate returns an error if k is not between 2 and the number of
+// samples, or if any fold's fit fails.
+func CrossValidate(samples []Sample, opts FitOptions, k int, seed uint64) (CrossValidation, error) {
+	if k < 2 {
+		return CrossValidation{}, errors.New("cross-validation needs at least 2 folds")
+	}
+	if k > len(samples) {
+		return CrossValidation{}, fmt.Errorf("%d samples cannot make %d folds", len(samples), k)
+	}
+
+	fold := make([]int, len(samples))
+	for i, j := range shuffled(len(samples), seed) {
+		fold[j] = i % k
+	}
+
+	var result CrossValidation
+	var held []Sample
+	var estimates []int
+	for f := range k {
+		var train, test []Sample
+		for i, s := range samples {
+			if fold[i] == f {
+				test = append(test, s)
+			} else {
+				train = append(train, s)
+			}
+		}
+		estimator, _, err := Fit(train, opts)
+		if err != nil {
+			return CrossValidation{}, fmt.Errorf("fold %d: %w", f+1, err)
+		}
+
+		foldEstimates := make([]int, len(test))
+		for i, s := range test {
+			foldEstimates[i] = estimator.Estimate(s.Text)
+		}
+		result.Folds = append(result.Folds, eval.EvaluateEstimates(test, foldEstimates, eval.Options{}))
+		held = append(held, test...)
+		estimates = append(estimates, foldEstimates...)
+	}
+	result.Report = eval.EvaluateEstimates(held, estimates, eval.Options{})
+	return result, nil
+}
+
+// shuffled returns a random permutation of 0 to n-1 determined by seed.
+func shuffled(n int, seed uint64) []int {
+	return rand.New(rand.NewPCG(seed, 0)).Perm(n)
+}
@@ -0,0 +1,71 @@
+package fit
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestHoldout(t *testing.T) {
+	samples := label(teacher(t), syntheticTexts(50, 6))
+	train, test := Holdout(samples, 0.2, 1)
+	if len(train) != 40 || len(test) != 10 {
+		t.Fatalf("Expected a 40/10 split, got %d/%d", len(train), len(test))
+	}
+	texts := func(samples []Sample) []string {
+		var texts []string
+		for _, s := range samples {
+			texts = append(texts, s.Text)
+		}
+		slices.Sort(texts)
+		return texts
+	}
+	if !slices.Equal(texts(append(slices.Clone(train), test...)), texts(samples)) {
+		t.Error("Expected the split to partition the samples")
+	}
+	if again, _ := Holdout(samples, 0.2, 1); !slices.Equal(again, train) {
+		t.Error("Expected the same seed to give the same split")
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	e := teacher(t)
+	samples := label(e, syntheticTexts(200, 7))
+
+	cv, err := CrossValidate(samples, FitOptions{Base: e}, 5, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cv.Folds) != 5 || cv.Samples != len(samples) {
+		t.Fatalf("Expected 5 folds over %d samples, got %d folds over %d", len(samples), len(cv.Folds), cv.Samples)
+	}
+	held := 0
+	for i, fold := range cv.Folds {
+		held += fold.Samples
+		if fold.Samples != 40 || fold.MAPE > 0.05 {
+			t.Errorf("Fold %d: unexpected metrics %+v", i+1, fold.Metrics)
+		}
+	}
+	if held != len(samples) {
+		t.Errorf("Expected every sample to be held out once, got %d", held)
+	}
+	if cv.MAPE > 0.05 || math.IsNaN(cv.RMSE) {
+		t.Errorf("Poor generalization: %+v", cv.Metrics)
+	}
+	byScript := 0
+	for _, m := range cv.ByScript {
+		byScript += m.Samples
+	}
+	if byScript != len(samples) {
+		t.Errorf("Expected the pooled report to break every sample down by script, got %d", byScript)
+	}
+
+	for _, k := range []int{0, 1, len(samples) + 1} {
+		if _, err := CrossValidate(samples, FitOptions{Base: e}, k, 1); err == nil {
+			t.Errorf("k=%d: expected an error", k)
+		}
+	}
+	if _, err := CrossValidate(samples[:4], FitOptions{Base: e}, 4, 1); err == nil {
+		t.Error("Expected the fit of a fold with too few samples to fail")
+	}
+}
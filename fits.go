@@ -0,0 +1,29 @@
+package tokenestimate
+
+// WithItemOverhead returns a clone of the estimator whose FitsWithin adds
+// tokens for every text, to account for the separators or framing that
+// join the texts into one prompt.
+func (e *Estimator) WithItemOverhead(tokens int) *Estimator {
+	clone := e.Clone()
+	clone.ItemOverhead = tokens
+	return clone
+}
+
+// FitsWithin reports whether texts joined into a single prompt fit in limit
+// tokens, counting the estimate of each text plus ItemOverhead per text.
+// It stops at the first text that takes the total over limit, in which
+// case total is the sum up to and including that text; otherwise total is
+// the sum over all texts.
+func (e *Estimator) FitsWithin(limit int, texts ...string) (fits bool, total int) {
+	for _, text := range texts {
+		total += e.ItemOverhead
+		if total > limit {
+			return false, total
+		}
+		total += e.Estimate(text)
+		if total > limit {
+			return false, total
+		}
+	}
+	return true, total
+}
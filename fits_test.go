@@ -0,0 +1,54 @@
+package tokenestimate
+
+import "testing"
+
+func TestFitsWithin(t *testing.T) {
+	estimator := NewEstimator()
+	texts := []string{
+		"You are a helpful assistant.",
+		"What is the capital of France?",
+		"The capital of France is Paris.",
+	}
+	sum := 0
+	for _, text := range texts {
+		sum += estimator.Estimate(text)
+	}
+
+	t.Run("Sums estimates", func(t *testing.T) {
+		fits, total := estimator.FitsWithin(sum, texts...)
+		if !fits || total != sum {
+			t.Errorf("FitsWithin(%d) = %v, %d; want true, %d", sum, fits, total, sum)
+		}
+		if fits, _ := estimator.FitsWithin(sum-1, texts...); fits {
+			t.Errorf("Expected %d tokens not to fit in %d", sum, sum-1)
+		}
+	})
+
+	t.Run("Item overhead", func(t *testing.T) {
+		withOverhead := estimator.WithItemOverhead(4)
+		fits, total := withOverhead.FitsWithin(1000, texts...)
+		if !fits || total != sum+12 {
+			t.Errorf("FitsWithin = %v, %d; want true, %d", fits, total, sum+12)
+		}
+		if estimator.ItemOverhead != 0 {
+			t.Error("WithItemOverhead modified the original estimator")
+		}
+	})
+
+	t.Run("Short-circuits", func(t *testing.T) {
+		first := estimator.Estimate(texts[0])
+		fits, total := estimator.FitsWithin(first, texts...)
+		if fits {
+			t.Fatal("Expected texts not to fit")
+		}
+		if want := first + estimator.Estimate(texts[1]); total != want {
+			t.Errorf("Expected total %d after the second text, got %d", want, total)
+		}
+	})
+
+	t.Run("No texts", func(t *testing.T) {
+		if fits, total := estimator.FitsWithin(0); !fits || total != 0 {
+			t.Errorf("FitsWithin(0) = %v, %d; want true, 0", fits, total)
+		}
+	})
+}
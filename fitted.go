@@ -0,0 +1,82 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"time"
+)
+
+//go:generate go run ./cmd/presetgen -dir testdata/presets -o presets_gen.go
+
+// fittedDictionaries holds the dictionaries of the fitted presets, which
+// cmd/dictgen generates from the encodings of their tokenizers.
+var fittedDictionaries = map[string]Dictionary{
+	"cl100k-base": cl100kDictionary,
+	"o200k-base":  o200kDictionary,
+}
+
+// Accuracy is how accurately a preset estimated the held-out samples of
+// the dataset it was fitted to, in the metrics of the eval package.
+// Relative errors are fractions of the actual token count.
+type Accuracy struct {
+	Dataset  string  // File name of the dataset
+	Samples  int     // Number of held-out samples measured
+	MAPE     float64 // Mean absolute relative error
+	P90      float64 // 90th percentile of the absolute relative error
+	PassRate float64 // Fraction of samples off by at most 15% or 20 tokens
+
+	// ByScript is the MAPE of the samples of each dominant script, keyed
+	// by LanguageProfile.Dominant, such as "Latin" or "Japanese"
+	ByScript map[string]float64
+}
+
+// fittedPreset is a preset presetgen fitted to a dataset of
+// testdata/presets: the coefficients it fitted on top of a built-in
+// preset, which provides the analysis settings and the coefficients of
+// the features that were not fitted, but not its dictionary, special
+// tokens or chat template; the dictionary is that of fittedDictionaries.
+type fittedPreset struct {
+	name         string
+	description  string
+	base         string // registry key of the built-in preset, such as "kimi-k2@11"
+	intercept    float64
+	coefficients map[string]float64
+	residualP10  float64
+	residualP90  float64
+	accuracy     Accuracy
+	trained      time.Time // day the coefficients last changed
+	corpus       string
+}
+
+// fittedPresetVersions returns the presets of generatedPresets, each as a
+// version history of its own, derived from the built-in presets of
+// versions.
+func fittedPresetVersions(versions [][]*Estimator) [][]*Estimator {
+	bases := map[string]*Estimator{}
+	for _, history := range versions {
+		for _, e := range history {
+			bases[e.Key()] = e
+		}
+	}
+	var out [][]*Estimator
+	for _, p := range generatedPresets {
+		base, ok := bases[p.base]
+		if !ok {
+			panic(fmt.Sprintf("tokenestimate: preset %s: unknown base preset: %s", p.name, p.base))
+		}
+		e, err := base.WithCoefficients(p.intercept, p.coefficients)
+		if err != nil {
+			panic(fmt.Sprintf("tokenestimate: preset %s: %v", p.name, err))
+		}
+		e.Name, e.Version, e.Description, e.Deprecated = p.name, 0, p.description, ""
+		// The dictionary, special tokens and chat template of the base are
+		// those of its tokenizer, not of the one the preset was fitted to.
+		e.dictionary, e.specialTokens, e.ChatTemplate = fittedDictionaries[p.name], nil, nil
+		e.defaultSampling()
+		e.residualP10, e.residualP90 = p.residualP10, p.residualP90
+		accuracy := p.accuracy
+		e.Accuracy = &accuracy
+		e.Metadata.TrainingDate, e.Metadata.Corpus, e.Metadata.MAPE = p.trained, p.corpus, accuracy.MAPE
+		out = append(out, []*Estimator{e})
+	}
+	return out
+}
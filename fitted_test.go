@@ -0,0 +1,91 @@
+package tokenestimate
+
+import (
+	"bufio"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFittedPresets(t *testing.T) {
+	if len(generatedPresets) == 0 {
+		t.Fatal("Expected presets_gen.go to declare presets")
+	}
+
+	for _, p := range generatedPresets {
+		t.Run(p.name, func(t *testing.T) {
+			e, err := GetPresetByName(p.name)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if e.Version != 0 || e.Deprecated != "" || e.Description != p.description {
+				t.Errorf("Unexpected preset %s@%d: %q, deprecated %q", e.Name, e.Version, e.Description, e.Deprecated)
+			}
+			if e.Accuracy == nil || e.Accuracy.Samples == 0 || len(e.Accuracy.ByScript) == 0 {
+				t.Fatalf("Expected the accuracy of %s, got %+v", p.name, e.Accuracy)
+			}
+			if low, high := e.EstimateRange("The quick brown fox jumps over the lazy dog."); low >= high {
+				t.Errorf("Expected a range, got %d..%d", low, high)
+			}
+			if e.specialTokens != nil || e.ChatTemplate != nil {
+				t.Errorf("Expected no special tokens or chat template of the base preset")
+			}
+			if dict := fittedDictionaries[p.name]; len(dict) == 0 || len(e.dictionary) != len(dict) {
+				t.Errorf("Expected the dictionary of %s, got %d words", p.name, len(e.dictionary))
+			}
+			if clone, _ := e.WithCoefficients(0, nil); clone.Accuracy != nil {
+				t.Error("Expected WithCoefficients to clear the accuracy")
+			}
+
+			// The preset fitted to 80% of its dataset is about as accurate
+			// on the whole dataset as the accuracy measured on the rest.
+			samples := loadPresetDataset(t, filepath.Join("testdata", "presets", e.Accuracy.Dataset))
+			var sum float64
+			for _, s := range samples {
+				sum += math.Abs(float64(e.Estimate(s.Text)-s.Tokens)) / float64(s.Tokens)
+			}
+			if mape := sum / float64(len(samples)); mape > 2*e.Accuracy.MAPE {
+				t.Errorf("Expected a MAPE near %.3f on %d samples, got %.3f", e.Accuracy.MAPE, len(samples), mape)
+			}
+		})
+	}
+
+	t.Run("Unknown base", func(t *testing.T) {
+		defer func(saved []fittedPreset) { generatedPresets = saved }(generatedPresets)
+		generatedPresets = []fittedPreset{{name: "broken", base: "nonexistent@1"}}
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected a panic for an unknown base preset")
+			}
+		}()
+		fittedPresetVersions(presetVersions)
+	})
+}
+
+// loadPresetDataset reads the samples with tokens of a JSONL dataset.
+func loadPresetDataset(t *testing.T, path string) []Sample {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var samples []Sample
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var s Sample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			t.Fatal(err)
+		}
+		if s.Tokens > 0 {
+			samples = append(samples, s)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return samples
+}
@@ -0,0 +1,212 @@
+package tokenestimate
+
+import (
+	"math/rand/v2"
+	"strings"
+)
+
+// Vocabularies of GenerateText. Texts are assembled from real words where
+// the script has a short list of frequent ones, because random letters
+// tokenize far worse than any real text and would bias a calibration.
+var (
+	generateLatin = strings.Fields(`the of and to in a is that for it as was with be by on not he this
+		are or his from at which but have an they you were her she there been one all we their has
+		would when if so no more can will who what up out about into some my than them only its do
+		him like your me our may time new just first also two these other after any very how most
+		over such people years could well now even many much where made before should because those
+		through work way between world state still here must own life same year last few good part
+		model token text value data system function return number string example request response`)
+	generateRussian = strings.Fields(`и в не на я что он с как это по но они к у же вы за бы мы от
+		так было все она для только есть уже когда если время можно жизнь человек год работа дело
+		слово день рука мир который должен очень здесь теперь вопрос система данные текст пример`)
+	generateArabic = strings.Fields(`في من على إلى أن هذا التي الذي عن مع كان هذه بين كل وقد ما لا
+		بعد قبل عند حتى أو ثم لم قد العالم الناس اليوم الوقت العمل النص المثال البيانات`)
+	generateChinese = []rune("的一是不了人我在有他这中大来上国个到说们为子和你地出道也时年得就那要下以生会自着去之过家" +
+		"学对可里后小么心多天而能好都然没日于起还发成事只作当想看文无开手十用主行方又如前所本见经头面公同三已老" +
+		"从动两长知民样现分将外但身些与高意进把法此实回二理美点月明其种声全工己话儿者向情部正名定女问力机给等几")
+	generateJapanese = []rune("のにはをたがでてとしれさあいうえおかきくけこすせそなまもやよらりるろわんっ" +
+		"アイウエオカキクケコサシスセソタチツテトナニマミムメモラリルレロンーデータシステム")
+	generateKorean = []rune("이의가에는을를하고다서한수있지도로으것들그사람없나대말일때해니면게아보거시기여우라되요전")
+	// Khmer, Lao, Myanmar and Ethiopic texts take their base letters.
+	generateKhmer    = runeRange(0x1780, 0x17A2)
+	generateLao      = []rune("ກຂຄງຈຊຍດຕຖທນບປຜຝພຟມຢຣລວສຫອຮ")
+	generateMyanmar  = runeRange(0x1000, 0x1021)
+	generateEthiopic = runeRange(0x1200, 0x1248)
+	generateUnknown  = runeRange(0xE000, 0xE100)
+
+	generateAccented = map[rune][]rune{
+		'a': []rune("áàâäã"), 'e': []rune("éèêë"), 'i': []rune("íìîï"),
+		'o': []rune("óòôöõ"), 'u': []rune("úùûü"), 'n': []rune("ñ"), 'c': []rune("ç"),
+	}
+	generateSymbols = []rune(".,.,.,!?;:()-\"'/")
+)
+
+// runeRange returns the runes from lo to hi inclusive.
+func runeRange(lo, hi rune) []rune {
+	runes := make([]rune, 0, hi-lo+1)
+	for r := lo; r <= hi; r++ {
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+// GenerateText returns a synthetic text of about chars characters with the
+// character mix of profile: its words and numbers have about the profile's
+// average lengths and are drawn from frequent words of each script where
+// possible, in random order. The same seed gives the same text.
+//
+// Synthetic texts make a calibration dataset for a tokenizer that can
+// only be called a limited number of times: label texts with the mixes
+// of the expected traffic by the tokenizer and pass them to Calibrate or
+// the fit package. They tokenize somewhat worse than natural text of the
+// same mix, as their words follow no grammar.
+func GenerateText(profile LanguageProfile, chars int, seed uint64) string {
+	rng := rand.New(rand.NewPCG(seed, 0x67656e))
+	return generateText(profile, chars, rng)
+}
+
+// GenerateTexts returns count synthetic texts generated like GenerateText,
+// with lengths spread evenly between minChars and maxChars.
+func GenerateTexts(profile LanguageProfile, count, minChars, maxChars int, seed uint64) []string {
+	rng := rand.New(rand.NewPCG(seed, 0x67656e))
+	maxChars = max(maxChars, minChars)
+	texts := make([]string, count)
+	for i := range texts {
+		texts[i] = generateText(profile, minChars+rng.IntN(maxChars-minChars+1), rng)
+	}
+	return texts
+}
+
+func generateText(profile LanguageProfile, chars int, rng *rand.Rand) string {
+	if chars <= 0 || profile.total() <= 0 {
+		return ""
+	}
+	s := profile.stats(chars)
+	wordLength := profile.AvgWordLength
+	if wordLength <= 0 {
+		wordLength = 5
+	}
+	runLength := profile.AvgDigitRunLength
+	if runLength <= 0 {
+		runLength = defaultDigitRunLength
+	}
+
+	// The text is made of units, words and numbers, shuffled and separated
+	// by the whitespace.
+	var units []string
+	words := func(n int, vocabulary []string) {
+		for n > 0 {
+			w := []rune(vocabulary[rng.IntN(len(vocabulary))])
+			if len(w) > n {
+				w = w[:n]
+			}
+			units = append(units, string(w))
+			n -= len(w)
+		}
+	}
+	letters := func(n int, alphabet []rune) {
+		var b strings.Builder
+		for n > 0 {
+			length := min(randomLength(wordLength, rng), n)
+			for range length {
+				b.WriteRune(alphabet[rng.IntN(len(alphabet))])
+			}
+			units = append(units, b.String())
+			b.Reset()
+			n -= length
+		}
+	}
+
+	latin := len(units)
+	words(s.LatinLetters+s.LatinExtended, generateLatin)
+	accentLatin(units[latin:], s.LatinExtended, rng)
+	words(s.RussianChars, generateRussian)
+	words(s.ArabicChars, generateArabic)
+	letters(s.ChineseChars, generateChinese)
+	letters(s.JapaneseKana, generateJapanese)
+	letters(s.KoreanHangul, generateKorean)
+	letters(s.KhmerChars, generateKhmer)
+	letters(s.LaoChars, generateLao)
+	letters(s.MyanmarChars, generateMyanmar)
+	letters(s.EthiopicChars, generateEthiopic)
+	letters(s.Unknown, generateUnknown)
+	for n := s.Digits; n > 0; {
+		length := min(randomLength(runLength, rng), n)
+		var b strings.Builder
+		b.WriteByte(byte('1' + rng.IntN(9)))
+		for range length - 1 {
+			b.WriteByte(byte('0' + rng.IntN(10)))
+		}
+		units = append(units, b.String())
+		n -= length
+	}
+	rng.Shuffle(len(units), func(i, j int) { units[i], units[j] = units[j], units[i] })
+	// Symbols follow words like punctuation.
+	for range s.Symbols {
+		symbol := string(generateSymbols[rng.IntN(len(generateSymbols))])
+		if len(units) == 0 {
+			units = append(units, symbol)
+		} else {
+			units[rng.IntN(len(units))] += symbol
+		}
+	}
+
+	// Whitespace goes between units, each gap getting one before any gets
+	// two, so that words stay apart and a text with more whitespace than
+	// gaps gets runs of it.
+	gaps := make([]int, max(len(units)-1, 0))
+	if len(gaps) > 0 {
+		order := rng.Perm(len(gaps))
+		for k := range s.Spaces {
+			gaps[order[k%len(order)]]++
+		}
+	}
+	var b strings.Builder
+	b.Grow(chars * 3)
+	for i, u := range units {
+		b.WriteString(u)
+		if i < len(gaps) {
+			for range gaps[i] {
+				if rng.IntN(12) == 0 {
+					b.WriteByte('\n')
+				} else {
+					b.WriteByte(' ')
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// randomLength returns a random length of mean about mean, at least one.
+func randomLength(mean float64, rng *rand.Rand) int {
+	return max(1, int(mean*(0.5+rng.Float64())+0.5))
+}
+
+// accentLatin replaces n letters of words that have accented forms with
+// one of them, at random.
+func accentLatin(words []string, n int, rng *rand.Rand) {
+	type position struct{ word, index int }
+	var candidates []position
+	runes := make([][]rune, len(words))
+	for w, word := range words {
+		runes[w] = []rune(word)
+		for i, r := range runes[w] {
+			if _, ok := generateAccented[r]; ok {
+				candidates = append(candidates, position{w, i})
+			}
+		}
+	}
+	rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	changed := make([]bool, len(words))
+	for _, c := range candidates[:min(n, len(candidates))] {
+		forms := generateAccented[runes[c.word][c.index]]
+		runes[c.word][c.index] = forms[rng.IntN(len(forms))]
+		changed[c.word] = true
+	}
+	for w := range words {
+		if changed[w] {
+			words[w] = string(runes[w])
+		}
+	}
+}
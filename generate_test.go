@@ -0,0 +1,64 @@
+package tokenestimate
+
+import (
+	"math"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGenerateText(t *testing.T) {
+	profiles := []LanguageProfile{
+		{Latin: 0.8, Whitespace: 0.17, Symbols: 0.03, AvgWordLength: 5},
+		{Chinese: 0.9, Symbols: 0.1},
+		{Latin: 0.4, Chinese: 0.3, Digits: 0.1, Whitespace: 0.15, Symbols: 0.03, LatinExtended: 0.02},
+		{Russian: 0.5, Japanese: 0.2, Korean: 0.1, Arabic: 0.05, Whitespace: 0.15},
+		{Khmer: 0.3, Lao: 0.2, Myanmar: 0.2, Ethiopic: 0.2, Unknown: 0.1},
+	}
+	for _, p := range profiles {
+		text := GenerateText(p, 2000, 1)
+		if n := utf8.RuneCountInString(text); math.Abs(float64(n-2000)) > 20 {
+			t.Errorf("%v: expected about 2000 characters, got %d", p, n)
+		}
+		got, want := ProfileOf(text), p.normalize()
+		for i, s := range want.shares() {
+			if g := *got.shares()[i].share; math.Abs(g-*s.share) > 0.01 {
+				t.Errorf("%v: expected a %s share of %.3f, got %.3f", p, s.name, *s.share, g)
+			}
+		}
+		if again := GenerateText(p, 2000, 1); again != text {
+			t.Errorf("%v: expected the same seed to give the same text", p)
+		}
+		if other := GenerateText(p, 2000, 2); other == text {
+			t.Errorf("%v: expected another seed to give another text", p)
+		}
+	}
+
+	// Word and number lengths follow the profile.
+	p := LanguageProfile{Latin: 0.6, Digits: 0.2, Whitespace: 0.2, AvgDigitRunLength: 6}
+	if got := ProfileOf(GenerateText(p, 5000, 3)).AvgDigitRunLength; math.Abs(got-6) > 1 {
+		t.Errorf("Expected numbers of about 6 digits, got %.1f", got)
+	}
+
+	if got := GenerateText(LanguageProfile{}, 100, 1); got != "" {
+		t.Errorf("Expected no text for an empty profile, got %q", got)
+	}
+	if got := GenerateText(profiles[0], 0, 1); got != "" {
+		t.Errorf("Expected no text for no characters, got %q", got)
+	}
+}
+
+func TestGenerateTexts(t *testing.T) {
+	p := LanguageProfile{Latin: 0.8, Whitespace: 0.2}
+	texts := GenerateTexts(p, 50, 20, 400, 1)
+	if len(texts) != 50 {
+		t.Fatalf("Expected 50 texts, got %d", len(texts))
+	}
+	shortest, longest := math.MaxInt, 0
+	for _, text := range texts {
+		n := utf8.RuneCountInString(text)
+		shortest, longest = min(shortest, n), max(longest, n)
+	}
+	if shortest < 18 || longest > 402 || longest-shortest < 200 {
+		t.Errorf("Expected lengths spread between 20 and 400 characters, got %d to %d", shortest, longest)
+	}
+}
@@ -0,0 +1,115 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//go:generate go run ./cmd/goldengen -pkg tokenestimate -var shippedGolden -o golden_gen_test.go
+
+// referenceTexts is the fixed set of strings used to snapshot preset output.
+// It covers every character category so that a change to any coefficient
+// shows up in at least one golden value.
+var referenceTexts = []string{
+	"",
+	"Hello, world!",
+	"The quick brown fox jumps over the lazy dog. This is a test sentence.",
+	"你好，世界！这是一个用于估算词元数量的测试句子。",
+	"こんにちは、世界。カタカナとひらがなのテストです。",
+	"안녕하세요, 세계! 한국어 테스트 문장입니다.",
+	"Привет, мир! Это тестовое предложение на русском языке.",
+	"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.",
+	"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា",
+	"ພາສາລາວເປັນພາສາທາງການຂອງລາວ",
+	"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။",
+	"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።",
+	"Ça va très bien, señor Müller? Où est la crème brûlée?",
+	"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).",
+	"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==",
+	"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n",
+	`{"id": 1, "name": "widget", "tags": ["a", "b"], "items": [{"id": 2}, {"id": 3}]}`,
+	"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n",
+	"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n",
+	"func main() {\n\tfmt.Println(\"hello\")\n}\n",
+	"    if x > 0:\n        return x * 2\n    return -x\n",
+	"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café",
+	"!@#$%^&*()_+-=[]{}|;':\",./<>?`~",
+}
+
+// ReferenceTexts returns a copy of the fixed reference strings used by
+// GenerateGolden and VerifyGolden.
+func ReferenceTexts() []string {
+	texts := make([]string, len(referenceTexts))
+	copy(texts, referenceTexts)
+	return texts
+}
+
+// Golden maps preset names to the estimate each preset produced for a set of
+// reference texts. It is keyed by text rather than by position so a snapshot
+// stays valid when the library adds new reference texts.
+type Golden map[string]map[string]int
+
+// GenerateGolden returns a snapshot of the estimates produced by the named
+// presets for every reference text. If no names are given, all registered
+// presets are included.
+func GenerateGolden(names ...string) (Golden, error) {
+	if len(names) == 0 {
+		names = ListPresets()
+	}
+
+	golden := make(Golden, len(names))
+	for _, name := range names {
+		estimator, err := lookupPreset(name)
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]int, len(referenceTexts))
+		for _, text := range referenceTexts {
+			values[text] = estimator.Estimate(text)
+		}
+		golden[name] = values
+	}
+	return golden, nil
+}
+
+// VerifyGolden checks that every preset recorded in golden still produces
+// the recorded estimates. Downstream users can store a snapshot generated by
+// cmd/goldengen and call VerifyGolden in their tests to detect coefficient
+// changes between library versions.
+func VerifyGolden(golden Golden) error {
+	names := make([]string, 0, len(golden))
+	for name := range golden {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var mismatches []string
+	for _, name := range names {
+		estimator, err := lookupPreset(name)
+		if err != nil {
+			mismatches = append(mismatches, err.Error())
+			continue
+		}
+
+		texts := make([]string, 0, len(golden[name]))
+		for text := range golden[name] {
+			texts = append(texts, text)
+		}
+		sort.Strings(texts)
+
+		for _, text := range texts {
+			want := golden[name][text]
+			if got := estimator.Estimate(text); got != want {
+				mismatches = append(mismatches,
+					fmt.Sprintf("preset %s: Estimate(%q) = %d, golden %d", name, text, got, want))
+			}
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("golden mismatch:\n%s", strings.Join(mismatches, "\n"))
+	}
+	return nil
+}
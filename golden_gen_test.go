@@ -0,0 +1,994 @@
+// Code generated by goldengen; DO NOT EDIT.
+
+package tokenestimate
+
+var shippedGolden = Golden{
+	"cl100k-base": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 15,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     19,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          48,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 46,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        16,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             25,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             28,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  17,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 44,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           12,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               30,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     36,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 22,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                25,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         32,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             85,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   52,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               57,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              26,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               26,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              25,
+	},
+	"kimi-k2": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        7,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2-code": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     14,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          32,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        7,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  14,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 44,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     29,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 24,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         28,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2-code-p90": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 16,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          39,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 47,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        9,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             27,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             28,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  17,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 53,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           14,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               35,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     35,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 30,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                34,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         34,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            50,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             101,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   60,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               68,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              30,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               19,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              27,
+	},
+	"kimi-k2-code-p90@1": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 16,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          39,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 47,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        19,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             27,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             28,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  17,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 53,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           14,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               35,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     35,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 30,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                34,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         34,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            50,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             101,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   60,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               68,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              30,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               19,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              27,
+	},
+	"kimi-k2-code-p90@2": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 16,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          39,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 47,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        9,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             27,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             28,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  17,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 53,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           14,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               35,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     35,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 30,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                34,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         34,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            50,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             101,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   60,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               68,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              30,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               19,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              27,
+	},
+	"kimi-k2-code@1": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 14,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     14,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          33,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 41,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        15,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  15,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     30,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         28,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2-code@2": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     14,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          32,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        15,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  14,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 44,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     29,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 24,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         28,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2-code@3": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     14,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          32,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        15,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  14,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 44,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     29,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 24,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         28,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2-code@4": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     14,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          32,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        7,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  14,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 44,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     29,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 24,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         28,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2-json": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     17,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          36,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        7,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             24,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 46,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           13,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     34,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              23,
+	},
+	"kimi-k2-json-p90": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 16,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     21,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          43,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 48,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        9,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             26,
+		"Hello, world!": 5,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             30,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  17,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 55,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           16,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               36,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     41,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 31,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                35,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         33,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            49,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             101,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   59,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               68,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              31,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               20,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              28,
+	},
+	"kimi-k2-json-p90@1": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 17,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     21,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          44,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 48,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        19,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             26,
+		"Hello, world!": 5,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             30,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  17,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 55,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           16,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               36,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     41,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 31,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                35,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         33,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            49,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             101,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   59,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               68,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              31,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               20,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              28,
+	},
+	"kimi-k2-json-p90@2": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 16,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     21,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          43,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 48,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        9,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             26,
+		"Hello, world!": 5,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             30,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  17,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 55,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           16,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               36,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     41,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 31,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                35,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         33,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            49,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             101,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   59,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               68,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              31,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               20,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              28,
+	},
+	"kimi-k2-json@1": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        16,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2-json@2": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     17,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          36,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        7,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             24,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 46,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           13,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     34,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              23,
+	},
+	"kimi-k2-markdown": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        7,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2-markdown-p90": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 16,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     22,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          41,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 48,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        9,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             26,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             29,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  17,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 54,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           14,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               35,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     40,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 31,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                34,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         33,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            49,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             101,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   59,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               68,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              31,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               20,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              27,
+	},
+	"kimi-k2-markdown-p90@1": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 16,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     22,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          41,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 48,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        19,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             26,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             29,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  17,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 54,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           14,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               35,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     40,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 31,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                34,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         33,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            49,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             101,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   59,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               68,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              31,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               20,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              27,
+	},
+	"kimi-k2-markdown-p90@2": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 16,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     22,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          41,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 48,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        9,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             26,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             29,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  17,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 54,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           14,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               35,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     40,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 31,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                34,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         33,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            49,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             101,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   59,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               68,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              31,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               20,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              27,
+	},
+	"kimi-k2-markdown@1": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        16,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2-markdown@2": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        7,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2-p90": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 15,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     21,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          39,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 45,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        9,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             25,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             27,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  16,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 51,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           13,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               33,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     38,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 29,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                32,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         32,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            47,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             96,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   56,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               64,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              29,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               19,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              26,
+	},
+	"kimi-k2-p90@1": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 15,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     21,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          39,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 43,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        9,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             25,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             27,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  16,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 51,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           13,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               33,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     38,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 29,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                32,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         32,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            47,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             96,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   56,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               64,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              29,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               19,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              26,
+	},
+	"kimi-k2-p90@2": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 15,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     21,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          39,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 45,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        9,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             25,
+		"Hello, world!": 4,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             27,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  16,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 51,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           13,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               33,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     38,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 29,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                32,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         32,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            47,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             96,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   56,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               64,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              29,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               19,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              26,
+	},
+	"kimi-k2@1": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 8,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          27,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 47,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        17,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             21,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             26,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 32,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           10,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               39,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     30,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 24,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                26,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         25,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            15,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             31,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   17,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               23,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              21,
+	},
+	"kimi-k2@10": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 37,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        7,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2@11": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 37,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        7,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2@12": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        7,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2@2": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 11,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          31,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 47,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        17,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             21,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             26,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 32,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               39,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     30,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 24,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                26,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         25,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            15,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             31,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   17,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               23,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              21,
+	},
+	"kimi-k2@3": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 11,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          31,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 47,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        17,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             21,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             26,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 32,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               39,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     30,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 24,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                26,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         25,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             83,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   17,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              21,
+	},
+	"kimi-k2@4": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 11,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          31,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 47,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        17,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             21,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             26,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 32,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               39,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     30,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 24,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                26,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         25,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             83,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   47,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              21,
+	},
+	"kimi-k2@5": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 12,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 47,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        16,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             27,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 26,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               38,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     32,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2@6": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        16,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 26,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               34,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2@7": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        16,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2@8": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 39,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        16,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"kimi-k2@9": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 13,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     18,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          34,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 37,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        16,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             22,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             23,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  13,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               29,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     33,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 25,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                28,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         27,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              25,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               16,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              22,
+	},
+	"o200k-base": {
+		"": 0,
+		"    if x > 0:\n        return x * 2\n    return -x\n":                                                                                 15,
+		"!@#$%^&*()_+-=[]{}|;':\",./<>?`~":                                                                                                     12,
+		"# Title\n\n- one\n- two\n\n| a | b |\n|---|---|\n\nSee [docs](https://example.com).\n\n```go\nx := 1\n```\n":                          42,
+		"2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=200\n2024-01-15 INFO request handled status=404\n": 67,
+		"<|im_start|>user\nHi there<|im_end|>\n<|im_start|>assistant\n":                                                                        15,
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café":                                                                                             19,
+		"Hello, world!": 3,
+		"Order #12345 shipped on 2024-01-15 for $1,299.99 (qty: 3).":                                                             28,
+		"The quick brown fox jumps over the lazy dog. This is a test sentence.":                                                  16,
+		"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==": 45,
+		"func main() {\n\tfmt.Println(\"hello\")\n}\n":                                                                           11,
+		"sha256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08":                                               30,
+		"{\"id\": 1, \"name\": \"widget\", \"tags\": [\"a\", \"b\"], \"items\": [{\"id\": 2}, {\"id\": 3}]}":                     26,
+		"Ça va très bien, señor Müller? Où est la crème brûlée?":                                                                 15,
+		"Привет, мир! Это тестовое предложение на русском языке.":                                                                16,
+		"مرحبا بالعالم! هذه جملة اختبار باللغة العربية.":                                                                         14,
+		"ພາສາລາວເປັນພາສາທາງການຂອງລາວ":                                                                                            41,
+		"မြန်မာဘာသာစကားသည် မြန်မာနိုင်ငံ၏ ရုံးသုံးဘာသာစကား ဖြစ်သည်။":                                                             84,
+		"ሰላም ለዓለም። ይህ የአማርኛ የሙከራ ዓረፍተ ነገር ነው።":                                                                                   49,
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា":                                                                               56,
+		"こんにちは、世界。カタカナとひらがなのテストです。":                                                                                              21,
+		"你好，世界！这是一个用于估算词元数量的测试句子。":                                                                                               20,
+		"안녕하세요, 세계! 한국어 테스트 문장입니다.":                                                                                              17,
+	},
+}
@@ -0,0 +1,49 @@
+package tokenestimate
+
+import "testing"
+
+// TestShippedGolden fails when preset output drifts from the committed
+// snapshot. Run `go generate` to refresh it after an intentional change.
+func TestShippedGolden(t *testing.T) {
+	if err := VerifyGolden(shippedGolden); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	t.Run("GenerateGolden covers reference texts", func(t *testing.T) {
+		golden, err := GenerateGolden("kimi-k2")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(golden) != 1 {
+			t.Fatalf("Expected 1 preset, got %d", len(golden))
+		}
+		if len(golden["kimi-k2"]) != len(ReferenceTexts()) {
+			t.Errorf("Expected %d values, got %d", len(ReferenceTexts()), len(golden["kimi-k2"]))
+		}
+		if err := VerifyGolden(golden); err != nil {
+			t.Errorf("Fresh snapshot should verify: %v", err)
+		}
+	})
+
+	t.Run("GenerateGolden unknown preset", func(t *testing.T) {
+		if _, err := GenerateGolden("nonexistent"); err == nil {
+			t.Error("Expected error for nonexistent preset")
+		}
+	})
+
+	t.Run("VerifyGolden detects changes", func(t *testing.T) {
+		golden := Golden{"kimi-k2": {"Hello, world!": 1000}}
+		if err := VerifyGolden(golden); err == nil {
+			t.Error("Expected mismatch error")
+		}
+	})
+
+	t.Run("VerifyGolden unknown preset", func(t *testing.T) {
+		golden := Golden{"nonexistent": {"Hello": 1}}
+		if err := VerifyGolden(golden); err == nil {
+			t.Error("Expected error for nonexistent preset")
+		}
+	})
+}
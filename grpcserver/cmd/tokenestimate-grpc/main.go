@@ -0,0 +1,39 @@
+// Command tokenestimate-grpc serves the Estimator gRPC service of the
+// grpcserver package.
+//
+// Usage:
+//
+//	go run github.com/infinigence/tokenestimate/grpcserver/cmd/tokenestimate-grpc -addr :50051 -preset kimi-k2
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+
+	"github.com/infinigence/tokenestimate/grpcserver"
+	"github.com/infinigence/tokenestimate/grpcserver/estimatepb"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	var (
+		addr   = flag.String("addr", "localhost:50051", "address to listen on")
+		preset = flag.String("preset", "kimi-k2", "preset estimating requests that name no preset")
+	)
+	flag.Parse()
+	log.SetFlags(0)
+
+	srv, err := grpcserver.New(grpcserver.Options{Preset: *preset})
+	if err != nil {
+		log.Fatal(err)
+	}
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	gs := grpc.NewServer()
+	estimatepb.RegisterEstimatorServer(gs, srv)
+	log.Printf("tokenestimate-grpc: serving on %s", lis.Addr())
+	log.Fatal(gs.Serve(lis))
+}
@@ -0,0 +1,206 @@
+// The Estimator service estimates token counts with the presets of
+// github.com/infinigence/tokenestimate, for low-latency use by internal
+// services.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.3
+// source: estimatepb/estimate.proto
+
+package estimatepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Estimator_Estimate_FullMethodName       = "/tokenestimate.v1.Estimator/Estimate"
+	Estimator_EstimateBatch_FullMethodName  = "/tokenestimate.v1.Estimator/EstimateBatch"
+	Estimator_EstimateStream_FullMethodName = "/tokenestimate.v1.Estimator/EstimateStream"
+)
+
+// EstimatorClient is the client API for Estimator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EstimatorClient interface {
+	// Estimate estimates the tokens of a text.
+	Estimate(ctx context.Context, in *EstimateRequest, opts ...grpc.CallOption) (*EstimateResponse, error)
+	// EstimateBatch estimates the tokens of several texts with one preset.
+	EstimateBatch(ctx context.Context, in *EstimateBatchRequest, opts ...grpc.CallOption) (*EstimateBatchResponse, error)
+	// EstimateStream estimates text sent in chunks, such as a streamed chat
+	// response, replying to every chunk with the running estimate of the
+	// text so far.
+	EstimateStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[EstimateStreamRequest, EstimateStreamResponse], error)
+}
+
+type estimatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEstimatorClient(cc grpc.ClientConnInterface) EstimatorClient {
+	return &estimatorClient{cc}
+}
+
+func (c *estimatorClient) Estimate(ctx context.Context, in *EstimateRequest, opts ...grpc.CallOption) (*EstimateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EstimateResponse)
+	err := c.cc.Invoke(ctx, Estimator_Estimate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *estimatorClient) EstimateBatch(ctx context.Context, in *EstimateBatchRequest, opts ...grpc.CallOption) (*EstimateBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EstimateBatchResponse)
+	err := c.cc.Invoke(ctx, Estimator_EstimateBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *estimatorClient) EstimateStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[EstimateStreamRequest, EstimateStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Estimator_ServiceDesc.Streams[0], Estimator_EstimateStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[EstimateStreamRequest, EstimateStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Estimator_EstimateStreamClient = grpc.BidiStreamingClient[EstimateStreamRequest, EstimateStreamResponse]
+
+// EstimatorServer is the server API for Estimator service.
+// All implementations must embed UnimplementedEstimatorServer
+// for forward compatibility.
+type EstimatorServer interface {
+	// Estimate estimates the tokens of a text.
+	Estimate(context.Context, *EstimateRequest) (*EstimateResponse, error)
+	// EstimateBatch estimates the tokens of several texts with one preset.
+	EstimateBatch(context.Context, *EstimateBatchRequest) (*EstimateBatchResponse, error)
+	// EstimateStream estimates text sent in chunks, such as a streamed chat
+	// response, replying to every chunk with the running estimate of the
+	// text so far.
+	EstimateStream(grpc.BidiStreamingServer[EstimateStreamRequest, EstimateStreamResponse]) error
+	mustEmbedUnimplementedEstimatorServer()
+}
+
+// UnimplementedEstimatorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEstimatorServer struct{}
+
+func (UnimplementedEstimatorServer) Estimate(context.Context, *EstimateRequest) (*EstimateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Estimate not implemented")
+}
+func (UnimplementedEstimatorServer) EstimateBatch(context.Context, *EstimateBatchRequest) (*EstimateBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateBatch not implemented")
+}
+func (UnimplementedEstimatorServer) EstimateStream(grpc.BidiStreamingServer[EstimateStreamRequest, EstimateStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method EstimateStream not implemented")
+}
+func (UnimplementedEstimatorServer) mustEmbedUnimplementedEstimatorServer() {}
+func (UnimplementedEstimatorServer) testEmbeddedByValue()                   {}
+
+// UnsafeEstimatorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EstimatorServer will
+// result in compilation errors.
+type UnsafeEstimatorServer interface {
+	mustEmbedUnimplementedEstimatorServer()
+}
+
+func RegisterEstimatorServer(s grpc.ServiceRegistrar, srv EstimatorServer) {
+	// If the following call pancis, it indicates UnimplementedEstimatorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Estimator_ServiceDesc, srv)
+}
+
+func _Estimator_Estimate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EstimatorServer).Estimate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Estimator_Estimate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EstimatorServer).Estimate(ctx, req.(*EstimateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Estimator_EstimateBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EstimatorServer).EstimateBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Estimator_EstimateBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EstimatorServer).EstimateBatch(ctx, req.(*EstimateBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Estimator_EstimateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EstimatorServer).EstimateStream(&grpc.GenericServerStream[EstimateStreamRequest, EstimateStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Estimator_EstimateStreamServer = grpc.BidiStreamingServer[EstimateStreamRequest, EstimateStreamResponse]
+
+// Estimator_ServiceDesc is the grpc.ServiceDesc for Estimator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Estimator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tokenestimate.v1.Estimator",
+	HandlerType: (*EstimatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Estimate",
+			Handler:    _Estimator_Estimate_Handler,
+		},
+		{
+			MethodName: "EstimateBatch",
+			Handler:    _Estimator_EstimateBatch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EstimateStream",
+			Handler:       _Estimator_EstimateStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "estimatepb/estimate.proto",
+}
@@ -0,0 +1,143 @@
+// Package grpcserver serves token estimates over gRPC, for low-latency use
+// by internal services. The Estimator service of estimatepb/estimate.proto
+// has an Estimate, an EstimateBatch and a streaming EstimateStream RPC,
+// which takes the chunks of a text, such as a streamed chat response, and
+// replies to each with the running estimate:
+//
+//	srv, err := grpcserver.New(grpcserver.Options{Preset: "kimi-k2"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	gs := grpc.NewServer()
+//	estimatepb.RegisterEstimatorServer(gs, srv)
+//	log.Fatal(gs.Serve(lis))
+//
+// Requests name a preset or alias, or none for the server's preset.
+// Unknown presets fail with codes.NotFound, and input over the preset's
+// MaxInputBytes, when its policy is InputLimitError, with
+// codes.InvalidArgument.
+//
+// The package is a separate module, so that the tokenestimate module
+// stays free of dependencies.
+package grpcserver
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative estimatepb/estimate.proto
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/infinigence/tokenestimate/grpcserver/estimatepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Options configures New.
+type Options struct {
+	// Preset estimates requests that name no preset (default: the
+	// estimator of NewEstimator)
+	Preset string
+}
+
+// Server implements estimatepb.EstimatorServer. It is safe for concurrent
+// use.
+type Server struct {
+	estimatepb.UnimplementedEstimatorServer
+	fallback *tokenestimate.Estimator
+}
+
+// New returns a Server with the options. It returns an error if
+// opts.Preset is not registered.
+func New(opts Options) (*Server, error) {
+	s := &Server{fallback: tokenestimate.NewEstimator()}
+	if opts.Preset != "" {
+		var err error
+		if s.fallback, err = tokenestimate.GetPresetByName(opts.Preset); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Estimate estimates the tokens of a text.
+func (s *Server) Estimate(ctx context.Context, req *estimatepb.EstimateRequest) (*estimatepb.EstimateResponse, error) {
+	e, err := s.preset(req.GetPreset())
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := e.Count(req.GetText())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &estimatepb.EstimateResponse{Tokens: int64(tokens), Preset: e.Key()}, nil
+}
+
+// EstimateBatch estimates the tokens of several texts with one preset.
+func (s *Server) EstimateBatch(ctx context.Context, req *estimatepb.EstimateBatchRequest) (*estimatepb.EstimateBatchResponse, error) {
+	e, err := s.preset(req.GetPreset())
+	if err != nil {
+		return nil, err
+	}
+	resp := &estimatepb.EstimateBatchResponse{Tokens: make([]int64, len(req.GetTexts())), Preset: e.Key()}
+	for i, text := range req.GetTexts() {
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		tokens, err := e.Count(text)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "text %d: %v", i, err)
+		}
+		resp.Tokens[i] = int64(tokens)
+		resp.Total += int64(tokens)
+	}
+	return resp, nil
+}
+
+// EstimateStream estimates text sent in chunks, replying to every chunk
+// with the running estimate. The preset is read from the first message.
+func (s *Server) EstimateStream(stream estimatepb.Estimator_EstimateStreamServer) error {
+	var (
+		e     *tokenestimate.Estimator
+		se    *tokenestimate.StreamEstimator
+		key   string
+		total int
+	)
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if se == nil {
+			if e, err = s.preset(req.GetPreset()); err != nil {
+				return err
+			}
+			se, key = tokenestimate.NewStreamEstimator(e), e.Key()
+		}
+		total += len(req.GetChunk())
+		if e.InputLimit == tokenestimate.InputLimitError && e.MaxInputBytes > 0 && total > e.MaxInputBytes {
+			err := &tokenestimate.InputTooLargeError{Limit: e.MaxInputBytes}
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		se.Write(req.GetChunk())
+		if err := stream.Send(&estimatepb.EstimateStreamResponse{Tokens: int64(se.Tokens()), Bytes: int64(total), Preset: key}); err != nil {
+			return err
+		}
+	}
+}
+
+// preset returns the preset of a request, or a NotFound status error.
+func (s *Server) preset(name string) (*tokenestimate.Estimator, error) {
+	if name == "" {
+		return s.fallback, nil
+	}
+	e, err := tokenestimate.GetPresetByName(name)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return e, nil
+}
@@ -0,0 +1,146 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/infinigence/tokenestimate/grpcserver/estimatepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves srv on an in-memory listener and returns a client of it.
+func dial(t *testing.T, srv *Server) estimatepb.EstimatorClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	estimatepb.RegisterEstimatorServer(gs, srv)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return estimatepb.NewEstimatorClient(conn)
+}
+
+func TestEstimate(t *testing.T) {
+	srv, err := New(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := dial(t, srv)
+	ctx := context.Background()
+	text := "Hello, world! 你好世界"
+
+	resp, err := client.Estimate(ctx, &estimatepb.EstimateRequest{Text: text})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := tokenestimate.NewEstimator().Estimate(text); resp.Tokens != int64(want) || resp.Preset != "kimi-k2@12" {
+		t.Errorf("Estimate = %d with %s, want %d with kimi-k2@12", resp.Tokens, resp.Preset, want)
+	}
+
+	cl100k, _ := tokenestimate.NewEstimatorWithName("cl100k-base")
+	texts := []string{"Hello, world!", "你好世界", ""}
+	batch, err := client.EstimateBatch(ctx, &estimatepb.EstimateBatchRequest{Texts: texts, Preset: "cl100k-base"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total int64
+	for i, text := range texts {
+		if want := int64(cl100k.Estimate(text)); batch.Tokens[i] != want {
+			t.Errorf("EstimateBatch text %d = %d, want %d", i, batch.Tokens[i], want)
+		}
+		total += batch.Tokens[i]
+	}
+	if batch.Total != total || batch.Preset != "cl100k-base" {
+		t.Errorf("EstimateBatch total %d with %s, want %d with cl100k-base", batch.Total, batch.Preset, total)
+	}
+
+	_, err = client.Estimate(ctx, &estimatepb.EstimateRequest{Text: text, Preset: "no-such-preset"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Estimate with an unknown preset: %v, want NotFound", err)
+	}
+}
+
+func TestEstimateStream(t *testing.T) {
+	srv, err := New(Options{Preset: "cl100k-base"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := dial(t, srv)
+	e, _ := tokenestimate.NewEstimatorWithName("cl100k-base")
+
+	stream, err := client.EstimateStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second chunk ends within the UTF-8 sequence of "界".
+	text := "Hello, world! 你好世界"
+	chunks := []string{"Hello, ", "world! 你好世\xe7", "\x95\x8c"}
+	var sent string
+	for _, chunk := range chunks {
+		if err := stream.Send(&estimatepb.EstimateStreamRequest{Chunk: []byte(chunk)}); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sent += chunk
+		if resp.Bytes != int64(len(sent)) || resp.Preset != "cl100k-base" {
+			t.Errorf("After %q: %d bytes with %s, want %d with cl100k-base", sent, resp.Bytes, resp.Preset, len(sent))
+		}
+		if sent == text && resp.Tokens != int64(e.Estimate(text)) {
+			t.Errorf("Running estimate of %q = %d, want %d", text, resp.Tokens, e.Estimate(text))
+		}
+	}
+	stream.CloseSend()
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Recv after CloseSend: %v, want EOF", err)
+	}
+}
+
+func TestEstimateStreamLimit(t *testing.T) {
+	limited := tokenestimate.NewEstimator().WithMaxInputBytes(16, tokenestimate.InputLimitError)
+	limited.Name, limited.Version = "grpcserver-limited", 0
+	if err := tokenestimate.RegisterPreset(limited); err != nil {
+		t.Fatal(err)
+	}
+	srv, err := New(Options{Preset: "grpcserver-limited"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := dial(t, srv)
+
+	_, err = client.Estimate(context.Background(), &estimatepb.EstimateRequest{Text: strings.Repeat("x", 17)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Estimate over the limit: %v, want InvalidArgument", err)
+	}
+
+	stream, err := client.EstimateStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		stream.Send(&estimatepb.EstimateStreamRequest{Chunk: []byte("0123456789")})
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("First chunk: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Stream over the limit: %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,158 @@
+package tokenestimate
+
+import (
+	"html"
+	"strings"
+)
+
+// HTMLHandling selects how an Estimator treats HTML markup.
+type HTMLHandling int
+
+const (
+	// HTMLAsText analyzes markup like any other text (the default).
+	HTMLAsText HTMLHandling = iota
+	// HTMLStripTags estimates only the visible text: tags, comments and
+	// the contents of script and style elements are dropped, and entities
+	// such as &amp; are decoded.
+	HTMLStripTags
+	// HTMLCountTags estimates the raw HTML, additionally counting tags and
+	// entities in Stats.HTMLTags and Stats.HTMLEntities so presets can
+	// price how densely markup tokenizes.
+	HTMLCountTags
+)
+
+// WithHTMLHandling returns a clone of the estimator using the given mode
+// for HTML markup.
+func (e *Estimator) WithHTMLHandling(mode HTMLHandling) *Estimator {
+	clone := e.Clone()
+	clone.HTMLHandling = mode
+	return clone
+}
+
+// htmlBlockElements are the elements that start a new line of visible
+// text, so stripping them leaves a line break rather than joining words.
+var htmlBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"br": true, "dd": true, "div": true, "dl": true, "dt": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true,
+	"td": true, "th": true, "title": true, "tr": true, "ul": true,
+}
+
+// htmlTagEnd returns the index just past the tag, comment or declaration
+// starting at text[i], or -1 if text[i] does not start one.
+func htmlTagEnd(text string, i int) int {
+	if text[i] != '<' || i+1 >= len(text) {
+		return -1
+	}
+	switch c := text[i+1]; {
+	case c == '!' && strings.HasPrefix(text[i:], "<!--"):
+		if end := strings.Index(text[i+4:], "-->"); end >= 0 {
+			return i + 4 + end + 3
+		}
+		return -1
+	case c == '/' || c == '!' || c == '?' || isASCIILetter(rune(c)):
+		if end := strings.IndexByte(text[i+1:], '>'); end >= 0 {
+			return i + 1 + end + 1
+		}
+	}
+	return -1
+}
+
+// htmlTagName returns the lower-cased element name of a tag and whether it
+// is a closing tag. Comments and declarations have an empty name.
+func htmlTagName(tag string) (name string, closing bool) {
+	tag = tag[1:]
+	if strings.HasPrefix(tag, "/") {
+		tag, closing = tag[1:], true
+	}
+	end := 0
+	for end < len(tag) && (isASCIILetter(rune(tag[end])) || tag[end] >= '0' && tag[end] <= '9') {
+		end++
+	}
+	return strings.ToLower(tag[:end]), closing
+}
+
+// htmlEntityEnd returns the index just past the character reference
+// starting at text[i] ("&amp;", "&#39;", "&#x27;"), or -1 if there is none.
+func htmlEntityEnd(text string, i int) int {
+	if text[i] != '&' {
+		return -1
+	}
+	j := i + 1
+	if j < len(text) && text[j] == '#' {
+		j++
+		if j < len(text) && (text[j] == 'x' || text[j] == 'X') {
+			j++
+		}
+	}
+	start := j
+	for j < len(text) && j-start < 32 && (isASCIILetter(rune(text[j])) || text[j] >= '0' && text[j] <= '9') {
+		j++
+	}
+	if j == start || j >= len(text) || text[j] != ';' {
+		return -1
+	}
+	return j + 1
+}
+
+// countHTML counts the tags and entities in text.
+func countHTML(text string) (tags, entities int) {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '<':
+			if end := htmlTagEnd(text, i); end >= 0 {
+				tags++
+				i = end - 1
+			}
+		case '&':
+			if end := htmlEntityEnd(text, i); end >= 0 {
+				entities++
+				i = end - 1
+			}
+		}
+	}
+	return tags, entities
+}
+
+// stripHTML returns the visible text of an HTML document.
+func stripHTML(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for i := 0; i < len(text); {
+		end := -1
+		if text[i] == '<' {
+			end = htmlTagEnd(text, i)
+		}
+		if end < 0 {
+			next := strings.IndexByte(text[i+1:], '<')
+			if next < 0 {
+				b.WriteString(text[i:])
+				break
+			}
+			b.WriteString(text[i : i+1+next])
+			i += 1 + next
+			continue
+		}
+
+		name, closing := htmlTagName(text[i:end])
+		i = end
+		if htmlBlockElements[name] {
+			b.WriteByte('\n')
+		}
+		if !closing && (name == "script" || name == "style") && !strings.HasSuffix(text[:end], "/>") {
+			// Skip the element's contents up to its closing tag.
+			closeTag := "</" + name
+			rest := strings.Index(strings.ToLower(text[i:]), closeTag)
+			if rest < 0 {
+				break
+			}
+			i += rest
+		}
+	}
+
+	return html.UnescapeString(b.String())
+}
@@ -0,0 +1,72 @@
+package tokenestimate
+
+import "testing"
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{name: "Inline tags", html: "Hello <b>bold</b> world", expected: "Hello bold world"},
+		{name: "Block tags", html: "<p>One</p><p>Two</p>", expected: "\nOne\n\nTwo\n"},
+		{name: "Entities", html: "Tom &amp; Jerry &lt;3 &#39;x&#x27;", expected: "Tom & Jerry <3 'x'"},
+		{name: "Comments", html: "a<!-- hidden <b> -->b", expected: "ab"},
+		{name: "Script and style", html: "x<script>if (a < b) {}</script><STYLE>p{}</STYLE>y", expected: "xy"},
+		{name: "Not a tag", html: "1 < 2 and 3 > 2", expected: "1 < 2 and 3 > 2"},
+		{name: "Unclosed tag", html: "a <b", expected: "a <b"},
+		{name: "Doctype", html: "<!DOCTYPE html><title>T</title>", expected: "\nT\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripHTML(tt.html); got != tt.expected {
+				t.Errorf("stripHTML(%q) = %q, want %q", tt.html, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHTMLHandling(t *testing.T) {
+	page := `<div class="post"><h1>Title</h1><p>Fish &amp; chips, <a href="/menu">menu</a>.</p></div>`
+
+	t.Run("Default treats markup as text", func(t *testing.T) {
+		stats := NewEstimator().Analyze(page)
+		if stats.HTMLTags != 0 || stats.HTMLEntities != 0 {
+			t.Errorf("Expected no HTML counts, got %d tags, %d entities", stats.HTMLTags, stats.HTMLEntities)
+		}
+	})
+
+	t.Run("StripTags estimates visible text", func(t *testing.T) {
+		estimator := NewEstimator().WithHTMLHandling(HTMLStripTags)
+		want := NewEstimator().Estimate("\n\nTitle\n\nFish & chips, menu.\n\n")
+		if got := estimator.Estimate(page); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("CountTags counts markup", func(t *testing.T) {
+		estimator := NewEstimator().WithHTMLHandling(HTMLCountTags)
+		stats := estimator.Analyze(page)
+		if stats.HTMLTags != 8 || stats.HTMLEntities != 1 {
+			t.Errorf("Expected 8 tags and 1 entity, got %d and %d", stats.HTMLTags, stats.HTMLEntities)
+		}
+		if got, plain := estimator.Estimate(page), NewEstimator().Estimate(page); got <= plain {
+			t.Errorf("Expected raw HTML estimate above %d, got %d", plain, got)
+		}
+	})
+
+	t.Run("Pinned versions ignore tag counts", func(t *testing.T) {
+		pinned, _ := NewEstimatorWithName("kimi-k2@7")
+		if got, want := pinned.WithHTMLHandling(HTMLCountTags).Estimate(page), pinned.Estimate(page); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Configuration is kept across content types", func(t *testing.T) {
+		estimator := NewEstimator().WithHTMLHandling(HTMLStripTags).WithContentType(ContentMarkdown)
+		if estimator.HTMLHandling != HTMLStripTags {
+			t.Errorf("Expected HTMLStripTags, got %v", estimator.HTMLHandling)
+		}
+	})
+}
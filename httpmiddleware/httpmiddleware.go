@@ -0,0 +1,193 @@
+// Package httpmiddleware enforces token limits in net/http servers, such as
+// gateways in front of a model, rejecting over-limit requests before they
+// reach the upstream:
+//
+//	h := httpmiddleware.LimitTokens(proxy, 128000, httpmiddleware.OpenAIPrompt)
+//	http.ListenAndServe(":8080", http.MaxBytesHandler(h, 32<<20))
+//
+// The extractor returns the text of a request to estimate, or an error if
+// the request cannot be read, which LimitTokens rejects. OpenAIPrompt
+// reads it from the JSON body of OpenAI-style chat completion, completion,
+// embedding and responses requests, and JSONFields from named fields of
+// other JSON bodies. Both leave the body readable by the next handler.
+//
+// TrackUsage and UsageTransport estimate the input and output tokens of
+// OpenAI-compatible requests passing through an httputil.ReverseProxy,
+// including streamed responses, for usage accounting in gateways whose
+// upstreams do not report usage:
+//
+//	proxy := httputil.NewSingleHostReverseProxy(upstream)
+//	httpmiddleware.TrackUsage(proxy, func(r *http.Request, u httpmiddleware.Usage) {
+//		log.Printf("%s %s: %d in, %d out", r.URL.Path, u.Model, u.InputTokens, u.OutputTokens)
+//	})
+package httpmiddleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// LimitTokens returns a handler that estimates the text extractor returns
+// for each request, with the estimator of NewEstimator at the time of the
+// call, and replies 413 Request Entity Too Large to requests estimated at
+// more than limit tokens instead of calling next. The reply is an error
+// object in the shape of OpenAI's, with the code
+// "context_length_exceeded", so that OpenAI clients report it as such.
+// Requests whose text extractor fails to read are not forwarded either:
+// they get 413 if their body is over the limit of an http.MaxBytesReader
+// and 400 Bad Request otherwise.
+func LimitTokens(next http.Handler, limit int, extractor func(*http.Request) (string, error)) http.Handler {
+	e := tokenestimate.NewEstimator()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		text, err := extractor(r)
+		if err != nil {
+			writeReadError(w, err)
+			return
+		}
+		if tokens := e.Estimate(text); tokens > limit {
+			writeLimitError(w, tokens, limit)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limitError is the body of error replies.
+type limitError struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
+
+func writeLimitError(w http.ResponseWriter, tokens, limit int) {
+	message := fmt.Sprintf("request is estimated at %d tokens, over the limit of %d", tokens, limit)
+	writeError(w, http.StatusRequestEntityTooLarge, message, "context_length_exceeded")
+}
+
+func writeReadError(w http.ResponseWriter, err error) {
+	var maxBytes *http.MaxBytesError
+	if errors.As(err, &maxBytes) {
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error(), "request_too_large")
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error(), "invalid_request_body")
+}
+
+func writeError(w http.ResponseWriter, status int, message, code string) {
+	var body limitError
+	body.Error.Message = message
+	body.Error.Type = "invalid_request_error"
+	body.Error.Code = code
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// ReadBody reads the body of r and replaces it with a reader of the same
+// bytes, so that the next handler can read it again. Bound the size of
+// bodies with http.MaxBytesHandler or http.MaxBytesReader.
+func ReadBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err
+}
+
+// OpenAIPrompt returns the prompt text of an OpenAI-style JSON request,
+// for LimitTokens: the contents of the messages of a chat completion, the
+// prompt of a completion, the input of an embedding, and the instructions
+// and input of a responses request, separated by newlines. Content parts
+// count by their text. It returns "" if the body is not JSON, and the
+// error of ReadBody if the body cannot be read.
+func OpenAIPrompt(r *http.Request) (string, error) {
+	body, err := ReadBody(r)
+	if err != nil {
+		return "", err
+	}
+	return OpenAIPromptJSON(body), nil
+}
+
+// OpenAIPromptJSON is like OpenAIPrompt but takes the body.
+func OpenAIPromptJSON(body []byte) string {
+	var req struct {
+		Messages     json.RawMessage `json:"messages"`
+		Prompt       json.RawMessage `json:"prompt"`
+		Input        json.RawMessage `json:"input"`
+		Instructions json.RawMessage `json:"instructions"`
+	}
+	if json.Unmarshal(body, &req) != nil {
+		return ""
+	}
+	var texts []string
+	for _, field := range []json.RawMessage{req.Instructions, req.Messages, req.Prompt, req.Input} {
+		texts = appendTexts(texts, field)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// appendTexts appends the texts of a JSON value to texts: a string, the
+// texts of the elements of an array, or the texts of the content and text
+// fields of an object, such as a message or a content part. Numbers, such
+// as the token IDs a prompt may be given as, are skipped.
+func appendTexts(texts []string, raw json.RawMessage) []string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return texts
+	}
+	switch raw[0] {
+	case '"':
+		var s string
+		if json.Unmarshal(raw, &s) == nil && s != "" {
+			texts = append(texts, s)
+		}
+	case '[':
+		var elems []json.RawMessage
+		json.Unmarshal(raw, &elems)
+		for _, elem := range elems {
+			texts = appendTexts(texts, elem)
+		}
+	case '{':
+		var obj struct {
+			Content json.RawMessage `json:"content"`
+			Text    json.RawMessage `json:"text"`
+		}
+		json.Unmarshal(raw, &obj)
+		texts = appendTexts(texts, obj.Content)
+		texts = appendTexts(texts, obj.Text)
+	}
+	return texts
+}
+
+// JSONFields returns an extractor of the top-level fields names of a JSON
+// request body, for payloads other than OpenAI's. Fields are read like the
+// fields of OpenAIPrompt: strings, arrays and the content and text of
+// objects, separated by newlines.
+func JSONFields(names ...string) func(*http.Request) (string, error) {
+	return func(r *http.Request) (string, error) {
+		body, err := ReadBody(r)
+		if err != nil {
+			return "", err
+		}
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(body, &fields) != nil {
+			return "", nil
+		}
+		var texts []string
+		for _, name := range names {
+			texts = appendTexts(texts, fields[name])
+		}
+		return strings.Join(texts, "\n"), nil
+	}
+}
@@ -0,0 +1,107 @@
+package httpmiddleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+func TestLimitTokens(t *testing.T) {
+	var upstream string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		upstream = string(body)
+	})
+	h := LimitTokens(next, 20, OpenAIPrompt)
+
+	short := `{"model": "kimi-k2", "messages": [{"role": "user", "content": "Hello, world!"}]}`
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(short)))
+	if rec.Code != http.StatusOK || upstream != short {
+		t.Errorf("Short request: %d, upstream read %q; want 200 and the whole body", rec.Code, upstream)
+	}
+
+	upstream = ""
+	long := `{"model": "kimi-k2", "messages": [{"role": "user", "content": "` + strings.Repeat("Hello, world! ", 20) + `"}]}`
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(long)))
+	var reply limitError
+	json.Unmarshal(rec.Body.Bytes(), &reply)
+	if rec.Code != http.StatusRequestEntityTooLarge || upstream != "" || reply.Error.Code != "context_length_exceeded" {
+		t.Errorf("Long request: %d %s, upstream read %q; want 413 context_length_exceeded without calling upstream", rec.Code, rec.Body, upstream)
+	}
+}
+
+func TestLimitTokensReadError(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true })
+	body := `{"messages": [{"role": "user", "content": "` + strings.Repeat("Hello, world! ", 100) + `"}]}`
+
+	h := http.MaxBytesHandler(LimitTokens(next, 1<<20, OpenAIPrompt), 64)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body)))
+	if rec.Code != http.StatusRequestEntityTooLarge || called {
+		t.Errorf("Body over MaxBytesHandler: %d, next called %v; want 413 without calling next", rec.Code, called)
+	}
+
+	h = LimitTokens(next, 1<<20, OpenAIPrompt)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", iotest.ErrReader(errors.New("connection reset"))))
+	var reply limitError
+	json.Unmarshal(rec.Body.Bytes(), &reply)
+	if rec.Code != http.StatusBadRequest || called || reply.Error.Code != "invalid_request_body" {
+		t.Errorf("Failed read: %d %s, next called %v; want 400 without calling next", rec.Code, rec.Body, called)
+	}
+}
+
+func TestOpenAIPrompt(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"chat", `{"model": "gpt-4o", "messages": [
+			{"role": "system", "content": "Be brief."},
+			{"role": "user", "content": [{"type": "text", "text": "What is this?"}, {"type": "image_url", "image_url": {"url": "https://example.com/a.png"}}]},
+			{"role": "assistant", "content": null, "tool_calls": []}]}`, "Be brief.\nWhat is this?"},
+		{"completion", `{"model": "gpt-3.5-turbo-instruct", "prompt": "Say this is a test", "max_tokens": 7}`, "Say this is a test"},
+		{"prompt list", `{"prompt": ["a", "b"]}`, "a\nb"},
+		{"token IDs", `{"prompt": [1212, 318]}`, ""},
+		{"embedding", `{"model": "text-embedding-3-small", "input": ["first", "second"]}`, "first\nsecond"},
+		{"responses", `{"instructions": "Be brief.", "input": [{"role": "user", "content": [{"type": "input_text", "text": "Hi"}]}]}`, "Be brief.\nHi"},
+		{"not JSON", `prompt=hello`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if got, err := OpenAIPrompt(r); got != tt.want || err != nil {
+				t.Errorf("OpenAIPrompt = %q, %v, want %q", got, err, tt.want)
+			}
+			if body, _ := io.ReadAll(r.Body); string(body) != tt.body {
+				t.Errorf("Body after OpenAIPrompt = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestJSONFields(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"query": "Hello", "context": ["a", {"text": "b"}], "top_k": 3}`))
+	if got, err := JSONFields("query", "context", "missing")(r); got != "Hello\na\nb" || err != nil {
+		t.Errorf("JSONFields = %q, %v, want %q", got, err, "Hello\na\nb")
+	}
+
+	e := tokenestimate.NewEstimator()
+	h := LimitTokens(http.NotFoundHandler(), e.Estimate("Hello")-1, JSONFields("query"))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"query": "Hello"}`)))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Over-limit request: %d, want 413", rec.Code)
+	}
+}
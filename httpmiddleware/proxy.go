@@ -0,0 +1,267 @@
+package httpmiddleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httputil"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// Usage is the token usage of a request that passed through a proxy.
+type Usage struct {
+	Model        string // model of the request, if it names one
+	InputTokens  int
+	OutputTokens int
+	Stream       bool // the response was streamed as server-sent events
+	Reported     bool // the upstream reported the usage, which replaces the estimates
+}
+
+// TrackUsage makes proxy report the usage of every OpenAI-compatible
+// request it forwards to callback, by wrapping its Transport with
+// UsageTransport.
+func TrackUsage(proxy *httputil.ReverseProxy, callback func(*http.Request, Usage)) {
+	proxy.Transport = UsageTransport(proxy.Transport, callback)
+}
+
+// UsageTransport returns a RoundTripper that sends requests with next, or
+// http.DefaultTransport if nil, and calls callback with the usage of each
+// request once its response body has been read to the end or closed, for
+// gateways that need usage accounting when upstreams do not return usage.
+// The input is the prompt of the request as OpenAIPrompt reads it, and the
+// output the generated text of the response: the message contents, tool
+// call arguments and texts of a JSON response, or of the chunks of a
+// streamed one as they pass; responses that are neither JSON nor a stream
+// are reported without output. The Accept-Encoding header of requests is
+// not forwarded, so that next, if an http.Transport, asks for gzip itself
+// and decodes the response before it is read; responses with a
+// Content-Encoding left on them are reported without output. Estimates
+// use the estimator of NewEstimator at the time of the call. When the
+// response carries usage fields, as OpenAI's do, those are reported
+// instead. Callback is not called for requests that fail without a
+// response.
+func UsageTransport(next http.RoundTripper, callback func(*http.Request, Usage)) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &usageTransport{next: next, e: tokenestimate.NewEstimator(), callback: callback}
+}
+
+type usageTransport struct {
+	next     http.RoundTripper
+	e        *tokenestimate.Estimator
+	callback func(*http.Request, Usage)
+}
+
+func (t *usageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Header.Del("Accept-Encoding")
+	body, err := ReadBody(out)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		out.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+	var model struct {
+		Model string `json:"model"`
+	}
+	json.Unmarshal(body, &model)
+	usage := Usage{Model: model.Model, InputTokens: t.e.Estimate(OpenAIPromptJSON(body))}
+
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	contentType := resp.Header.Get("Content-Type")
+	usage.Stream = strings.HasPrefix(contentType, "text/event-stream")
+	encoded := resp.Header.Get("Content-Encoding") != "" && resp.Header.Get("Content-Encoding") != "identity"
+	if encoded || !usage.Stream && !strings.HasPrefix(contentType, "application/json") {
+		t.callback(req, usage)
+		return resp, nil
+	}
+	resp.Body = &usageBody{
+		ReadCloser: resp.Body,
+		output:     tokenestimate.NewStreamEstimator(t.e),
+		usage:      usage,
+		done:       func(u Usage) { t.callback(req, u) },
+	}
+	return resp, nil
+}
+
+// usageBody is a response body that estimates the output passing through
+// it, and reports the usage at its end.
+type usageBody struct {
+	io.ReadCloser
+	output  *tokenestimate.StreamEstimator
+	usage   Usage
+	pending []byte // incomplete line of a stream, or the whole JSON response
+	data    []byte // data of the current server-sent event
+	once    sync.Once
+	done    func(Usage)
+}
+
+func (b *usageBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.pending = append(b.pending, p[:n]...)
+	if b.usage.Stream {
+		b.scanLines()
+	}
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *usageBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish()
+	return err
+}
+
+// scanLines parses the complete lines of a stream of server-sent events,
+// handling each event at the blank line that ends it.
+func (b *usageBody) scanLines() {
+	for {
+		i := bytes.IndexByte(b.pending, '\n')
+		if i < 0 {
+			return
+		}
+		line := bytes.TrimSuffix(b.pending[:i], []byte("\r"))
+		b.pending = b.pending[i+1:]
+		switch {
+		case len(line) == 0:
+			b.event()
+		case bytes.HasPrefix(line, []byte("data:")):
+			if b.data != nil {
+				b.data = append(b.data, '\n')
+			}
+			b.data = append(b.data, bytes.TrimPrefix(line[len("data:"):], []byte(" "))...)
+		}
+	}
+}
+
+// event handles the data of a server-sent event: a chunk of a chat
+// completion, or an event of a streamed responses request.
+func (b *usageBody) event() {
+	data := b.data
+	b.data = nil
+	if len(data) == 0 || string(data) == "[DONE]" {
+		return
+	}
+	var event struct {
+		Type     string          `json:"type"`
+		Delta    json.RawMessage `json:"delta"`
+		Response json.RawMessage `json:"response"`
+	}
+	if json.Unmarshal(data, &event) != nil {
+		return
+	}
+	if strings.HasPrefix(event.Type, "response.") {
+		// Events other than deltas repeat the text of earlier ones.
+		if strings.HasSuffix(event.Type, ".delta") {
+			b.writeTexts(event.Delta)
+		}
+		b.reported(event.Response)
+		return
+	}
+	b.writeTexts(data)
+	b.reported(data)
+}
+
+// finish estimates a JSON response, and reports the usage once.
+func (b *usageBody) finish() {
+	b.once.Do(func() {
+		if b.usage.Stream {
+			b.pending = append(b.pending, '\n') // end a last line without a newline
+			b.scanLines()
+			b.event()
+		} else {
+			b.writeTexts(b.pending)
+			b.reported(b.pending)
+		}
+		b.pending = nil
+		if !b.usage.Reported {
+			b.usage.OutputTokens = b.output.Tokens()
+		}
+		b.done(b.usage)
+	})
+}
+
+// outputFields are the fields whose strings are generated text, in chat
+// completions, completions, responses and their streamed chunks.
+var outputFields = map[string]bool{
+	"content":           true,
+	"text":              true,
+	"arguments":         true,
+	"refusal":           true,
+	"reasoning_content": true,
+	"delta":             true,
+}
+
+// writeTexts writes the generated text of a JSON value to the output: the
+// strings of outputFields in any object within it, in the order of their
+// names so that the estimate does not vary.
+func (b *usageBody) writeTexts(raw json.RawMessage) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return
+	}
+	switch raw[0] {
+	case '"':
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			b.output.WriteString(s)
+		}
+	case '[':
+		var elems []json.RawMessage
+		json.Unmarshal(raw, &elems)
+		for _, elem := range elems {
+			b.writeTexts(elem)
+		}
+	case '{':
+		var fields map[string]json.RawMessage
+		json.Unmarshal(raw, &fields)
+		for _, name := range slices.Sorted(maps.Keys(fields)) {
+			value := bytes.TrimSpace(fields[name])
+			if len(value) > 0 && value[0] == '"' {
+				if outputFields[name] {
+					b.writeTexts(value)
+				}
+			} else if name != "usage" {
+				b.writeTexts(value)
+			}
+		}
+	}
+}
+
+// reported takes the usage fields of a JSON response, if it has them.
+func (b *usageBody) reported(raw json.RawMessage) {
+	var resp struct {
+		Usage *struct {
+			PromptTokens     *int `json:"prompt_tokens"`
+			CompletionTokens *int `json:"completion_tokens"`
+			InputTokens      *int `json:"input_tokens"`
+			OutputTokens     *int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if json.Unmarshal(raw, &resp) != nil || resp.Usage == nil {
+		return
+	}
+	u := resp.Usage
+	switch {
+	case u.PromptTokens != nil && u.CompletionTokens != nil:
+		b.usage.InputTokens, b.usage.OutputTokens = *u.PromptTokens, *u.CompletionTokens
+	case u.InputTokens != nil && u.OutputTokens != nil:
+		b.usage.InputTokens, b.usage.OutputTokens = *u.InputTokens, *u.OutputTokens
+	default:
+		return
+	}
+	b.usage.Reported = true
+}
@@ -0,0 +1,166 @@
+package httpmiddleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// proxyTo returns a proxy to an upstream replying with contentType and
+// body, and the usages reported for its requests.
+func proxyTo(t *testing.T, contentType string, body ...string) (*httptest.Server, func() []Usage) {
+	t.Helper()
+	return proxyToHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", contentType)
+		for _, chunk := range body {
+			io.WriteString(w, chunk)
+			w.(http.Flusher).Flush()
+		}
+	}))
+}
+
+// proxyToHandler is like proxyTo for an upstream served by h.
+func proxyToHandler(t *testing.T, h http.Handler) (*httptest.Server, func() []Usage) {
+	t.Helper()
+	upstream := httptest.NewServer(h)
+	t.Cleanup(upstream.Close)
+	target, _ := url.Parse(upstream.URL)
+
+	var mu sync.Mutex
+	var usages []Usage
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	TrackUsage(proxy, func(r *http.Request, u Usage) {
+		mu.Lock()
+		defer mu.Unlock()
+		usages = append(usages, u)
+	})
+	srv := httptest.NewServer(proxy)
+	t.Cleanup(srv.Close)
+	return srv, func() []Usage {
+		mu.Lock()
+		defer mu.Unlock()
+		return usages
+	}
+}
+
+// send posts body through the proxy and returns the response body.
+func send(t *testing.T, srv *httptest.Server, body string) string {
+	t.Helper()
+	resp, err := http.Post(srv.URL+"/v1/chat/completions", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return string(b)
+}
+
+const chatRequest = `{"model": "kimi-k2", "messages": [{"role": "user", "content": "Write a haiku about the sea."}]}`
+
+func TestTrackUsage(t *testing.T) {
+	e := tokenestimate.NewEstimator()
+	input := e.Estimate("Write a haiku about the sea.")
+	reply := "Waves fold into foam,\nsalt wind carries gull voices,\nthe tide keeps its time."
+
+	t.Run("JSON", func(t *testing.T) {
+		srv, usages := proxyTo(t, "application/json", `{"id": "chatcmpl-1", "object": "chat.completion", "model": "kimi-k2",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "`+strings.ReplaceAll(reply, "\n", `\n`)+`"}, "finish_reason": "stop"}]}`)
+		send(t, srv, chatRequest)
+		want := Usage{Model: "kimi-k2", InputTokens: input, OutputTokens: e.Estimate(reply)}
+		if got := usages(); len(got) != 1 || got[0] != want {
+			t.Errorf("Usage = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		var chunks []string
+		for _, word := range strings.SplitAfter(reply, " ") {
+			chunks = append(chunks, `data: {"object": "chat.completion.chunk", "choices": [{"index": 0, "delta": {"content": "`+strings.ReplaceAll(word, "\n", `\n`)+`"}}]}`+"\n\n")
+		}
+		// A chunk split within an event, as a network read may split it.
+		last := `data: {"object": "chat.completion.chunk", "choices": [{"index": 0, "delta": {}, "finish_reason": "stop"}]}` + "\n\ndata: [DONE]\n\n"
+		chunks = append(chunks, last[:20], last[20:])
+		srv, usages := proxyTo(t, "text/event-stream", chunks...)
+		if got := send(t, srv, chatRequest); got != strings.Join(chunks, "") {
+			t.Errorf("Proxy changed the stream to %q", got)
+		}
+		want := Usage{Model: "kimi-k2", InputTokens: input, OutputTokens: e.Estimate(reply), Stream: true}
+		if got := usages(); len(got) != 1 || got[0] != want {
+			t.Errorf("Usage = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("responses stream", func(t *testing.T) {
+		srv, usages := proxyTo(t, "text/event-stream",
+			"event: response.output_text.delta\ndata: {\"type\": \"response.output_text.delta\", \"delta\": \"Waves fold \"}\n\n",
+			"event: response.output_text.delta\ndata: {\"type\": \"response.output_text.delta\", \"delta\": \"into foam\"}\n\n",
+			"event: response.output_text.done\ndata: {\"type\": \"response.output_text.done\", \"text\": \"Waves fold into foam\"}\n\n")
+		send(t, srv, `{"model": "gpt-4o", "input": "Write a haiku about the sea."}`)
+		want := Usage{Model: "gpt-4o", InputTokens: input, OutputTokens: e.Estimate("Waves fold into foam"), Stream: true}
+		if got := usages(); len(got) != 1 || got[0] != want {
+			t.Errorf("Usage = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("reported", func(t *testing.T) {
+		srv, usages := proxyTo(t, "text/event-stream",
+			`data: {"choices": [{"index": 0, "delta": {"content": "Hi"}}]}`+"\n\n",
+			`data: {"choices": [], "usage": {"prompt_tokens": 15, "completion_tokens": 1, "total_tokens": 16}}`+"\n\n",
+			"data: [DONE]\n\n")
+		send(t, srv, chatRequest)
+		want := Usage{Model: "kimi-k2", InputTokens: 15, OutputTokens: 1, Stream: true, Reported: true}
+		if got := usages(); len(got) != 1 || got[0] != want {
+			t.Errorf("Usage = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		response := `{"choices": [{"index": 0, "message": {"role": "assistant", "content": "` + strings.ReplaceAll(reply, "\n", `\n`) + `"}}]}`
+		srv, usages := proxyToHandler(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+				io.WriteString(w, response)
+				return
+			}
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			io.WriteString(zw, response)
+			zw.Close()
+		}))
+		req, _ := http.NewRequest("POST", srv.URL+"/v1/chat/completions", strings.NewReader(chatRequest))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != response {
+			t.Errorf("Proxy returned %q, want the decoded response", body)
+		}
+		want := Usage{Model: "kimi-k2", InputTokens: input, OutputTokens: e.Estimate(reply)}
+		if got := usages(); len(got) != 1 || got[0] != want {
+			t.Errorf("Usage = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("not JSON", func(t *testing.T) {
+		srv, usages := proxyTo(t, "text/plain", "upstream unavailable")
+		send(t, srv, chatRequest)
+		want := Usage{Model: "kimi-k2", InputTokens: input}
+		if got := usages(); len(got) != 1 || got[0] != want {
+			t.Errorf("Usage = %+v, want %+v", got, want)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package tokenestimate
+
+import "fmt"
+
+// InputLimitPolicy selects what happens to input longer than
+// MaxInputBytes.
+type InputLimitPolicy int
+
+const (
+	// InputLimitSample samples oversized input, even when sampling is
+	// disabled, so that its cost is bounded by the sample size rather
+	// than the length of the text.
+	InputLimitSample InputLimitPolicy = iota
+	// InputLimitError makes EstimateStrict, EstimateContext and
+	// EstimateReader return an *InputTooLargeError for oversized input.
+	// Methods that cannot return an error sample it like InputLimitSample.
+	InputLimitError
+)
+
+// InputTooLargeError is returned for input longer than MaxInputBytes when
+// the policy is InputLimitError.
+type InputTooLargeError struct {
+	Limit int // MaxInputBytes of the estimator
+}
+
+func (err *InputTooLargeError) Error() string {
+	return fmt.Sprintf("input exceeds the limit of %d bytes", err.Limit)
+}
+
+// WithMaxInputBytes returns a clone of the estimator that treats input
+// longer than n bytes as oversized, so that untrusted input cannot make
+// an estimate arbitrarily expensive. Oversized text is sampled with
+// SamplingSize characters (16384 if unset), including by EstimateContext
+// and ExceedsLimit; with InputLimitError, the methods that return an
+// error reject it instead, and EstimateReader stops reading once it has
+// read more than n bytes. A limit of 0 removes the limit.
+//
+// Streamed input cannot be sampled, since its length is not known in
+// advance: with InputLimitSample, EstimateReader, EstimateFile,
+// StreamEstimator and the counting readers and writers read and analyze
+// all of it, in bounded memory. Use InputLimitError to bound their time.
+//
+// Sampling bounds the time spent on an oversized text but not the memory
+// used by HTMLStripTags, which strips a copy of the whole text.
+func (e *Estimator) WithMaxInputBytes(n int, policy InputLimitPolicy) *Estimator {
+	clone := e.Clone()
+	clone.MaxInputBytes = n
+	clone.InputLimit = policy
+	return clone
+}
+
+// oversized reports whether input of n bytes is longer than MaxInputBytes.
+func (e *Estimator) oversized(n int) bool {
+	return e.MaxInputBytes > 0 && n > e.MaxInputBytes
+}
+
+// checkInput returns an *InputTooLargeError if input of n bytes is
+// oversized and the policy is InputLimitError.
+func (e *Estimator) checkInput(n int) error {
+	if e.InputLimit == InputLimitError && e.oversized(n) {
+		return &InputTooLargeError{Limit: e.MaxInputBytes}
+	}
+	return nil
+}
+
+// samplingSize returns the number of characters to sample, falling back
+// to the default for oversized input when SamplingSize is unset.
+func (e *Estimator) samplingSize() int {
+	if e.SamplingSize > 0 {
+		return e.SamplingSize
+	}
+	return defaultSamplingSize
+}
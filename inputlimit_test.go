@@ -0,0 +1,104 @@
+package tokenestimate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMaxInputBytes(t *testing.T) {
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 1000)
+
+	t.Run("Texts within the limit are analyzed in full", func(t *testing.T) {
+		base := NewEstimator().WithoutSampling()
+		estimator := base.WithMaxInputBytes(len(text), InputLimitSample)
+		if got, want := estimator.Analyze(text), base.Analyze(text); got != want {
+			t.Errorf("Expected %+v, got %+v", want, got)
+		}
+		if _, err := estimator.EstimateStrict(text); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Oversized texts are sampled", func(t *testing.T) {
+		base := NewEstimator().WithoutSampling()
+		estimator := base.WithMaxInputBytes(1000, InputLimitSample)
+		a := estimator.AnalyzeDetailed(text)
+		if !a.Sampled || a.SampleSize != defaultSamplingSize {
+			t.Fatalf("Expected a sample of %d characters, got %+v", defaultSamplingSize, a)
+		}
+		want := base.Estimate(text)
+		if diff := float64(a.Tokens-want) / float64(want); diff > 0.05 || diff < -0.05 {
+			t.Errorf("Expected about %d tokens, got %d", want, a.Tokens)
+		}
+	})
+
+	t.Run("Oversized long texts are sampled by all string methods", func(t *testing.T) {
+		long := strings.Repeat(text, 4)
+		estimator := NewEstimator().WithoutSampling().WithMaxInputBytes(1000, InputLimitSample)
+		want := estimator.Estimate(long)
+		if got, err := estimator.EstimateContext(context.Background(), long); got != want || err != nil {
+			t.Errorf("EstimateContext = %d, %v, want %d", got, err, want)
+		}
+		if estimator.ExceedsLimit(long, want) || !estimator.ExceedsLimit(long, want-1) {
+			t.Errorf("ExceedsLimit is inconsistent with the sampled estimate %d", want)
+		}
+	})
+
+	t.Run("Sample size of the estimator", func(t *testing.T) {
+		estimator := NewEstimator().WithSampling(len(text), 4000).WithMaxInputBytes(1000, InputLimitSample)
+		if a := estimator.AnalyzeDetailed(text); a.SampleSize != 4000 {
+			t.Errorf("Expected a sample of 4000 characters, got %d", a.SampleSize)
+		}
+	})
+
+	t.Run("Errors for oversized input", func(t *testing.T) {
+		estimator := NewEstimator().WithMaxInputBytes(1000, InputLimitError)
+		var limitErr *InputTooLargeError
+
+		if _, err := estimator.EstimateStrict(text); !errors.As(err, &limitErr) || limitErr.Limit != 1000 {
+			t.Errorf("EstimateStrict: expected *InputTooLargeError, got %v", err)
+		}
+		if _, err := estimator.EstimateContext(context.Background(), text); !errors.As(err, &limitErr) {
+			t.Errorf("EstimateContext: expected *InputTooLargeError, got %v", err)
+		}
+		if _, err := estimator.EstimateReader(strings.NewReader(text)); !errors.As(err, &limitErr) {
+			t.Errorf("EstimateReader: expected *InputTooLargeError, got %v", err)
+		}
+		if got := estimator.Estimate(text); got == 0 {
+			t.Error("Expected Estimate to sample oversized input")
+		}
+	})
+
+	t.Run("EstimateReader stops reading", func(t *testing.T) {
+		estimator := NewEstimator().WithMaxInputBytes(1<<20, InputLimitError)
+		r := &infiniteReader{}
+		if _, err := estimator.EstimateReader(r); err == nil {
+			t.Fatal("Expected an error")
+		}
+		if r.read > 1<<20+64<<10 {
+			t.Errorf("Expected reading to stop after the limit, read %d bytes", r.read)
+		}
+	})
+
+	t.Run("WithContentType keeps the limit", func(t *testing.T) {
+		estimator := NewEstimator().WithMaxInputBytes(1000, InputLimitError).WithContentType(ContentCode)
+		if estimator.MaxInputBytes != 1000 || estimator.InputLimit != InputLimitError {
+			t.Errorf("Expected the limit to be kept, got %d, %v", estimator.MaxInputBytes, estimator.InputLimit)
+		}
+	})
+}
+
+// infiniteReader returns an endless stream of text.
+type infiniteReader struct {
+	read int
+}
+
+func (r *infiniteReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = "abc "[(r.read+i)%4]
+	}
+	r.read += len(p)
+	return len(p), nil
+}
@@ -0,0 +1,119 @@
+// Package fitcmd implements the commands that fit presets to exact
+// tokenizers, such as tiktoken-fit: they label texts with a tokenizer's
+// counts and print the fitted coefficients with their accuracy on
+// held-out texts, or evaluate an existing preset instead.
+package fitcmd
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/infinigence/tokenestimate/corpus"
+	"github.com/infinigence/tokenestimate/dataset"
+	"github.com/infinigence/tokenestimate/eval"
+	"github.com/infinigence/tokenestimate/fit"
+)
+
+// Flags are the command-line flags the commands share.
+type Flags struct {
+	Dataset   string // file of texts, instead of the corpus
+	Synthetic int    // number of synthetic texts added to the corpus
+	Evaluate  string // preset to evaluate instead of fitting one
+	Name      string // name of the fitted preset
+}
+
+// Register defines the flags on the default flag set.
+func Register() *Flags {
+	f := &Flags{}
+	flag.StringVar(&f.Dataset, "dataset", "", "JSONL, CSV or TSV file of texts (default: the bundled corpus)")
+	flag.IntVar(&f.Synthetic, "synthetic", 400, "number of synthetic texts added to the bundled corpus")
+	flag.StringVar(&f.Evaluate, "evaluate", "", "evaluate this preset instead of fitting one")
+	flag.StringVar(&f.Name, "name", "", "name of the fitted preset (default: the tokenizer)")
+	return f
+}
+
+// profiles are the language mixes of the synthetic texts.
+var profiles = []tokenestimate.LanguageProfile{
+	{Latin: 0.8, Whitespace: 0.16, Symbols: 0.03, Digits: 0.01, AvgWordLength: 5},
+	{Chinese: 0.88, Symbols: 0.1, Digits: 0.02},
+	{Latin: 0.45, Chinese: 0.3, Whitespace: 0.12, Symbols: 0.08, Digits: 0.05},
+	{Russian: 0.78, Whitespace: 0.15, Symbols: 0.05, Digits: 0.02},
+	{Japanese: 0.5, Chinese: 0.35, Symbols: 0.15},
+	{Korean: 0.7, Whitespace: 0.2, Symbols: 0.1},
+	{Latin: 0.55, LatinExtended: 0.03, Whitespace: 0.25, Symbols: 0.15, Digits: 0.02},
+	{Arabic: 0.75, Whitespace: 0.18, Symbols: 0.05, Digits: 0.02},
+}
+
+// Run labels the texts with counter, whose tokenizer is named tokenizer,
+// and writes the preset fitted to them, or the accuracy of f.Evaluate, to
+// w.
+func (f *Flags) Run(w io.Writer, counter tokenestimate.TokenCounter, tokenizer string) error {
+	texts, err := f.texts()
+	if err != nil {
+		return err
+	}
+	samples, err := fit.Label(counter, texts)
+	if err != nil {
+		return err
+	}
+
+	if f.Evaluate != "" {
+		e, err := tokenestimate.NewEstimatorWithName(f.Evaluate)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s against %s\n\n%s", f.Evaluate, tokenizer, eval.Evaluate(e, samples))
+		return err
+	}
+
+	train, test := fit.Holdout(samples, 0.2, 1)
+	e, report, err := fit.Fit(train, fit.FitOptions{
+		Method:   fit.NNLS,
+		Relative: true,
+		Robust:   true,
+		Name:     cmp.Or(f.Name, tokenizer),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s fitted to %d samples labeled by %s\n\n", e.Name, report.Samples, tokenizer)
+	fmt.Fprintf(w, "intercept %.4f\n", report.Intercept)
+	for _, feature := range slices.Sorted(maps.Keys(report.Coefficients)) {
+		fmt.Fprintf(w, "%-22s %.4f\n", feature, report.Coefficients[feature])
+	}
+	_, err = fmt.Fprintf(w, "\non %d held-out samples:\n%s", len(test), eval.Evaluate(e, test))
+	return err
+}
+
+// texts returns the texts of f.Dataset, whose token counts are ignored, or
+// the corpus followed by f.Synthetic synthetic texts.
+func (f *Flags) texts() ([]string, error) {
+	if f.Dataset != "" {
+		samples, err := dataset.Load(f.Dataset)
+		if err != nil {
+			return nil, err
+		}
+		if len(samples) == 0 {
+			return nil, fmt.Errorf("%s has no samples", f.Dataset)
+		}
+		texts := make([]string, len(samples))
+		for i, s := range samples {
+			texts[i] = s.Text
+		}
+		return texts, nil
+	}
+	texts := corpus.Texts()
+	for i, p := range profiles {
+		count := f.Synthetic / len(profiles)
+		if i < f.Synthetic%len(profiles) {
+			count++
+		}
+		texts = append(texts, tokenestimate.GenerateTexts(p, count, 20, 2000, uint64(i))...)
+	}
+	return texts, nil
+}
@@ -0,0 +1,69 @@
+package fitcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// runeCounter counts one token per two runes, rounded up.
+type runeCounter struct{}
+
+func (runeCounter) Count(text string) (int, error) {
+	return (utf8.RuneCountInString(text) + 1) / 2, nil
+}
+
+func TestRun(t *testing.T) {
+	var out strings.Builder
+	f := &Flags{Synthetic: 40}
+	if err := f.Run(&out, runeCounter{}, "runes"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"runes fitted to", "labeled by runes", "intercept", "held-out samples"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected the output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	f.Evaluate = "kimi-k2"
+	if err := f.Run(&out, runeCounter{}, "runes"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "kimi-k2 against runes\n") {
+		t.Errorf("Expected an evaluation of kimi-k2, got:\n%s", out.String())
+	}
+
+	f.Evaluate = "unknown-preset"
+	if err := f.Run(&out, runeCounter{}, "runes"); err == nil {
+		t.Error("Expected an error for an unknown preset")
+	}
+}
+
+func TestRunDataset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "texts.jsonl")
+	data := strings.Repeat(`{"text": "The quick brown fox jumps over the lazy dog.", "token_count": 0}`+"\n"+`{"text": "你好，世界", "token_count": 0}`+"\n", 10)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	f := &Flags{Dataset: path, Name: "custom"}
+	if err := f.Run(&out, runeCounter{}, "runes"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "custom fitted to 16 samples") {
+		t.Errorf("Expected a preset fitted to 16 of the 20 texts, got:\n%s", out.String())
+	}
+
+	empty := filepath.Join(dir, "empty.jsonl")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f.Dataset = empty
+	if err := f.Run(&out, runeCounter{}, "runes"); err == nil || !strings.Contains(err.Error(), "has no samples") {
+		t.Errorf("Expected an error for an empty dataset, got %v", err)
+	}
+}
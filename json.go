@@ -0,0 +1,134 @@
+package tokenestimate
+
+import (
+	"strings"
+	"sync"
+)
+
+// EstimateJSONText estimates the number of tokens in a JSON document using
+// the preset's JSON model, which prices structural punctuation and keys
+// rather than treating them as prose symbols. The text need not be valid
+// JSON.
+func (e *Estimator) EstimateJSONText(s string) int {
+	if e.ContentType != ContentJSON {
+		e = e.WithContentType(ContentJSON)
+	}
+	return e.Estimate(s)
+}
+
+// jsonStats holds the structural features of a JSON document.
+type jsonStats struct {
+	structure    int // structural characters outside strings, plus string quotes
+	runs         int // maximal runs of adjacent structural characters
+	keys         int // strings followed by ':'
+	repeatedKeys int // keys already seen earlier in the document
+}
+
+// isJSONStructural reports whether c is JSON punctuation outside a string.
+func isJSONStructural(c byte) bool {
+	switch c {
+	case '{', '}', '[', ']', ':', ',':
+		return true
+	}
+	return false
+}
+
+// jsonKeySets recycles the key sets of scanJSON, so that analysis does not
+// allocate once the pool is warm.
+var jsonKeySets = sync.Pool{
+	New: func() any { return make(map[string]struct{}) },
+}
+
+// streamJSONKeys is the number of distinct keys a stream remembers across
+// its chunks, which bounds the memory of streaming estimation; keys first
+// seen after that are repeated only within their chunk.
+const streamJSONKeys = 1 << 16
+
+// jsonKeys carries the keys of a JSON document from one chunk of a stream
+// to the next, so that keys repeating those of earlier chunks count as
+// repeated, as they do in the whole document.
+type jsonKeys struct {
+	seen map[string]struct{} // keys of the earlier chunks
+	keep bool                // add the keys of the text to seen
+}
+
+// scanJSON measures the structure of a JSON document. It tolerates
+// malformed input: an unterminated string runs to the end of the text.
+// If carried is not nil, the text continues a document whose earlier keys
+// are in carried.
+func scanJSON(text string, carried *jsonKeys) jsonStats {
+	var js jsonStats
+	seen := jsonKeySets.Get().(map[string]struct{})
+	defer func() {
+		clear(seen)
+		jsonKeySets.Put(seen)
+	}()
+	inRun := false
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c != '"' {
+			if isJSONStructural(c) {
+				js.structure++
+				if !inRun {
+					js.runs++
+				}
+				inRun = true
+			} else {
+				inRun = false
+			}
+			continue
+		}
+
+		// Opening quote joins the preceding structural run.
+		js.structure++
+		if !inRun {
+			js.runs++
+		}
+
+		start := i + 1
+		end := start
+		for end < len(text) && text[end] != '"' {
+			if text[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(text) {
+			break
+		}
+
+		// Closing quote starts a new run, which cannot touch the opening one.
+		js.structure++
+		js.runs++
+		inRun = true
+		i = end
+
+		next := end + 1
+		for next < len(text) && (text[next] == ' ' || text[next] == '\t' || text[next] == '\n' || text[next] == '\r') {
+			next++
+		}
+		if next < len(text) && text[next] == ':' {
+			js.keys++
+			key := text[start:end]
+			if _, ok := seen[key]; ok {
+				js.repeatedKeys++
+				continue
+			}
+			seen[key] = struct{}{}
+			if carried == nil {
+				continue
+			}
+			if _, ok := carried.seen[key]; ok {
+				js.repeatedKeys++
+			} else if carried.keep && len(carried.seen) < streamJSONKeys {
+				if carried.seen == nil {
+					carried.seen = make(map[string]struct{})
+				}
+				// The text may share the memory of a reused buffer.
+				carried.seen[strings.Clone(key)] = struct{}{}
+			}
+		}
+	}
+	return js
+}
@@ -0,0 +1,77 @@
+package tokenestimate
+
+import "testing"
+
+func TestScanJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected jsonStats
+	}{
+		{
+			name:     "Compact object",
+			text:     `{"id":1,"name":"x"}`,
+			expected: jsonStats{structure: 11, runs: 5, keys: 2},
+		},
+		{
+			name:     "Spaced object",
+			text:     `{"a": 1, "b": [2, 3]}`,
+			expected: jsonStats{structure: 12, runs: 8, keys: 2},
+		},
+		{
+			name:     "Repeated keys",
+			text:     `[{"id":1},{"id":2},{"id":3}]`,
+			expected: jsonStats{structure: 19, runs: 7, keys: 3, repeatedKeys: 2},
+		},
+		{
+			name:     "Punctuation inside strings",
+			text:     `{"k": "a, b: {c}", "q": "say \"hi\""}`,
+			expected: jsonStats{structure: 13, runs: 8, keys: 2},
+		},
+		{
+			name:     "Unterminated string",
+			text:     `{"a": "bc`,
+			expected: jsonStats{structure: 5, runs: 3, keys: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scanJSON(tt.text, nil); got != tt.expected {
+				t.Errorf("scanJSON(%q) = %+v, want %+v", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEstimateJSONText(t *testing.T) {
+	payload := `{"query": "weather", "location": {"city": "Paris", "country": "FR"}, "days": [1, 2, 3]}`
+
+	t.Run("Uses the JSON model", func(t *testing.T) {
+		if got, want := NewEstimator().EstimateJSONText(payload), KimiK2JSONEstimator.Estimate(payload); got != want {
+			t.Errorf("Expected %d tokens like kimi-k2-json, got %d", want, got)
+		}
+	})
+
+	t.Run("Structure is priced", func(t *testing.T) {
+		text, json := KimiK2Estimator.Estimate(payload), KimiK2JSONEstimator.Estimate(payload)
+		if text == json {
+			t.Errorf("Expected JSON model to differ from prose model, both gave %d", text)
+		}
+	})
+
+	t.Run("Stats only with ContentJSON", func(t *testing.T) {
+		if stats := NewEstimator().Analyze(payload); stats.JSONKeys != 0 {
+			t.Errorf("Expected no JSON keys for prose model, got %d", stats.JSONKeys)
+		}
+		if stats := KimiK2JSONEstimator.Analyze(payload); stats.JSONKeys != 5 {
+			t.Errorf("Expected 5 JSON keys, got %d", stats.JSONKeys)
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		if ContentJSON.String() != "json" {
+			t.Errorf("Unexpected name %q", ContentJSON)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+// Package langchaingo adapts the token-budget chunker of tokenestimate to
+// langchaingo, so that documents can be split by estimated tokens instead
+// of characters, without a tokenizer. Its TextSplitter is a
+// textsplitter.TextSplitter:
+//
+//	splitter, err := langchaingo.NewTextSplitter(nil, tokenestimate.ChunkOptions{
+//		MaxTokens:     512,
+//		OverlapTokens: 64,
+//		BoundaryMode:  tokenestimate.BoundaryParagraph,
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	chunks, err := textsplitter.SplitDocuments(splitter, docs)
+//
+// The package is a separate module, so that the tokenestimate module
+// stays free of dependencies.
+package langchaingo
+
+import (
+	"github.com/infinigence/tokenestimate"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// TextSplitter splits texts into chunks of at most a number of estimated
+// tokens with SplitByTokens. It is safe for concurrent use.
+type TextSplitter struct {
+	e    *tokenestimate.Estimator
+	opts tokenestimate.ChunkOptions
+}
+
+var _ textsplitter.TextSplitter = (*TextSplitter)(nil)
+
+// NewTextSplitter returns a TextSplitter splitting with e, or with the
+// estimator of NewEstimator if e is nil, and opts. It returns an error if
+// opts describe no possible split, such as an overlap of MaxTokens or
+// more.
+func NewTextSplitter(e *tokenestimate.Estimator, opts tokenestimate.ChunkOptions) (*TextSplitter, error) {
+	if e == nil {
+		e = tokenestimate.NewEstimator()
+	}
+	// SplitByTokens checks the options before it looks at the text.
+	if _, err := e.SplitByTokens("", opts); err != nil {
+		return nil, err
+	}
+	return &TextSplitter{e: e, opts: opts}, nil
+}
+
+// SplitText splits text into chunks, as SplitByTokens does.
+func (s *TextSplitter) SplitText(text string) ([]string, error) {
+	return s.e.SplitByTokens(text, s.opts)
+}
@@ -0,0 +1,43 @@
+package langchaingo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+func TestTextSplitter(t *testing.T) {
+	opts := tokenestimate.ChunkOptions{MaxTokens: 40, BoundaryMode: tokenestimate.BoundaryParagraph}
+	splitter, err := NewTextSplitter(nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog.\n\n", 20)
+	e := tokenestimate.NewEstimator()
+	want, _ := e.SplitByTokens(text, opts)
+
+	docs, err := textsplitter.SplitDocuments(splitter, []schema.Document{{PageContent: text, Metadata: map[string]any{"source": "fox.txt"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != len(want) || len(docs) < 2 {
+		t.Fatalf("SplitDocuments returned %d chunks, want %d", len(docs), len(want))
+	}
+	for i, doc := range docs {
+		if doc.PageContent != want[i] || doc.Metadata["source"] != "fox.txt" {
+			t.Errorf("Chunk %d = %q with %v, want %q with the document's metadata", i, doc.PageContent, doc.Metadata, want[i])
+		}
+		if tokens := e.Estimate(doc.PageContent); tokens > opts.MaxTokens {
+			t.Errorf("Chunk %d is estimated at %d tokens, over %d", i, tokens, opts.MaxTokens)
+		}
+	}
+}
+
+func TestNewTextSplitterValidates(t *testing.T) {
+	if _, err := NewTextSplitter(nil, tokenestimate.ChunkOptions{MaxTokens: 10, OverlapTokens: 10}); err == nil {
+		t.Error("NewTextSplitter with an overlap of MaxTokens succeeded")
+	}
+}
@@ -0,0 +1,53 @@
+package tokenestimate
+
+import "slices"
+
+// ExceedsLimit reports whether the estimated number of tokens in text is
+// greater than limit. Long texts are analyzed in chunks of 64 KiB, and the
+// scan stops as soon as the estimate of the chunks analyzed so far passes
+// the limit, so gating a huge document does not require reading all of it.
+// The scan stops early only when no coefficient or term of the model is
+// negative, as the estimate of a prefix may otherwise exceed that of the
+// whole text. For texts longer than a chunk the result is consistent with
+// EstimateContext; oversized texts under WithMaxInputBytes are sampled.
+func (e *Estimator) ExceedsLimit(text string, limit int) bool {
+	exceeds, _ := e.scanLimit(text, limit)
+	return exceeds
+}
+
+// scanLimit implements ExceedsLimit, also returning the number of bytes of
+// text analyzed.
+func (e *Estimator) scanLimit(text string, limit int) (exceeds bool, scanned int) {
+	if len(text) <= streamChunkSize || e.oversized(len(text)) {
+		return e.Estimate(text) > limit, len(text)
+	}
+
+	early := e.nondecreasing()
+	s := streamer{e: e}
+	for scanned < len(text) {
+		n := min(len(text)-scanned, streamChunkSize)
+		s.writeString(text[scanned : scanned+n])
+		scanned += n
+		if early && e.EstimateFromStats(s.total) > limit {
+			return true, scanned
+		}
+	}
+	return e.EstimateFromStats(s.stats()) > limit, scanned
+}
+
+// nondecreasing reports whether the estimate of a text can only grow as
+// the text does, because no coefficient and no term of the model is
+// negative. The markdown and JSON discounts of some presets are.
+func (e *Estimator) nondecreasing() bool {
+	for _, coef := range e.Coefficients() {
+		if coef < 0 {
+			return false
+		}
+	}
+	for _, term := range e.Terms {
+		if term.Coef < 0 || slices.ContainsFunc(term.Slopes, func(s float64) bool { return s < 0 }) {
+			return false
+		}
+	}
+	return true
+}
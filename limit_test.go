@@ -0,0 +1,63 @@
+package tokenestimate
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExceedsLimit(t *testing.T) {
+	estimator := NewEstimator()
+
+	t.Run("Short text", func(t *testing.T) {
+		text := "The quick brown fox jumps over the lazy dog."
+		tokens := estimator.Estimate(text)
+		if estimator.ExceedsLimit(text, tokens) {
+			t.Errorf("Expected %d tokens not to exceed %d", tokens, tokens)
+		}
+		if !estimator.ExceedsLimit(text, tokens-1) {
+			t.Errorf("Expected %d tokens to exceed %d", tokens, tokens-1)
+		}
+	})
+
+	t.Run("Long text agrees with EstimateContext", func(t *testing.T) {
+		text := strings.Repeat("A long document that goes on and on, 第二章.\n", 8000)
+		tokens, _ := estimator.EstimateContext(context.Background(), text)
+		if estimator.ExceedsLimit(text, tokens) {
+			t.Errorf("Expected %d tokens not to exceed %d", tokens, tokens)
+		}
+		if !estimator.ExceedsLimit(text, tokens-1) {
+			t.Errorf("Expected %d tokens to exceed %d", tokens, tokens-1)
+		}
+	})
+
+	t.Run("Stops early", func(t *testing.T) {
+		text := strings.Repeat("word \uE000 ", 1<<19)
+		exceeds, scanned := estimator.scanLimit(text, 100)
+		if !exceeds {
+			t.Fatal("Expected 4 MiB of words to exceed 100 tokens")
+		}
+		// The streamer holds a chunk back until the next one arrives.
+		if scanned > 2*streamChunkSize {
+			t.Errorf("Expected the scan to stop after the first chunk, scanned %d bytes", scanned)
+		}
+	})
+
+	t.Run("Negative coefficients scan everything", func(t *testing.T) {
+		coefs := estimator.Coefficients()
+		coefs["Digits"] = -1
+		discounted, err := estimator.WithCoefficients(estimator.Intercept(), coefs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := strings.Repeat("word ", 1<<15) + strings.Repeat("1234 ", 1<<17)
+		tokens, _ := discounted.EstimateContext(context.Background(), text)
+		exceeds, scanned := discounted.scanLimit(text, tokens)
+		if exceeds {
+			t.Errorf("Expected %d tokens not to exceed %d", tokens, tokens)
+		}
+		if scanned != len(text) {
+			t.Errorf("Expected the scan to read all %d bytes, scanned %d", len(text), scanned)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package tokenestimate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// presetFile is the format of the preset files LoadPreset reads.
+type presetFile struct {
+	Name        string `json:"name"`
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	Deprecated  string `json:"deprecated"`
+
+	// Base is the preset providing the analysis settings and the
+	// coefficients not in Coefficients (default: the default preset)
+	Base string `json:"base"`
+
+	Intercept    *float64           `json:"intercept"` // default: Base's intercept
+	Coefficients map[string]float64 `json:"coefficients"`
+	ResidualP10  float64            `json:"residual_p10"`
+	ResidualP90  float64            `json:"residual_p90"`
+
+	TrainingDate string   `json:"training_date"` // as 2006-01-02
+	Corpus       string   `json:"corpus"`
+	MAPE         float64  `json:"mape"`
+	Scripts      []string `json:"scripts"` // default: Base's scripts
+}
+
+// LoadPreset reads a preset in JSON or YAML from r and registers it with
+// RegisterPreset, so that models can be added by configuration without
+// recompiling. The preset names a base preset, whose analysis settings it
+// keeps, and the intercept and the coefficients of the Stats features it
+// changes, as WithCoefficients takes them:
+//
+//	name: my-model
+//	version: 2                 # registered as my-model@2 and my-model
+//	description: My model's tokenizer
+//	base: kimi-k2@11           # default: the default preset
+//	intercept: 0.5             # default: the base's intercept
+//	coefficients:
+//	  LatinLetters: 0.21
+//	  ChineseChars: 0.71
+//	residual_p10: 0.87         # quantiles of EstimateRange (default: none)
+//	residual_p90: 1.15
+//	training_date: 2025-06-01  # Metadata, shown by ListPresetsDetailed
+//	corpus: Support tickets labeled by the provider's API
+//	mape: 0.07
+//	scripts: [Latin, Chinese]  # default: the base's scripts
+func LoadPreset(r io.Reader) (*Estimator, error) {
+	e, err := readPreset(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := RegisterPreset(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// LoadPresetDir loads the presets of every .json, .yaml and .yml file in
+// dir, in file name order, as LoadPreset does. It registers none of them if
+// any fails to load.
+func LoadPresetDir(dir string) ([]*Estimator, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			if !entry.IsDir() {
+				paths = append(paths, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	loaded := make([]*Estimator, 0, len(paths))
+	for _, path := range paths {
+		e, err := readPresetFile(path)
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, e)
+	}
+	registerPresets(loaded)
+	return loaded, nil
+}
+
+// registerPresets registers presets at once, so that concurrent lookups
+// see either none or all of them.
+func registerPresets(loaded []*Estimator) {
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	for _, e := range loaded {
+		if e.Name != "" {
+			registerPreset(e)
+		}
+	}
+}
+
+func readPresetFile(path string) (*Estimator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	e, err := readPreset(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return e, nil
+}
+
+// readPreset decodes a preset file, as JSON if it starts with "{" and as
+// YAML otherwise.
+func readPreset(r io.Reader) (*Estimator, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(data); !bytes.HasPrefix(trimmed, []byte("{")) {
+		v, err := parseYAML(string(data))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := v.(map[string]any); !ok {
+			return nil, errors.New("preset is not a mapping")
+		}
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	var f presetFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after preset")
+	}
+	return f.estimator()
+}
+
+// estimator returns the Estimator the preset file describes.
+func (f *presetFile) estimator() (*Estimator, error) {
+	if f.Name == "" {
+		return nil, errors.New("preset has no name")
+	}
+	if strings.Contains(f.Name, "@") {
+		return nil, fmt.Errorf("invalid preset name: %s", f.Name)
+	}
+
+	if f.MAPE < 0 {
+		return nil, fmt.Errorf("invalid mape: %v", f.MAPE)
+	}
+
+	base := NewEstimator()
+	if f.Base != "" {
+		var err error
+		if base, err = GetPresetByName(f.Base); err != nil {
+			return nil, err
+		}
+	}
+	intercept := base.intercept
+	if f.Intercept != nil {
+		intercept = *f.Intercept
+	}
+	e, err := base.WithCoefficients(intercept, f.Coefficients)
+	if err != nil {
+		return nil, err
+	}
+	e.Name, e.Version, e.Description, e.Deprecated = f.Name, f.Version, f.Description, f.Deprecated
+	e.residualP10, e.residualP90 = f.ResidualP10, f.ResidualP90
+	if f.TrainingDate != "" {
+		if e.Metadata.TrainingDate, err = time.Parse(time.DateOnly, f.TrainingDate); err != nil {
+			return nil, fmt.Errorf("invalid training_date: %s", f.TrainingDate)
+		}
+	}
+	e.Metadata.Corpus, e.Metadata.MAPE = f.Corpus, f.MAPE
+	if f.Scripts != nil {
+		e.Metadata.Scripts = f.Scripts
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,138 @@
+package tokenestimate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// unregister removes the named registry keys when the test ends.
+func unregister(t *testing.T, names ...string) {
+	t.Cleanup(func() {
+		presetsMu.Lock()
+		defer presetsMu.Unlock()
+		for _, name := range names {
+			delete(presets, name)
+		}
+	})
+}
+
+func TestLoadPreset(t *testing.T) {
+	const text = "Hello, world! 你好，世界"
+
+	t.Run("YAML", func(t *testing.T) {
+		unregister(t, "yaml-model", "yaml-model@2")
+		e, err := LoadPreset(strings.NewReader(`
+name: yaml-model
+version: 2
+description: A YAML preset
+base: kimi-k2@11
+intercept: 1.5
+coefficients:
+  ChineseChars: 1.2
+residual_p10: 0.9
+residual_p90: 1.1
+training_date: 2025-06-01
+corpus: Support tickets
+mape: 0.07
+scripts: [Latin, Chinese]
+`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want, _ := kimiK2V11.WithCoefficients(1.5, map[string]float64{"ChineseChars": 1.2})
+		if got := e.Estimate(text); got != want.Estimate(text) {
+			t.Errorf("Expected %d tokens, got %d", want.Estimate(text), got)
+		}
+		if e.Description != "A YAML preset" || e.residualP10 != 0.9 || e.residualP90 != 1.1 {
+			t.Errorf("Unexpected preset %+v", e)
+		}
+		if m := e.Metadata; m.TrainingDate.Format(time.DateOnly) != "2025-06-01" || m.Corpus != "Support tickets" || m.MAPE != 0.07 || len(m.Scripts) != 2 {
+			t.Errorf("Unexpected metadata %+v", m)
+		}
+		for _, name := range []string{"yaml-model", "yaml-model@2"} {
+			if registered, err := GetPresetByName(name); err != nil || registered != e {
+				t.Errorf("Expected %s to be registered, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("JSON keeps the base's intercept", func(t *testing.T) {
+		unregister(t, "json-model")
+		e, err := LoadPreset(strings.NewReader(`{"name": "json-model", "coefficients": {"Words": 0.5}}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want, _ := NewEstimator().WithCoefficients(NewEstimator().intercept, map[string]float64{"Words": 0.5})
+		if got := e.Estimate(text); got != want.Estimate(text) {
+			t.Errorf("Expected %d tokens, got %d", want.Estimate(text), got)
+		}
+		if e.Version != 0 || e.Accuracy != nil || !e.Metadata.Supports("Ethiopic") {
+			t.Errorf("Unexpected preset %+v", e)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := map[string]string{
+			`{"name": "x", "typo": 1}`:                            `unknown field "typo"`,
+			`{"coefficients": {}}`:                                "preset has no name",
+			`{"name": "x@1"}`:                                     "invalid preset name: x@1",
+			`{"name": "x", "version": -1}`:                        "invalid preset version: -1",
+			`{"name": "x", "base": "nonexistent"}`:                "unknown preset: nonexistent",
+			`{"name": "x", "coefficients": {"Foo": 1}}`:           "unknown feature: Foo",
+			`{"name": "x", "residual_p10": 2, "residual_p90": 1}`: "invalid residual quantiles",
+			`{"name": "x", "training_date": "June"}`:              "invalid training_date: June",
+			`{"name": "x", "mape": -1}`:                           "invalid mape: -1",
+			"- a\n- b":                                            "preset is not a mapping",
+			"name: x\nname":                                       "line 2: expected a key",
+		}
+		for data, want := range tests {
+			if _, err := LoadPreset(strings.NewReader(data)); err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("LoadPreset(%q): expected an error containing %q, got %v", data, want, err)
+			}
+		}
+	})
+}
+
+func TestLoadPresetDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.yaml":    "name: dir-a\n",
+		"b.json":    `{"name": "dir-b"}`,
+		"c.yml":     "name: dir-c\n",
+		"README.md": "not a preset",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unregister(t, "dir-a", "dir-b", "dir-c")
+
+	loaded, err := LoadPresetDir(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var names []string
+	for _, e := range loaded {
+		names = append(names, e.Name)
+	}
+	if got := strings.Join(names, ","); got != "dir-a,dir-b,dir-c" {
+		t.Errorf("Expected dir-a,dir-b,dir-c, got %s", got)
+	}
+
+	t.Run("A bad file registers nothing", func(t *testing.T) {
+		bad := t.TempDir()
+		os.WriteFile(filepath.Join(bad, "a.yaml"), []byte("name: dir-ok\n"), 0o644)
+		os.WriteFile(filepath.Join(bad, "b.yaml"), []byte("name: dir-bad\nfoo: 1\n"), 0o644)
+		_, err := LoadPresetDir(bad)
+		if err == nil || !strings.HasPrefix(err.Error(), filepath.Join(bad, "b.yaml")+": ") {
+			t.Errorf("Expected an error naming b.yaml, got %v", err)
+		}
+		if _, err := lookupPreset("dir-ok"); err == nil {
+			t.Error("Expected dir-ok not to be registered")
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package tokenestimate
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// logger holds the logger set by SetLogger. A nil pointer means slog.Default().
+var logger atomic.Pointer[slog.Logger]
+
+// discardLogger drops every record; it is installed by SetLogger(nil).
+var discardLogger = slog.New(discardHandler{})
+
+// SetLogger sets the logger used for warnings such as the use of deprecated
+// presets. By default warnings go to slog.Default(). A nil logger disables
+// logging.
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		l = discardLogger
+	}
+	logger.Store(l)
+}
+
+// getLogger returns the logger set by SetLogger, or slog.Default().
+func getLogger() *slog.Logger {
+	if l := logger.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// discardHandler is a slog.Handler that ignores all records.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,24 @@
+package tokenestimate
+
+// WithSafetyMargin returns a clone of the estimator that inflates every
+// estimate by fraction of itself, and by at least minTokens tokens, for
+// conservative budgeting: WithSafetyMargin(0.1, 5) adds 10% and never less
+// than 5 tokens. The margin is added to the model output before rounding
+// and carries through every method built on estimates, such as Truncate,
+// FitsWithin, SplitByTokens and ExceedsLimit, so that budgets are enforced
+// consistently. Empty text still estimates to zero tokens. Negative
+// arguments count as zero.
+func (e *Estimator) WithSafetyMargin(fraction float64, minTokens int) *Estimator {
+	clone := e.Clone()
+	clone.SafetyMargin = max(fraction, 0)
+	clone.SafetyMinTokens = max(minTokens, 0)
+	return clone
+}
+
+// withMargin adds the safety margin to a model output.
+func (e *Estimator) withMargin(count float64) float64 {
+	if count <= 0 {
+		return count
+	}
+	return count + max(e.SafetyMargin*count, float64(e.SafetyMinTokens))
+}
@@ -0,0 +1,73 @@
+package tokenestimate
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWithSafetyMargin(t *testing.T) {
+	e := NewEstimator()
+	safe := e.WithSafetyMargin(0.1, 5)
+	long := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"Hi", e.Estimate("Hi") + 5},
+		{long, int(math.Round(1.1 * e.EstimateFloat(long)))},
+	}
+	for _, tt := range tests {
+		if got := safe.Estimate(tt.text); got != tt.want {
+			t.Errorf("Estimate(%.20q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+	if got, want := safe.EstimateFloat(long), 1.1*e.EstimateFloat(long); math.Abs(got-want) > 1e-9 {
+		t.Errorf("EstimateFloat = %v, want %v", got, want)
+	}
+	if got := safe.EstimateWithOptions(long, EstimateOpts{}); got != safe.Estimate(long) {
+		t.Errorf("EstimateWithOptions = %d, want %d", got, safe.Estimate(long))
+	}
+	if got := e.WithSafetyMargin(-1, -1).Estimate(long); got != e.Estimate(long) {
+		t.Errorf("Expected negative margins to be ignored, got %d instead of %d", got, e.Estimate(long))
+	}
+	if got := safe.WithContentType(ContentCode).SafetyMargin; got != 0.1 {
+		t.Errorf("Expected WithContentType to keep the margin, got %v", got)
+	}
+
+	t.Run("Helpers", func(t *testing.T) {
+		const limit = 100
+		truncated := safe.Truncate(long, limit)
+		if got := safe.Estimate(truncated); got > limit {
+			t.Errorf("Truncate left %d tokens, over %d", got, limit)
+		}
+		if len(truncated) >= len(e.Truncate(long, limit)) {
+			t.Error("Expected the margin to truncate more")
+		}
+
+		fits, total := safe.FitsWithin(1000, "Hi", long)
+		if want := safe.Estimate("Hi") + safe.Estimate(long); !fits || total != want {
+			t.Errorf("FitsWithin = %v, %d, want true, %d", fits, total, want)
+		}
+
+		chunks, err := safe.SplitByTokens(long, ChunkOptions{MaxTokens: 50, BoundaryMode: BoundaryWord})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		plain, _ := e.SplitByTokens(long, ChunkOptions{MaxTokens: 50, BoundaryMode: BoundaryWord})
+		for _, chunk := range chunks {
+			if got := safe.Estimate(chunk); got > 50 {
+				t.Errorf("Chunk of %d tokens exceeds 50: %q", got, chunk)
+			}
+		}
+		if len(chunks[0]) >= len(plain[0]) {
+			t.Errorf("Expected the margin to shorten the chunks of %d bytes, got %d", len(plain[0]), len(chunks[0]))
+		}
+
+		if !safe.ExceedsLimit(long, e.Estimate(long)) {
+			t.Error("Expected ExceedsLimit to count the margin")
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package tokenestimate
+
+import "unicode/utf8"
+
+// markdownEvent is a markdown construct recognized by markdownLine.
+type markdownEvent int8
+
+const (
+	mdNone markdownEvent = iota
+	mdHeading
+	mdFence
+	mdListItem
+	mdTableRow
+	mdLink
+)
+
+// Phases of markdownLine while reading the start of a line.
+const (
+	mdLineStart  int8 = iota // at the start of a line or in its indentation
+	mdHashes                 // after one or more leading '#'
+	mdFenceRun               // after leading '`' or '~'
+	mdBullet                 // after a leading '-', '*' or '+'
+	mdOrdered                // after leading digits
+	mdOrderedDot             // after leading digits and '.' or ')'
+	mdBody                   // past the line prefix
+)
+
+// markdownLine recognizes markdown structure one rune at a time: headings,
+// code fences, list items and table rows from the start of each line, and
+// links anywhere. It needs no lookahead, so it works in streaming analysis.
+type markdownLine struct {
+	phase int8
+	count int8 // number of '#' or fence characters seen
+	fence rune // the fence character
+	prev  rune
+}
+
+// step advances the state with r and returns the construct completed by r.
+func (m *markdownLine) step(r rune) markdownEvent {
+	event := mdNone
+	if m.prev == ']' && r == '(' {
+		event = mdLink
+	}
+	m.prev = r
+
+	if r == '\n' {
+		m.phase = mdLineStart
+		return event
+	}
+
+	switch m.phase {
+	case mdLineStart:
+		switch {
+		case r == ' ' || r == '\t':
+			// indentation
+		case r == '#':
+			m.phase, m.count = mdHashes, 1
+		case r == '`' || r == '~':
+			m.phase, m.count, m.fence = mdFenceRun, 1, r
+		case r == '-' || r == '*' || r == '+':
+			m.phase = mdBullet
+		case r >= '0' && r <= '9':
+			m.phase = mdOrdered
+		case r == '|':
+			m.phase = mdBody
+			return mdTableRow
+		default:
+			m.phase = mdBody
+		}
+	case mdHashes:
+		switch {
+		case r == '#' && m.count < 6:
+			m.count++
+		case r == ' ':
+			m.phase = mdBody
+			return mdHeading
+		default:
+			m.phase = mdBody
+		}
+	case mdFenceRun:
+		if r != m.fence {
+			m.phase = mdBody
+		} else if m.count++; m.count == 3 {
+			m.phase = mdBody
+			return mdFence
+		}
+	case mdBullet:
+		m.phase = mdBody
+		if r == ' ' {
+			return mdListItem
+		}
+	case mdOrdered:
+		switch {
+		case r >= '0' && r <= '9':
+		case r == '.' || r == ')':
+			m.phase = mdOrderedDot
+		default:
+			m.phase = mdBody
+		}
+	case mdOrderedDot:
+		m.phase = mdBody
+		if r == ' ' {
+			return mdListItem
+		}
+	}
+	return event
+}
+
+// markdownScanLimit bounds how far sampling mode looks back for the start
+// of the line containing a sampled rune.
+const markdownScanLimit = 16
+
+// seekMarkdown returns the state markdownLine would have after reading
+// text[:i]. Only the current line's prefix matters, so if the line started
+// more than markdownScanLimit characters ago the state is mdBody.
+func seekMarkdown(text string, i int) markdownLine {
+	start := i
+	for n := 0; start > 0 && n < markdownScanLimit && text[start-1] != '\n'; n++ {
+		_, size := utf8.DecodeLastRuneInString(text[:start])
+		start -= size
+	}
+
+	var m markdownLine
+	if start > 0 && text[start-1] != '\n' {
+		m.phase = mdBody
+		if i > 0 {
+			m.prev, _ = utf8.DecodeLastRuneInString(text[:i])
+		}
+		return m
+	}
+	for _, r := range text[start:i] {
+		m.step(r)
+	}
+	return m
+}
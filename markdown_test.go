@@ -0,0 +1,94 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownStructure(t *testing.T) {
+	estimator := NewEstimator()
+
+	tests := []struct {
+		name     string
+		text     string
+		headings int
+		fences   int
+		items    int
+		rows     int
+		links    int
+	}{
+		{name: "Headings", text: "# One\n## Two\n####### Seven\n#hashtag", headings: 2},
+		{name: "Fences", text: "```go\nx := 1\n```\n~~~\ny\n~~~", fences: 4},
+		{name: "Inline code is not a fence", text: "use `x` or ``y``"},
+		{name: "List items", text: "- a\n* b\n  + c\n1. d\n10) e\n-not\n---", items: 5},
+		{name: "Table rows", text: "| a | b |\n|---|---|\n| 1 | 2 |\na | b", rows: 3},
+		{name: "Links", text: "See [docs](https://example.com) and ![img](a.png), not [x] (y).", links: 2},
+		{name: "Plain prose", text: "Price: 3.5 - 2 # not a heading"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := estimator.Analyze(tt.text)
+			if stats.MarkdownHeadings != tt.headings || stats.MarkdownFences != tt.fences ||
+				stats.MarkdownListItems != tt.items || stats.MarkdownTableRows != tt.rows ||
+				stats.MarkdownLinks != tt.links {
+				t.Errorf("Analyze(%q) = headings %d, fences %d, items %d, rows %d, links %d; want %d, %d, %d, %d, %d",
+					tt.text, stats.MarkdownHeadings, stats.MarkdownFences, stats.MarkdownListItems,
+					stats.MarkdownTableRows, stats.MarkdownLinks,
+					tt.headings, tt.fences, tt.items, tt.rows, tt.links)
+			}
+		})
+	}
+}
+
+func TestMarkdownContentType(t *testing.T) {
+	doc := "## Install\n\n```sh\ngo get example.com/pkg\n```\n\nSee [the docs](https://example.com/docs).\n"
+
+	t.Run("Markdown variant matches markdown preset", func(t *testing.T) {
+		estimator := NewEstimator().WithContentType(ContentMarkdown)
+		if estimator.Name != "kimi-k2-markdown" {
+			t.Errorf("Expected name kimi-k2-markdown, got %q", estimator.Name)
+		}
+		if got, want := estimator.Estimate(doc), KimiK2MarkdownEstimator.Estimate(doc); got != want {
+			t.Errorf("Expected %d tokens like kimi-k2-markdown, got %d", want, got)
+		}
+	})
+
+	t.Run("Markup lowers the estimate", func(t *testing.T) {
+		text, markdown := KimiK2Estimator.Estimate(doc), KimiK2MarkdownEstimator.Estimate(doc)
+		if markdown >= text {
+			t.Errorf("Expected markdown estimate below %d, got %d", text, markdown)
+		}
+	})
+
+	t.Run("Prose is unchanged", func(t *testing.T) {
+		prose := "The quick brown fox jumps over the lazy dog."
+		if got, want := KimiK2MarkdownEstimator.Estimate(prose), KimiK2Estimator.Estimate(prose); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		if ContentMarkdown.String() != "markdown" {
+			t.Errorf("Unexpected name %q", ContentMarkdown)
+		}
+	})
+}
+
+func TestMarkdownSampling(t *testing.T) {
+	text := strings.Repeat("- item [link](u)\n", 500)
+	full := NewEstimator().Analyze(text)
+	sampled := NewEstimator().WithSampling(100, 70).Analyze(text)
+
+	for _, c := range []struct {
+		name      string
+		full, got int
+	}{
+		{"MarkdownListItems", full.MarkdownListItems, sampled.MarkdownListItems},
+		{"MarkdownLinks", full.MarkdownLinks, sampled.MarkdownLinks},
+	} {
+		if diff := float64(c.got-c.full) / float64(c.full); diff > 0.1 || diff < -0.1 {
+			t.Errorf("%s: sampled %d, full %d", c.name, c.got, c.full)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package tokenestimate
+
+// Message is one message of a chat conversation.
+type Message struct {
+	Role    string // Author of the message, such as "system", "user" or "assistant"
+	Content string // Text of the message
+}
+
+// RoleSystem is the role of system prompts, which TrimMessages preserves.
+const RoleSystem = "system"
+
+// ChatTemplate describes the tokens a chat template adds around messages
+// when a conversation is rendered into the model's prompt.
+type ChatTemplate struct {
+	Name          string         // Template name, such as "kimi-k2"
+	MessageTokens int            // Tokens framing every message, such as start and end markers
+	RoleTokens    map[string]int // Tokens of each role header; other roles are estimated from their name
+	ReplyTokens   int            // Tokens priming the assistant's reply after the last message
+}
+
+// roleTokens returns the tokens of the header for role.
+func (t *ChatTemplate) roleTokens(e *Estimator, role string) int {
+	if n, ok := t.RoleTokens[role]; ok {
+		return n
+	}
+	return e.estimateQuietly(role)
+}
+
+// EstimateMessages estimates a chat request made of msgs, including the
+// tokens the estimator's ChatTemplate adds for every message and role and
+// to prime the reply. Estimating the concatenated contents instead misses
+// several tokens per message. Without a ChatTemplate only the contents are
+// counted.
+func (e *Estimator) EstimateMessages(msgs []Message) int {
+	total := e.replyTokens()
+	for _, m := range msgs {
+		total += e.estimateMessage(m)
+	}
+	return total
+}
+
+// estimateMessage returns the estimated tokens of m within a conversation.
+func (e *Estimator) estimateMessage(m Message) int {
+	return e.Estimate(m.Content) + e.messageOverhead(m.Role)
+}
+
+// messageOverhead returns the tokens the chat template adds around the
+// content of a message with the given role.
+func (e *Estimator) messageOverhead(role string) int {
+	if e.ChatTemplate == nil {
+		return 0
+	}
+	return e.ChatTemplate.MessageTokens + e.ChatTemplate.roleTokens(e, role)
+}
+
+// replyTokens returns the tokens priming the reply to a conversation.
+func (e *Estimator) replyTokens() int {
+	if e.ChatTemplate == nil {
+		return 0
+	}
+	return e.ChatTemplate.ReplyTokens
+}
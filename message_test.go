@@ -0,0 +1,50 @@
+package tokenestimate
+
+import "testing"
+
+func TestEstimateMessages(t *testing.T) {
+	estimator := NewEstimator()
+	msgs := []Message{
+		{Role: RoleSystem, Content: "You are a helpful assistant."},
+		{Role: "user", Content: "What is the capital of France?"},
+	}
+	content := estimator.Estimate(msgs[0].Content) + estimator.Estimate(msgs[1].Content)
+
+	t.Run("Default template", func(t *testing.T) {
+		// Two messages of 3 framing tokens and 1 role token, plus 3 tokens
+		// priming the reply.
+		if got, want := estimator.EstimateMessages(msgs), content+2*4+3; got != want {
+			t.Errorf("EstimateMessages = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Unlisted role", func(t *testing.T) {
+		msg := []Message{{Role: "function_result", Content: "42"}}
+		want := estimator.Estimate("42") + 3 + estimator.Estimate("function_result") + 3
+		if got := estimator.EstimateMessages(msg); got != want {
+			t.Errorf("EstimateMessages = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Custom template", func(t *testing.T) {
+		custom := estimator.Clone()
+		custom.ChatTemplate = &ChatTemplate{Name: "custom", MessageTokens: 5, RoleTokens: map[string]int{"system": 2, "user": 2}, ReplyTokens: 1}
+		if got, want := custom.EstimateMessages(msgs), content+2*7+1; got != want {
+			t.Errorf("EstimateMessages = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("No template", func(t *testing.T) {
+		bare := estimator.Clone()
+		bare.ChatTemplate = nil
+		if got := bare.EstimateMessages(msgs); got != content {
+			t.Errorf("EstimateMessages = %d, want %d", got, content)
+		}
+	})
+
+	t.Run("Kept across content types", func(t *testing.T) {
+		if estimator.WithContentType(ContentCode).ChatTemplate != estimator.ChatTemplate {
+			t.Error("Expected WithContentType to keep the chat template")
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package tokenestimate
+
+import (
+	"sort"
+	"time"
+)
+
+// Metadata describes how a preset was trained, for tools and user
+// interfaces choosing between presets. Fields are zero when unknown.
+type Metadata struct {
+	TrainingDate time.Time // Day the coefficients were trained
+	Corpus       string    // Description of the training texts
+	MAPE         float64   // Measured mean absolute relative error, such as 0.085 for 8.5%
+
+	// Scripts lists the scripts the model prices with coefficients of
+	// their own, named like LanguageProfile.Dominant, such as "Latin" or
+	// "Japanese". Other scripts are estimated from their bytes or as
+	// symbols, with larger errors.
+	Scripts []string
+}
+
+func (m Metadata) clone() Metadata {
+	m.Scripts = append([]string(nil), m.Scripts...)
+	return m
+}
+
+// Supports reports whether the model prices script with coefficients of
+// its own.
+func (m Metadata) Supports(script string) bool {
+	for _, s := range m.Scripts {
+		if s == script {
+			return true
+		}
+	}
+	return false
+}
+
+// PresetInfo describes a registered preset version.
+type PresetInfo struct {
+	Name        string // Name to pass to NewEstimatorWithName, such as "kimi-k2@11"
+	Preset      string // Name of the preset without the version, such as "kimi-k2"
+	Version     int    // Version of the preset; 0 means unversioned
+	Latest      bool   // Whether the bare preset name resolves to this version
+	Description string
+	Deprecated  string      // If non-empty, why the version is deprecated
+	ContentType ContentType // Kind of text the coefficients are tuned for
+	Aliases     []string    // Aliases resolving to this version, sorted; see RegisterAlias
+	Metadata
+}
+
+// ListPresetsDetailed describes every registered preset, one entry per
+// version, sorted by preset name and then version. Unlike ListPresets it
+// does not list the bare name of a versioned preset separately; its latest
+// version has Latest set.
+func ListPresetsDetailed() []PresetInfo {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	aliasesOf := map[*Estimator][]string{}
+	for alias, target := range aliases {
+		e := presets[target]
+		aliasesOf[e] = append(aliasesOf[e], alias)
+	}
+	infos := make([]PresetInfo, 0, len(presets))
+	for key, e := range presets {
+		if e.Version != 0 && key == e.Name {
+			continue
+		}
+		infos = append(infos, PresetInfo{
+			Name:        key,
+			Preset:      e.Name,
+			Version:     e.Version,
+			Latest:      presets[e.Name] == e,
+			Description: e.Description,
+			Deprecated:  e.Deprecated,
+			ContentType: e.ContentType,
+			Aliases:     aliasesOf[e],
+			Metadata:    e.Metadata.clone(),
+		})
+	}
+	for _, info := range infos {
+		sort.Strings(info.Aliases)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Preset != infos[j].Preset {
+			return infos[i].Preset < infos[j].Preset
+		}
+		return infos[i].Version < infos[j].Version
+	})
+	return infos
+}
@@ -0,0 +1,63 @@
+package tokenestimate
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestListPresetsDetailed(t *testing.T) {
+	infos := ListPresetsDetailed()
+	byName := map[string]PresetInfo{}
+	for _, info := range infos {
+		byName[info.Name] = info
+	}
+	if !sort.SliceIsSorted(infos, func(i, j int) bool {
+		return infos[i].Preset < infos[j].Preset || infos[i].Preset == infos[j].Preset && infos[i].Version < infos[j].Version
+	}) {
+		t.Error("Expected presets sorted by name and version")
+	}
+	if _, ok := byName["kimi-k2"]; ok {
+		t.Error("Expected the bare name of kimi-k2 not to be listed")
+	}
+
+	latest := byName["kimi-k2@12"]
+	if !latest.Latest || latest.Preset != "kimi-k2" || latest.Version != 12 || latest.MAPE != 0.085 || !latest.Supports("Ethiopic") {
+		t.Errorf("Unexpected kimi-k2@12: %+v", latest)
+	}
+	original := byName["kimi-k2@1"]
+	if original.Latest || original.Deprecated == "" || original.Supports("Khmer") || !original.Supports("Japanese") {
+		t.Errorf("Unexpected kimi-k2@1: %+v", original)
+	}
+	if code := byName["kimi-k2-code@4"]; code.ContentType != ContentCode || code.Corpus == "" || code.MAPE != 0 {
+		t.Errorf("Unexpected kimi-k2-code@4: %+v", code)
+	}
+	if p90 := byName["kimi-k2-p90@2"]; p90.MAPE != 0 {
+		t.Errorf("Expected no measured error for kimi-k2-p90, got %v", p90.MAPE)
+	}
+	for _, p := range generatedPresets {
+		info := byName[p.name]
+		if info.TrainingDate.IsZero() || info.MAPE != p.accuracy.MAPE || info.Corpus == "" || len(info.Scripts) == 0 {
+			t.Errorf("Unexpected %s: %+v", p.name, info)
+		}
+	}
+
+	info := byName["kimi-k2@12"]
+	info.Scripts[0] = "changed"
+	if kimiK2V12.Metadata.Scripts[0] == "changed" {
+		t.Error("Expected ListPresetsDetailed to copy the scripts")
+	}
+}
+
+func TestMetadataAfterCoefficientChanges(t *testing.T) {
+	e, err := NewEstimator().WithCoefficients(0, map[string]float64{"Words": 0.3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e.Metadata.MAPE != 0 || e.Metadata.Corpus != "" || !e.Metadata.Supports("Latin") {
+		t.Errorf("Expected only the scripts to be kept, got %+v", e.Metadata)
+	}
+	e.Metadata.Scripts[0] = "changed"
+	if NewEstimator().Metadata.Scripts[0] == "changed" {
+		t.Error("Expected WithCoefficients to copy the scripts")
+	}
+}
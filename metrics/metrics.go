@@ -0,0 +1,184 @@
+// Package metrics records the estimates of a tokenestimate.Estimator to
+// Prometheus: their latency, the sizes of their inputs and the estimated
+// tokens, in histograms labeled by preset, so that token throughput can be
+// monitored per service:
+//
+//	m, err := metrics.New(prometheus.DefaultRegisterer)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	e := m.Wrap(tokenestimate.NewEstimator())
+//	tokens := e.Estimate(prompt)
+//
+// The histograms are tokenestimate_estimate_duration_seconds,
+// tokenestimate_input_bytes and tokenestimate_estimated_tokens, with a
+// preset label such as "kimi-k2@12".
+//
+// The package is a separate module, so that the tokenestimate module
+// stays free of dependencies.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics is the histograms of estimates.
+type Metrics struct {
+	duration   *prometheus.HistogramVec
+	inputBytes *prometheus.HistogramVec
+	tokens     *prometheus.HistogramVec
+}
+
+// New returns Metrics registered with reg, or with
+// prometheus.DefaultRegisterer if reg is nil. Histograms already
+// registered, by an earlier call, are shared.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	m := &Metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tokenestimate_estimate_duration_seconds",
+			Help:    "Time to estimate a text, in seconds.",
+			Buckets: prometheus.ExponentialBuckets(1e-6, 4, 10), // 1µs to 262ms
+		}, []string{"preset"}),
+		inputBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tokenestimate_input_bytes",
+			Help:    "Size of the estimated texts, in bytes.",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 10), // 64 B to 16 MiB
+		}, []string{"preset"}),
+		tokens: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tokenestimate_estimated_tokens",
+			Help:    "Estimated tokens of the estimated texts.",
+			Buckets: prometheus.ExponentialBuckets(16, 4, 10), // 16 to 4M tokens
+		}, []string{"preset"}),
+	}
+	for _, h := range []**prometheus.HistogramVec{&m.duration, &m.inputBytes, &m.tokens} {
+		if err := reg.Register(*h); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if !errors.As(err, &are) {
+				return nil, err
+			}
+			existing, ok := are.ExistingCollector.(*prometheus.HistogramVec)
+			if !ok {
+				return nil, err
+			}
+			*h = existing
+		}
+	}
+	return m, nil
+}
+
+// Wrap returns an Estimator recording the estimates of e.
+func (m *Metrics) Wrap(e *tokenestimate.Estimator) *Estimator {
+	preset := e.Key()
+	return &Estimator{
+		e:          e,
+		duration:   m.duration.WithLabelValues(preset),
+		inputBytes: m.inputBytes.WithLabelValues(preset),
+		tokens:     m.tokens.WithLabelValues(preset),
+	}
+}
+
+// Estimator estimates like the estimator it wraps, recording every
+// estimate. It is a tokenestimate.TokenCounter, and safe for concurrent
+// use.
+type Estimator struct {
+	e          *tokenestimate.Estimator
+	duration   prometheus.Observer
+	inputBytes prometheus.Observer
+	tokens     prometheus.Observer
+}
+
+// Estimator returns the wrapped estimator, whose estimates are not
+// recorded.
+func (m *Estimator) Estimator() *tokenestimate.Estimator {
+	return m.e
+}
+
+// observe records an estimate of size bytes that started at start.
+func (m *Estimator) observe(start time.Time, size, tokens int) {
+	m.duration.Observe(time.Since(start).Seconds())
+	m.inputBytes.Observe(float64(size))
+	m.tokens.Observe(float64(tokens))
+}
+
+// Estimate estimates the number of tokens in text.
+func (m *Estimator) Estimate(text string) int {
+	start := time.Now()
+	tokens := m.e.Estimate(text)
+	m.observe(start, len(text), tokens)
+	return tokens
+}
+
+// EstimateBytes is like Estimate but takes a byte slice.
+func (m *Estimator) EstimateBytes(b []byte) int {
+	start := time.Now()
+	tokens := m.e.EstimateBytes(b)
+	m.observe(start, len(b), tokens)
+	return tokens
+}
+
+// Count is like Estimate but returns an *InputTooLargeError for input the
+// wrapped estimator rejects, which is not recorded.
+func (m *Estimator) Count(text string) (int, error) {
+	start := time.Now()
+	tokens, err := m.e.Count(text)
+	if err != nil {
+		return 0, err
+	}
+	m.observe(start, len(text), tokens)
+	return tokens, nil
+}
+
+// EstimateContext is like Estimate but stops when ctx is done, returning
+// ctx.Err(); estimates that fail are not recorded.
+func (m *Estimator) EstimateContext(ctx context.Context, text string) (int, error) {
+	start := time.Now()
+	tokens, err := m.e.EstimateContext(ctx, text)
+	if err != nil {
+		return tokens, err
+	}
+	m.observe(start, len(text), tokens)
+	return tokens, nil
+}
+
+// EstimateBatch estimates each text, recording each estimate with its share
+// of the batch's time in proportion to its size.
+func (m *Estimator) EstimateBatch(texts []string) []int {
+	start := time.Now()
+	tokens := m.e.EstimateBatch(texts)
+	elapsed := time.Since(start).Seconds()
+	size := 0
+	for _, text := range texts {
+		size += len(text)
+	}
+	for i, text := range texts {
+		share := 1 / float64(len(texts))
+		if size > 0 {
+			share = float64(len(text)) / float64(size)
+		}
+		m.duration.Observe(elapsed * share)
+		m.inputBytes.Observe(float64(len(text)))
+		m.tokens.Observe(float64(tokens[i]))
+	}
+	return tokens
+}
+
+// EstimateMessages estimates a chat request made of msgs, recording it as
+// one estimate of the size of its contents.
+func (m *Estimator) EstimateMessages(msgs []tokenestimate.Message) int {
+	start := time.Now()
+	tokens := m.e.EstimateMessages(msgs)
+	size := 0
+	for _, msg := range msgs {
+		size += len(msg.Content)
+	}
+	m.observe(start, size, tokens)
+	return tokens
+}
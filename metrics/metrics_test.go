@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// histograms returns the gathered histograms of reg by metric name and
+// preset label.
+func histograms(t *testing.T, reg *prometheus.Registry) map[string]map[string]*dto.Histogram {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(map[string]map[string]*dto.Histogram)
+	for _, f := range families {
+		out[f.GetName()] = make(map[string]*dto.Histogram)
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "preset" {
+					out[f.GetName()][l.GetValue()] = m.GetHistogram()
+				}
+			}
+		}
+	}
+	return out
+}
+
+func TestWrap(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := New(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kimi := m.Wrap(tokenestimate.NewEstimator())
+	cl100k, _ := tokenestimate.NewEstimatorWithName("cl100k-base")
+	cl := m.Wrap(cl100k)
+
+	text := "Hello, world! 你好世界"
+	if got, want := kimi.Estimate(text), tokenestimate.NewEstimator().Estimate(text); got != want {
+		t.Errorf("Estimate = %d, want %d", got, want)
+	}
+	kimi.EstimateBytes([]byte(text))
+	if _, err := kimi.Count(text); err != nil {
+		t.Fatal(err)
+	}
+	tokens := cl.EstimateBatch([]string{text, "", "x"})
+
+	h := histograms(t, reg)
+	for _, name := range []string{"tokenestimate_estimate_duration_seconds", "tokenestimate_input_bytes", "tokenestimate_estimated_tokens"} {
+		if got := h[name]["kimi-k2@12"].GetSampleCount(); got != 3 {
+			t.Errorf("%s{preset=kimi-k2@12} has %d samples, want 3", name, got)
+		}
+		if got := h[name]["cl100k-base"].GetSampleCount(); got != 3 {
+			t.Errorf("%s{preset=cl100k-base} has %d samples, want 3", name, got)
+		}
+	}
+	if got, want := h["tokenestimate_input_bytes"]["kimi-k2@12"].GetSampleSum(), float64(3*len(text)); got != want {
+		t.Errorf("Input bytes sum = %v, want %v", got, want)
+	}
+	if got, want := h["tokenestimate_estimated_tokens"]["cl100k-base"].GetSampleSum(), float64(tokens[0]+tokens[1]+tokens[2]); got != want {
+		t.Errorf("Estimated tokens sum = %v, want %v", got, want)
+	}
+}
+
+func TestNewShares(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m1, err := New(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := New(reg)
+	if err != nil {
+		t.Fatalf("Second New: %v", err)
+	}
+	m1.Wrap(tokenestimate.NewEstimator()).Estimate("a")
+	m2.Wrap(tokenestimate.NewEstimator()).Estimate("b")
+	if got := histograms(t, reg)["tokenestimate_estimated_tokens"]["kimi-k2@12"].GetSampleCount(); got != 2 {
+		t.Errorf("Shared histogram has %d samples, want 2", got)
+	}
+}
@@ -0,0 +1,153 @@
+package tokenestimate
+
+import (
+	"cmp"
+	"math"
+	"strings"
+)
+
+// EstimateOpts adjusts a single EstimateWithOptions call. The zero value
+// estimates exactly like Estimate.
+type EstimateOpts struct {
+	Sampling      SamplingOverride // Whether to sample long texts
+	Normalization Normalization    // Normalizations applied to the text first
+	ContentType   *ContentType     // Content type to estimate as, or nil for the estimator's
+	Rounding      RoundingMode     // How the model output is rounded to a count, if not as configured
+}
+
+// SamplingOverride selects whether an EstimateWithOptions call samples.
+type SamplingOverride int
+
+const (
+	// SamplingDefault samples as the estimator is configured to.
+	SamplingDefault SamplingOverride = iota
+	// SamplingEnabled samples long texts, with the estimator's threshold
+	// and sample size or, if it has none, the defaults.
+	SamplingEnabled
+	// SamplingDisabled analyzes the whole text.
+	SamplingDisabled
+)
+
+// Normalization is a set of text normalizations, for text that will be
+// normalized the same way before it is tokenized.
+type Normalization int
+
+const (
+	// NormalizeLineEndings turns "\r\n" and lone "\r" into "\n".
+	NormalizeLineEndings Normalization = 1 << iota
+	// NormalizeTrimSpace removes leading and trailing whitespace.
+	NormalizeTrimSpace
+	// NormalizeCollapseSpaces turns every run of spaces and tabs into a
+	// single space.
+	NormalizeCollapseSpaces
+)
+
+// apply returns text with the normalizations applied.
+func (n Normalization) apply(text string) string {
+	if n&NormalizeLineEndings != 0 && strings.IndexByte(text, '\r') >= 0 {
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+		text = strings.ReplaceAll(text, "\r", "\n")
+	}
+	if n&NormalizeTrimSpace != 0 {
+		text = strings.TrimSpace(text)
+	}
+	if n&NormalizeCollapseSpaces != 0 {
+		text = collapseSpaces(text)
+	}
+	return text
+}
+
+// collapseSpaces turns every run of spaces and tabs in text into a single
+// space, returning text itself if there is none to collapse.
+func collapseSpaces(text string) string {
+	if !strings.Contains(text, "  ") && strings.IndexByte(text, '\t') < 0 {
+		return text
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	inRun := false
+	for i := 0; i < len(text); i++ {
+		if c := text[i]; c == ' ' || c == '\t' {
+			if !inRun {
+				b.WriteByte(' ')
+			}
+			inRun = true
+			continue
+		}
+		inRun = false
+		b.WriteByte(text[i])
+	}
+	return b.String()
+}
+
+// RoundingMode selects how the model output is rounded to a token count.
+// Negative outputs are always clamped to zero.
+type RoundingMode int
+
+const (
+	// RoundDefault rounds as the estimator is configured to; for the
+	// estimator itself it means RoundNearest.
+	RoundDefault RoundingMode = iota
+	// RoundNearest rounds to the nearest count.
+	RoundNearest
+	// RoundUp rounds up, for budgets that must not be exceeded.
+	RoundUp
+	// RoundDown rounds down.
+	RoundDown
+)
+
+// round rounds a model output to a token count.
+func (m RoundingMode) round(count float64) int {
+	if count < 0 {
+		return 0
+	}
+	switch m {
+	case RoundUp:
+		return int(math.Ceil(count))
+	case RoundDown:
+		return int(count)
+	default:
+		return int(count + 0.5) // Round to nearest integer
+	}
+}
+
+// EstimateWithOptions is like Estimate with per-call adjustments, so that a
+// request handler can, for example, force a full scan or hint that a
+// payload is JSON without building and keeping a configured clone of the
+// estimator.
+func (e *Estimator) EstimateWithOptions(text string, opts EstimateOpts) int {
+	text = opts.Normalization.apply(text)
+
+	estimator := e
+	if opts.ContentType != nil && *opts.ContentType != e.ContentType {
+		estimator = estimator.WithContentType(*opts.ContentType)
+	}
+	switch opts.Sampling {
+	case SamplingEnabled:
+		if !estimator.EnableSampling {
+			estimator = estimator.WithSampling(
+				cmp.Or(estimator.SamplingThreshold, defaultSamplingThreshold),
+				cmp.Or(estimator.SamplingSize, defaultSamplingSize))
+		}
+	case SamplingDisabled:
+		if estimator.EnableSampling {
+			estimator = estimator.Clone()
+			estimator.EnableSampling = false
+		}
+	}
+
+	h := estimator.anomalyHooks()
+	var stats Stats
+	sampled := 0
+	if h != nil {
+		stats, sampled = estimator.analyzeSampled(text)
+	} else {
+		stats = estimator.Analyze(text)
+	}
+	estimator.warnUnknown(stats)
+	tokens := cmp.Or(opts.Rounding, estimator.Rounding).round(estimator.withMargin(estimator.calculateTokenCount(stats)))
+	if h != nil {
+		h.check(estimator, text, stats, sampled, float64(tokens))
+	}
+	return tokens
+}
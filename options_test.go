@@ -0,0 +1,140 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateWithOptions(t *testing.T) {
+	estimator := NewEstimator()
+
+	t.Run("Zero options match Estimate", func(t *testing.T) {
+		for _, text := range referenceTexts {
+			if got, want := estimator.EstimateWithOptions(text, EstimateOpts{}), estimator.Estimate(text); got != want {
+				t.Errorf("EstimateWithOptions(%q) = %d, want %d", text, got, want)
+			}
+		}
+	})
+
+	t.Run("Sampling override", func(t *testing.T) {
+		estimator := estimator.WithoutSampling()
+		text := strings.Repeat("The quick brown fox jumps over the lazy dog. 你好世界。", 1000)
+		sampled := estimator.WithSampling(1000, 100)
+		if got, want := sampled.EstimateWithOptions(text, EstimateOpts{Sampling: SamplingDisabled}), estimator.Estimate(text); got != want {
+			t.Errorf("SamplingDisabled = %d, want the full estimate %d", got, want)
+		}
+		if got, want := estimator.EstimateWithOptions(text, EstimateOpts{Sampling: SamplingEnabled}),
+			estimator.WithSampling(defaultSamplingThreshold, defaultSamplingSize).Estimate(text); got != want {
+			t.Errorf("SamplingEnabled = %d, want the default sampled estimate %d", got, want)
+		}
+		if got, want := sampled.EstimateWithOptions(text, EstimateOpts{Sampling: SamplingEnabled}), sampled.Estimate(text); got != want {
+			t.Errorf("SamplingEnabled = %d, want the estimator's sampled estimate %d", got, want)
+		}
+		if estimator.EnableSampling || !sampled.EnableSampling {
+			t.Error("EstimateWithOptions modified the estimator")
+		}
+	})
+
+	t.Run("Content type hint", func(t *testing.T) {
+		text := `{"id": 1, "name": "widget", "tags": ["a", "b"]}`
+		ct := ContentJSON
+		got := estimator.EstimateWithOptions(text, EstimateOpts{ContentType: &ct})
+		if want := estimator.WithContentType(ContentJSON).Estimate(text); got != want {
+			t.Errorf("ContentJSON hint = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Normalization", func(t *testing.T) {
+		opts := EstimateOpts{Normalization: NormalizeLineEndings | NormalizeTrimSpace | NormalizeCollapseSpaces}
+		got := estimator.EstimateWithOptions("  a\r\nb \t\t c  \r ", opts)
+		if want := estimator.Estimate("a\nb c"); got != want {
+			t.Errorf("Normalized estimate = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Rounding", func(t *testing.T) {
+		text := "Hello, world! This is a test."
+		count := estimator.calculateTokenCount(estimator.Analyze(text))
+		up := estimator.EstimateWithOptions(text, EstimateOpts{Rounding: RoundUp})
+		down := estimator.EstimateWithOptions(text, EstimateOpts{Rounding: RoundDown})
+		if float64(up) < count || float64(down) > count || up-down > 1 {
+			t.Errorf("Rounding %.2f gave up %d and down %d", count, up, down)
+		}
+		if got := estimator.WithRounding(RoundUp).EstimateWithOptions(text, EstimateOpts{}); got != up {
+			t.Errorf("Expected the estimator's rounding mode, got %d instead of %d", got, up)
+		}
+	})
+}
+
+func TestNormalization(t *testing.T) {
+	tests := []struct {
+		n    Normalization
+		text string
+		want string
+	}{
+		{0, " a\r\n ", " a\r\n "},
+		{NormalizeLineEndings, "a\r\nb\rc\n", "a\nb\nc\n"},
+		{NormalizeTrimSpace, "\n\t a b \n", "a b"},
+		{NormalizeCollapseSpaces, "a  b\t\tc \td\n  e", "a b c d\n e"},
+		{NormalizeCollapseSpaces, "a b", "a b"},
+	}
+	for _, tt := range tests {
+		if got := tt.n.apply(tt.text); got != tt.want {
+			t.Errorf("Normalization(%d).apply(%q) = %q, want %q", tt.n, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRoundingMode(t *testing.T) {
+	tests := []struct {
+		mode  RoundingMode
+		count float64
+		want  int
+	}{
+		{RoundDefault, 2.5, 3},
+		{RoundNearest, 2.5, 3},
+		{RoundNearest, 2.49, 2},
+		{RoundUp, 2.01, 3},
+		{RoundUp, 2, 2},
+		{RoundDown, 2.99, 2},
+		{RoundUp, -0.5, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.round(tt.count); got != tt.want {
+			t.Errorf("RoundingMode(%d).round(%v) = %d, want %d", tt.mode, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestWithRounding(t *testing.T) {
+	text := "Hello, world! This is a test."
+	count := NewEstimator().EstimateFloat(text)
+	if count == float64(int(count)) {
+		t.Fatalf("Expected a fractional model output, got %v", count)
+	}
+
+	tests := []struct {
+		mode RoundingMode
+		want int
+	}{
+		{RoundNearest, int(count + 0.5)},
+		{RoundUp, int(count) + 1},
+		{RoundDown, int(count)},
+	}
+	for _, tt := range tests {
+		estimator := NewEstimator().WithRounding(tt.mode)
+		if got := estimator.Estimate(text); got != tt.want {
+			t.Errorf("Estimate with rounding %d = %d, want %d", tt.mode, got, tt.want)
+		}
+		if got := estimator.EstimateFromStats(estimator.Analyze(text)); got != tt.want {
+			t.Errorf("EstimateFromStats with rounding %d = %d, want %d", tt.mode, got, tt.want)
+		}
+	}
+
+	if got := NewEstimator().Estimate(text); got != int(count+0.5) {
+		t.Errorf("Expected Estimate to round to nearest by default, got %d for %v", got, count)
+	}
+	if got := NewEstimator().EstimateFloat(""); got != 0 {
+		t.Errorf("Expected 0 for empty text, got %v", got)
+	}
+}
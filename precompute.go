@@ -0,0 +1,38 @@
+package tokenestimate
+
+// Precomputed holds the analysis of a fixed prompt prefix, such as a system
+// prompt or few-shot examples, so that it is not analyzed again for every
+// request. A Precomputed is immutable and safe for concurrent use.
+type Precomputed struct {
+	e     *Estimator
+	text  string
+	stats Stats
+}
+
+// Precompute analyzes prefix once for use with EstimateWithPrefix.
+func (e *Estimator) Precompute(prefix string) *Precomputed {
+	return &Precomputed{e: e, text: prefix, stats: e.Analyze(prefix)}
+}
+
+// Text returns the prefix.
+func (p *Precomputed) Text() string {
+	return p.text
+}
+
+// Stats returns the statistics of the prefix.
+func (p *Precomputed) Stats() Stats {
+	return p.stats
+}
+
+// EstimateWithPrefix estimates the prefix held by pre followed by text,
+// analyzing only text. When the prefix ends in whitespace the result
+// matches Estimate(pre.Text() + text), except for features measured over a
+// whole text, such as repetition. If pre was computed by a different
+// estimator, the prefix is analyzed again.
+func (e *Estimator) EstimateWithPrefix(pre *Precomputed, text string) int {
+	prefix := pre.stats
+	if pre.e != e {
+		prefix = e.Analyze(pre.text)
+	}
+	return e.EstimateFromStats(prefix.Add(e.Analyze(text)))
+}
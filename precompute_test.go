@@ -0,0 +1,52 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateWithPrefix(t *testing.T) {
+	estimator := NewEstimator()
+	system := strings.Repeat("You are a helpful assistant. Answer briefly and cite sources.\n", 20)
+	pre := estimator.Precompute(system)
+
+	for _, text := range []string{
+		"",
+		"What is the capital of France?",
+		"你好，请介绍一下巴黎。",
+		"Order #12345 shipped on 2024-01-15.",
+	} {
+		want := estimator.Estimate(system + text)
+		if got := estimator.EstimateWithPrefix(pre, text); got != want {
+			t.Errorf("EstimateWithPrefix(%q) = %d, Estimate = %d", text, got, want)
+		}
+	}
+
+	t.Run("Accessors", func(t *testing.T) {
+		if pre.Text() != system {
+			t.Error("Text does not return the prefix")
+		}
+		if pre.Stats() != estimator.Analyze(system) {
+			t.Error("Stats does not match Analyze")
+		}
+	})
+
+	t.Run("Other estimator", func(t *testing.T) {
+		code := estimator.WithContentType(ContentCode)
+		text := "func main() {}"
+		if got, want := code.EstimateWithPrefix(pre, text), code.Estimate(system+text); got != want {
+			t.Errorf("EstimateWithPrefix = %d, Estimate = %d", got, want)
+		}
+	})
+}
+
+func BenchmarkEstimator_EstimateWithPrefix(b *testing.B) {
+	estimator := NewEstimator()
+	pre := estimator.Precompute(strings.Repeat("You are a helpful assistant. Answer briefly and cite sources.\n", 100))
+	text := "What is the capital of France?"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		estimator.EstimateWithPrefix(pre, text)
+	}
+}
@@ -0,0 +1,606 @@
+package tokenestimate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Predefined estimator presets
+var (
+	// KimiK2Estimator is an estimator trained on Kimi-K2 tokenizer data.
+	// Achieves ~8.5% average relative error. It is the latest version of the
+	// kimi-k2 preset; pin a versioned name such as "kimi-k2@12" to keep its
+	// numbers across retrains.
+	KimiK2Estimator = kimiK2V12
+
+	// kimiK2V1 is the original kimi-k2 preset. Categories added after its
+	// release are priced like the bucket they used to fall into, so its
+	// estimates never change.
+	kimiK2V1 = &Estimator{
+		Name:             "kimi-k2",
+		Version:          1,
+		Description:      "Kimi-K2 tokenizer preset, original release",
+		intercept:        0.0,
+		coefSymbols:      0.5671194745036742,
+		coefLatinLetters: 0.20601617930567592,
+		coefLatinExt:     5.87908499852652,
+		coefDigits:       0.8030572147361226,
+		coefChinese:      0.6627122076124944,
+		coefJapanese:     1.0879350533022305,
+		coefKorean:       1.0509515625240804,
+		coefRussian:      0.5306900990158002,
+		coefArabic:       0.6352704975749803,
+		coefKhmer:        0.5671194745036742,
+		coefLao:          0.5671194745036742,
+		coefMyanmar:      0.5671194745036742,
+		coefEthiopic:     0.5671194745036742,
+		coefSpaces:       0.02578661842488973,
+		coefTabs:         0.02578661842488973,
+		coefUnknown:      2.0,
+		coefInvalidBytes: 1.0,
+		ChatTemplate:     kimiK2ChatTemplate,
+		// The residual quantiles are a heuristic ±13.6% around the
+		// estimate, not measured percentiles.
+		residualP10: 0.864,
+		residualP90: 1.136,
+		Metadata: Metadata{
+			Corpus:  "Texts labeled with the Kimi-K2 tokenizer",
+			Scripts: []string{"Latin", "LatinExtended", "Chinese", "Japanese", "Korean", "Russian", "Arabic"},
+		},
+	}
+
+	// kimiK2V2 counts whitespace runs.
+	kimiK2V2 = kimiK2V1.revise(2, "Kimi-K2 tokenizer preset with whitespace runs", func(e *Estimator) {
+		e.coefWhitespaceRuns = 0.9
+	})
+
+	// kimiK2V3 prices Khmer, Lao and Myanmar, which have no spaces between
+	// words and are poorly covered by the vocabulary.
+	kimiK2V3 = kimiK2V2.revise(3, "Kimi-K2 tokenizer preset with Southeast Asian scripts", func(e *Estimator) {
+		e.coefKhmer = 1.4
+		e.coefLao = 1.5
+		e.coefMyanmar = 1.5
+		e.Metadata.Scripts = append(e.Metadata.Scripts, "Khmer", "Lao", "Myanmar")
+	})
+
+	// kimiK2V4 prices Ethiopic syllables, most of which are not merged
+	// beyond their UTF-8 bytes.
+	kimiK2V4 = kimiK2V3.revise(4, "Kimi-K2 tokenizer preset with Ethiopic", func(e *Estimator) {
+		e.coefEthiopic = 1.6
+		e.Metadata.Scripts = append(e.Metadata.Scripts, "Ethiopic")
+	})
+
+	// kimiK2V5 adds a per-word cost. Together with the per-letter cost it
+	// makes tokens per word grow with average word length: a word of
+	// typical English length (~4.7 letters) costs the same as before,
+	// short function words cost more and long words less.
+	kimiK2V5 = kimiK2V4.revise(5, "Kimi-K2 tokenizer preset with word counts", func(e *Estimator) {
+		e.coefLatinLetters = 0.15
+		e.coefWords = 0.27
+	})
+
+	// kimiK2V6 prices numbers by run: the tokenizer splits digits into
+	// chunks of up to three, so a run of n digits costs ceil(n/3) tokens,
+	// which n/3 + 2/3 approximates.
+	kimiK2V6 = kimiK2V5.revise(6, "Kimi-K2 tokenizer preset with digit runs", func(e *Estimator) {
+		e.coefDigits = 0.34
+		e.coefDigitRuns = 0.66
+	})
+
+	// kimiK2V7 detects base64 and hex blobs, whose random-looking
+	// characters merge poorly: about one token per 2.5 characters.
+	kimiK2V7 = kimiK2V6.revise(7, "Kimi-K2 tokenizer preset with blob detection", func(e *Estimator) {
+		e.detectBlobs = true
+		e.coefBlobChars = 0.4
+	})
+
+	// kimiK2V8 prices HTML tags and entities counted under HTMLCountTags.
+	// Tags split at nearly every symbol ("</", "div", ">") and entities
+	// take about three tokens each, more than their characters suggest.
+	// Estimates without HTMLCountTags are the same as kimi-k2@7.
+	kimiK2V8 = kimiK2V7.revise(8, "Kimi-K2 tokenizer preset with HTML tags", func(e *Estimator) {
+		e.coefHTMLTags = 0.3
+		e.coefHTMLEntities = 1.1
+	})
+
+	// kimiK2V9 measures repetitiveness. Repeated templates such as log
+	// lines and boilerplate are made of frequent sequences that merge into
+	// longer tokens than unique text, so each repeated four-word shingle
+	// is discounted.
+	kimiK2V9 = kimiK2V8.revise(9, "Kimi-K2 tokenizer preset with repetition", func(e *Estimator) {
+		e.detectRepetition = true
+		e.coefRepeatedShingles = -0.4
+	})
+
+	// kimiK2V10 recognizes literal special tokens such as "<|im_end|>",
+	// which the tokenizer encodes as one token instead of about ten.
+	kimiK2V10 = kimiK2V9.revise(10, "Kimi-K2 tokenizer preset with special tokens", func(e *Estimator) {
+		e.specialTokens = kimiK2SpecialTokens
+		e.coefSpecialTokens = 1.0
+	})
+
+	// kimiK2V11 prices tabs separately from spaces. Runs of spaces merge
+	// into few tokens, but tabs mostly map to dedicated tokens.
+	kimiK2V11 = kimiK2V10.revise(11, "Kimi-K2 tokenizer preset with tabs", func(e *Estimator) {
+		e.coefTabs = 0.3
+		e.Metadata.MAPE = 0.085
+	})
+
+	// kimiK2V12 samples texts of a million characters or more, which the
+	// versions released before sampling became the default analyze in
+	// full. It drops the repetition discount of kimi-k2@9: the tokenizer
+	// splits text into words before merging, so repeated words cost as
+	// much as new ones, and a fit on the labeled texts gives a positive
+	// coefficient, not a discount. Whitespace runs are priced at the 0.86
+	// tokens a run costs under o200k_base beyond its spaces, where
+	// kimi-k2@2 guessed 0.9. The longest token of cl100k_base and
+	// o200k_base is 128 bytes; that of Kimi-K2 is not measured, so the
+	// lower bound allows twice as much.
+	kimiK2V12 = kimiK2V11.revise(12, "Kimi-K2 tokenizer preset (~8.5% avg error)", func(e *Estimator) {
+		e.defaultSampling()
+		e.detectRepetition = false
+		e.coefRepeatedShingles = 0
+		e.coefWhitespaceRuns = 0.86
+		e.MaxTokenBytes = 256
+	})
+
+	// kimiK2Versions lists every released kimi-k2 version, oldest first.
+	kimiK2Versions = []*Estimator{kimiK2V1, kimiK2V2, kimiK2V3, kimiK2V4, kimiK2V5, kimiK2V6, kimiK2V7, kimiK2V8, kimiK2V9, kimiK2V10, kimiK2V11, kimiK2V12}
+
+	// KimiK2CodeEstimator is the kimi-k2 model tuned for source code, where
+	// punctuation clusters like "()" or "];" merge into single tokens,
+	// identifiers split into more pieces than prose words, and indentation
+	// runs are frequent. It is selected by
+	// KimiK2Estimator.WithContentType(ContentCode).
+	KimiK2CodeEstimator = kimiK2CodeV4
+
+	// kimiK2CodeV1 is derived from kimi-k2@7. Its coefficients were tuned
+	// by hand and its error was never measured.
+	kimiK2CodeV1 = kimiK2V7.variant("kimi-k2-code", 1, "Kimi-K2 tokenizer preset for source code", func(e *Estimator) {
+		e.coefSymbols = 0.45
+		e.coefLatinLetters = 0.17
+		e.coefWords = 0.35
+		e.coefSpaces = 0.02
+		e.coefTabs = 0.02
+		e.coefWhitespaceRuns = 1.0
+		e.Metadata.Corpus = "None: tuned by hand on source code, not measured"
+		// A heuristic ±20%, like that of the other variants.
+		e.residualP10 = 0.8
+		e.residualP90 = 1.2
+	})
+
+	// kimiK2CodeV2 prices sub-word boundaries inside identifiers, so that
+	// foo_bar.baz.QuxHandler is estimated from its pieces rather than its
+	// letter count.
+	kimiK2CodeV2 = kimiK2CodeV1.revise(2, "Kimi-K2 tokenizer preset for source code", func(e *Estimator) {
+		e.coefLatinLetters = 0.14
+		e.coefIdentifierBoundaries = 0.55
+	})
+
+	// kimiK2CodeV3 prices tab indentation separately from space
+	// indentation.
+	kimiK2CodeV3 = kimiK2CodeV2.revise(3, "Kimi-K2 tokenizer preset for source code", func(e *Estimator) {
+		e.coefTabs = 0.3
+	})
+
+	// kimiK2CodeV4 applies the code coefficients of kimi-k2-code@3 to
+	// kimi-k2@12, so that code is priced with the repetition, special
+	// tokens and sampling of the text model. The error of the text model
+	// does not carry over; that of the code model is not measured.
+	kimiK2CodeV4 = kimiK2V12.variant("kimi-k2-code", 4, "Kimi-K2 tokenizer preset for source code", func(e *Estimator) {
+		e.coefSymbols = kimiK2CodeV3.coefSymbols
+		e.coefLatinLetters = kimiK2CodeV3.coefLatinLetters
+		e.coefWords = kimiK2CodeV3.coefWords
+		e.coefSpaces = kimiK2CodeV3.coefSpaces
+		e.coefTabs = kimiK2CodeV3.coefTabs
+		e.coefWhitespaceRuns = kimiK2CodeV3.coefWhitespaceRuns
+		e.coefIdentifierBoundaries = kimiK2CodeV3.coefIdentifierBoundaries
+		e.Metadata.Corpus = kimiK2CodeV3.Metadata.Corpus
+		e.Metadata.MAPE = 0
+		e.residualP10 = kimiK2CodeV3.residualP10
+		e.residualP90 = kimiK2CodeV3.residualP90
+	})
+
+	// kimiK2CodeVersions lists every released kimi-k2-code version, oldest first.
+	kimiK2CodeVersions = []*Estimator{kimiK2CodeV1, kimiK2CodeV2, kimiK2CodeV3, kimiK2CodeV4}
+
+	// KimiK2MarkdownEstimator is the kimi-k2 model for markdown. Markup
+	// such as "## ", "```" and "](" is encoded in fewer tokens than its
+	// symbols suggest, while list and table rows add separator tokens the
+	// prose model misses. It is selected by
+	// KimiK2Estimator.WithContentType(ContentMarkdown).
+	KimiK2MarkdownEstimator = kimiK2MarkdownV2
+
+	// kimiK2MarkdownV1 is derived from kimi-k2@7. Its coefficients were
+	// tuned by hand and its error was never measured.
+	kimiK2MarkdownV1 = kimiK2V7.variant("kimi-k2-markdown", 1, "Kimi-K2 tokenizer preset for markdown", func(e *Estimator) {
+		e.coefMarkdownHeadings = -0.15
+		e.coefMarkdownFences = -0.7
+		e.coefMarkdownListItems = 0.3
+		e.coefMarkdownTableRows = 0.5
+		e.coefMarkdownLinks = -0.1
+		e.Metadata.Corpus = "None: tuned by hand on markdown, not measured"
+		e.residualP10 = 0.8
+		e.residualP90 = 1.2
+	})
+
+	// kimiK2MarkdownV2 applies the markup coefficients of
+	// kimi-k2-markdown@1 to kimi-k2@12.
+	kimiK2MarkdownV2 = kimiK2V12.variant("kimi-k2-markdown", 2, "Kimi-K2 tokenizer preset for markdown", func(e *Estimator) {
+		e.coefMarkdownHeadings = kimiK2MarkdownV1.coefMarkdownHeadings
+		e.coefMarkdownFences = kimiK2MarkdownV1.coefMarkdownFences
+		e.coefMarkdownListItems = kimiK2MarkdownV1.coefMarkdownListItems
+		e.coefMarkdownTableRows = kimiK2MarkdownV1.coefMarkdownTableRows
+		e.coefMarkdownLinks = kimiK2MarkdownV1.coefMarkdownLinks
+		e.Metadata.Corpus = kimiK2MarkdownV1.Metadata.Corpus
+		e.Metadata.MAPE = 0
+		e.residualP10 = kimiK2MarkdownV1.residualP10
+		e.residualP90 = kimiK2MarkdownV1.residualP90
+	})
+
+	// kimiK2MarkdownVersions lists every released kimi-k2-markdown version, oldest first.
+	kimiK2MarkdownVersions = []*Estimator{kimiK2MarkdownV1, kimiK2MarkdownV2}
+
+	// KimiK2JSONEstimator is the kimi-k2 model for JSON. Structural
+	// punctuation merges into clusters such as `{"`, `":"` and `"},{"` that
+	// are one token each, so it is priced per cluster rather than per
+	// character. Keys repeated across the objects of an array are mostly
+	// short vocabulary words that merge with their quotes. It is selected by
+	// KimiK2Estimator.WithContentType(ContentJSON) or EstimateJSONText.
+	KimiK2JSONEstimator = kimiK2JSONV2
+
+	// kimiK2JSONV1 is derived from kimi-k2@8. Its coefficients were tuned
+	// by hand and its error was never measured.
+	kimiK2JSONV1 = kimiK2V8.variant("kimi-k2-json", 1, "Kimi-K2 tokenizer preset for JSON", func(e *Estimator) {
+		e.coefJSONStructure = -0.45
+		e.coefJSONStructureRuns = 0.9
+		e.coefJSONRepeatedKeys = -0.2
+		e.Metadata.Corpus = "None: tuned by hand on JSON, not measured"
+		e.residualP10 = 0.8
+		e.residualP90 = 1.2
+	})
+
+	// kimiK2JSONV2 applies the structure coefficients of kimi-k2-json@1 to
+	// kimi-k2@12.
+	kimiK2JSONV2 = kimiK2V12.variant("kimi-k2-json", 2, "Kimi-K2 tokenizer preset for JSON", func(e *Estimator) {
+		e.coefJSONStructure = kimiK2JSONV1.coefJSONStructure
+		e.coefJSONStructureRuns = kimiK2JSONV1.coefJSONStructureRuns
+		e.coefJSONRepeatedKeys = kimiK2JSONV1.coefJSONRepeatedKeys
+		e.Metadata.Corpus = kimiK2JSONV1.Metadata.Corpus
+		e.Metadata.MAPE = 0
+		e.residualP10 = kimiK2JSONV1.residualP10
+		e.residualP90 = kimiK2JSONV1.residualP90
+	})
+
+	// kimiK2JSONVersions lists every released kimi-k2-json version, oldest first.
+	kimiK2JSONVersions = []*Estimator{kimiK2JSONV1, kimiK2JSONV2}
+
+	// KimiK2P90Estimator is the upper-bound counterpart of KimiK2Estimator
+	// for budget enforcement, which would rather overestimate than
+	// overrun. It is KimiK2Estimator scaled by the 90th percentile of its
+	// residuals and rounded up, and its content-type variants are those of
+	// the other presets, scaled alike. How often it covers the actual count
+	// is not measured; see upperBound.
+	KimiK2P90Estimator = kimiK2P90V2
+
+	// Version 1 of the bounds scales kimi-k2@11 and the variants of its
+	// time; version 2 scales kimi-k2@12 and its variants.
+	kimiK2P90V1         = kimiK2V11.upperBound("kimi-k2-p90", 1, "Kimi-K2 tokenizer preset, 90th percentile")
+	kimiK2CodeP90V1     = kimiK2CodeV3.upperBound("kimi-k2-code-p90", 1, "Kimi-K2 tokenizer preset for source code, 90th percentile")
+	kimiK2MarkdownP90V1 = kimiK2MarkdownV1.upperBound("kimi-k2-markdown-p90", 1, "Kimi-K2 tokenizer preset for markdown, 90th percentile")
+	kimiK2JSONP90V1     = kimiK2JSONV1.upperBound("kimi-k2-json-p90", 1, "Kimi-K2 tokenizer preset for JSON, 90th percentile")
+	kimiK2P90V2         = kimiK2V12.upperBound("kimi-k2-p90", 2, "Kimi-K2 tokenizer preset, 90th percentile")
+	kimiK2CodeP90V2     = kimiK2CodeV4.upperBound("kimi-k2-code-p90", 2, "Kimi-K2 tokenizer preset for source code, 90th percentile")
+	kimiK2MarkdownP90V2 = kimiK2MarkdownV2.upperBound("kimi-k2-markdown-p90", 2, "Kimi-K2 tokenizer preset for markdown, 90th percentile")
+	kimiK2JSONP90V2     = kimiK2JSONV2.upperBound("kimi-k2-json-p90", 2, "Kimi-K2 tokenizer preset for JSON, 90th percentile")
+
+	// presetVersions lists the version history of every built-in preset.
+	// The presets of presets_gen.go are added at init.
+	presetVersions = [][]*Estimator{
+		kimiK2Versions, kimiK2CodeVersions, kimiK2MarkdownVersions, kimiK2JSONVersions,
+		{kimiK2P90V1, kimiK2P90V2}, {kimiK2CodeP90V1, kimiK2CodeP90V2},
+		{kimiK2MarkdownP90V1, kimiK2MarkdownP90V2}, {kimiK2JSONP90V1, kimiK2JSONP90V2},
+	}
+
+	// presets maps preset names to their estimator instances. Versioned
+	// presets are stored under "name@version", and the bare name maps to
+	// the latest registered version.
+	presets = map[string]*Estimator{}
+
+	// aliases maps alias names to the registry keys of the presets they
+	// resolve to; see RegisterAlias.
+	aliases = map[string]string{}
+
+	// defaultPreset is the name of the preset NewEstimator returns a clone
+	// of; see SetDefaultPreset. Empty means KimiK2Estimator.
+	defaultPreset string
+
+	// presetsMu guards presets, aliases and defaultPreset, which
+	// FetchPresets may update while presets are in use.
+	presetsMu sync.RWMutex
+
+	// deprecationWarned records which deprecated presets have already been
+	// reported, so each is logged only once per process.
+	deprecationWarned sync.Map
+)
+
+func init() {
+	linkContentVariants(map[ContentType]*Estimator{
+		ContentText:     KimiK2Estimator,
+		ContentCode:     KimiK2CodeEstimator,
+		ContentMarkdown: KimiK2MarkdownEstimator,
+		ContentJSON:     KimiK2JSONEstimator,
+	})
+	linkContentVariants(map[ContentType]*Estimator{
+		ContentText:     kimiK2P90V1,
+		ContentCode:     kimiK2CodeP90V1,
+		ContentMarkdown: kimiK2MarkdownP90V1,
+		ContentJSON:     kimiK2JSONP90V1,
+	})
+	linkContentVariants(map[ContentType]*Estimator{
+		ContentText:     KimiK2P90Estimator,
+		ContentCode:     kimiK2CodeP90V2,
+		ContentMarkdown: kimiK2MarkdownP90V2,
+		ContentJSON:     kimiK2JSONP90V2,
+	})
+
+	presetVersions = append(presetVersions, fittedPresetVersions(presetVersions)...)
+	for _, versions := range presetVersions {
+		latest := versions[len(versions)-1]
+		for _, estimator := range versions {
+			if estimator != latest {
+				estimator.Deprecated = "superseded by " + latest.Key()
+			}
+			if err := RegisterPreset(estimator); err != nil {
+				panic("tokenestimate: " + err.Error())
+			}
+		}
+	}
+}
+
+// NewEstimatorWithName creates a new estimator using a preset name.
+// The name may carry a version suffix ("kimi-k2@1") to pin an exact
+// version; a bare name resolves to the latest version.
+// Returns an error if the preset name is not found. Like NewEstimator, it
+// returns a clone of the preset.
+func NewEstimatorWithName(name string) (*Estimator, error) {
+	e, err := GetPresetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return e.Clone(), nil
+}
+
+// ListPresets returns a list of all available preset names, including
+// the "name@version" form of every versioned preset.
+func ListPresets() []string {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	return names
+}
+
+// GetPresetByName returns an estimator preset by name, or an error if not found.
+// If the preset is deprecated, a warning is logged the first time it is used.
+// The preset is shared with every caller and must not be modified; use
+// NewEstimatorWithName for a copy of its own.
+func GetPresetByName(name string) (*Estimator, error) {
+	estimator, err := lookupPreset(name)
+	if err != nil {
+		return nil, err
+	}
+	if estimator.Deprecated != "" {
+		if _, warned := deprecationWarned.LoadOrStore(name, true); !warned {
+			getLogger().Warn("tokenestimate: deprecated preset",
+				"preset", name, "reason", estimator.Deprecated)
+		}
+	}
+	return estimator, nil
+}
+
+// Latest returns the latest registered version of the named preset.
+// Any version suffix on name is ignored, so Latest("kimi-k2@1") returns
+// the same estimator as Latest("kimi-k2"), and an alias resolves to the
+// latest version of its preset.
+func Latest(name string) (*Estimator, error) {
+	presetsMu.RLock()
+	if target, ok := aliases[name]; ok {
+		name = target
+	}
+	presetsMu.RUnlock()
+	base, _, err := parsePresetName(name)
+	if err != nil {
+		return nil, err
+	}
+	return GetPresetByName(base)
+}
+
+// RegisterAlias makes alias resolve to the preset target, so that names
+// callers already have, such as a provider's model IDs, find the preset of
+// their tokenizer without a mapping table of their own:
+//
+//	tokenestimate.RegisterAlias("moonshot-v1", "kimi-k2")
+//
+// An alias of a bare name follows its latest version, and an alias of
+// "name@version" stays on that version. An alias of an alias resolves to
+// the latter's target. Registering an alias again replaces it. It returns
+// an error if target is not registered or alias is a preset's name, which
+// would take precedence.
+func RegisterAlias(alias, target string) error {
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	if alias == "" {
+		return errors.New("empty alias")
+	}
+	if _, ok := presets[alias]; ok {
+		return fmt.Errorf("alias is the name of a preset: %s", alias)
+	}
+	if t, ok := aliases[target]; ok {
+		target = t
+	}
+	if _, ok := presets[target]; !ok {
+		return fmt.Errorf("unknown preset: %s", target)
+	}
+	aliases[alias] = target
+	return nil
+}
+
+// RegisterPreset allows users to register custom estimator presets.
+// If an estimator with the same name already exists, it will be overwritten.
+// An estimator with a non-zero Version is registered as "name@version" and
+// also becomes the bare name's target unless a newer version is registered.
+// It is safe to call while presets are in use. It returns the error of
+// the estimator's Validate and registers nothing if there is one.
+func RegisterPreset(estimator *Estimator) error {
+	if err := estimator.Validate(); err != nil {
+		return fmt.Errorf("preset %s: %w", estimator.Key(), err)
+	}
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	registerPreset(estimator)
+	return nil
+}
+
+// registerPreset is RegisterPreset with presetsMu held.
+func registerPreset(estimator *Estimator) {
+	if estimator.Version == 0 {
+		presets[estimator.Name] = estimator
+		return
+	}
+
+	presets[estimator.Key()] = estimator
+	if current, ok := presets[estimator.Name]; !ok || current.Version <= estimator.Version {
+		presets[estimator.Name] = estimator
+	}
+}
+
+// revise returns a copy of the estimator as a new version of the same
+// preset, with update applied to its coefficients.
+func (e *Estimator) revise(version int, description string, update func(*Estimator)) *Estimator {
+	return e.variant(e.Name, version, description, update)
+}
+
+// variant returns a copy of the estimator as the given version of the named
+// preset, with update applied to its coefficients. The copy is not linked
+// to the estimator's content-type variants, and records the estimator as
+// its base if the names differ.
+func (e *Estimator) variant(name string, version int, description string, update func(*Estimator)) *Estimator {
+	clone := e.Clone()
+	clone.Name = name
+	clone.Version = version
+	clone.Description = description
+	clone.variants = nil
+	if name != e.Name {
+		clone.base = e.Key()
+	}
+	update(clone)
+	return clone
+}
+
+// upperBound returns the given version of the named preset derived from
+// the estimator by scaling its model by residualP90, the 90th percentile
+// of its residuals. Estimates are rounded up, and the residuals are
+// rescaled to the new estimates. Dictionary words keep their costs.
+// The bound is not fitted by quantile regression, and neither its
+// coverage nor its mean error is measured: it covers nine texts in ten
+// only as far as residualP90 is the 90th percentile for them. Like every
+// preset released since sampling became the default, the bound samples
+// giant texts.
+func (e *Estimator) upperBound(name string, version int, description string) *Estimator {
+	return e.variant(name, version, description, func(u *Estimator) {
+		u.scaleModel(e.residualP90)
+		u.residualP10 = e.residualP10 / e.residualP90
+		u.residualP90 = 1
+		u.Rounding = RoundUp
+		u.Metadata.MAPE = 0
+		u.defaultSampling()
+	})
+}
+
+// lookupPreset resolves name in the registry without logging.
+func lookupPreset(name string) (*Estimator, error) {
+	presetsMu.RLock()
+	estimator, ok := resolvePreset(name)
+	presetsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown preset: %s", name)
+	}
+	return estimator, nil
+}
+
+// resolvePreset is lookupPreset with presetsMu held.
+func resolvePreset(name string) (*Estimator, bool) {
+	estimator, ok := presets[name]
+	if target, isAlias := aliases[name]; !ok && isAlias {
+		estimator, ok = presets[target]
+	}
+	return estimator, ok
+}
+
+// SetDefaultPreset makes NewEstimator, and the functions using its
+// estimator such as NewBuilder and LoadPreset without a base, return the
+// named preset, so that an application sets its deployment's model once at
+// startup instead of passing a preset name to every library estimating
+// tokens:
+//
+//	if err := tokenestimate.SetDefaultPreset("kimi-k2-code"); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// The name is resolved whenever NewEstimator is called, so a bare name or
+// an alias follows the versions registered later. An empty name restores
+// KimiK2Estimator. It returns an error if the preset is not registered.
+func SetDefaultPreset(name string) error {
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	if _, ok := resolvePreset(name); !ok && name != "" {
+		return fmt.Errorf("unknown preset: %s", name)
+	}
+	defaultPreset = name
+	return nil
+}
+
+// defaultEstimator returns the preset NewEstimator clones.
+func defaultEstimator() *Estimator {
+	presetsMu.RLock()
+	name := defaultPreset
+	presetsMu.RUnlock()
+	if name == "" {
+		return KimiK2Estimator
+	}
+	estimator, err := GetPresetByName(name)
+	if err != nil {
+		return KimiK2Estimator
+	}
+	return estimator
+}
+
+// Key returns the registry key of the estimator, which names its exact
+// preset version, such as "kimi-k2@12", or just its name for presets
+// without versions. NewEstimatorWithName resolves it back to the preset.
+func (e *Estimator) Key() string {
+	if e.Version == 0 {
+		return e.Name
+	}
+	return e.Name + "@" + strconv.Itoa(e.Version)
+}
+
+// parsePresetName splits "name@version" into its parts. The version is 0
+// when name carries no suffix.
+func parsePresetName(name string) (base string, version int, err error) {
+	base, suffix, found := strings.Cut(name, "@")
+	if !found {
+		return name, 0, nil
+	}
+	version, err = strconv.Atoi(suffix)
+	if err != nil || version <= 0 {
+		return "", 0, fmt.Errorf("invalid preset version: %s", name)
+	}
+	return base, version, nil
+}
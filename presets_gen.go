@@ -0,0 +1,104 @@
+// Code generated by presetgen; DO NOT EDIT.
+
+package tokenestimate
+
+import "time"
+
+var generatedPresets = []fittedPreset{
+	{
+		name:        "cl100k-base",
+		description: "Preset fitted to 167 samples of cl100k-base.jsonl (~8.0% avg error)",
+		base:        "kimi-k2@11",
+		intercept:   0,
+		coefficients: map[string]float64{
+			"ArabicChars":          0.6850664551116067,
+			"ChineseChars":         1.1102907906845387,
+			"DigitRuns":            0,
+			"Digits":               0.6767404381653312,
+			"IdentifierBoundaries": 0.25079859322618014,
+			"JapaneseKana":         1.06989904373867,
+			"KoreanHangul":         1.0871647501982993,
+			"LatinExtended":        2.826974339666657,
+			"LatinLetters":         0.09931742020817651,
+			"MarkdownFences":       0,
+			"MarkdownHeadings":     0,
+			"MarkdownLinks":        0,
+			"MarkdownListItems":    3.8604586784759602,
+			"MarkdownTableRows":    0,
+			"RepeatedShingles":     0,
+			"RussianChars":         0.38981717028748614,
+			"Spaces":               0.03056049864997717,
+			"Symbols":              0.5926134693767712,
+			"Tabs":                 0.10642394445436368,
+			"WhitespaceRuns":       0.6457407096066093,
+			"Words":                0.6882969421187686,
+		},
+		residualP10: 0.87,
+		residualP90: 1.173,
+		accuracy: Accuracy{
+			Dataset:  "cl100k-base.jsonl",
+			Samples:  42,
+			MAPE:     0.08,
+			P90:      0.1659,
+			PassRate: 0.9286,
+			ByScript: map[string]float64{
+				"Chinese":  0.0805,
+				"Japanese": 0.0274,
+				"Korean":   0.033,
+				"Latin":    0.1002,
+				"Russian":  0.0272,
+				"Symbols":  0.2874,
+			},
+		},
+		trained: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC),
+		corpus:  "209 labeled texts of cl100k-base.jsonl",
+	},
+	{
+		name:        "o200k-base",
+		description: "Preset fitted to 167 samples of o200k-base.jsonl (~11.8% avg error)",
+		base:        "kimi-k2@11",
+		intercept:   0,
+		coefficients: map[string]float64{
+			"ArabicChars":          0.30346142600496173,
+			"ChineseChars":         0.869103234542182,
+			"DigitRuns":            0.02671631975737674,
+			"Digits":               0.8727775331727331,
+			"IdentifierBoundaries": 0.31823451222515686,
+			"JapaneseKana":         0.9068464075152004,
+			"KoreanHangul":         0.810276291800594,
+			"LatinExtended":        0.6496905791258553,
+			"LatinLetters":         0.19495872597139757,
+			"MarkdownFences":       0,
+			"MarkdownHeadings":     0,
+			"MarkdownLinks":        0,
+			"MarkdownListItems":    2.3013630033102097,
+			"MarkdownTableRows":    1.8916572011880843,
+			"RepeatedShingles":     2.6107462420862193,
+			"RussianChars":         0.2840175700111546,
+			"Spaces":               0,
+			"Symbols":              0.38028712857751007,
+			"Tabs":                 0,
+			"WhitespaceRuns":       1.7190850762575798,
+			"Words":                0.306041860521485,
+		},
+		residualP10: 0.872,
+		residualP90: 1.172,
+		accuracy: Accuracy{
+			Dataset:  "o200k-base.jsonl",
+			Samples:  42,
+			MAPE:     0.118,
+			P90:      0.1564,
+			PassRate: 0.9048,
+			ByScript: map[string]float64{
+				"Chinese":  0.1413,
+				"Japanese": 0.1126,
+				"Korean":   0.0567,
+				"Latin":    0.1234,
+				"Russian":  0.0785,
+				"Symbols":  0.1364,
+			},
+		},
+		trained: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC),
+		corpus:  "209 labeled texts of o200k-base.jsonl",
+	},
+}
@@ -0,0 +1,219 @@
+package tokenestimate
+
+import (
+	"bytes"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestVersionedPresets(t *testing.T) {
+	t.Run("Bare name resolves to latest version", func(t *testing.T) {
+		estimator, err := GetPresetByName("kimi-k2")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if estimator != KimiK2Estimator {
+			t.Error("Expected kimi-k2 to resolve to KimiK2Estimator")
+		}
+		for _, name := range ListPresets() {
+			if other, _ := lookupPreset(name); other.Name == "kimi-k2" && other.Version > estimator.Version {
+				t.Errorf("%s is newer than the bare name's version %d", name, estimator.Version)
+			}
+		}
+	})
+
+	t.Run("Pinned version", func(t *testing.T) {
+		SetLogger(nil)
+		defer logger.Store(nil)
+
+		estimator, err := NewEstimatorWithName("kimi-k2@1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if estimator.Version != 1 {
+			t.Errorf("Expected version 1, got %d", estimator.Version)
+		}
+		if estimator.Deprecated == "" {
+			t.Error("Expected kimi-k2@1 to be deprecated")
+		}
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		estimator, err := Latest("kimi-k2@1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if estimator != KimiK2Estimator {
+			t.Error("Expected Latest to return KimiK2Estimator")
+		}
+		if _, err := Latest("kimi-k2@x"); err == nil {
+			t.Error("Expected error for invalid version")
+		}
+	})
+
+	t.Run("Older version does not replace latest", func(t *testing.T) {
+		if err := RegisterPreset(&Estimator{Name: "versioned-test", Version: 3}); err != nil {
+			t.Fatal(err)
+		}
+		if err := RegisterPreset(&Estimator{Name: "versioned-test", Version: 2}); err != nil {
+			t.Fatal(err)
+		}
+
+		estimator, err := GetPresetByName("versioned-test")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if estimator.Version != 3 {
+			t.Errorf("Expected version 3, got %d", estimator.Version)
+		}
+		if _, err := GetPresetByName("versioned-test@2"); err != nil {
+			t.Errorf("Expected versioned-test@2 to be registered: %v", err)
+		}
+	})
+
+	t.Run("Deprecation warning is logged once", func(t *testing.T) {
+		var buf bytes.Buffer
+		SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+		defer logger.Store(nil)
+
+		if err := RegisterPreset(&Estimator{Name: "deprecated-test", Version: 1, Deprecated: "use something else"}); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := GetPresetByName("deprecated-test@1"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+
+		if n := strings.Count(buf.String(), "deprecated preset"); n != 1 {
+			t.Errorf("Expected 1 warning, got %d: %s", n, buf.String())
+		}
+		if !strings.Contains(buf.String(), "use something else") {
+			t.Errorf("Expected reason in warning, got %s", buf.String())
+		}
+	})
+}
+
+func TestUpperBoundPresets(t *testing.T) {
+	pairs := map[string]string{
+		"kimi-k2":          "kimi-k2-p90",
+		"kimi-k2-code":     "kimi-k2-code-p90",
+		"kimi-k2-markdown": "kimi-k2-markdown-p90",
+		"kimi-k2-json":     "kimi-k2-json-p90",
+	}
+	for name, upper := range pairs {
+		mean, err := NewEstimatorWithName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bound, err := NewEstimatorWithName(upper)
+		if err != nil {
+			t.Fatalf("Expected preset %s: %v", upper, err)
+		}
+		for _, text := range ReferenceTexts() {
+			got, floor := bound.Estimate(text), mean.Estimate(text)
+			if got < floor {
+				t.Errorf("%s: Estimate(%q) = %d, below the %d of %s", upper, text, got, floor, name)
+			}
+			// The bound is the top of its own range.
+			if _, high := bound.EstimateRange(text); high != got {
+				t.Errorf("%s: expected the range of %q to end at %d, got %d", upper, text, got, high)
+			}
+		}
+	}
+
+	// Without dictionary words the whole estimate scales.
+	text := "Кошка спит на подоконнике, а собака во дворе."
+	mean := KimiK2Estimator.WithDictionary(false)
+	want := int(math.Ceil(mean.EstimateFloat(text) * mean.residualP90))
+	if got := KimiK2P90Estimator.WithDictionary(false).Estimate(text); got != want {
+		t.Errorf("Expected %d tokens, got %d", want, got)
+	}
+
+	if got := KimiK2P90Estimator.WithContentType(ContentCode).Name; got != "kimi-k2-code-p90" {
+		t.Errorf("Expected the code variant of kimi-k2-p90 to be kimi-k2-code-p90, got %s", got)
+	}
+}
+
+func TestRegisterAlias(t *testing.T) {
+	t.Cleanup(func() {
+		presetsMu.Lock()
+		defer presetsMu.Unlock()
+		for _, alias := range []string{"moonshot-test", "pinned-test", "chained-test"} {
+			delete(aliases, alias)
+		}
+	})
+
+	if err := RegisterAlias("moonshot-test", "kimi-k2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := RegisterAlias("pinned-test", "kimi-k2@1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := RegisterAlias("chained-test", "moonshot-test"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	SetLogger(nil)
+	defer logger.Store(nil)
+	for alias, want := range map[string]*Estimator{
+		"moonshot-test": KimiK2Estimator,
+		"pinned-test":   kimiK2V1,
+		"chained-test":  KimiK2Estimator,
+	} {
+		if e, err := GetPresetByName(alias); err != nil || e != want {
+			t.Errorf("%s: expected %s, got %v, %v", alias, want.Key(), e, err)
+		}
+	}
+	if e, err := Latest("pinned-test"); err != nil || e != KimiK2Estimator {
+		t.Errorf("Expected Latest to resolve the alias to the latest version, got %v", err)
+	}
+
+	for _, info := range ListPresetsDetailed() {
+		if info.Name == "kimi-k2@12" && strings.Join(info.Aliases, ",") != "chained-test,moonshot-test" {
+			t.Errorf("Expected the aliases of kimi-k2@12, got %v", info.Aliases)
+		}
+	}
+
+	tests := map[[2]string]string{
+		{"", "kimi-k2"}:             "empty alias",
+		{"kimi-k2-code", "kimi-k2"}: "alias is the name of a preset: kimi-k2-code",
+		{"other-test", "nope"}:      "unknown preset: nope",
+	}
+	for args, want := range tests {
+		if err := RegisterAlias(args[0], args[1]); err == nil || err.Error() != want {
+			t.Errorf("RegisterAlias(%q, %q): expected %q, got %v", args[0], args[1], want, err)
+		}
+	}
+}
+
+func TestSetDefaultPreset(t *testing.T) {
+	t.Cleanup(func() { SetDefaultPreset("") })
+
+	if err := SetDefaultPreset("nope"); err == nil || err.Error() != "unknown preset: nope" {
+		t.Errorf("Expected an error for an unknown preset, got %v", err)
+	}
+	if e := NewEstimator(); e.Key() != KimiK2Estimator.Key() {
+		t.Errorf("Expected the failed call to keep the default, got %s", e.Key())
+	}
+
+	if err := SetDefaultPreset("kimi-k2-code"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	e := NewEstimator()
+	if e.Key() != KimiK2CodeEstimator.Key() || e == KimiK2CodeEstimator {
+		t.Errorf("Expected a clone of %s, got %s", KimiK2CodeEstimator.Key(), e.Key())
+	}
+	if b, err := NewBuilder("default-test").Build(); err != nil || b.coefLatinLetters != KimiK2CodeEstimator.coefLatinLetters {
+		t.Errorf("Expected the builder to start from the default preset, got %v", err)
+	}
+
+	if err := SetDefaultPreset(""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e := NewEstimator(); e.Key() != KimiK2Estimator.Key() {
+		t.Errorf("Expected an empty name to restore %s, got %s", KimiK2Estimator.Key(), e.Key())
+	}
+}
@@ -0,0 +1,204 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LanguageProfile describes the character mix of a kind of text as the
+// share of each category among its characters. The shares need not sum to
+// one; they are taken relative to their sum.
+type LanguageProfile struct {
+	Latin         float64 // ASCII letters
+	LatinExtended float64 // Accented and other non-ASCII Latin letters
+	Digits        float64
+	Symbols       float64 // Punctuation and other symbols
+	Whitespace    float64 // Spaces, tabs and line breaks
+	Chinese       float64
+	Japanese      float64 // Hiragana and katakana
+	Korean        float64
+	Russian       float64 // Cyrillic
+	Arabic        float64
+	Khmer         float64
+	Lao           float64
+	Myanmar       float64
+	Ethiopic      float64
+	Unknown       float64 // Private-use and unassigned characters and invalid bytes
+
+	// AvgWordLength is the average number of non-whitespace characters per
+	// word. If zero, every whitespace character is taken to end a word.
+	AvgWordLength float64
+
+	// AvgDigitRunLength is the average number of digits per number. If
+	// zero, numbers are taken to have three digits.
+	AvgDigitRunLength float64
+}
+
+// defaultDigitRunLength is the number length assumed by a LanguageProfile
+// without AvgDigitRunLength.
+const defaultDigitRunLength = 3
+
+// plainEstimator analyzes text without preset-specific detectors such as
+// dictionaries, blobs or special tokens, so that every character is
+// counted in its category.
+var plainEstimator = &Estimator{}
+
+// ProfileOf returns the language profile of text, a compact description
+// of what kind of text it is. The profile of a sample can stand in for
+// the text it was taken from, as in CharsForTokens.
+func ProfileOf(text string) LanguageProfile {
+	return profileOfStats(plainEstimator.Analyze(text))
+}
+
+// profileOfStats returns the profile of a text with the statistics s.
+func profileOfStats(s Stats) LanguageProfile {
+	return LanguageProfile{
+		Latin:             float64(s.LatinLetters),
+		LatinExtended:     float64(s.LatinExtended),
+		Digits:            float64(s.Digits),
+		Symbols:           float64(s.Symbols),
+		Whitespace:        float64(s.Spaces + s.Tabs),
+		Chinese:           float64(s.ChineseChars),
+		Japanese:          float64(s.JapaneseKana),
+		Korean:            float64(s.KoreanHangul),
+		Russian:           float64(s.RussianChars),
+		Arabic:            float64(s.ArabicChars),
+		Khmer:             float64(s.KhmerChars),
+		Lao:               float64(s.LaoChars),
+		Myanmar:           float64(s.MyanmarChars),
+		Ethiopic:          float64(s.EthiopicChars),
+		Unknown:           float64(s.Unknown + s.InvalidBytes),
+		AvgWordLength:     s.AvgWordLength(),
+		AvgDigitRunLength: s.AvgDigitRunLength(),
+	}.normalize()
+}
+
+// profileShare names one share of a LanguageProfile.
+type profileShare struct {
+	name  string
+	share *float64
+}
+
+// shares lists the shares of p in the order of the struct fields.
+func (p *LanguageProfile) shares() []profileShare {
+	return []profileShare{
+		{"Latin", &p.Latin}, {"LatinExtended", &p.LatinExtended},
+		{"Digits", &p.Digits}, {"Symbols", &p.Symbols},
+		{"Whitespace", &p.Whitespace}, {"Chinese", &p.Chinese},
+		{"Japanese", &p.Japanese}, {"Korean", &p.Korean},
+		{"Russian", &p.Russian}, {"Arabic", &p.Arabic},
+		{"Khmer", &p.Khmer}, {"Lao", &p.Lao},
+		{"Myanmar", &p.Myanmar}, {"Ethiopic", &p.Ethiopic},
+		{"Unknown", &p.Unknown},
+	}
+}
+
+// total returns the sum of the shares.
+func (p LanguageProfile) total() float64 {
+	total := 0.0
+	for _, s := range p.shares() {
+		total += *s.share
+	}
+	return total
+}
+
+// normalize scales the shares to sum to one. A profile without shares is
+// returned unchanged.
+func (p LanguageProfile) normalize() LanguageProfile {
+	total := p.total()
+	if total <= 0 {
+		return p
+	}
+	for _, s := range p.shares() {
+		*s.share /= total
+	}
+	return p
+}
+
+// Dominant returns the name of the largest share, such as "Latin" or
+// "Chinese", or "" for a profile without shares. Whitespace and symbols
+// are skipped unless the profile has nothing else.
+func (p LanguageProfile) Dominant() string {
+	best, fallback := "", ""
+	var bestShare, fallbackShare float64
+	for _, s := range p.shares() {
+		switch {
+		case s.name == "Whitespace" || s.name == "Symbols":
+			if *s.share > fallbackShare {
+				fallback, fallbackShare = s.name, *s.share
+			}
+		case *s.share > bestShare:
+			best, bestShare = s.name, *s.share
+		}
+	}
+	if best == "" {
+		return fallback
+	}
+	return best
+}
+
+// Confidence returns the confidence EstimateWithConfidence reports for
+// text with the profile.
+func (p LanguageProfile) Confidence() float64 {
+	return p.stats(profileConfidenceChars).confidence()
+}
+
+// profileConfidenceChars is the length of the synthetic text Confidence
+// scores; shares are resolved to a tenth of a percent.
+const profileConfidenceChars = 1000
+
+// String formats the nonzero shares as percentages in decreasing order,
+// for example "Latin 80%, Whitespace 17%, Symbols 3%".
+func (p LanguageProfile) String() string {
+	p = p.normalize()
+	shares := p.shares()
+	sort.SliceStable(shares, func(i, j int) bool { return *shares[i].share > *shares[j].share })
+
+	var b strings.Builder
+	for _, s := range shares {
+		if *s.share <= 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s %.0f%%", s.name, *s.share*100)
+	}
+	return b.String()
+}
+
+// stats returns the statistics of n characters of text with the profile.
+func (p LanguageProfile) stats(n int) Stats {
+	p = p.normalize()
+	count := func(share float64) int { return int(share*float64(n) + 0.5) }
+
+	s := Stats{
+		LatinLetters:  count(p.Latin),
+		LatinExtended: count(p.LatinExtended),
+		Digits:        count(p.Digits),
+		Symbols:       count(p.Symbols),
+		Spaces:        count(p.Whitespace),
+		ChineseChars:  count(p.Chinese),
+		JapaneseKana:  count(p.Japanese),
+		KoreanHangul:  count(p.Korean),
+		RussianChars:  count(p.Russian),
+		ArabicChars:   count(p.Arabic),
+		KhmerChars:    count(p.Khmer),
+		LaoChars:      count(p.Lao),
+		MyanmarChars:  count(p.Myanmar),
+		EthiopicChars: count(p.Ethiopic),
+		Unknown:       count(p.Unknown),
+	}
+	if p.AvgWordLength > 0 {
+		s.Words = int(float64(s.chars()-s.Spaces)/p.AvgWordLength + 0.5)
+	} else {
+		s.Words = s.Spaces
+	}
+	runLength := p.AvgDigitRunLength
+	if runLength <= 0 {
+		runLength = defaultDigitRunLength
+	}
+	s.DigitRuns = int(float64(s.Digits)/runLength + 0.5)
+	return s
+}
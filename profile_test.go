@@ -0,0 +1,69 @@
+package tokenestimate
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProfileOf(t *testing.T) {
+	t.Run("Shares", func(t *testing.T) {
+		p := ProfileOf("ab 12 你好!")
+		// 9 characters: 2 Latin, 2 digits, 2 spaces, 2 Chinese and 1 symbol
+		want := LanguageProfile{Latin: 2.0 / 9, Digits: 2.0 / 9, Whitespace: 2.0 / 9, Chinese: 2.0 / 9, Symbols: 1.0 / 9}
+		for i, s := range p.shares() {
+			if w := *want.shares()[i].share; math.Abs(*s.share-w) > 1e-9 {
+				t.Errorf("%s share = %.3f, want %.3f", s.name, *s.share, w)
+			}
+		}
+		if math.Abs(p.total()-1) > 1e-9 {
+			t.Errorf("Shares sum to %f, want 1", p.total())
+		}
+		if p.AvgDigitRunLength != 2 {
+			t.Errorf("AvgDigitRunLength = %f, want 2", p.AvgDigitRunLength)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		if p := ProfileOf(""); p.total() != 0 || p.String() != "" || p.Dominant() != "" {
+			t.Errorf("ProfileOf(\"\") = %+v, want an empty profile", p)
+		}
+	})
+}
+
+func TestLanguageProfileDominant(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"The quick brown fox jumps over the lazy dog.", "Latin"},
+		{"你好，世界！这是一个测试。", "Chinese"},
+		{"Привет, мир!", "Russian"},
+		{"!!! ... ???", "Symbols"},
+	}
+	for _, tt := range tests {
+		if got := ProfileOf(tt.text).Dominant(); got != tt.want {
+			t.Errorf("ProfileOf(%q).Dominant() = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLanguageProfileConfidence(t *testing.T) {
+	texts := []string{
+		"The quick brown fox jumps over the lazy dog.",
+		"Hello 世界! 123 こんにちは 안녕 Привет مرحبا café",
+		"ភាសាខ្មែរជាភាសាផ្លូវការរបស់ប្រទេសកម្ពុជា",
+	}
+	for _, text := range texts {
+		_, want := NewEstimator().EstimateWithConfidence(text)
+		if got := ProfileOf(text).Confidence(); math.Abs(got-want) > 0.02 {
+			t.Errorf("ProfileOf(%q).Confidence() = %.3f, want about %.3f", text, got, want)
+		}
+	}
+}
+
+func TestLanguageProfileString(t *testing.T) {
+	p := LanguageProfile{Latin: 8, Whitespace: 1.5, Symbols: 0.5}
+	if got, want := p.String(), "Latin 80%, Whitespace 15%, Symbols 5%"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
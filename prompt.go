@@ -0,0 +1,131 @@
+package tokenestimate
+
+import (
+	"sort"
+	"strings"
+)
+
+// SectionTruncation selects how PromptBuilder may shorten a section that
+// does not fit.
+type SectionTruncation int
+
+const (
+	// TruncateNone keeps the section whole or drops it.
+	TruncateNone SectionTruncation = iota
+	// TruncateEnd keeps the start of the section, for documents whose
+	// opening matters most.
+	TruncateEnd
+	// TruncateStart keeps the end of the section, for histories whose most
+	// recent part matters most.
+	TruncateStart
+)
+
+// promptSection is one section added to a PromptBuilder.
+type promptSection struct {
+	text       string
+	priority   int
+	truncation SectionTruncation
+}
+
+// PromptBuilder assembles a prompt from sections that are dropped or
+// truncated by priority to fit a token budget, such as a system prompt,
+// retrieved documents and a question. Sections appear in the prompt in the
+// order they were added, joined by Separator. A PromptBuilder is not safe
+// for concurrent use.
+type PromptBuilder struct {
+	// Separator is placed between sections (default: a blank line)
+	Separator string
+
+	e        *Estimator
+	sections []promptSection
+}
+
+// NewPromptBuilder returns an empty PromptBuilder using the given
+// estimator. If e is nil, the default estimator is used.
+func NewPromptBuilder(e *Estimator) *PromptBuilder {
+	if e == nil {
+		e = NewEstimator()
+	}
+	return &PromptBuilder{Separator: "\n\n", e: e}
+}
+
+// Add appends a section with the given priority and truncation rule and
+// returns the builder. Sections with a lower priority are shortened first;
+// among sections of equal priority, the one added last goes first.
+func (b *PromptBuilder) Add(text string, priority int, truncation SectionTruncation) *PromptBuilder {
+	b.sections = append(b.sections, promptSection{text: text, priority: priority, truncation: truncation})
+	return b
+}
+
+// Build returns the largest prompt whose estimate fits in budget. Sections
+// are considered from the lowest priority up: each is dropped, or cut
+// according to its truncation rule to the tokens left over, until the rest
+// of the prompt fits, and higher-priority sections are left untouched. If
+// no section fits, Build returns the empty string. The builder itself is
+// not modified, so it can be built again for another budget. The anomaly
+// hooks run on the result only.
+func (b *PromptBuilder) Build(budget int) string {
+	prompt := b.build(budget)
+	b.e.runAnomalyHooks(prompt)
+	return prompt
+}
+
+// build implements Build without running the anomaly hooks.
+func (b *PromptBuilder) build(budget int) string {
+	texts := make([]string, len(b.sections))
+	for i, s := range b.sections {
+		texts[i] = s.text
+	}
+	prompt := b.join(texts)
+	if b.e.estimateQuietly(prompt) <= budget {
+		return prompt
+	}
+
+	order := make([]int, len(b.sections))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(x, y int) bool {
+		px, py := b.sections[order[x]].priority, b.sections[order[y]].priority
+		if px != py {
+			return px < py
+		}
+		return order[x] > order[y]
+	})
+
+	for _, i := range order {
+		section := b.sections[i]
+		texts[i] = ""
+		rest := b.e.estimateQuietly(b.join(texts))
+		if rest > budget {
+			continue
+		}
+
+		for available := budget - rest; available > 0 && section.truncation != TruncateNone; {
+			if section.truncation == TruncateEnd {
+				texts[i] = b.e.truncate(section.text, available)
+			} else {
+				texts[i] = b.e.truncateStart(section.text, available)
+			}
+			over := b.e.estimateQuietly(b.join(texts)) - budget
+			if over <= 0 {
+				break
+			}
+			available -= over
+			texts[i] = ""
+		}
+		return b.join(texts)
+	}
+	return ""
+}
+
+// join joins the non-empty texts with the separator.
+func (b *PromptBuilder) join(texts []string) string {
+	parts := make([]string, 0, len(texts))
+	for _, text := range texts {
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, b.Separator)
+}
@@ -0,0 +1,89 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPromptBuilder(t *testing.T) {
+	estimator := NewEstimator()
+	system := "You are a helpful assistant. Answer using the documents."
+	doc1 := strings.Repeat("Paris is the capital and largest city of France. ", 10)
+	doc2 := strings.Repeat("Lyon is the third-largest city of France. ", 10)
+	question := "What is the capital of France?"
+
+	newBuilder := func() *PromptBuilder {
+		return NewPromptBuilder(estimator).
+			Add(system, 10, TruncateNone).
+			Add(doc1, 1, TruncateEnd).
+			Add(doc2, 1, TruncateEnd).
+			Add(question, 10, TruncateNone)
+	}
+	full := strings.Join([]string{system, doc1, doc2, question}, "\n\n")
+
+	t.Run("Fits unchanged", func(t *testing.T) {
+		if got := newBuilder().Build(1000); got != full {
+			t.Errorf("Expected the full prompt, got %q", got)
+		}
+	})
+
+	t.Run("Shortens the last low-priority section", func(t *testing.T) {
+		budget := estimator.Estimate(full) - 20
+		got := newBuilder().Build(budget)
+		if tokens := estimator.Estimate(got); tokens > budget {
+			t.Errorf("Prompt has %d tokens, budget %d", tokens, budget)
+		}
+		if !strings.Contains(got, doc1) {
+			t.Error("Expected the first document to be kept whole")
+		}
+		if strings.Contains(got, doc2) || !strings.Contains(got, doc2[:40]) {
+			t.Error("Expected the second document to be truncated")
+		}
+		if !strings.HasPrefix(got, system) || !strings.HasSuffix(got, question) {
+			t.Error("Expected high-priority sections to be kept")
+		}
+	})
+
+	t.Run("Drops sections that do not help", func(t *testing.T) {
+		budget := estimator.Estimate(system + "\n\n" + question + "\n\n" + doc1[:50])
+		got := newBuilder().Build(budget)
+		if tokens := estimator.Estimate(got); tokens > budget {
+			t.Errorf("Prompt has %d tokens, budget %d", tokens, budget)
+		}
+		if strings.Contains(got, "Lyon") {
+			t.Error("Expected the second document to be dropped")
+		}
+		if !strings.Contains(got, "Paris") {
+			t.Error("Expected part of the first document to be kept")
+		}
+	})
+
+	t.Run("Untruncatable section is dropped", func(t *testing.T) {
+		b := NewPromptBuilder(estimator).Add(system, 10, TruncateNone).Add(doc1, 1, TruncateNone)
+		if got := b.Build(estimator.Estimate(system) + 5); got != system {
+			t.Errorf("Expected only the system prompt, got %q", got)
+		}
+	})
+
+	t.Run("Truncate start", func(t *testing.T) {
+		b := NewPromptBuilder(estimator).Add(doc1, 1, TruncateStart)
+		got := b.Build(20)
+		if !strings.HasSuffix(doc1, got) || got == "" || estimator.Estimate(got) > 20 {
+			t.Errorf("Expected a fitting suffix, got %q", got)
+		}
+	})
+
+	t.Run("Nothing fits", func(t *testing.T) {
+		if got := newBuilder().Build(1); got != "" {
+			t.Errorf("Expected an empty prompt, got %q", got)
+		}
+	})
+
+	t.Run("Rebuild", func(t *testing.T) {
+		b := newBuilder()
+		b.Build(10)
+		if got := b.Build(1000); got != full {
+			t.Error("Expected Build not to modify the builder")
+		}
+	})
+}
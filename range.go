@@ -0,0 +1,29 @@
+package tokenestimate
+
+import "math"
+
+// EstimateRange returns an interval that the actual token count of text is
+// expected to fall in. The interval spans the 10th to the 90th percentile of
+// the preset's residuals, that is the ratio of actual to estimated tokens.
+// For presets fitted by presetgen, such as cl100k-base, they are measured
+// on held-out samples, so about 80% of texts land inside the interval. For
+// the kimi-k2 presets they are heuristics, not measured on Kimi-K2 counts,
+// and the share of texts inside is unknown. The point estimate from
+// Estimate always lies within [low, high]. Estimators without residual
+// data, such as hand-built ones, return low == high.
+func (e *Estimator) EstimateRange(text string) (low, high int) {
+	return e.rangeOf(e.Estimate(text))
+}
+
+// rangeOf scales a point estimate by the residual quantiles, rounding
+// outwards.
+func (e *Estimator) rangeOf(tokens int) (low, high int) {
+	low, high = tokens, tokens
+	if e.residualP10 > 0 {
+		low = min(int(math.Floor(float64(tokens)*e.residualP10)), tokens)
+	}
+	if e.residualP90 > 0 {
+		high = max(int(math.Ceil(float64(tokens)*e.residualP90)), tokens)
+	}
+	return low, high
+}
@@ -0,0 +1,56 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateRange(t *testing.T) {
+	estimator := NewEstimator()
+
+	t.Run("Contains point estimate", func(t *testing.T) {
+		for _, text := range referenceTexts {
+			tokens := estimator.Estimate(text)
+			low, high := estimator.EstimateRange(text)
+			if low > tokens || high < tokens {
+				t.Errorf("EstimateRange(%q) = [%d, %d], does not contain %d", text, low, high, tokens)
+			}
+		}
+	})
+
+	t.Run("Scales with length", func(t *testing.T) {
+		text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 100)
+		tokens := estimator.Estimate(text)
+		low, high := estimator.EstimateRange(text)
+		if low >= tokens || high <= tokens {
+			t.Errorf("Expected a non-trivial interval around %d, got [%d, %d]", tokens, low, high)
+		}
+		if float64(high-low) > 0.4*float64(tokens) {
+			t.Errorf("Interval [%d, %d] is too wide for %d tokens", low, high, tokens)
+		}
+	})
+
+	t.Run("Empty text", func(t *testing.T) {
+		if low, high := estimator.EstimateRange(""); low != 0 || high != 0 {
+			t.Errorf("Expected [0, 0], got [%d, %d]", low, high)
+		}
+	})
+
+	t.Run("Variants are wider", func(t *testing.T) {
+		text := strings.Repeat("func main() { fmt.Println(x) }\n", 50)
+		low, high := estimator.EstimateRange(text)
+		codeLow, codeHigh := estimator.WithContentType(ContentCode).EstimateRange(text)
+		tokens := estimator.WithContentType(ContentCode).Estimate(text)
+		if codeHigh-codeLow <= 0 || float64(codeHigh-codeLow)/float64(tokens) <= float64(high-low)/float64(estimator.Estimate(text)) {
+			t.Errorf("Expected code interval [%d, %d] to be relatively wider than [%d, %d]", codeLow, codeHigh, low, high)
+		}
+	})
+
+	t.Run("No residual data", func(t *testing.T) {
+		custom := &Estimator{coefLatinLetters: 0.25}
+		tokens := custom.Estimate("hello world")
+		if low, high := custom.EstimateRange("hello world"); low != tokens || high != tokens {
+			t.Errorf("Expected [%d, %d], got [%d, %d]", tokens, tokens, low, high)
+		}
+	})
+}
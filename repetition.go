@@ -0,0 +1,84 @@
+package tokenestimate
+
+import "sync"
+
+// shingleWords is the number of consecutive words in a shingle.
+const shingleWords = 4
+
+// repetitionScanLimit bounds how many bytes scanRepetition reads; counts
+// for longer texts are scaled up from this prefix.
+const repetitionScanLimit = 64 << 10
+
+// shingleSets recycles the hash sets of scanRepetition, so that analysis
+// does not allocate once the pool is warm.
+var shingleSets = sync.Pool{
+	New: func() any { return make(map[uint64]struct{}) },
+}
+
+// scanRepetition counts the word shingles of text and how many of them
+// repeat an earlier shingle. Repeated boilerplate and log lines have a high
+// duplication rate, unique prose a rate near zero.
+func scanRepetition(text string) (shingles, repeated int) {
+	sample := text
+	if len(sample) > repetitionScanLimit {
+		sample = sample[:repetitionScanLimit]
+		// Drop the word cut in half by the limit.
+		for len(sample) > 0 && !isASCIISpace(sample[len(sample)-1]) {
+			sample = sample[:len(sample)-1]
+		}
+	}
+
+	const (
+		fnvOffset = 14695981039346656037
+		fnvPrime  = 1099511628211
+	)
+	var window [shingleWords]uint64
+	words := 0
+	seen := shingleSets.Get().(map[uint64]struct{})
+	defer func() {
+		clear(seen)
+		shingleSets.Put(seen)
+	}()
+
+	word, inWord := uint64(fnvOffset), false
+	for i := 0; i <= len(sample); i++ {
+		if i < len(sample) && !isASCIISpace(sample[i]) {
+			word = (word ^ uint64(sample[i])) * fnvPrime
+			inWord = true
+			continue
+		}
+		if !inWord {
+			continue
+		}
+
+		window[words%shingleWords] = word
+		words++
+		word, inWord = fnvOffset, false
+		if words < shingleWords {
+			continue
+		}
+
+		var shingle uint64 = fnvOffset
+		for j := words - shingleWords; j < words; j++ {
+			shingle = (shingle ^ window[j%shingleWords]) * fnvPrime
+		}
+		shingles++
+		if _, ok := seen[shingle]; ok {
+			repeated++
+		} else {
+			seen[shingle] = struct{}{}
+		}
+	}
+
+	if len(sample) < len(text) && len(sample) > 0 {
+		scale := float64(len(text)) / float64(len(sample))
+		shingles = int(float64(shingles)*scale + 0.5)
+		repeated = int(float64(repeated)*scale + 0.5)
+	}
+	return shingles, repeated
+}
+
+// isASCIISpace reports whether c is an ASCII whitespace byte.
+func isASCIISpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
@@ -0,0 +1,80 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanRepetition(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		shingles int
+		repeated int
+	}{
+		{name: "Too few words", text: "one two three", shingles: 0, repeated: 0},
+		{name: "Unique words", text: "one two three four five six", shingles: 3, repeated: 0},
+		{name: "Repeated line", text: "GET /health 200 OK\nGET /health 200 OK\n", shingles: 5, repeated: 1},
+		{name: "Whitespace is ignored", text: "a b c d\n\n  a\tb c d", shingles: 5, repeated: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shingles, repeated := scanRepetition(tt.text)
+			if shingles != tt.shingles || repeated != tt.repeated {
+				t.Errorf("scanRepetition(%q) = %d, %d; want %d, %d", tt.text, shingles, repeated, tt.shingles, tt.repeated)
+			}
+		})
+	}
+
+	t.Run("Long text is scaled from a prefix", func(t *testing.T) {
+		text := strings.Repeat("lorem ipsum dolor sit ", repetitionScanLimit/11)
+		shingles, repeated := scanRepetition(text)
+		words := len(strings.Fields(text))
+		if diff := shingles - (words - shingleWords + 1); diff > words/100 || diff < -words/100 {
+			t.Errorf("Expected about %d shingles, got %d", words-shingleWords+1, shingles)
+		}
+		if repeated < shingles*99/100 {
+			t.Errorf("Expected nearly all of %d shingles repeated, got %d", shingles, repeated)
+		}
+	})
+}
+
+func TestRepetitionFeature(t *testing.T) {
+	line := "2024-01-15T10:00:00Z INFO worker started job queue=default attempt=1\n"
+	logs := strings.Repeat(line, 50)
+
+	t.Run("Repeated templates are discounted", func(t *testing.T) {
+		pinned, _ := NewEstimatorWithName("kimi-k2@8")
+		if got, prev := kimiK2V11.Estimate(logs), pinned.Estimate(logs); got >= prev {
+			t.Errorf("Expected fewer than kimi-k2@8's %d tokens, got %d", prev, got)
+		}
+	})
+
+	t.Run("Unique text is unchanged", func(t *testing.T) {
+		text := "The quick brown fox jumps over the lazy dog."
+		pinned, _ := NewEstimatorWithName("kimi-k2@8")
+		if got, want := kimiK2V11.Estimate(text), pinned.Estimate(text); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Latest version does not discount", func(t *testing.T) {
+		pinned, _ := NewEstimatorWithName("kimi-k2@8")
+		if got, want := NewEstimator().Estimate(logs), pinned.Estimate(logs); got != want {
+			t.Errorf("Expected kimi-k2@8's %d tokens, got %d", want, got)
+		}
+		if stats := NewEstimator().Analyze(logs); stats.Shingles != 0 {
+			t.Errorf("Expected no shingles counted, got %d", stats.Shingles)
+		}
+	})
+
+	t.Run("DuplicationRate", func(t *testing.T) {
+		if rate := kimiK2V11.Analyze(logs).DuplicationRate(); rate < 0.9 {
+			t.Errorf("Expected duplication rate above 0.9, got %f", rate)
+		}
+		if rate := (Stats{}).DuplicationRate(); rate != 0 {
+			t.Errorf("Expected 0 for unmeasured stats, got %f", rate)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package tokenestimate
+
+import (
+	"math"
+	"unicode/utf8"
+)
+
+// maxCharsForTokens bounds the search of charsFittingTokens, within int on
+// 32-bit platforms.
+const maxCharsForTokens = min(1<<40, math.MaxInt/2)
+
+// CharsForTokens returns about how many characters of text with the given
+// language profile fit in tokens, inverting the model, so that a UI can
+// show "about N characters remaining" for a token budget. English prose
+// fits about four characters per token and Chinese about one and a half.
+// It returns 0 for a profile without shares.
+func (e *Estimator) CharsForTokens(tokens int, profile LanguageProfile) int {
+	if profile.total() <= 0 {
+		return 0
+	}
+	return e.charsFittingTokens(tokens, profile.stats)
+}
+
+// ApproxCharsForTokens is like CharsForTokens for text like sample. The
+// sample's own statistics are extrapolated, so it should be long enough to
+// be representative, such as the text typed so far.
+func (e *Estimator) ApproxCharsForTokens(tokens int, sample string) int {
+	stats := e.Analyze(sample)
+	chars := utf8.RuneCountInString(sample)
+	if chars == 0 {
+		return 0
+	}
+	return e.charsFittingTokens(tokens, func(n int) Stats {
+		return stats.Scale(float64(n) / float64(chars))
+	})
+}
+
+// charsFittingTokens returns the largest n whose statistics, as given by
+// statsOf, are estimated at no more than tokens.
+func (e *Estimator) charsFittingTokens(tokens int, statsOf func(n int) Stats) int {
+	fits := func(n int) bool {
+		return e.roundTokens(e.calculateTokenCount(statsOf(n))) <= tokens
+	}
+	if !fits(0) {
+		return 0
+	}
+
+	hi := 1
+	for hi < maxCharsForTokens && fits(hi) {
+		hi *= 2
+	}
+	lo := hi / 2 // fits(lo) holds, fits(hi) does not unless hi hit the bound
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	if fits(hi) {
+		return hi
+	}
+	return lo
+}
@@ -0,0 +1,76 @@
+package tokenestimate
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCharsForTokens(t *testing.T) {
+	estimator := NewEstimator()
+	english := LanguageProfile{Latin: 0.8, Whitespace: 0.17, Symbols: 0.03, AvgWordLength: 5}
+	chinese := LanguageProfile{Chinese: 0.9, Symbols: 0.1}
+
+	t.Run("Inverts the model", func(t *testing.T) {
+		for _, profile := range []LanguageProfile{english, chinese} {
+			for _, tokens := range []int{1, 10, 100, 4096} {
+				n := estimator.CharsForTokens(tokens, profile)
+				if got := estimator.roundTokens(estimator.calculateTokenCount(profile.stats(n))); got > tokens {
+					t.Errorf("CharsForTokens(%d) = %d, which is estimated at %d tokens", tokens, n, got)
+				}
+				if got := estimator.roundTokens(estimator.calculateTokenCount(profile.stats(n + 1))); got <= tokens {
+					t.Errorf("CharsForTokens(%d) = %d, but %d characters also fit", tokens, n, n+1)
+				}
+			}
+		}
+	})
+
+	t.Run("Script density", func(t *testing.T) {
+		latin := estimator.CharsForTokens(1000, english)
+		cjk := estimator.CharsForTokens(1000, chinese)
+		if latin <= 2*cjk {
+			t.Errorf("Expected far more English than Chinese characters per token, got %d and %d", latin, cjk)
+		}
+	})
+
+	t.Run("Normalizes shares", func(t *testing.T) {
+		scaled := english
+		scaled.Latin, scaled.Whitespace, scaled.Symbols = 80, 17, 3
+		if got, want := estimator.CharsForTokens(500, scaled), estimator.CharsForTokens(500, english); got != want {
+			t.Errorf("CharsForTokens with scaled shares = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Edge cases", func(t *testing.T) {
+		if got := estimator.CharsForTokens(100, LanguageProfile{}); got != 0 {
+			t.Errorf("CharsForTokens with an empty profile = %d, want 0", got)
+		}
+		if got := estimator.CharsForTokens(-1, english); got != 0 {
+			t.Errorf("CharsForTokens(-1) = %d, want 0", got)
+		}
+	})
+}
+
+func TestApproxCharsForTokens(t *testing.T) {
+	estimator := NewEstimator()
+	sample := "The quick brown fox jumps over the lazy dog. "
+
+	t.Run("Round trips the sample", func(t *testing.T) {
+		tokens := estimator.Estimate(sample)
+		chars := utf8.RuneCountInString(sample)
+		if got := estimator.ApproxCharsForTokens(tokens, sample); got < chars*9/10 || got > chars*11/10 {
+			t.Errorf("ApproxCharsForTokens(%d) = %d, want about %d", tokens, got, chars)
+		}
+	})
+
+	t.Run("Agrees with profile", func(t *testing.T) {
+		got := estimator.ApproxCharsForTokens(1000, sample)
+		want := estimator.CharsForTokens(1000, ProfileOf(sample))
+		if got < want*9/10 || got > want*11/10 {
+			t.Errorf("ApproxCharsForTokens = %d, CharsForTokens(ProfileOf) = %d", got, want)
+		}
+	})
+
+	if got := estimator.ApproxCharsForTokens(1000, ""); got != 0 {
+		t.Errorf("ApproxCharsForTokens with an empty sample = %d, want 0", got)
+	}
+}
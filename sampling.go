@@ -0,0 +1,281 @@
+package tokenestimate
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"unicode/utf8"
+)
+
+// SamplingStrategy selects where sampling mode takes its samples.
+type SamplingStrategy int
+
+const (
+	// SamplingStride takes samples at evenly spaced positions. It is
+	// deterministic, but aliases with periodic content such as tables or
+	// logs whose period shares a factor with the spacing.
+	SamplingStride SamplingStrategy = iota
+	// SamplingRandom takes samples at uniformly random positions drawn
+	// from SamplingSeed, so a given text always gets the same estimate.
+	SamplingRandom
+	// SamplingStratified takes a third of the samples at random positions
+	// in each third of the text, which keeps the coverage of
+	// SamplingStride while avoiding its aliasing.
+	SamplingStratified
+)
+
+// String returns the name of the strategy.
+func (s SamplingStrategy) String() string {
+	switch s {
+	case SamplingStride:
+		return "stride"
+	case SamplingRandom:
+		return "random"
+	case SamplingStratified:
+		return "stratified"
+	default:
+		return "unknown"
+	}
+}
+
+// samplingStrata is the number of parts SamplingStratified divides the
+// text into.
+const samplingStrata = 3
+
+// WithSamplingStrategy returns a clone of the estimator placing its samples
+// with the given strategy and seed. The seed is used only by the random
+// strategies. It does not enable sampling; see WithSampling.
+func (e *Estimator) WithSamplingStrategy(strategy SamplingStrategy, seed uint64) *Estimator {
+	clone := e.Clone()
+	clone.SamplingStrategy = strategy
+	clone.SamplingSeed = seed
+	return clone
+}
+
+// Sampling parameters of the built-in presets, also used when an estimator
+// does not set its own. Texts of a million characters or more are sampled;
+// a stride sample of 16384 characters estimates mixed documents within
+// about 1% of a full scan at a fifth of its cost.
+const (
+	defaultSamplingThreshold = 1 << 20
+	defaultSamplingSize      = 1 << 14
+)
+
+// defaultSampling turns on the sampling of giant texts that presets
+// released since it became the default ship with. Earlier versions keep
+// analyzing texts in full, so that their estimates do not change.
+func (e *Estimator) defaultSampling() {
+	e.EnableSampling = true
+	e.SamplingThreshold = defaultSamplingThreshold
+	e.SamplingSize = defaultSamplingSize
+}
+
+// samplingGroups is the number of groups of neighbouring samples whose
+// spread gives the standard error of a sampled estimate.
+const samplingGroups = 10
+
+// WithoutSampling returns a clone of the estimator that always analyzes the
+// whole text, however long.
+func (e *Estimator) WithoutSampling() *Estimator {
+	clone := e.Clone()
+	clone.EnableSampling = false
+	return clone
+}
+
+// WithAdaptiveSampling returns a clone of the estimator with sampling
+// enabled that picks the sample size itself: starting from SamplingSize,
+// the sample is doubled until the spread between the estimates of groups
+// of neighbouring samples implies a standard error within targetRelErr of
+// the estimate, or until it covers the whole text. Homogeneous texts thus
+// stop at a small sample while varied ones get a larger one.
+func (e *Estimator) WithAdaptiveSampling(targetRelErr float64) *Estimator {
+	clone := e.Clone()
+	clone.EnableSampling = true
+	if clone.SamplingThreshold <= 0 {
+		clone.SamplingThreshold = defaultSamplingThreshold
+	}
+	if clone.SamplingSize <= 0 {
+		clone.SamplingSize = defaultSamplingSize
+	}
+	clone.SamplingTargetError = targetRelErr
+	return clone
+}
+
+// sampleResult is a sampled analysis: the statistics scaled to the whole
+// text and, to judge their precision, those of groups of neighbouring
+// samples each scaled to the whole text on its own.
+type sampleResult struct {
+	stats   Stats
+	groups  [samplingGroups]Stats
+	tokens  [samplingGroups]float64 // the estimate from each group's statistics
+	ngroups int
+	size    int // characters sampled
+}
+
+// sample analyzes sampleSize characters of text, which has textLen
+// characters, placed by the sampling strategy.
+func (e *Estimator) sample(text string, textLen, sampleSize int) sampleResult {
+	// Sample isolated characters or contiguous blocks
+	length := 1
+	if e.SamplingBlockSize > 0 {
+		length = min(e.SamplingBlockSize, sampleSize)
+	}
+	starts := e.samplePositions(textLen, (sampleSize+length-1)/length, length)
+
+	a := e.newAnalyzer()
+	cursor := runeCursor{text: text}
+	r := sampleResult{ngroups: min(samplingGroups, len(starts))}
+	var prev Stats
+	for g := range r.ngroups {
+		n := a.sampleUnits(&cursor, starts[g*len(starts)/r.ngroups:(g+1)*len(starts)/r.ngroups], length)
+		group := a.stats.Sub(prev).Scale(float64(textLen) / float64(n))
+		adjustLatinExtended(&group)
+		r.groups[g] = group
+		r.tokens[g] = e.calculateTokenCount(group)
+		prev = a.stats
+		r.size += n
+	}
+
+	// Scale up the sampled statistics to the full text length
+	r.stats = a.stats.Scale(float64(textLen) / float64(r.size))
+	adjustLatinExtended(&r.stats)
+	return r
+}
+
+// relErr returns the standard error of the estimate relative to the
+// estimate, or +Inf if there are too few groups to tell.
+func (r *sampleResult) relErr() float64 {
+	mean, stdErr := standardError(r.tokens[:r.ngroups])
+	if stdErr == 0 {
+		return 0
+	}
+	return stdErr / math.Abs(mean)
+}
+
+// stdErr returns the standard error of feature f of the scaled statistics.
+func (r *sampleResult) stdErr(f feature) float64 {
+	var values [samplingGroups]float64
+	for g := range r.ngroups {
+		values[g] = float64(*f.field(&r.groups[g]))
+	}
+	_, stdErr := standardError(values[:r.ngroups])
+	return stdErr
+}
+
+// standardError returns the mean of xs and its standard error, which is
+// +Inf if xs has fewer than two values.
+func standardError(xs []float64) (mean, stdErr float64) {
+	for _, x := range xs {
+		mean += x
+	}
+	if len(xs) < 2 {
+		return mean, math.Inf(1)
+	}
+	mean /= float64(len(xs))
+
+	variance := 0.0
+	for _, x := range xs {
+		variance += (x - mean) * (x - mean)
+	}
+	variance /= float64(len(xs) - 1)
+	return mean, math.Sqrt(variance / float64(len(xs)))
+}
+
+// samplePositions returns the start of each of units samples of length
+// characters in a text of n characters, in increasing order.
+func (e *Estimator) samplePositions(n, units, length int) []int {
+	starts := make([]int, units)
+	switch e.SamplingStrategy {
+	case SamplingRandom:
+		rng := rand.New(rand.NewPCG(e.SamplingSeed, 0))
+		for i := range starts {
+			starts[i] = rng.IntN(n - length + 1)
+		}
+		slices.Sort(starts)
+	case SamplingStratified:
+		rng := rand.New(rand.NewPCG(e.SamplingSeed, 0))
+		for k := range samplingStrata {
+			from, to := k*n/samplingStrata, (k+1)*n/samplingStrata
+			span := max(to-from-length+1, 1)
+			for i := k * units / samplingStrata; i < (k+1)*units/samplingStrata; i++ {
+				starts[i] = min(from+rng.IntN(span), n-length)
+			}
+		}
+		slices.Sort(starts)
+	default:
+		interval := max(n/units, 1)
+		for i := range starts {
+			starts[i] = i * interval
+		}
+	}
+	return starts
+}
+
+// runeCursor maps increasing character indices of text to byte offsets by
+// scanning forward from the previous position, so that sampling needs no
+// []rune copy of the text.
+type runeCursor struct {
+	text   string
+	index  int // character index of offset
+	offset int
+}
+
+// seek moves the cursor to character index and returns its byte offset.
+func (c *runeCursor) seek(index int) int {
+	for c.index < index && c.offset < len(c.text) {
+		_, size := utf8.DecodeRuneInString(c.text[c.offset:])
+		c.offset += size
+		c.index++
+	}
+	return c.offset
+}
+
+// sampleUnits adds the runes of the samples of length characters starting
+// at the character indices starts, which must be in increasing order, and
+// returns the number of characters sampled. Each sample is read in
+// sequence after restoring the context before its start.
+func (a *analyzer) sampleUnits(cursor *runeCursor, starts []int, length int) int {
+	text := cursor.text
+	sampled := 0
+	for _, start := range starts {
+		i := cursor.seek(start)
+		seek := true
+		for n := 0; n < length && i < len(text); n++ {
+			if seek {
+				a.seek(text, i)
+			}
+			size, ok := a.sampleRune(text, i)
+			seek = !ok
+			i += size
+			sampled++
+		}
+	}
+	return sampled
+}
+
+// sampleRune adds the sampled rune at byte offset i of text in the context
+// the analyzer has and returns its size. It reports false for a rune
+// inside a special token that starts before i, which adds nothing and
+// leaves the context to be restored with seek.
+func (a *analyzer) sampleRune(text string, i int) (size int, ok bool) {
+	r, size := utf8.DecodeRuneInString(text[i:])
+	if a.special != nil {
+		// A special token is counted when its first rune is sampled.
+		if start, ok := a.special.locate(text, i); ok {
+			if start != i {
+				return size, false
+			}
+			a.addSpecial()
+			return size, true
+		}
+	}
+	switch {
+	case r == utf8.RuneError && isInvalidAt(text, i):
+		a.addInvalid()
+	case a.detectBlobs && inBlob(text, i):
+		a.addBlob()
+	default:
+		a.add(r)
+	}
+	return size, true
+}
@@ -0,0 +1,142 @@
+package tokenestimate
+
+import (
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSamplingStrategy(t *testing.T) {
+	// A period of 10 characters aliases with the stride of 100 used for
+	// 100 samples of 10000 characters: every sample lands on 'a'.
+	periodic := strings.Repeat("abcdefghi\n", 1000)
+	sampled := NewEstimator().WithSampling(1000, 100)
+	full := NewEstimator().Analyze(periodic)
+
+	t.Run("Stride aliases", func(t *testing.T) {
+		if got := sampled.Analyze(periodic).Spaces; got != 0 {
+			t.Errorf("Stride sampling found %d spaces, want 0 from aliasing", got)
+		}
+	})
+
+	for _, strategy := range []SamplingStrategy{SamplingRandom, SamplingStratified} {
+		estimator := sampled.WithSamplingStrategy(strategy, 1)
+
+		t.Run(strategy.String()+"/Avoids aliasing", func(t *testing.T) {
+			got := estimator.Analyze(periodic).Spaces
+			if got < full.Spaces/2 || got > full.Spaces*3/2 {
+				t.Errorf("Strategy %s found %d spaces, want about %d", strategy, got, full.Spaces)
+			}
+		})
+
+		t.Run(strategy.String()+"/Deterministic for a seed", func(t *testing.T) {
+			first := estimator.Analyze(periodic)
+			if again := estimator.Clone().Analyze(periodic); again != first {
+				t.Errorf("Strategy %s gave %+v, then %+v", strategy, first, again)
+			}
+			differs := false
+			for seed := uint64(2); seed < 10 && !differs; seed++ {
+				differs = sampled.WithSamplingStrategy(strategy, seed).Analyze(periodic) != first
+			}
+			if !differs {
+				t.Errorf("Strategy %s ignores the seed", strategy)
+			}
+		})
+	}
+
+	t.Run("Stratified covers thirds", func(t *testing.T) {
+		text := strings.Repeat("a", 1000) + strings.Repeat("中", 1000) + strings.Repeat("1", 1000)
+		stats := NewEstimator().WithSampling(1000, 30).
+			WithSamplingStrategy(SamplingStratified, 7).Analyze(text)
+		if stats.LatinLetters != 1000 || stats.ChineseChars != 1000 || stats.Digits != 1000 {
+			t.Errorf("Stratified sampling = %+v, want 1000 of each category", stats)
+		}
+	})
+
+	t.Run("Blocks", func(t *testing.T) {
+		estimator := NewEstimator().WithBlockSampling(1000, 500, 25).
+			WithSamplingStrategy(SamplingStratified, 3)
+		got := estimator.Estimate(periodic)
+		want := NewEstimator().Estimate(periodic)
+		if got < want*9/10 || got > want*11/10 {
+			t.Errorf("Stratified block sampling = %d, want about %d", got, want)
+		}
+	})
+}
+
+func TestAdaptiveSampling(t *testing.T) {
+	var b strings.Builder
+	for i := range 300 {
+		switch i % 3 {
+		case 0:
+			b.WriteString("The quick brown fox jumps over the lazy dog. ")
+		case 1:
+			b.WriteString(strings.Repeat("今天天气很好。", i%7+1))
+		default:
+			b.WriteString(strings.Repeat("1234 ", i%5+1))
+		}
+	}
+	mixed := b.String()
+	fixed := NewEstimator().WithSampling(1000, 20)
+
+	t.Run("Enables sampling", func(t *testing.T) {
+		estimator := NewEstimator().WithoutSampling().WithAdaptiveSampling(0.02)
+		if !estimator.EnableSampling || estimator.SamplingThreshold != defaultSamplingThreshold ||
+			estimator.SamplingSize != defaultSamplingSize || estimator.SamplingTargetError != 0.02 {
+			t.Errorf("WithAdaptiveSampling configured %+v", estimator)
+		}
+		if NewEstimator().SamplingTargetError != 0 {
+			t.Error("WithAdaptiveSampling modified the original estimator")
+		}
+	})
+
+	t.Run("Stops on homogeneous text", func(t *testing.T) {
+		text := strings.Repeat("ab ", 2000)
+		if got, want := fixed.WithAdaptiveSampling(0.01).Analyze(text), fixed.Analyze(text); got != want {
+			t.Errorf("Adaptive sampling of a uniform text = %+v, want the initial sample %+v", got, want)
+		}
+	})
+
+	t.Run("Grows to the target", func(t *testing.T) {
+		full := NewEstimator().Estimate(mixed)
+		coarse := fixed.Estimate(mixed)
+		adaptive := fixed.WithAdaptiveSampling(0.01).Estimate(mixed)
+		if diff := math.Abs(float64(adaptive-full)) / float64(full); diff > 0.03 {
+			t.Errorf("Adaptive estimate %d is %.1f%% off the full estimate %d (fixed sample: %d)",
+				adaptive, diff*100, full, coarse)
+		}
+	})
+
+	t.Run("Covers the whole text at worst", func(t *testing.T) {
+		if got, want := fixed.WithAdaptiveSampling(1e-12).Analyze(mixed), NewEstimator().Analyze(mixed); got != want {
+			t.Errorf("Adaptive sampling with an unreachable target = %+v, want full analysis %+v", got, want)
+		}
+	})
+}
+
+func TestSamplingMemory(t *testing.T) {
+	text := strings.Repeat("Hello, 世界! 123 ", 1<<18) // 5MB
+	estimator := NewEstimator().WithSampling(1000, 1000)
+	estimator.Analyze(text)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	estimator.Analyze(text)
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<10 {
+		t.Errorf("Sampling a %d byte text allocated %d bytes", len(text), allocated)
+	}
+}
+
+func TestSamplingInvalidUTF8(t *testing.T) {
+	text := strings.Repeat("ok \xff\xfe ", 2000)
+	stats := NewEstimator().WithSampling(1000, 600).Analyze(text)
+	full := NewEstimator().Analyze(text)
+	if stats.InvalidBytes < full.InvalidBytes*8/10 || stats.InvalidBytes > full.InvalidBytes*12/10 {
+		t.Errorf("Sampled %d invalid bytes, want about %d", stats.InvalidBytes, full.InvalidBytes)
+	}
+	if stats.Unknown != 0 {
+		t.Errorf("Sampled %d unknown characters from invalid bytes, want 0", stats.Unknown)
+	}
+}
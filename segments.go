@@ -0,0 +1,75 @@
+package tokenestimate
+
+import "unicode/utf8"
+
+// Segment is a maximal run of text in one character category.
+type Segment struct {
+	Category Category // Category of the characters in the segment
+	Text     string   // The segment's text
+	Start    int      // Index of the first rune in the text
+	End      int      // Index one past the last rune in the text
+	Tokens   int      // Estimate of the segment on its own
+}
+
+// Segments splits text into maximal runs of one category, such as a run
+// of Chinese characters inside English prose, with the estimate of each,
+// so that tools can show which parts of a prompt cost the most.
+// Whitespace does not start a segment of its own but belongs to the
+// segment before it, so "hello world" is a single Latin segment; only
+// leading whitespace forms a space segment, with tabs counted as spaces.
+// Invalid bytes count as one rune each and special tokens as their runes.
+// Each segment is estimated separately, so the sum of their tokens only
+// approximates Estimate(text).
+func (e *Estimator) Segments(text string) []Segment {
+	var segments []Segment
+	start, startRune, runes := 0, 0, 0
+	current := Category(-1)
+
+	for i := 0; i < len(text); {
+		c, size, n := e.unitAt(text, i)
+		if c == CategoryTab {
+			c = CategorySpace
+		}
+		if c == CategorySpace && current >= 0 {
+			c = current
+		}
+		if c != current {
+			if current >= 0 {
+				segments = append(segments, e.segment(current, text, start, i, startRune, runes))
+			}
+			current, start, startRune = c, i, runes
+		}
+		i += size
+		runes += n
+	}
+	if current >= 0 {
+		segments = append(segments, e.segment(current, text, start, len(text), startRune, runes))
+	}
+	return segments
+}
+
+// unitAt returns the category, length in bytes and length in runes of the
+// character or special token at text[i:].
+func (e *Estimator) unitAt(text string, i int) (c Category, size, runes int) {
+	if text[i] == '<' && e.specialTokens != nil {
+		if n := e.specialTokens.match(text[i:]); n > 0 {
+			return CategorySpecial, n, utf8.RuneCountInString(text[i : i+n])
+		}
+	}
+	r, size := utf8.DecodeRuneInString(text[i:])
+	if r == utf8.RuneError && isInvalidAt(text, i) {
+		return CategoryInvalid, size, 1
+	}
+	return classify(r), size, 1
+}
+
+// segment builds the Segment for text[start:end].
+func (e *Estimator) segment(c Category, text string, start, end, startRune, endRune int) Segment {
+	return Segment{
+		Category: c,
+		Text:     text[start:end],
+		Start:    startRune,
+		End:      endRune,
+		Tokens:   e.Estimate(text[start:end]),
+	}
+}
@@ -0,0 +1,75 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSegments(t *testing.T) {
+	estimator := NewEstimator()
+
+	t.Run("Mixed scripts", func(t *testing.T) {
+		text := "Hello world 你好世界 123, Привет"
+		segments := estimator.Segments(text)
+		want := []struct {
+			category Category
+			text     string
+		}{
+			{CategoryLatin, "Hello world "},
+			{CategoryChinese, "你好世界 "},
+			{CategoryDigit, "123"},
+			{CategorySymbol, ", "},
+			{CategoryRussian, "Привет"},
+		}
+		if len(segments) != len(want) {
+			t.Fatalf("Expected %d segments, got %+v", len(want), segments)
+		}
+		for i, w := range want {
+			if segments[i].Category != w.category || segments[i].Text != w.text {
+				t.Errorf("Segment %d = %s %q, want %s %q", i, segments[i].Category, segments[i].Text, w.category, w.text)
+			}
+			if got := estimator.Estimate(w.text); segments[i].Tokens != got {
+				t.Errorf("Segment %d has %d tokens, want %d", i, segments[i].Tokens, got)
+			}
+		}
+	})
+
+	t.Run("Rune spans cover the text", func(t *testing.T) {
+		text := "  \tこんにちは、世界。<|im_end|>\xffabc"
+		segments := estimator.Segments(text)
+		var sb strings.Builder
+		end := 0
+		for _, s := range segments {
+			if s.Start != end {
+				t.Errorf("Segment %q starts at rune %d, want %d", s.Text, s.Start, end)
+			}
+			end = s.End
+			sb.WriteString(s.Text)
+		}
+		if sb.String() != text {
+			t.Errorf("Segments do not reassemble the text: %q", sb.String())
+		}
+		if segments[0].Category != CategorySpace || segments[0].Text != "  \t" {
+			t.Errorf("Expected a leading space segment, got %+v", segments[0])
+		}
+		categories := make(map[Category]bool)
+		for _, s := range segments {
+			categories[s.Category] = true
+		}
+		for _, c := range []Category{CategoryJapanese, CategoryChinese, CategorySpecial, CategoryInvalid, CategoryLatin} {
+			if !categories[c] {
+				t.Errorf("Expected a %s segment in %+v", c, segments)
+			}
+		}
+		if end != utf8.RuneCountInString(text) {
+			t.Errorf("Unexpected rune count %d", end)
+		}
+	})
+
+	t.Run("Empty text", func(t *testing.T) {
+		if segments := estimator.Segments(""); len(segments) != 0 {
+			t.Errorf("Expected no segments, got %+v", segments)
+		}
+	})
+}
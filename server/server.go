@@ -0,0 +1,217 @@
+// Package server serves token estimates over HTTP, so that services in any
+// language can estimate tokens without a tokenizer of their own:
+//
+//	h, err := server.New(server.Options{Preset: "kimi-k2"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	log.Fatal(http.ListenAndServe(":8080", h))
+//
+// POST /estimate takes {"text": "...", "preset": "..."} and returns
+// {"tokens": N, "preset": "kimi-k2@12"}. POST /tokenize is shaped like the
+// tokenize endpoints of inference servers such as vLLM, taking
+// {"model": "...", "prompt": "..."} or {"model": "...", "messages": [...]}
+// and returning {"count": N}, so that clients already speaking that
+// protocol can point at the estimator as a fast path. The model is looked
+// up as a preset name or alias; RegisterAlias maps a model's ID to its
+// preset. Unknown models are estimated with the default preset.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// defaultMaxBodyBytes is the default Options.MaxBodyBytes.
+const defaultMaxBodyBytes = 32 << 20
+
+// Options configures New.
+type Options struct {
+	// Preset estimates requests that name no preset or an unknown model
+	// (default: the estimator of NewEstimator)
+	Preset string
+
+	// MaxBodyBytes is the size beyond which request bodies are rejected
+	// with 413 Request Entity Too Large (default: 32 MiB)
+	MaxBodyBytes int64
+}
+
+// Handler is the http.Handler serving the endpoints. It is safe for
+// concurrent use.
+type Handler struct {
+	mux          *http.ServeMux
+	fallback     *tokenestimate.Estimator
+	maxBodyBytes int64
+}
+
+// New returns a Handler with the options. It returns an error if
+// opts.Preset is not registered.
+func New(opts Options) (*Handler, error) {
+	h := &Handler{mux: http.NewServeMux(), fallback: tokenestimate.NewEstimator(), maxBodyBytes: opts.MaxBodyBytes}
+	if opts.Preset != "" {
+		var err error
+		if h.fallback, err = tokenestimate.GetPresetByName(opts.Preset); err != nil {
+			return nil, err
+		}
+	}
+	if h.maxBodyBytes <= 0 {
+		h.maxBodyBytes = defaultMaxBodyBytes
+	}
+	h.mux.HandleFunc("POST /estimate", h.estimate)
+	h.mux.HandleFunc("POST /tokenize", h.tokenize)
+	return h, nil
+}
+
+// ServeHTTP serves the endpoints.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// estimateRequest is the body of /estimate requests.
+type estimateRequest struct {
+	Text   string `json:"text"`
+	Preset string `json:"preset"` // default: Options.Preset
+}
+
+// estimateResponse is the body of /estimate responses.
+type estimateResponse struct {
+	Tokens int    `json:"tokens"`
+	Preset string `json:"preset"` // preset version that estimated the text
+}
+
+func (h *Handler) estimate(w http.ResponseWriter, r *http.Request) {
+	var req estimateRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	e := h.fallback
+	if req.Preset != "" {
+		var err error
+		if e, err = tokenestimate.GetPresetByName(req.Preset); err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+	}
+	tokens, err := e.Count(req.Text)
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+	writeJSON(w, estimateResponse{Tokens: tokens, Preset: e.Key()})
+}
+
+// tokenizeRequest is the body of /tokenize requests, which have either a
+// prompt or the messages of a chat request.
+type tokenizeRequest struct {
+	Model    string            `json:"model"`
+	Prompt   *string           `json:"prompt"`
+	Messages []tokenizeMessage `json:"messages"`
+}
+
+// tokenizeMessage is a chat message, whose content is a string or a list
+// of content parts of which the text parts are counted.
+type tokenizeMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// text returns the text of the message's content.
+func (m tokenizeMessage) text() (string, error) {
+	if len(m.Content) == 0 || string(m.Content) == "null" {
+		return "", nil
+	}
+	var text string
+	if err := json.Unmarshal(m.Content, &text); err == nil {
+		return text, nil
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(m.Content, &parts); err != nil {
+		return "", errors.New("message content is neither a string nor a list of parts")
+	}
+	for _, p := range parts {
+		text += p.Text
+	}
+	return text, nil
+}
+
+// tokenizeResponse is the body of /tokenize responses.
+type tokenizeResponse struct {
+	Count int `json:"count"`
+}
+
+func (h *Handler) tokenize(w http.ResponseWriter, r *http.Request) {
+	var req tokenizeRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	e := h.fallback
+	if req.Model != "" {
+		if preset, err := tokenestimate.GetPresetByName(req.Model); err == nil {
+			e = preset
+		}
+	}
+
+	var count int
+	switch {
+	case req.Prompt != nil && req.Messages != nil:
+		writeError(w, http.StatusBadRequest, errors.New("request has both a prompt and messages"))
+		return
+	case req.Prompt != nil:
+		var err error
+		if count, err = e.Count(*req.Prompt); err != nil {
+			writeError(w, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+	default:
+		msgs := make([]tokenestimate.Message, len(req.Messages))
+		for i, m := range req.Messages {
+			text, err := m.text()
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("message %d: %w", i, err))
+				return
+			}
+			msgs[i] = tokenestimate.Message{Role: m.Role, Content: text}
+		}
+		count = e.EstimateMessages(msgs)
+	}
+	writeJSON(w, tokenizeResponse{Count: count})
+}
+
+// decode decodes the JSON body of r into v, writing an error response and
+// returning false if it fails.
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes)).Decode(v)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return false
+	case err != nil:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return false
+	}
+	return true
+}
+
+// errorResponse is the body of error responses.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
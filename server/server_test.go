@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+func post(t *testing.T, h http.Handler, path, body string) (int, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	var out map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatalf("POST %s: invalid response %q: %v", path, rec.Body, err)
+	}
+	return rec.Code, out
+}
+
+func TestEstimate(t *testing.T) {
+	h, err := New(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "Hello, world! 你好世界"
+	want := tokenestimate.NewEstimator().Estimate(text)
+	code, out := post(t, h, "/estimate", `{"text": "Hello, world! 你好世界"}`)
+	if code != http.StatusOK || out["tokens"] != float64(want) || out["preset"] != "kimi-k2@12" {
+		t.Errorf("POST /estimate = %d %v, want 200 tokens %d preset kimi-k2@12", code, out, want)
+	}
+
+	cl100k, _ := tokenestimate.NewEstimatorWithName("cl100k-base")
+	code, out = post(t, h, "/estimate", `{"text": "Hello, world! 你好世界", "preset": "cl100k-base"}`)
+	if code != http.StatusOK || out["tokens"] != float64(cl100k.Estimate(text)) {
+		t.Errorf("POST /estimate with cl100k-base = %d %v, want 200 tokens %d", code, out, cl100k.Estimate(text))
+	}
+
+	if code, out = post(t, h, "/estimate", `{"text": "x", "preset": "no-such-preset"}`); code != http.StatusNotFound || out["error"] == nil {
+		t.Errorf("POST /estimate with an unknown preset = %d %v, want 404 with an error", code, out)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	h, err := New(Options{Preset: "cl100k-base"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl100k, _ := tokenestimate.NewEstimatorWithName("cl100k-base")
+	kimi := tokenestimate.NewEstimator()
+	msgs := []tokenestimate.Message{{Role: "system", Content: "Be brief."}, {Role: "user", Content: "Hello, world!"}}
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"prompt", `{"model": "kimi-k2", "prompt": "Hello, world!"}`, kimi.Estimate("Hello, world!")},
+		{"unknown model", `{"model": "no-such-model", "prompt": "Hello, world!"}`, cl100k.Estimate("Hello, world!")},
+		{"no model", `{"prompt": "Hello, world!"}`, cl100k.Estimate("Hello, world!")},
+		{"empty prompt", `{"model": "kimi-k2", "prompt": ""}`, 0},
+		{"messages", `{"model": "kimi-k2", "messages": [
+			{"role": "system", "content": "Be brief."},
+			{"role": "user", "content": "Hello, world!"}]}`, kimi.EstimateMessages(msgs)},
+		{"content parts", `{"model": "kimi-k2", "messages": [
+			{"role": "system", "content": "Be brief."},
+			{"role": "user", "content": [{"type": "text", "text": "Hello, "}, {"type": "image_url"}, {"type": "text", "text": "world!"}]}]}`, kimi.EstimateMessages(msgs)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out := post(t, h, "/tokenize", tt.body)
+			if code != http.StatusOK || out["count"] != float64(tt.want) {
+				t.Errorf("POST /tokenize = %d %v, want 200 count %d", code, out, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := New(Options{Preset: "no-such-preset"}); err == nil {
+		t.Error("New with an unknown preset succeeded")
+	}
+	h, err := New(Options{MaxBodyBytes: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"invalid JSON", `{"prompt": `, http.StatusBadRequest},
+		{"prompt and messages", `{"prompt": "x", "messages": []}`, http.StatusBadRequest},
+		{"invalid content", `{"messages": [{"role": "user", "content": 42}]}`, http.StatusBadRequest},
+		{"body too large", `{"prompt": "` + strings.Repeat("x", 100) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out := post(t, h, "/tokenize", tt.body)
+			if code != tt.want || out["error"] == nil {
+				t.Errorf("POST /tokenize = %d %v, want %d with an error", code, out, tt.want)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tokenize", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /tokenize = %d, want 405", rec.Code)
+	}
+}
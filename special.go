@@ -0,0 +1,70 @@
+package tokenestimate
+
+import "strings"
+
+// maxSpecialTokenLength bounds the length in bytes of a special token.
+const maxSpecialTokenLength = 64
+
+// specialTokenSet holds the special tokens of a preset's vocabulary, such
+// as "<|im_end|>". Literal special tokens in text are encoded by the
+// tokenizer as a single token each rather than as their characters.
+type specialTokenSet struct {
+	tokens map[string]struct{}
+	maxLen int
+}
+
+// newSpecialTokenSet returns a set of the given special tokens. Each token
+// must have the form "<|name|>" and be at most maxSpecialTokenLength bytes.
+func newSpecialTokenSet(tokens ...string) *specialTokenSet {
+	s := &specialTokenSet{tokens: make(map[string]struct{}, len(tokens))}
+	for _, token := range tokens {
+		if len(token) > maxSpecialTokenLength || !strings.HasPrefix(token, "<|") || !strings.HasSuffix(token, "|>") {
+			panic("tokenestimate: invalid special token " + token)
+		}
+		s.tokens[token] = struct{}{}
+		s.maxLen = max(s.maxLen, len(token))
+	}
+	return s
+}
+
+// match returns the length in bytes of the special token at the start of
+// text, or 0 if text does not start with one.
+func (s *specialTokenSet) match(text string) int {
+	if len(text) < 4 || text[0] != '<' || text[1] != '|' {
+		return 0
+	}
+	end := strings.Index(text[2:min(len(text), s.maxLen)], "|>")
+	if end < 0 {
+		return 0
+	}
+	n := 2 + end + 2
+	if _, ok := s.tokens[text[:n]]; !ok {
+		return 0
+	}
+	return n
+}
+
+// locate returns the byte offset of the special token covering the byte at
+// offset i of text, if any. Only the bytes within the longest token's
+// length of i are examined.
+func (s *specialTokenSet) locate(text string, i int) (start int, ok bool) {
+	for j := i; j >= 0 && i-j < s.maxLen; j-- {
+		if text[j] == '<' {
+			if length := s.match(text[j:]); length > 0 && j+length > i {
+				return j, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// kimiK2SpecialTokens are the chat, tool-call and fill-in-the-middle
+// markers of the Kimi-K2 vocabulary, together with the ChatML markers
+// commonly found in fine-tuning data.
+var kimiK2SpecialTokens = newSpecialTokenSet(
+	"<|im_start|>", "<|im_end|>", "<|im_system|>", "<|im_user|>",
+	"<|im_assistant|>", "<|im_middle|>", "<|endoftext|>",
+	"<|tool_calls_section_begin|>", "<|tool_calls_section_end|>",
+	"<|tool_call_begin|>", "<|tool_call_argument_begin|>", "<|tool_call_end|>",
+	"<|fim_prefix|>", "<|fim_middle|>", "<|fim_suffix|>", "<|fim_pad|>",
+)
@@ -0,0 +1,101 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpecialTokens(t *testing.T) {
+	estimator := NewEstimator()
+
+	tests := []struct {
+		name     string
+		text     string
+		expected Stats
+	}{
+		{
+			name: "Chat markers",
+			text: "<|im_start|>user<|im_end|>",
+			expected: Stats{
+				LatinLetters:  4,
+				Words:         1,
+				SpecialTokens: 2,
+			},
+		},
+		{
+			name: "Unknown marker is text",
+			text: "<|not_a_token|>",
+			expected: Stats{
+				Symbols:              6,
+				LatinLetters:         9,
+				Words:                1,
+				IdentifierBoundaries: 2,
+			},
+		},
+		{
+			name: "Between words",
+			text: "a <|endoftext|> b",
+			expected: Stats{
+				LatinLetters:  2,
+				Spaces:        2,
+				Words:         3,
+				SpecialTokens: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimator.Analyze(tt.text); got != tt.expected {
+				t.Errorf("Analyze(%q) = %+v, want %+v", tt.text, got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("Llama-3 markers are text", func(t *testing.T) {
+		if got := estimator.Analyze("<|start_header_id|>user<|end_header_id|>").SpecialTokens; got != 0 {
+			t.Errorf("Expected no special tokens, got %d", got)
+		}
+	})
+
+	t.Run("Priced as one token each", func(t *testing.T) {
+		if got := estimator.Estimate("<|fim_prefix|><|fim_suffix|><|fim_middle|>"); got != 3 {
+			t.Errorf("Expected 3 tokens, got %d", got)
+		}
+	})
+
+	t.Run("Pinned versions price characters", func(t *testing.T) {
+		pinned, _ := NewEstimatorWithName("kimi-k2@9")
+		if stats := pinned.Analyze("<|im_end|>"); stats.SpecialTokens != 0 {
+			t.Errorf("Expected no special tokens for kimi-k2@9, got %d", stats.SpecialTokens)
+		}
+	})
+
+	t.Run("Sampling", func(t *testing.T) {
+		text := strings.Repeat("<|im_start|>user\nhello<|im_end|>\n", 200)
+		full := estimator.Analyze(text)
+		sampled := estimator.WithSampling(100, 1320).Analyze(text)
+		if diff := float64(sampled.SpecialTokens-full.SpecialTokens) / float64(full.SpecialTokens); diff > 0.15 || diff < -0.15 {
+			t.Errorf("Sampled %d special tokens, full %d", sampled.SpecialTokens, full.SpecialTokens)
+		}
+	})
+}
+
+func TestSpecialTokenSetMatch(t *testing.T) {
+	set := newSpecialTokenSet("<|a|>", "<|bc|>")
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"<|a|>rest", 5},
+		{"<|bc|>", 6},
+		{"<|b|>", 0},
+		{"<|a", 0},
+		{"a<|a|>", 0},
+	}
+	for _, tt := range tests {
+		if got := set.match(tt.text); got != tt.want {
+			t.Errorf("match(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
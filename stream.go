@@ -0,0 +1,284 @@
+package tokenestimate
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"unicode"
+	"unicode/utf8"
+)
+
+// streamChunkSize is the amount of text streaming estimation analyzes at
+// a time. Memory use is bounded by about twice this size.
+const streamChunkSize = 64 << 10
+
+// streamer analyzes text that arrives in pieces. Text is buffered until a
+// chunk of streamChunkSize bytes is available, then split at a point where
+// no feature spans the split and analyzed like a separate text; the chunk
+// statistics are summed. JSON keys are carried from chunk to chunk, so
+// that they repeat as they do in the whole text.
+type streamer struct {
+	e       *Estimator
+	total   Stats    // statistics of the analyzed chunks
+	pending []byte   // text not analyzed yet
+	keys    jsonKeys // JSON keys of the analyzed chunks
+}
+
+// write appends p to the stream, analyzing every complete chunk.
+func (s *streamer) write(p []byte) {
+	s.pending = append(s.pending, p...)
+	s.analyzeChunks()
+}
+
+// writeString is like write but takes a string.
+func (s *streamer) writeString(p string) {
+	s.pending = append(s.pending, p...)
+	s.analyzeChunks()
+}
+
+// analyzeChunks analyzes complete chunks of the pending text.
+func (s *streamer) analyzeChunks() {
+	for len(s.pending) > streamChunkSize {
+		n := s.split()
+		s.total = s.total.Add(s.analyze(s.pending[:n], true))
+		s.pending = s.pending[:copy(s.pending, s.pending[n:])]
+	}
+}
+
+// analyze returns the statistics of a chunk of the stream. If keep is
+// true, the chunk is not analyzed again and its JSON keys are remembered
+// for the chunks after it.
+func (s *streamer) analyze(chunk []byte, keep bool) Stats {
+	var stats Stats
+	s.keys.keep = keep
+	s.e.analyze(bytesToString(chunk), &stats, nil, &s.keys)
+	return stats
+}
+
+// stats returns the statistics of everything written so far.
+func (s *streamer) stats() Stats {
+	if len(s.pending) == 0 {
+		return s.total
+	}
+	return s.total.Add(s.analyze(s.pending, false))
+}
+
+// partialStats is like stats but leaves out an incomplete UTF-8 sequence
+// at the end of the text, which the next write may complete.
+func (s *streamer) partialStats() Stats {
+	pending := s.pending
+	for i := len(pending) - 1; i >= 0 && i >= len(pending)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(pending[i]) {
+			if !utf8.FullRune(pending[i:]) {
+				pending = pending[:i]
+			}
+			break
+		}
+	}
+	if len(pending) == 0 {
+		return s.total
+	}
+	return s.total.Add(s.analyze(pending, false))
+}
+
+// split returns the length of the next chunk of the pending text.
+func (s *streamer) split() int {
+	json := s.e.ContentType == ContentJSON
+	html := s.e.HTMLHandling != HTMLAsText
+	if !json && !html {
+		return streamSplit(s.pending, streamChunkSize)
+	}
+	return markupSplit(s.pending, streamChunkSize, json, html)
+}
+
+// streamSplit returns the length of the prefix of buf to analyze as one
+// chunk, at most limit bytes. It prefers to split after a newline, then
+// after a space, followed by a non-whitespace character: there no word,
+// whitespace run or special token crosses the split, so the chunks add up
+// to the statistics of the whole text. Otherwise it splits at a rune
+// boundary.
+func streamSplit(buf []byte, limit int) int {
+	if limit >= len(buf) {
+		return len(buf)
+	}
+	for _, sep := range []byte{'\n', ' '} {
+		for i := limit - 1; i > limit/2; i-- {
+			if buf[i] != sep {
+				continue
+			}
+			if next, _ := utf8.DecodeRune(buf[i+1:]); !unicode.IsSpace(next) {
+				return i + 1
+			}
+		}
+	}
+	n := limit
+	for n > 0 && !utf8.RuneStart(buf[n]) {
+		n--
+	}
+	if n == 0 {
+		return limit
+	}
+	return n
+}
+
+// markupSplit is like streamSplit for JSON documents, if json is true, and
+// HTML, if html is true. It splits only outside JSON strings and outside
+// HTML tags, comments and script and style elements, and not before a
+// key's colon or before markup, so that the chunks scan like the whole
+// text. It splits in the first half of the chunk if it must, and falls
+// back on streamSplit only when a single string or element fills it.
+func markupSplit(buf []byte, limit int, json, html bool) int {
+	if limit >= len(buf) {
+		return len(buf)
+	}
+	newline, space := 0, 0 // last splits after a newline and after a space
+	inString := false
+	tagStart := -1 // start of the current tag, or -1
+	inComment := false
+	closeTag := "" // closing tag of the current script or style element
+	for i := 0; i < limit; i++ {
+		c := buf[i]
+		switch {
+		case inComment:
+			if c == '>' && i-2 >= tagStart+4 && bytes.HasSuffix(buf[:i+1], []byte("-->")) {
+				inComment, tagStart = false, -1
+			}
+			continue
+		case tagStart >= 0:
+			if c == '>' {
+				name, closing := htmlTagName(bytesToString(buf[tagStart : i+1]))
+				if !closing && (name == "script" || name == "style") && buf[i-1] != '/' {
+					closeTag = "</" + name
+				}
+				tagStart = -1
+			}
+			continue
+		case closeTag != "":
+			if c == '<' && len(buf)-i >= len(closeTag) && bytes.EqualFold(buf[i:i+len(closeTag)], []byte(closeTag)) {
+				closeTag, tagStart = "", i
+			}
+			continue
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		case html && c == '<':
+			if bytes.HasPrefix(buf[i:], []byte("<!--")) {
+				inComment, tagStart = true, i
+				continue
+			}
+			if n := buf[i+1]; n == '/' || n == '!' || n == '?' || isASCIILetter(rune(n)) {
+				tagStart = i
+			}
+			continue
+		case json && c == '"':
+			inString = true
+			continue
+		case c != '\n' && c != ' ':
+			continue
+		}
+
+		next := buf[i+1]
+		if r, _ := utf8.DecodeRune(buf[i+1:]); unicode.IsSpace(r) || json && next == ':' || html && (next == '<' || next == '&') {
+			continue
+		}
+		if c == '\n' {
+			newline = i + 1
+		} else {
+			space = i + 1
+		}
+	}
+
+	switch {
+	case newline > limit/2:
+		return newline
+	case space > limit/2:
+		return space
+	case max(newline, space) > 0:
+		return max(newline, space)
+	}
+	return streamSplit(buf, limit)
+}
+
+// EstimateReader estimates the number of tokens in everything read from r
+// in a single streaming pass with bounded memory, so inputs far larger
+// than memory can be estimated. The text is analyzed in chunks of 64 KiB,
+// split outside JSON strings and HTML markup where the estimator looks at
+// them; repetition is measured per chunk. It returns the estimate of the
+// text read so far together with any read error other than io.EOF, or an
+// *InputTooLargeError once it has read more than WithMaxInputBytes
+// allows.
+func (e *Estimator) EstimateReader(r io.Reader) (int, error) {
+	return e.EstimateReaderContext(context.Background(), r)
+}
+
+// EstimateReaderContext is like EstimateReader but stops reading when ctx
+// is done, returning the estimate of the text read so far and ctx.Err().
+func (e *Estimator) EstimateReaderContext(ctx context.Context, r io.Reader) (int, error) {
+	s := streamer{e: e}
+	buf := make([]byte, 32<<10)
+	read := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return e.EstimateFromStats(s.stats()), err
+		}
+		n, err := r.Read(buf)
+		s.write(buf[:n])
+		read += n
+		if limitErr := e.checkInput(read); limitErr != nil {
+			return e.EstimateFromStats(s.stats()), limitErr
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return e.EstimateFromStats(s.stats()), err
+		}
+	}
+	return e.EstimateFromStats(s.stats()), nil
+}
+
+// EstimateFile estimates the number of tokens in the named file, reading
+// it like EstimateReader with a fixed-size buffer instead of loading the
+// whole file into memory.
+func (e *Estimator) EstimateFile(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return e.EstimateReader(f)
+}
+
+// EstimateContext is like Estimate but checks ctx between chunks of
+// 64 KiB, so that estimating a very large text can be abandoned when the
+// caller goes away. It returns 0 and ctx.Err() if ctx is done before the
+// analysis completes, or 0 and an *InputTooLargeError if the text is
+// rejected by WithMaxInputBytes. Texts longer than a chunk are analyzed
+// like EstimateReader does, unless they are oversized and sampled.
+func (e *Estimator) EstimateContext(ctx context.Context, text string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if err := e.checkInput(len(text)); err != nil {
+		return 0, err
+	}
+	if len(text) <= streamChunkSize || e.oversized(len(text)) {
+		return e.Estimate(text), nil
+	}
+
+	s := streamer{e: e}
+	for len(text) > 0 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		n := min(len(text), streamChunkSize)
+		s.writeString(text[:n])
+		text = text[n:]
+	}
+	return e.EstimateFromStats(s.stats()), nil
+}
@@ -0,0 +1,249 @@
+package tokenestimate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStreamSplit(t *testing.T) {
+	tests := []struct {
+		name  string
+		buf   string
+		limit int
+		want  int
+	}{
+		{name: "Fits", buf: "short", limit: 10, want: 5},
+		{name: "Newline", buf: "aaaa bbbb\ncccc dddd", limit: 16, want: 10},
+		{name: "Space", buf: "aaaa bbbb cccc dddd", limit: 16, want: 15},
+		{name: "Not inside whitespace run", buf: "aaaaaaaa  \n\n  bbbbbbbb", limit: 13, want: 13},
+		{name: "Rune boundary", buf: strings.Repeat("世", 10), limit: 16, want: 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := streamSplit([]byte(tt.buf), tt.limit); got != tt.want {
+				t.Errorf("streamSplit(%q, %d) = %d, want %d", tt.buf, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkupSplit(t *testing.T) {
+	tests := []struct {
+		name       string
+		buf        string
+		limit      int
+		json, html bool
+		want       int
+	}{
+		{name: "Fits", buf: `{"a": 1}`, limit: 10, json: true, want: 8},
+		{name: "Not inside string", buf: `{"aa": "b c d e f g"} x`, limit: 19, json: true, want: 7},
+		{name: "Not before colon", buf: "[\"a\"\n: 1, \"b\"\n : 2]", limit: 16, json: true, want: 10},
+		{name: "Not inside tag", buf: `a b <p class="c d e f g">`, limit: 23, html: true, want: 2},
+		{name: "Not inside comment", buf: "x y <!-- a b c d e --> z", limit: 21, html: true, want: 2},
+		{name: "Not inside script", buf: "x y\n<script>\na b c\n</script>\nz", limit: 27, html: true, want: 2},
+		{name: "String fills the chunk", buf: `"a b c d e f g h"`, limit: 12, json: true, want: 11},
+		{name: "Not before markup", buf: "aa bb <b>cc\n<i>dd</i>", limit: 16, html: true, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markupSplit([]byte(tt.buf), tt.limit, tt.json, tt.html); got != tt.want {
+				t.Errorf("markupSplit(%q, %d) = %d, want %d", tt.buf, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimateReader(t *testing.T) {
+	estimator := NewEstimator()
+
+	t.Run("Matches Estimate for short text", func(t *testing.T) {
+		text := "Hello, 世界! <|im_end|> 12345"
+		got, err := estimator.EstimateReader(strings.NewReader(text))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := estimator.Estimate(text); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Chunks add up", func(t *testing.T) {
+		var b strings.Builder
+		for b.Len() < 3*streamChunkSize {
+			b.WriteString("The quick brown fox 跳过 lazy_dog 12345.\n\n\tindented line with café\n")
+		}
+		text := b.String()
+
+		s := streamer{e: estimator}
+		s.write([]byte(text))
+		got, want := s.stats(), estimator.Analyze(text)
+		// Repetition is measured per chunk.
+		got.Shingles, got.RepeatedShingles, want.Shingles, want.RepeatedShingles = 0, 0, 0, 0
+		if got != want {
+			t.Errorf("Streamed stats %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("JSON and HTML", func(t *testing.T) {
+		var doc, page strings.Builder
+		doc.WriteString("[\n")
+		page.WriteString("<html><head><style>\n  p { margin: 0 }\n</style></head><body>\n")
+		for i := 0; doc.Len() < 3*streamChunkSize || page.Len() < 3*streamChunkSize; i++ {
+			fmt.Fprintf(&doc, "  {\"id\": %d, \"note\": \"a long value with spaces,\\n newlines and \\\"quotes\\\" %d\", \"tags\": [\"x y\", \"z\"], \"key %d\"\n : true}, ", i, i, i%5000)
+			fmt.Fprintf(&page, "<p class=\"note wide\" title=\"entry %d\">Entry %d &amp; more text</p>\n<!-- a comment\n with spaces -->\n<script>\n if (a < b) { run(\"x y\") }\n</script>\n", i, i)
+		}
+		doc.WriteString("  {}\n]\n")
+		page.WriteString("</body></html>\n")
+
+		tests := []struct {
+			name string
+			e    *Estimator
+			text string
+		}{
+			{"JSON", NewEstimator().WithContentType(ContentJSON), doc.String()},
+			{"HTML with tags stripped", NewEstimator().WithHTMLHandling(HTMLStripTags), page.String()},
+			{"HTML with tags counted", NewEstimator().WithHTMLHandling(HTMLCountTags), page.String()},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				s := streamer{e: tt.e}
+				s.write([]byte(tt.text))
+				if got, want := s.stats(), tt.e.Analyze(tt.text); got != want {
+					t.Errorf("Streamed stats %+v, want %+v", got, want)
+				}
+				got, err := tt.e.EstimateReader(strings.NewReader(tt.text))
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if want := tt.e.Estimate(tt.text); got != want {
+					t.Errorf("Expected %d tokens, got %d", want, got)
+				}
+			})
+		}
+	})
+
+	t.Run("Runes split across reads", func(t *testing.T) {
+		text := strings.Repeat("日本語のテキスト。\n", 20000)
+		// Repetition is measured per chunk, so leave it out of the comparison.
+		prose := estimator.Clone()
+		prose.detectRepetition = false
+		got, err := prose.EstimateReader(iotest.OneByteReader(strings.NewReader(text)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := prose.Estimate(text); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Text without whitespace", func(t *testing.T) {
+		text := strings.Repeat("日本語のテキスト。", 20000)
+		got, err := estimator.EstimateReader(strings.NewReader(text))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		// Each chunk after the first starts a new word.
+		if want := estimator.Estimate(text); got < want || got > want+len(text)/streamChunkSize {
+			t.Errorf("Expected about %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		if _, err := estimator.EstimateReader(iotest.ErrReader(errBoom)); !errors.Is(err, errBoom) {
+			t.Errorf("Expected read error, got %v", err)
+		}
+	})
+}
+
+func TestEstimateFile(t *testing.T) {
+	estimator := NewEstimator()
+	text := strings.Repeat("The quick brown fox 跳过 lazy_dog 12345.\n", 5000)
+	path := filepath.Join(t.TempDir(), "text.txt")
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := estimator.EstimateFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want, _ := estimator.EstimateReader(strings.NewReader(text)); got != want {
+		t.Errorf("Expected %d tokens, got %d", want, got)
+	}
+
+	if _, err := estimator.EstimateFile(filepath.Join(t.TempDir(), "missing.txt")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestEstimateContext(t *testing.T) {
+	estimator := NewEstimator()
+	long := strings.Repeat("Some long document text, line after line.\n", 10000)
+
+	t.Run("Matches Estimate for short text", func(t *testing.T) {
+		text := "Hello, world!"
+		got, err := estimator.EstimateContext(context.Background(), text)
+		if err != nil || got != estimator.Estimate(text) {
+			t.Errorf("Expected %d, nil; got %d, %v", estimator.Estimate(text), got, err)
+		}
+	})
+
+	t.Run("Matches EstimateReader for long text", func(t *testing.T) {
+		got, err := estimator.EstimateContext(context.Background(), long)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want, _ := estimator.EstimateReader(strings.NewReader(long))
+		if got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := estimator.EstimateContext(ctx, long); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if _, err := estimator.EstimateReaderContext(ctx, strings.NewReader(long)); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled from reader, got %v", err)
+		}
+	})
+
+	t.Run("Canceled while reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		r := &cancelingReader{r: strings.NewReader(long), cancel: cancel, after: 3}
+		tokens, err := estimator.EstimateReaderContext(ctx, r)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+		if full := estimator.Estimate(long); tokens <= 0 || tokens >= full {
+			t.Errorf("Expected a partial estimate below %d, got %d", full, tokens)
+		}
+	})
+}
+
+// cancelingReader calls cancel after a number of reads.
+type cancelingReader struct {
+	r      io.Reader
+	cancel context.CancelFunc
+	after  int
+}
+
+func (c *cancelingReader) Read(p []byte) (int, error) {
+	if c.after--; c.after == 0 {
+		c.cancel()
+	}
+	return c.r.Read(p)
+}
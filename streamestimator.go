@@ -0,0 +1,48 @@
+package tokenestimate
+
+// StreamEstimator accumulates an estimate for text that arrives in pieces,
+// such as a streamed chat response. A UTF-8 sequence split between writes
+// is carried over to the next write. Memory use is bounded regardless of
+// how much text is written. A StreamEstimator is not safe for concurrent
+// use.
+type StreamEstimator struct {
+	s streamer
+}
+
+// NewStreamEstimator returns a StreamEstimator using the given estimator.
+// If e is nil, the default estimator is used.
+func NewStreamEstimator(e *Estimator) *StreamEstimator {
+	if e == nil {
+		e = NewEstimator()
+	}
+	return &StreamEstimator{s: streamer{e: e}}
+}
+
+// Write adds p to the stream. It always returns len(p), nil.
+func (se *StreamEstimator) Write(p []byte) (int, error) {
+	se.s.write(p)
+	return len(p), nil
+}
+
+// WriteString adds s to the stream. It always returns len(s), nil.
+func (se *StreamEstimator) WriteString(s string) (int, error) {
+	se.s.writeString(s)
+	return len(s), nil
+}
+
+// Stats returns the statistics of the text written so far, leaving out an
+// incomplete UTF-8 sequence at its end.
+func (se *StreamEstimator) Stats() Stats {
+	return se.s.partialStats()
+}
+
+// Tokens returns the estimated number of tokens in the text written so far.
+func (se *StreamEstimator) Tokens() int {
+	return se.s.e.EstimateFromStats(se.Stats())
+}
+
+// Reset discards the text written so far.
+func (se *StreamEstimator) Reset() {
+	se.s.total = Stats{}
+	se.s.pending = se.s.pending[:0]
+}
@@ -0,0 +1,68 @@
+package tokenestimate
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+var (
+	_ io.Writer       = (*StreamEstimator)(nil)
+	_ io.StringWriter = (*StreamEstimator)(nil)
+)
+
+func TestStreamEstimator(t *testing.T) {
+	estimator := NewEstimator()
+
+	t.Run("Accumulates chunks", func(t *testing.T) {
+		text := "Hello, 世界! This reply arrives <|im_end|> in pieces."
+		se := NewStreamEstimator(estimator)
+		for i := 0; i < len(text); i += 3 {
+			se.WriteString(text[i:min(i+3, len(text))])
+		}
+		if got, want := se.Tokens(), estimator.Estimate(text); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+		if got, want := se.Stats(), estimator.Analyze(text); got != want {
+			t.Errorf("Expected stats %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("Partial rune is carried over", func(t *testing.T) {
+		se := NewStreamEstimator(estimator)
+		b := []byte("世界")
+		se.Write(b[:4])
+		if stats := se.Stats(); stats.ChineseChars != 1 || stats.InvalidBytes != 0 {
+			t.Errorf("Expected 1 Chinese character and no invalid bytes, got %+v", stats)
+		}
+		se.Write(b[4:])
+		if stats := se.Stats(); stats.ChineseChars != 2 || stats.InvalidBytes != 0 {
+			t.Errorf("Expected 2 Chinese characters and no invalid bytes, got %+v", stats)
+		}
+	})
+
+	t.Run("Long stream", func(t *testing.T) {
+		se := NewStreamEstimator(nil)
+		line := "streamed line of text with a number 42\n"
+		for i := 0; i < 5000; i++ {
+			se.WriteString(line)
+		}
+		want := NewEstimator().Analyze(strings.Repeat(line, 5000))
+		got := se.Stats()
+		if got.Words != want.Words || got.LatinLetters != want.LatinLetters || got.DigitRuns != want.DigitRuns {
+			t.Errorf("Expected stats %+v, got %+v", want, got)
+		}
+		if len(se.s.pending) > 2*streamChunkSize {
+			t.Errorf("Expected bounded buffer, have %d bytes", len(se.s.pending))
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		se := NewStreamEstimator(estimator)
+		se.WriteString("some text")
+		se.Reset()
+		if tokens := se.Tokens(); tokens != 0 {
+			t.Errorf("Expected 0 tokens after Reset, got %d", tokens)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package tokenestimate
+
+import (
+	"io"
+	"strings"
+	"text/template"
+)
+
+// TemplateFuncs returns template functions backed by the estimator, for
+// use with Funcs on a text/template or html/template template:
+//
+//	tokens TEXT                 the estimate of TEXT
+//	truncateTokens N TEXT       the longest prefix of TEXT within N tokens
+//
+// so that a prompt template can be written as
+//
+//	{{if lt (tokens .History) 2000}}{{.History}}{{end}}
+//	{{.Document | truncateTokens 1000}}
+func (e *Estimator) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"tokens":         e.Estimate,
+		"truncateTokens": func(maxTokens int, text string) string { return e.Truncate(text, maxTokens) },
+	}
+}
+
+// TemplateExecutor is implemented by *text/template.Template and
+// *html/template.Template.
+type TemplateExecutor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// EstimateTemplate renders tmpl with data and returns the rendered text
+// together with its estimate. If rendering fails, it returns the error
+// and no text.
+func (e *Estimator) EstimateTemplate(tmpl TemplateExecutor, data any) (string, int, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", 0, err
+	}
+	text := sb.String()
+	return text, e.Estimate(text), nil
+}
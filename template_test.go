@@ -0,0 +1,55 @@
+package tokenestimate
+
+import (
+	htmltemplate "html/template"
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestTemplate(t *testing.T) {
+	estimator := NewEstimator()
+	doc := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 50)
+
+	t.Run("tokens", func(t *testing.T) {
+		tmpl := template.Must(template.New("t").Funcs(estimator.TemplateFuncs()).Parse(`{{tokens .}}`))
+		text, _, err := estimator.EstimateTemplate(tmpl, doc)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := estimator.Estimate(doc); text != strconv.Itoa(want) {
+			t.Errorf("Expected %d, got %s", want, text)
+		}
+	})
+
+	t.Run("truncateTokens", func(t *testing.T) {
+		tmpl := template.Must(template.New("t").Funcs(estimator.TemplateFuncs()).Parse(`{{. | truncateTokens 20}}`))
+		text, tokens, err := estimator.EstimateTemplate(tmpl, doc)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if text != estimator.Truncate(doc, 20) || tokens > 20 {
+			t.Errorf("Expected the truncated document, got %q (%d tokens)", text, tokens)
+		}
+	})
+
+	t.Run("html/template", func(t *testing.T) {
+		tmpl := htmltemplate.Must(htmltemplate.New("t").Funcs(estimator.TemplateFuncs()).Parse(
+			`<p>{{if gt (tokens .) 100}}{{. | truncateTokens 100}}{{else}}{{.}}{{end}}</p>`))
+		text, tokens, err := estimator.EstimateTemplate(tmpl, doc)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(text, "<p>The quick") || tokens != estimator.Estimate(text) {
+			t.Errorf("Unexpected output %q (%d tokens)", text, tokens)
+		}
+	})
+
+	t.Run("Execution error", func(t *testing.T) {
+		tmpl := template.Must(template.New("t").Parse(`{{.Missing.Field}}`))
+		if _, _, err := estimator.EstimateTemplate(tmpl, struct{}{}); err == nil {
+			t.Error("Expected execution error")
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"math"
+)
+
+// TermKind selects the shape of a nonlinear Term.
+type TermKind int
+
+const (
+	// TermPiecewise is piecewise linear in the feature: the count up to
+	// Breakpoints[0] is priced at Slopes[0], the count between
+	// Breakpoints[0] and Breakpoints[1] at Slopes[1], and so on, with the
+	// count beyond the last breakpoint priced at the last slope.
+	TermPiecewise TermKind = iota
+	// TermSqrt adds Coef * sqrt(x).
+	TermSqrt
+	// TermLog adds Coef * ln(1 + x).
+	TermLog
+)
+
+// Term is a nonlinear contribution of one Stats feature to the estimate,
+// added on top of the feature's linear coefficient. For example, a
+// piecewise term on "Digits" with Breakpoints {1000} and Slopes {0.1, 0}
+// makes the first 1000 digits cost 0.1 tokens more per digit than the rest.
+type Term struct {
+	Feature     string    // Name of a Stats field, such as "Digits"
+	Kind        TermKind  // Shape of the term
+	Coef        float64   // Scale of TermSqrt and TermLog
+	Breakpoints []float64 // Increasing segment boundaries of TermPiecewise
+	Slopes      []float64 // Per-segment slopes of TermPiecewise; one more than Breakpoints
+}
+
+// WithTerms returns a clone of the estimator with terms added to its
+// model. It returns an error if a term names an unknown feature or is
+// malformed.
+func (e *Estimator) WithTerms(terms ...Term) (*Estimator, error) {
+	for _, term := range terms {
+		if err := term.validate(); err != nil {
+			return nil, err
+		}
+	}
+	clone := e.Clone()
+	clone.Terms = append(clone.Terms, terms...)
+	return clone, nil
+}
+
+// validate checks that the term can be evaluated.
+func (t Term) validate() error {
+	if _, ok := featuresByName[t.Feature]; !ok {
+		return fmt.Errorf("unknown feature: %s", t.Feature)
+	}
+	switch t.Kind {
+	case TermSqrt, TermLog:
+		return nil
+	case TermPiecewise:
+		if len(t.Slopes) != len(t.Breakpoints)+1 {
+			return fmt.Errorf("term %s: %d breakpoints need %d slopes, got %d",
+				t.Feature, len(t.Breakpoints), len(t.Breakpoints)+1, len(t.Slopes))
+		}
+		for i, b := range t.Breakpoints {
+			if b <= 0 || (i > 0 && b <= t.Breakpoints[i-1]) {
+				return fmt.Errorf("term %s: breakpoints must be positive and increasing", t.Feature)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("term %s: unknown kind %d", t.Feature, t.Kind)
+	}
+}
+
+// value returns the term's contribution for stats. Terms naming an
+// unknown feature contribute nothing.
+func (t Term) value(stats *Stats) float64 {
+	f, ok := featuresByName[t.Feature]
+	if !ok {
+		return 0
+	}
+	x := float64(*f.field(stats))
+
+	switch t.Kind {
+	case TermSqrt:
+		return t.Coef * math.Sqrt(x)
+	case TermLog:
+		return t.Coef * math.Log1p(x)
+	case TermPiecewise:
+		if len(t.Slopes) != len(t.Breakpoints)+1 {
+			return 0
+		}
+		total, lower := 0.0, 0.0
+		for i, upper := range t.Breakpoints {
+			if x <= upper {
+				return total + t.Slopes[i]*(x-lower)
+			}
+			total += t.Slopes[i] * (upper - lower)
+			lower = upper
+		}
+		return total + t.Slopes[len(t.Slopes)-1]*(x-lower)
+	}
+	return 0
+}
@@ -0,0 +1,98 @@
+package tokenestimate
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFeaturesCoverStats(t *testing.T) {
+	typ := reflect.TypeOf(Stats{})
+	if typ.NumField() != len(features) {
+		t.Fatalf("Stats has %d fields, features lists %d", typ.NumField(), len(features))
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if features[i].name != typ.Field(i).Name {
+			t.Errorf("features[%d] = %s, want %s", i, features[i].name, typ.Field(i).Name)
+		}
+		var stats Stats
+		reflect.ValueOf(&stats).Elem().Field(i).SetInt(7)
+		if got := *features[i].field(&stats); got != 7 {
+			t.Errorf("feature %s reads %d, want 7", features[i].name, got)
+		}
+	}
+}
+
+func TestTermValue(t *testing.T) {
+	stats := &Stats{Digits: 1500}
+	tests := []struct {
+		name string
+		term Term
+		want float64
+	}{
+		{"Sqrt", Term{Feature: "Digits", Kind: TermSqrt, Coef: 2}, 2 * math.Sqrt(1500)},
+		{"Log", Term{Feature: "Digits", Kind: TermLog, Coef: 1}, math.Log1p(1500)},
+		{"Piecewise beyond breakpoint", Term{Feature: "Digits", Breakpoints: []float64{1000}, Slopes: []float64{0.1, 0.01}}, 100 + 5},
+		{"Piecewise within segment", Term{Feature: "Digits", Breakpoints: []float64{1000, 2000}, Slopes: []float64{0.1, 0.2, 0}}, 100 + 100},
+		{"Piecewise first segment", Term{Feature: "Digits", Breakpoints: []float64{2000}, Slopes: []float64{0.1, 0}}, 150},
+		{"Unknown feature", Term{Feature: "Nope", Kind: TermSqrt, Coef: 1}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.term.value(stats); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("value = %f, want %f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithTerms(t *testing.T) {
+	t.Run("Adds to the linear estimate", func(t *testing.T) {
+		base := &Estimator{Name: "terms-test", coefDigits: 0.3}
+		estimator, err := base.WithTerms(Term{Feature: "Digits", Breakpoints: []float64{10}, Slopes: []float64{0.2, 0}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		// 100 digits: 30 linear + 2 for the first ten digits.
+		if got := estimator.Estimate(strings.Repeat("1234567890", 10)); got != 32 {
+			t.Errorf("Expected 32 tokens, got %d", got)
+		}
+		if len(base.Terms) != 0 {
+			t.Error("Expected the original estimator to be unchanged")
+		}
+	})
+
+	t.Run("Built-in presets are linear", func(t *testing.T) {
+		if terms := NewEstimator().Terms; len(terms) != 0 {
+			t.Errorf("Expected no terms, got %v", terms)
+		}
+	})
+
+	t.Run("Clone copies terms", func(t *testing.T) {
+		estimator, _ := NewEstimator().WithTerms(Term{Feature: "Words", Kind: TermLog, Coef: 1})
+		clone := estimator.Clone()
+		clone.Terms[0].Coef = 5
+		if estimator.Terms[0].Coef != 1 {
+			t.Error("Expected clone's terms to be independent")
+		}
+	})
+
+	errorTests := []struct {
+		name string
+		term Term
+	}{
+		{"Unknown feature", Term{Feature: "Nope", Kind: TermLog}},
+		{"Slope count", Term{Feature: "Digits", Breakpoints: []float64{10}, Slopes: []float64{1}}},
+		{"Decreasing breakpoints", Term{Feature: "Digits", Breakpoints: []float64{10, 5}, Slopes: []float64{1, 1, 1}}},
+		{"Unknown kind", Term{Feature: "Digits", Kind: TermKind(99)}},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEstimator().WithTerms(tt.term); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
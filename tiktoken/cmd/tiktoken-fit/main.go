@@ -0,0 +1,49 @@
+// Command tiktoken-fit fits a tokenestimate preset to a tiktoken encoding,
+// or evaluates an existing preset against it, in one command: it labels
+// texts with their exact token counts and prints the fitted coefficients
+// with their accuracy on held-out texts.
+//
+// Usage:
+//
+//	go run github.com/infinigence/tokenestimate/tiktoken/cmd/tiktoken-fit -encoding o200k_base
+//	go run github.com/infinigence/tokenestimate/tiktoken/cmd/tiktoken-fit -model gpt-4o -dataset texts.jsonl
+//	go run github.com/infinigence/tokenestimate/tiktoken/cmd/tiktoken-fit -encoding cl100k_base -evaluate kimi-k2
+//
+// The texts are those of -dataset, whose token counts are ignored, or the
+// bundled multilingual corpus and synthetic texts of common language mixes
+// otherwise.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/infinigence/tokenestimate/internal/fitcmd"
+	"github.com/infinigence/tokenestimate/tiktoken"
+)
+
+func main() {
+	var (
+		encoding = flag.String("encoding", "o200k_base", "tiktoken encoding: "+strings.Join(tiktoken.Encodings(), ", "))
+		model    = flag.String("model", "", "OpenAI model whose encoding to use, instead of -encoding")
+		flags    = fitcmd.Register()
+	)
+	flag.Parse()
+	log.SetFlags(0)
+
+	var counter *tiktoken.Counter
+	var err error
+	if *model != "" {
+		counter, err = tiktoken.ForModel(*model)
+	} else {
+		counter, err = tiktoken.New(*encoding)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := flags.Run(os.Stdout, counter, counter.Encoding()); err != nil {
+		log.Fatal(err)
+	}
+}
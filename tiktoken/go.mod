@@ -0,0 +1,16 @@
+module github.com/infinigence/tokenestimate/tiktoken
+
+go 1.23.11
+
+require (
+	github.com/infinigence/tokenestimate v0.0.0
+	github.com/pkoukk/tiktoken-go v0.1.8
+	github.com/pkoukk/tiktoken-go-loader v0.0.2
+)
+
+require (
+	github.com/dlclark/regexp2 v1.10.0 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+)
+
+replace github.com/infinigence/tokenestimate => ../
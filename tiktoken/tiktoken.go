@@ -0,0 +1,84 @@
+// Package tiktoken counts tokens exactly with OpenAI's tiktoken encodings,
+// such as cl100k_base and o200k_base, through tiktoken-go. Its Counter is
+// a tokenestimate.TokenCounter: the ground truth for fitting and
+// evaluating presets locally, and an exact fallback where an estimate is
+// not good enough.
+//
+// The package is a separate module, so that the tokenestimate module
+// stays free of dependencies. The encodings are embedded, and counting
+// needs no network access.
+package tiktoken
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/infinigence/tokenestimate"
+	tiktoken "github.com/pkoukk/tiktoken-go"
+	loader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+func init() {
+	tiktoken.SetBpeLoader(loader.NewOfflineLoader())
+}
+
+// encodings lists the supported encodings, newest first.
+var encodings = []string{
+	tiktoken.MODEL_O200K_BASE,
+	tiktoken.MODEL_CL100K_BASE,
+	tiktoken.MODEL_P50K_BASE,
+	tiktoken.MODEL_R50K_BASE,
+}
+
+// Encodings returns the names of the supported encodings.
+func Encodings() []string {
+	return slices.Clone(encodings)
+}
+
+// Counter counts the tokens of texts with a tiktoken encoding. It is safe
+// for concurrent use.
+type Counter struct {
+	encoding string
+	enc      *tiktoken.Tiktoken
+}
+
+var _ tokenestimate.TokenCounter = (*Counter)(nil)
+
+// New returns a Counter for the named encoding, such as "o200k_base".
+func New(encoding string) (*Counter, error) {
+	if !slices.Contains(encodings, encoding) {
+		return nil, fmt.Errorf("unknown encoding: %s", encoding)
+	}
+	enc, err := tiktoken.GetEncoding(encoding)
+	if err != nil {
+		return nil, err
+	}
+	return &Counter{encoding: encoding, enc: enc}, nil
+}
+
+// ForModel returns a Counter for the encoding of an OpenAI model, such as
+// "gpt-4o" or "gpt-3.5-turbo".
+func ForModel(model string) (*Counter, error) {
+	if encoding, ok := tiktoken.MODEL_TO_ENCODING[model]; ok {
+		return New(encoding)
+	}
+	for prefix, encoding := range tiktoken.MODEL_PREFIX_TO_ENCODING {
+		if strings.HasPrefix(model, prefix) {
+			return New(encoding)
+		}
+	}
+	return nil, fmt.Errorf("unknown model: %s", model)
+}
+
+// Encoding returns the name of the counter's encoding.
+func (c *Counter) Encoding() string {
+	return c.encoding
+}
+
+// Count returns the number of tokens text encodes to. Special tokens such
+// as "<|endoftext|>" in text are counted as ordinary text, as an API
+// counts them in user content. The error is always nil.
+func (c *Counter) Count(text string) (int, error) {
+	return len(c.enc.EncodeOrdinary(text)), nil
+}
@@ -0,0 +1,271 @@
+package tiktoken
+
+import (
+	"bufio"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/infinigence/tokenestimate/corpus"
+	"github.com/infinigence/tokenestimate/eval"
+	"github.com/infinigence/tokenestimate/fit"
+)
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		encoding string
+		text     string
+		want     int
+	}{
+		{"cl100k_base", "Hello, world!", 4},
+		{"o200k_base", "Hello, world!", 4},
+		{"cl100k_base", "", 0},
+		{"cl100k_base", "<|endoftext|>", 7},
+	}
+	for _, tt := range tests {
+		c, err := New(tt.encoding)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got, err := c.Count(tt.text); err != nil || got != tt.want {
+			t.Errorf("%s: expected %q to count %d tokens, got %d, %v", tt.encoding, tt.text, tt.want, got, err)
+		}
+	}
+}
+
+func TestForModel(t *testing.T) {
+	tests := map[string]string{
+		"gpt-4o":        "o200k_base",
+		"gpt-4o-mini":   "o200k_base",
+		"gpt-4":         "cl100k_base",
+		"gpt-3.5-turbo": "cl100k_base",
+	}
+	for model, want := range tests {
+		c, err := ForModel(model)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", model, err)
+		}
+		if got := c.Encoding(); got != want {
+			t.Errorf("%s: expected %s, got %s", model, want, got)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := New("gpt2"); err == nil || !strings.Contains(err.Error(), "unknown encoding") {
+		t.Errorf("Expected an unknown encoding error, got %v", err)
+	}
+	if _, err := ForModel("kimi-k2"); err == nil || !strings.Contains(err.Error(), "unknown model") {
+		t.Errorf("Expected an unknown model error, got %v", err)
+	}
+}
+
+func TestRepeatedText(t *testing.T) {
+	c, err := New("o200k_base")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Words are split before merging, so repetition saves no tokens.
+	for _, text := range []string{
+		strings.Repeat("the cat sat on the mat ", 200),
+		strings.Repeat("2024-01-15T10:00:00Z INFO worker started job queue=default attempt=1\n", 50),
+	} {
+		want, err := c.Count(text)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		got := tokenestimate.NewEstimator().Estimate(text)
+		if diff := float64(got-want) / float64(want); diff < -0.3 || diff > 0.3 {
+			t.Errorf("Expected about %d tokens for %q..., got %d", want, text[:23], got)
+		}
+	}
+}
+
+// TestWhitespaceRunCost checks the WhitespaceRuns coefficient of the
+// default preset against the cost of whitespace runs measured by o200k_base:
+// the difference in tokens between the labeled texts and the texts with
+// every run collapsed to its first character, beyond what the preset
+// charges for the collapsed spaces and tabs.
+func TestWhitespaceRunCost(t *testing.T) {
+	c, err := New("o200k_base")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f, err := os.Open("../testdata/presets/o200k-base.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	texts := corpus.Texts()
+	for s := bufio.NewScanner(f); s.Scan(); {
+		var record struct{ Text string }
+		if err := json.Unmarshal(s.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		texts = append(texts, record.Text)
+	}
+
+	e := tokenestimate.NewEstimator()
+	coef := e.Coefficients()
+	run := regexp.MustCompile(`\s{2,}`)
+	var runs2, runsCost float64
+	for _, text := range texts {
+		collapsed := run.ReplaceAllStringFunc(text, func(r string) string { return r[:1] })
+		if collapsed == text {
+			continue
+		}
+		full, err := c.Count(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		short, err := c.Count(collapsed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, b := e.Analyze(text), e.Analyze(collapsed)
+		runs := float64(a.WhitespaceRuns - b.WhitespaceRuns)
+		cost := float64(full-short) - coef["Spaces"]*float64(a.Spaces-b.Spaces) - coef["Tabs"]*float64(a.Tabs-b.Tabs)
+		runs2 += runs * runs
+		runsCost += runs * cost
+	}
+	if fitted := runsCost / runs2; math.Abs(fitted-coef["WhitespaceRuns"]) > 0.05 {
+		t.Errorf("Expected a WhitespaceRuns coefficient near the measured %.3f, got %.3f", fitted, coef["WhitespaceRuns"])
+	}
+}
+
+func TestDictionaryCosts(t *testing.T) {
+	data, err := os.ReadFile("../testdata/dictionary/words.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"cl100k-base", "o200k-base"} {
+		t.Run(name, func(t *testing.T) {
+			e, err := tokenestimate.NewEstimatorWithName(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e = e.WithDictionary(true)
+			c, err := New(strings.ReplaceAll(name, "-", "_"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			priced := 0
+			for _, word := range strings.Split(string(data), "\n") {
+				if word == "" || word[0] == '#' || word[0] == '[' {
+					continue
+				}
+				forms := []string{word}
+				if word[0] < 0x80 {
+					capital := strings.ToUpper(word[:1]) + word[1:]
+					forms = []string{" " + word, word, " " + capital, capital}
+				}
+				for _, form := range forms {
+					stats := e.Analyze(form)
+					if stats.DictionaryWords == 0 {
+						continue
+					}
+					priced++
+					if want, _ := c.Count(form); stats.DictionaryTokens != want {
+						t.Errorf("%q is priced at %d tokens, %s counts %d", form, stats.DictionaryTokens, c.Encoding(), want)
+					}
+				}
+			}
+			if priced == 0 {
+				t.Error("Expected dictionary words")
+			}
+		})
+	}
+}
+
+func TestTokenCeilings(t *testing.T) {
+	// An estimator whose coefficients are far too high estimates every
+	// text at the ceiling of its token bounds.
+	base := tokenestimate.NewEstimator()
+	coefs := base.Coefficients()
+	for feature := range coefs {
+		coefs[feature] = 100
+	}
+	ceiling, err := base.WithCoefficients(0, coefs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pools of characters of each category, found by analyzing them after
+	// plain letters, which keep Latin Extended letters from counting as
+	// symbols.
+	categories := map[string]func(tokenestimate.Stats) int{
+		"Symbols":       func(s tokenestimate.Stats) int { return s.Symbols },
+		"LatinExtended": func(s tokenestimate.Stats) int { return s.LatinExtended },
+		"Digits":        func(s tokenestimate.Stats) int { return s.Digits },
+		"ChineseChars":  func(s tokenestimate.Stats) int { return s.ChineseChars },
+		"JapaneseKana":  func(s tokenestimate.Stats) int { return s.JapaneseKana },
+		"KoreanHangul":  func(s tokenestimate.Stats) int { return s.KoreanHangul },
+		"RussianChars":  func(s tokenestimate.Stats) int { return s.RussianChars },
+		"ArabicChars":   func(s tokenestimate.Stats) int { return s.ArabicChars },
+		"KhmerChars":    func(s tokenestimate.Stats) int { return s.KhmerChars },
+		"LaoChars":      func(s tokenestimate.Stats) int { return s.LaoChars },
+		"MyanmarChars":  func(s tokenestimate.Stats) int { return s.MyanmarChars },
+		"EthiopicChars": func(s tokenestimate.Stats) int { return s.EthiopicChars },
+		"Spaces":        func(s tokenestimate.Stats) int { return s.Spaces },
+		"Unknown":       func(s tokenestimate.Stats) int { return s.Unknown },
+	}
+	pools := make(map[string][]rune)
+	for r := rune(0x80); r < 0x30000; r++ {
+		if r >= 0xD800 && r < 0xE000 {
+			continue
+		}
+		stats := base.Analyze("abcdefghijklmnop" + string(r))
+		for name, count := range categories {
+			if count(stats) == 1 {
+				pools[name] = append(pools[name], r)
+			}
+		}
+	}
+
+	for _, encoding := range []string{"cl100k_base", "o200k_base"} {
+		c, err := New(encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, pool := range pools {
+			rng := rand.New(rand.NewSource(1))
+			for trial := 0; trial < 20; trial++ {
+				var b strings.Builder
+				for range 400 {
+					b.WriteRune(pool[rng.Intn(len(pool))])
+				}
+				text := b.String()
+				got, _ := c.Count(text)
+				if high := ceiling.Estimate(text); got > high {
+					t.Errorf("%s: random %s take %d tokens, above the ceiling of %d", encoding, name, got, high)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	c, err := New("o200k_base")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p := tokenestimate.LanguageProfile{Latin: 0.6, Chinese: 0.2, Whitespace: 0.15, Symbols: 0.05}
+	samples, err := fit.Label(c, tokenestimate.GenerateTexts(p, 200, 50, 1000, 1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	train, test := fit.Holdout(samples, 0.2, 1)
+	e, _, err := fit.Fit(train, fit.FitOptions{Method: fit.NNLS, Relative: true, Name: "o200k"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r := eval.Evaluate(e, test); r.PassRate < 0.95 {
+		t.Errorf("Expected a fitted preset to estimate o200k_base closely, got\n%s", r)
+	}
+}
@@ -0,0 +1,92 @@
+// Command tokenizer-fit fits a tokenestimate preset to the tokenizer of an
+// open-weights model, read from its HuggingFace tokenizer.json file or its
+// SentencePiece .model file, or evaluates an existing preset against it.
+// Supporting a new model takes a single command:
+//
+//	go run github.com/infinigence/tokenestimate/tokenizers/cmd/tokenizer-fit path/to/tokenizer.json
+//	go run github.com/infinigence/tokenestimate/tokenizers/cmd/tokenizer-fit path/to/tokenizer.model
+//	go run github.com/infinigence/tokenestimate/tokenizers/cmd/tokenizer-fit -dataset texts.jsonl tokenizer.json
+//	go run github.com/infinigence/tokenestimate/tokenizers/cmd/tokenizer-fit -evaluate kimi-k2 tokenizer.json
+//
+// It labels the bundled multilingual corpus and synthetic texts of common
+// language mixes, or the texts of -dataset, with their exact token counts
+// and prints the fitted coefficients with their accuracy on held-out texts.
+// Files with the .model extension are read as SentencePiece models. The
+// preset is named after the directory of a tokenizer.json, tokenizer.model
+// or spiece.model file unless -name says otherwise.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/infinigence/tokenestimate/internal/fitcmd"
+	"github.com/infinigence/tokenestimate/tokenizers/huggingface"
+	"github.com/infinigence/tokenestimate/tokenizers/sentencepiece"
+)
+
+func main() {
+	flags := fitcmd.Register()
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: tokenizer-fit [flags] tokenizer.json|tokenizer.model\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetFlags(0)
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	path := flag.Arg(0)
+	tok, err := load(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := flags.Run(os.Stdout, tok, modelName(path)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load reads the tokenizer at path, a SentencePiece model if its extension
+// is .model and a tokenizer.json file otherwise.
+func load(path string) (tokenestimate.TokenCounter, error) {
+	if strings.EqualFold(filepath.Ext(path), ".model") {
+		tok, err := sentencepiece.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return tok, nil
+	}
+	tok, err := huggingface.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return tok, nil
+}
+
+// fileNames are the names models give their tokenizer files, which say
+// nothing about the model.
+var fileNames = map[string]bool{
+	"tokenizer.json":  true,
+	"tokenizer.model": true,
+	"spiece.model":    true,
+}
+
+// modelName returns the name of the model whose tokenizer is at path: the
+// directory of models/Qwen2.5-7B/tokenizer.json, or the file name without
+// its extension otherwise.
+func modelName(path string) string {
+	abs, err := filepath.Abs(path)
+	if err == nil && fileNames[filepath.Base(abs)] {
+		if dir := filepath.Base(filepath.Dir(abs)); dir != string(filepath.Separator) {
+			return dir
+		}
+	}
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
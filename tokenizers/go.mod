@@ -0,0 +1,11 @@
+module github.com/infinigence/tokenestimate/tokenizers
+
+go 1.23.11
+
+require (
+	github.com/dlclark/regexp2 v1.11.5
+	github.com/infinigence/tokenestimate v0.0.0
+	golang.org/x/text v0.28.0
+)
+
+replace github.com/infinigence/tokenestimate => ../
@@ -0,0 +1,229 @@
+package huggingface
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// bpe merges the characters of words into tokens pair by pair, in the
+// order of its merge list, the model of GPT and Llama tokenizers.
+type bpe struct {
+	vocab        map[string]int
+	merges       map[[2]int]merge
+	unk          int
+	prefix       string // continuing_subword_prefix
+	suffix       string // end_of_word_suffix
+	byteFallback bool
+	fuseUnk      bool
+	ignoreMerges bool // words in the vocabulary are single tokens
+
+	mu    sync.RWMutex
+	cache map[string][]int
+}
+
+// merge is the result of merging a pair of tokens.
+type merge struct {
+	rank int // position in the merge list; lower merges first
+	id   int
+}
+
+// bpeCacheSize bounds the number of words whose tokens are cached.
+const bpeCacheSize = 10000
+
+func parseBPE(raw json.RawMessage) (*bpe, error) {
+	var c struct {
+		Vocab                   map[string]int    `json:"vocab"`
+		Merges                  []json.RawMessage `json:"merges"`
+		UnkToken                *string           `json:"unk_token"`
+		ContinuingSubwordPrefix *string           `json:"continuing_subword_prefix"`
+		EndOfWordSuffix         *string           `json:"end_of_word_suffix"`
+		ByteFallback            bool              `json:"byte_fallback"`
+		FuseUnk                 bool              `json:"fuse_unk"`
+		IgnoreMerges            bool              `json:"ignore_merges"`
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	m := &bpe{
+		vocab:        c.Vocab,
+		merges:       make(map[[2]int]merge, len(c.Merges)),
+		unk:          unknown(c.Vocab, c.UnkToken),
+		byteFallback: c.ByteFallback,
+		fuseUnk:      c.FuseUnk,
+		ignoreMerges: c.IgnoreMerges,
+		cache:        make(map[string][]int),
+	}
+	if c.ContinuingSubwordPrefix != nil {
+		m.prefix = *c.ContinuingSubwordPrefix
+	}
+	if c.EndOfWordSuffix != nil {
+		m.suffix = *c.EndOfWordSuffix
+	}
+	for rank, raw := range c.Merges {
+		// Merges are "a b" strings, or ["a", "b"] pairs in newer files.
+		var pair []string
+		if err := json.Unmarshal(raw, &pair); err != nil {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, fmt.Errorf("merge %d: %w", rank, err)
+			}
+			pair = strings.SplitN(s, " ", 2)
+		}
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("merge %d: invalid pair %q", rank, pair)
+		}
+		a, okA := m.vocab[pair[0]]
+		b, okB := m.vocab[pair[1]]
+		id, ok := m.vocab[pair[0]+strings.TrimPrefix(pair[1], m.prefix)]
+		if !okA || !okB || !ok {
+			return nil, fmt.Errorf("merge %d: %q not in the vocabulary", rank, pair)
+		}
+		if _, dup := m.merges[[2]int{a, b}]; !dup {
+			m.merges[[2]int{a, b}] = merge{rank: rank, id: id}
+		}
+	}
+	return m, nil
+}
+
+func (m *bpe) tokenize(word string, ids []int) []int {
+	if word == "" {
+		return ids
+	}
+	if m.ignoreMerges {
+		if id, ok := m.vocab[word]; ok {
+			return append(ids, id)
+		}
+	}
+	m.mu.RLock()
+	cached, ok := m.cache[word]
+	m.mu.RUnlock()
+	if ok {
+		return append(ids, cached...)
+	}
+
+	tokens := m.merge(m.symbols(word))
+	m.mu.Lock()
+	if len(m.cache) >= bpeCacheSize {
+		clear(m.cache)
+	}
+	m.cache[word] = tokens
+	m.mu.Unlock()
+	return append(ids, tokens...)
+}
+
+// symbols returns the IDs of the characters of word, the tokens merging
+// starts from.
+func (m *bpe) symbols(word string) []int {
+	symbols := make([]int, 0, len(word))
+	unk := false
+	for i, r := range word {
+		size := utf8.RuneLen(r)
+		if r == utf8.RuneError {
+			_, size = utf8.DecodeRuneInString(word[i:])
+		}
+		s := word[i : i+size]
+		if i > 0 {
+			s = m.prefix + s
+		}
+		if i+size == len(word) {
+			s += m.suffix
+		}
+		if id, ok := m.vocab[s]; ok {
+			symbols = append(symbols, id)
+			unk = false
+			continue
+		}
+		if m.byteFallback {
+			var ok bool
+			if symbols, ok = appendBytes(symbols, word[i:i+size], m.vocab); ok {
+				unk = false
+				continue
+			}
+		}
+		if m.unk >= 0 && !(m.fuseUnk && unk) {
+			symbols = append(symbols, m.unk)
+		}
+		unk = true
+	}
+	return symbols
+}
+
+// merge applies the merges to symbols, lowest rank first and leftmost
+// first among equal ranks, until none applies.
+func (m *bpe) merge(symbols []int) []int {
+	if len(symbols) < 2 {
+		return symbols
+	}
+	next := make([]int, len(symbols)) // next live symbol, or -1
+	prev := make([]int, len(symbols)) // previous live symbol, or -1
+	for i := range symbols {
+		next[i], prev[i] = i+1, i-1
+	}
+	next[len(next)-1] = -1
+
+	var queue pairQueue
+	push := func(left int) {
+		if left < 0 || next[left] < 0 {
+			return
+		}
+		right := next[left]
+		if mg, ok := m.merges[[2]int{symbols[left], symbols[right]}]; ok {
+			heap.Push(&queue, pair{rank: mg.rank, left: left, ids: [2]int{symbols[left], symbols[right]}})
+		}
+	}
+	for i := range len(symbols) - 1 {
+		push(i)
+	}
+	for queue.Len() > 0 {
+		p := heap.Pop(&queue).(pair)
+		right := next[p.left]
+		// Skip pairs whose symbols have merged since they were queued.
+		if symbols[p.left] != p.ids[0] || right < 0 || symbols[right] != p.ids[1] {
+			continue
+		}
+		symbols[p.left] = m.merges[p.ids].id
+		symbols[right] = -1
+		next[p.left] = next[right]
+		if next[right] >= 0 {
+			prev[next[right]] = p.left
+		}
+		push(prev[p.left])
+		push(p.left)
+	}
+
+	out := symbols[:0]
+	for i := 0; i >= 0; i = next[i] {
+		out = append(out, symbols[i])
+	}
+	return out
+}
+
+// pair is a pair of adjacent symbols that can merge.
+type pair struct {
+	rank int
+	left int // position of the left symbol
+	ids  [2]int
+}
+
+// pairQueue orders pairs by rank, then position.
+type pairQueue []pair
+
+func (q pairQueue) Len() int { return len(q) }
+func (q pairQueue) Less(i, j int) bool {
+	if q[i].rank != q[j].rank {
+		return q[i].rank < q[j].rank
+	}
+	return q[i].left < q[j].left
+}
+func (q pairQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *pairQueue) Push(x any)   { *q = append(*q, x.(pair)) }
+func (q *pairQueue) Pop() any {
+	old := *q
+	p := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return p
+}
@@ -0,0 +1,268 @@
+package huggingface
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// model splits a word into tokens, appending their IDs to ids.
+type model interface {
+	tokenize(word string, ids []int) []int
+}
+
+// parseModel returns the model of a tokenizer.json component.
+func parseModel(raw json.RawMessage) (model, error) {
+	typ, ok, err := typeOf(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("missing model")
+	}
+	switch typ {
+	case "BPE":
+		return parseBPE(raw)
+	case "WordPiece":
+		return parseWordPiece(raw)
+	case "Unigram":
+		return parseUnigram(raw)
+	case "WordLevel":
+		return parseWordLevel(raw)
+	case "":
+		// Files written by old versions of the tokenizers library leave
+		// out the type of BPE models.
+		var probe struct {
+			Merges json.RawMessage `json:"merges"`
+		}
+		if json.Unmarshal(raw, &probe) == nil && probe.Merges != nil {
+			return parseBPE(raw)
+		}
+	}
+	return nil, fmt.Errorf("unknown model: %s", typ)
+}
+
+// unknown returns the ID of the unknown token in vocab, or -1.
+func unknown(vocab map[string]int, token *string) int {
+	if token == nil {
+		return -1
+	}
+	if id, ok := vocab[*token]; ok {
+		return id
+	}
+	return -1
+}
+
+// appendBytes appends the byte-fallback tokens of s, "<0x41>" and so on,
+// and reports whether the vocabulary has all of them.
+func appendBytes(ids []int, s string, vocab map[string]int) ([]int, bool) {
+	n := len(ids)
+	for i := 0; i < len(s); i++ {
+		id, ok := vocab[fmt.Sprintf("<0x%02X>", s[i])]
+		if !ok {
+			return ids[:n], false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+// wordPiece splits words greedily into the longest tokens of its
+// vocabulary, BERT's model.
+type wordPiece struct {
+	vocab    map[string]int
+	unk      int
+	prefix   string
+	maxChars int
+}
+
+func parseWordPiece(raw json.RawMessage) (*wordPiece, error) {
+	var c struct {
+		Vocab                   map[string]int `json:"vocab"`
+		UnkToken                *string        `json:"unk_token"`
+		ContinuingSubwordPrefix *string        `json:"continuing_subword_prefix"`
+		MaxInputCharsPerWord    *int           `json:"max_input_chars_per_word"`
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	m := &wordPiece{vocab: c.Vocab, unk: unknown(c.Vocab, c.UnkToken), prefix: "##", maxChars: 100}
+	if c.ContinuingSubwordPrefix != nil {
+		m.prefix = *c.ContinuingSubwordPrefix
+	}
+	if c.MaxInputCharsPerWord != nil {
+		m.maxChars = *c.MaxInputCharsPerWord
+	}
+	return m, nil
+}
+
+func (m *wordPiece) tokenize(word string, ids []int) []int {
+	if word == "" {
+		return ids
+	}
+	if utf8.RuneCountInString(word) > m.maxChars {
+		return append(ids, m.unk)
+	}
+	n := len(ids)
+	for start := 0; start < len(word); {
+		end, id := len(word), -1
+		for ; end > start; end-- {
+			if end < len(word) && !utf8.RuneStart(word[end]) {
+				continue
+			}
+			piece := word[start:end]
+			if start > 0 {
+				piece = m.prefix + piece
+			}
+			if v, ok := m.vocab[piece]; ok {
+				id = v
+				break
+			}
+		}
+		if id < 0 {
+			return append(ids[:n], m.unk)
+		}
+		ids = append(ids, id)
+		start = end
+	}
+	return ids
+}
+
+// wordLevel maps each word to a single token.
+type wordLevel struct {
+	vocab map[string]int
+	unk   int
+}
+
+func parseWordLevel(raw json.RawMessage) (*wordLevel, error) {
+	var c struct {
+		Vocab    map[string]int `json:"vocab"`
+		UnkToken *string        `json:"unk_token"`
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	return &wordLevel{vocab: c.Vocab, unk: unknown(c.Vocab, c.UnkToken)}, nil
+}
+
+func (m *wordLevel) tokenize(word string, ids []int) []int {
+	if word == "" {
+		return ids
+	}
+	if id, ok := m.vocab[word]; ok {
+		return append(ids, id)
+	}
+	return append(ids, m.unk)
+}
+
+// unigram splits words into the tokens whose probabilities have the
+// largest product, SentencePiece's default model.
+type unigram struct {
+	pieces       map[string]int // ID of each piece
+	scores       []float64      // log probability of each ID
+	unk          int
+	unkScore     float64
+	maxLen       int // longest piece in bytes
+	byteFallback bool
+}
+
+func parseUnigram(raw json.RawMessage) (*unigram, error) {
+	var c struct {
+		UnkID        *int                 `json:"unk_id"`
+		Vocab        [][2]json.RawMessage `json:"vocab"`
+		ByteFallback bool                 `json:"byte_fallback"`
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	m := &unigram{pieces: make(map[string]int, len(c.Vocab)), scores: make([]float64, len(c.Vocab)), unk: -1, byteFallback: c.ByteFallback}
+	minScore := math.Inf(1)
+	for id, entry := range c.Vocab {
+		var piece string
+		if err := json.Unmarshal(entry[0], &piece); err != nil {
+			return nil, fmt.Errorf("piece %d: %w", id, err)
+		}
+		if err := json.Unmarshal(entry[1], &m.scores[id]); err != nil {
+			return nil, fmt.Errorf("piece %d: %w", id, err)
+		}
+		if _, dup := m.pieces[piece]; !dup {
+			m.pieces[piece] = id
+		}
+		m.maxLen = max(m.maxLen, len(piece))
+		minScore = min(minScore, m.scores[id])
+	}
+	if c.UnkID != nil {
+		m.unk = *c.UnkID
+	}
+	m.unkScore = minScore - 10
+	return m, nil
+}
+
+// tokenize appends the most probable segmentation of word into pieces.
+// Characters no piece covers are unknown tokens, with consecutive ones
+// fused, or their bytes with byte fallback.
+func (m *unigram) tokenize(word string, ids []int) []int {
+	if word == "" {
+		return ids
+	}
+	type node struct {
+		score float64
+		start int // start of the last piece
+		id    int // ID of the last piece, or -1 if unknown
+	}
+	best := make([]node, len(word)+1)
+	for i := 1; i <= len(word); i++ {
+		best[i].score = math.Inf(-1)
+	}
+	for start := 0; start < len(word); start++ {
+		if !utf8.RuneStart(word[start]) || math.IsInf(best[start].score, -1) {
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(word[start:])
+		single := false
+		for end := start + 1; end <= min(len(word), start+m.maxLen); end++ {
+			if end < len(word) && !utf8.RuneStart(word[end]) {
+				continue
+			}
+			id, ok := m.pieces[word[start:end]]
+			if !ok {
+				continue
+			}
+			single = single || end == start+size
+			if s := best[start].score + m.scores[id]; s > best[end].score {
+				best[end] = node{score: s, start: start, id: id}
+			}
+		}
+		if !single {
+			if s := best[start].score + m.unkScore; s > best[start+size].score {
+				best[start+size] = node{score: s, start: start, id: -1}
+			}
+		}
+	}
+
+	// Walk back from the end, then append the pieces in order.
+	type piece struct{ start, end, id int }
+	var path []piece
+	for end := len(word); end > 0; end = best[end].start {
+		path = append(path, piece{best[end].start, end, best[end].id})
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		p := path[i]
+		if p.id >= 0 {
+			ids = append(ids, p.id)
+			continue
+		}
+		if m.byteFallback {
+			var ok bool
+			if ids, ok = appendBytes(ids, word[p.start:p.end], m.pieces); ok {
+				continue
+			}
+		}
+		if i == len(path)-1 || path[i+1].id >= 0 {
+			ids = append(ids, m.unk)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,213 @@
+package huggingface
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dlclark/regexp2"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/infinigence/tokenestimate/tokenizers/internal/charsmap"
+)
+
+// normalizer rewrites text before it is split into words.
+type normalizer interface {
+	normalize(s string) string
+}
+
+// normalizerFunc is a normalizer implemented by a function.
+type normalizerFunc func(s string) string
+
+func (f normalizerFunc) normalize(s string) string { return f(s) }
+
+// normalizerSequence applies normalizers in order.
+type normalizerSequence []normalizer
+
+func (seq normalizerSequence) normalize(s string) string {
+	for _, n := range seq {
+		s = n.normalize(s)
+	}
+	return s
+}
+
+// normalizerConfig holds the fields of every normalizer type.
+type normalizerConfig struct {
+	Type                string            `json:"type"`
+	Normalizers         []json.RawMessage `json:"normalizers"`
+	Pattern             pattern           `json:"pattern"`
+	Content             string            `json:"content"`
+	Prepend             string            `json:"prepend"`
+	StripLeft           bool              `json:"strip_left"`
+	StripRight          bool              `json:"strip_right"`
+	CleanText           bool              `json:"clean_text"`
+	HandleChineseChars  bool              `json:"handle_chinese_chars"`
+	StripAccents        *bool             `json:"strip_accents"`
+	Lowercase           bool              `json:"lowercase"`
+	PrecompiledCharsmap []byte            `json:"precompiled_charsmap"`
+}
+
+// parseNormalizer returns the normalizer of a tokenizer.json component, or
+// nil for null.
+func parseNormalizer(raw json.RawMessage) (normalizer, error) {
+	if _, ok, err := typeOf(raw); !ok || err != nil {
+		return nil, err
+	}
+	var c normalizerConfig
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	switch c.Type {
+	case "Sequence":
+		seq := make(normalizerSequence, 0, len(c.Normalizers))
+		for _, raw := range c.Normalizers {
+			n, err := parseNormalizer(raw)
+			if err != nil {
+				return nil, err
+			}
+			if n != nil {
+				seq = append(seq, n)
+			}
+		}
+		return seq, nil
+	case "NFC":
+		return normalizerFunc(norm.NFC.String), nil
+	case "NFD":
+		return normalizerFunc(norm.NFD.String), nil
+	case "NFKC":
+		return normalizerFunc(norm.NFKC.String), nil
+	case "NFKD":
+		return normalizerFunc(norm.NFKD.String), nil
+	case "Lowercase":
+		return normalizerFunc(strings.ToLower), nil
+	case "StripAccents":
+		return normalizerFunc(stripAccents), nil
+	case "Strip":
+		return normalizerFunc(func(s string) string {
+			if c.StripLeft {
+				s = strings.TrimLeftFunc(s, unicode.IsSpace)
+			}
+			if c.StripRight {
+				s = strings.TrimRightFunc(s, unicode.IsSpace)
+			}
+			return s
+		}), nil
+	case "Prepend":
+		return normalizerFunc(func(s string) string {
+			if s == "" {
+				return s
+			}
+			return c.Prepend + s
+		}), nil
+	case "Replace":
+		re, err := c.Pattern.compile()
+		if err != nil {
+			return nil, err
+		}
+		return normalizerFunc(func(s string) string {
+			out, err := re.ReplaceFunc(s, func(regexp2.Match) string { return c.Content }, -1, -1)
+			if err != nil {
+				return s
+			}
+			return out
+		}), nil
+	case "Nmt":
+		return normalizerFunc(nmt), nil
+	case "BertNormalizer":
+		// Accents are stripped when lowercasing unless said otherwise.
+		strip := c.Lowercase
+		if c.StripAccents != nil {
+			strip = *c.StripAccents
+		}
+		return normalizerFunc(func(s string) string {
+			if c.CleanText {
+				s = cleanText(s)
+			}
+			if c.HandleChineseChars {
+				s = padChineseChars(s)
+			}
+			if strip {
+				s = stripAccents(s)
+			}
+			if c.Lowercase {
+				s = strings.ToLower(s)
+			}
+			return s
+		}), nil
+	case "Precompiled":
+		m, err := charsmap.Parse(c.PrecompiledCharsmap)
+		if err != nil {
+			return nil, err
+		}
+		return normalizerFunc(m.Normalize), nil
+	}
+	return nil, fmt.Errorf("unknown normalizer: %s", c.Type)
+}
+
+// stripAccents removes combining marks after decomposing s.
+func stripAccents(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(s))
+}
+
+// cleanText removes control characters and turns whitespace into spaces,
+// as BERT's tokenizer does.
+func cleanText(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			return ' '
+		case r == 0 || r == unicode.ReplacementChar || unicode.In(r, unicode.C):
+			return -1
+		case unicode.IsSpace(r):
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// padChineseChars surrounds CJK ideographs with spaces, so that each is a
+// word of its own.
+func padChineseChars(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if isChineseChar(r) {
+			b.WriteByte(' ')
+			b.WriteRune(r)
+			b.WriteByte(' ')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isChineseChar reports whether r is in a CJK Unified Ideographs block.
+func isChineseChar(r rune) bool {
+	return r >= 0x4E00 && r <= 0x9FFF ||
+		r >= 0x3400 && r <= 0x4DBF ||
+		r >= 0x20000 && r <= 0x2A6DF ||
+		r >= 0x2A700 && r <= 0x2CEAF ||
+		r >= 0xF900 && r <= 0xFAFF ||
+		r >= 0x2F800 && r <= 0x2FA1F
+}
+
+// nmt removes control characters and turns other separators into spaces,
+// as SentencePiece's nmt_nfkc rules do before applying NFKC.
+func nmt(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 0x01 && r <= 0x08, r == 0x0B, r >= 0x0E && r <= 0x1F, r == 0x7F, r == 0x8F, r == 0x9F:
+			return -1
+		case r == 0x09, r == 0x0A, r == 0x0C, r == 0x0D, r == 0x1680, r >= 0x200B && r <= 0x200F,
+			r == 0x2028, r == 0x2029, r == 0x2581, r == 0xFEFF, r == 0xFFFD:
+			return ' '
+		}
+		return r
+	}, s)
+}
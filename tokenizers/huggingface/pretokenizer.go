@@ -0,0 +1,370 @@
+package huggingface
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dlclark/regexp2"
+)
+
+// preTokenizer splits words into smaller words, which the model tokenizes
+// independently. atStart reports whether the first word starts the text.
+type preTokenizer interface {
+	split(words []string, atStart bool) []string
+}
+
+// preTokenizerSequence applies pre-tokenizers in order.
+type preTokenizerSequence []preTokenizer
+
+func (seq preTokenizerSequence) split(words []string, atStart bool) []string {
+	for _, p := range seq {
+		words = p.split(words, atStart)
+	}
+	return words
+}
+
+// preTokenizerConfig holds the fields of every pre-tokenizer type.
+type preTokenizerConfig struct {
+	Type             string            `json:"type"`
+	PreTokenizers    []json.RawMessage `json:"pretokenizers"`
+	Pattern          pattern           `json:"pattern"`
+	Behavior         string            `json:"behavior"`
+	Invert           bool              `json:"invert"`
+	AddPrefixSpace   *bool             `json:"add_prefix_space"`
+	UseRegex         *bool             `json:"use_regex"`
+	Replacement      string            `json:"replacement"`
+	PrependScheme    string            `json:"prepend_scheme"`
+	Split            *bool             `json:"split"`
+	IndividualDigits bool              `json:"individual_digits"`
+	Delimiter        string            `json:"delimiter"`
+}
+
+// gpt2Pattern is the regular expression GPT-2 splits words with, which the
+// ByteLevel pre-tokenizer uses by default.
+const gpt2Pattern = `'s|'t|'re|'ve|'m|'ll|'d| ?\p{L}+| ?\p{N}+| ?[^\s\p{L}\p{N}]+|\s+(?!\S)|\s+`
+
+// parsePreTokenizer returns the pre-tokenizer of a tokenizer.json
+// component, or nil for null.
+func parsePreTokenizer(raw json.RawMessage) (preTokenizer, error) {
+	if _, ok, err := typeOf(raw); !ok || err != nil {
+		return nil, err
+	}
+	var c preTokenizerConfig
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, err
+	}
+	switch c.Type {
+	case "Sequence":
+		seq := make(preTokenizerSequence, 0, len(c.PreTokenizers))
+		for _, raw := range c.PreTokenizers {
+			p, err := parsePreTokenizer(raw)
+			if err != nil {
+				return nil, err
+			}
+			if p != nil {
+				seq = append(seq, p)
+			}
+		}
+		return seq, nil
+	case "Split":
+		re, err := c.Pattern.compile()
+		if err != nil {
+			return nil, err
+		}
+		b, err := parseBehavior(c.Behavior)
+		if err != nil {
+			return nil, err
+		}
+		return splitter{match: regexMatcher(re), behavior: b, invert: c.Invert}, nil
+	case "ByteLevel":
+		p := byteLevel{addPrefixSpace: c.AddPrefixSpace == nil || *c.AddPrefixSpace}
+		if c.UseRegex == nil || *c.UseRegex {
+			p.re = regexp2.MustCompile(gpt2Pattern, regexp2.None)
+		}
+		return p, nil
+	case "Metaspace":
+		p := metaspace{replacement: c.Replacement, scheme: c.PrependScheme}
+		if p.replacement == "" {
+			p.replacement = "▁"
+		}
+		if c.Split == nil || *c.Split {
+			re := regexp2.MustCompile(regexp2.Escape(p.replacement), regexp2.None)
+			p.splitter = &splitter{match: regexMatcher(re), behavior: mergedWithNext}
+		}
+		if p.scheme == "" {
+			// Older files say add_prefix_space instead.
+			p.scheme = "always"
+			if c.AddPrefixSpace != nil && !*c.AddPrefixSpace {
+				p.scheme = "never"
+			}
+		}
+		return p, nil
+	case "Whitespace":
+		return splitter{match: regexMatcher(regexp2.MustCompile(`\w+|[^\w\s]+`, regexp2.None)), behavior: removed, invert: true}, nil
+	case "WhitespaceSplit":
+		return splitter{match: runeMatcher(unicode.IsSpace), behavior: removed}, nil
+	case "BertPreTokenizer":
+		return preTokenizerSequence{
+			splitter{match: runeMatcher(unicode.IsSpace), behavior: removed},
+			splitter{match: runeMatcher(isPunctuation), behavior: isolated},
+		}, nil
+	case "Punctuation":
+		b, err := parseBehavior(c.Behavior)
+		if err != nil {
+			return nil, err
+		}
+		return splitter{match: runeMatcher(isPunctuation), behavior: b}, nil
+	case "Digits":
+		if c.IndividualDigits {
+			return splitter{match: runeMatcher(unicode.IsDigit), behavior: isolated}, nil
+		}
+		return splitter{match: runeMatcher(unicode.IsDigit), behavior: contiguous}, nil
+	case "CharDelimiterSplit":
+		delimiter := []rune(c.Delimiter)
+		if len(delimiter) != 1 {
+			return nil, fmt.Errorf("invalid delimiter %q", c.Delimiter)
+		}
+		return splitter{match: runeMatcher(func(r rune) bool { return r == delimiter[0] }), behavior: removed}, nil
+	}
+	return nil, fmt.Errorf("unknown pre-tokenizer: %s", c.Type)
+}
+
+// pattern is a literal string or a regular expression.
+type pattern struct {
+	String *string `json:"String"`
+	Regex  *string `json:"Regex"`
+}
+
+// compile returns the regular expression matching the pattern.
+func (p pattern) compile() (*regexp2.Regexp, error) {
+	switch {
+	case p.String != nil:
+		return regexp2.Compile(regexp2.Escape(*p.String), regexp2.None)
+	case p.Regex != nil:
+		return regexp2.Compile(*p.Regex, regexp2.None)
+	}
+	return nil, errors.New("missing pattern")
+}
+
+// matcher returns the spans of runes that match a pattern, in order.
+type matcher func(runes []rune) [][2]int
+
+// regexMatcher matches the non-empty matches of re.
+func regexMatcher(re *regexp2.Regexp) matcher {
+	return func(runes []rune) [][2]int {
+		var spans [][2]int
+		m, _ := re.FindRunesMatch(runes)
+		for m != nil {
+			if m.Length > 0 {
+				spans = append(spans, [2]int{m.Index, m.Index + m.Length})
+			}
+			m, _ = re.FindNextMatch(m)
+		}
+		return spans
+	}
+}
+
+// runeMatcher matches each rune for which f is true.
+func runeMatcher(f func(rune) bool) matcher {
+	return func(runes []rune) [][2]int {
+		var spans [][2]int
+		for i, r := range runes {
+			if f(r) {
+				spans = append(spans, [2]int{i, i + 1})
+			}
+		}
+		return spans
+	}
+}
+
+// behavior is what a splitter does with the matches of its pattern.
+type behavior int
+
+const (
+	removed            behavior = iota // dropped
+	isolated                           // words of their own
+	mergedWithPrevious                 // appended to the preceding word
+	mergedWithNext                     // prepended to the following word
+	contiguous                         // adjacent matches form one word
+)
+
+// parseBehavior parses the behavior of a splitter.
+func parseBehavior(s string) (behavior, error) {
+	switch s {
+	case "Removed":
+		return removed, nil
+	case "Isolated", "":
+		return isolated, nil
+	case "MergedWithPrevious":
+		return mergedWithPrevious, nil
+	case "MergedWithNext":
+		return mergedWithNext, nil
+	case "Contiguous":
+		return contiguous, nil
+	}
+	return 0, fmt.Errorf("unknown split behavior: %s", s)
+}
+
+// splitter splits words on the matches of a pattern.
+type splitter struct {
+	match    matcher
+	behavior behavior
+	invert   bool // split on what does not match instead
+}
+
+// span is a part of a word, and whether it matches the pattern.
+type span struct {
+	start, end int
+	match      bool
+}
+
+func (s splitter) split(words []string, _ bool) []string {
+	var out []string
+	for _, word := range words {
+		runes := []rune(word)
+		var spans []span
+		prev := 0
+		for _, m := range s.match(runes) {
+			if m[0] > prev {
+				spans = append(spans, span{prev, m[0], s.invert})
+			}
+			spans = append(spans, span{m[0], m[1], !s.invert})
+			prev = m[1]
+		}
+		if prev < len(runes) {
+			spans = append(spans, span{prev, len(runes), s.invert})
+		}
+		for _, sp := range s.merge(spans) {
+			if sp.end > sp.start {
+				out = append(out, string(runes[sp.start:sp.end]))
+			}
+		}
+	}
+	return out
+}
+
+// merge returns the words the spans of a word form.
+func (s splitter) merge(spans []span) []span {
+	var out []span
+	switch s.behavior {
+	case removed:
+		for _, sp := range spans {
+			if !sp.match {
+				out = append(out, sp)
+			}
+		}
+	case isolated:
+		out = spans
+	case mergedWithPrevious:
+		previous := false
+		for _, sp := range spans {
+			if sp.match && !previous && len(out) > 0 {
+				out[len(out)-1].end = sp.end
+			} else {
+				out = append(out, sp)
+			}
+			previous = sp.match
+		}
+	case mergedWithNext:
+		next := false
+		for i := len(spans) - 1; i >= 0; i-- {
+			sp := spans[i]
+			if sp.match && !next && len(out) > 0 {
+				out[len(out)-1].start = sp.start
+			} else {
+				out = append(out, sp)
+			}
+			next = sp.match
+		}
+		for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+	case contiguous:
+		for i, sp := range spans {
+			if i > 0 && sp.match && spans[i-1].match {
+				out[len(out)-1].end = sp.end
+			} else {
+				out = append(out, sp)
+			}
+		}
+	}
+	return out
+}
+
+// isPunctuation reports whether r is ASCII or Unicode punctuation.
+func isPunctuation(r rune) bool {
+	return unicode.IsPunct(r) || r < 0x80 && unicode.IsSymbol(r)
+}
+
+// byteLevel maps the bytes of words to printable characters, GPT-2's way
+// of tokenizing any text with a byte-level vocabulary. It first splits
+// words with GPT-2's pattern unless the tokenizer splits them already.
+type byteLevel struct {
+	addPrefixSpace bool
+	re             *regexp2.Regexp
+}
+
+func (p byteLevel) split(words []string, _ bool) []string {
+	if p.addPrefixSpace {
+		for i, w := range words {
+			if !strings.HasPrefix(w, " ") {
+				words[i] = " " + w
+			}
+		}
+	}
+	if p.re != nil {
+		words = splitter{match: regexMatcher(p.re), behavior: isolated}.split(words, false)
+	}
+	for i, w := range words {
+		var b strings.Builder
+		for j := 0; j < len(w); j++ {
+			b.WriteRune(byteChars[w[j]])
+		}
+		words[i] = b.String()
+	}
+	return words
+}
+
+// byteChars maps each byte to the character GPT-2's vocabulary represents
+// it with: printable Latin-1 characters stand for themselves and the
+// others are shifted past U+00FF.
+var byteChars = func() [256]rune {
+	var chars [256]rune
+	next := rune(256)
+	for b := range 256 {
+		if b >= '!' && b <= '~' || b >= 0xA1 && b <= 0xAC || b >= 0xAE {
+			chars[b] = rune(b)
+		} else {
+			chars[b] = next
+			next++
+		}
+	}
+	return chars
+}()
+
+// metaspace replaces spaces with a visible character, SentencePiece's
+// "▁", prepends one to the text, and splits words before each.
+type metaspace struct {
+	replacement string
+	scheme      string // "always", "first" or "never" prepend the replacement
+	splitter    *splitter
+}
+
+func (p metaspace) split(words []string, atStart bool) []string {
+	out := make([]string, 0, len(words))
+	for i, w := range words {
+		w = strings.ReplaceAll(w, " ", p.replacement)
+		prepend := p.scheme == "always" || p.scheme == "first" && i == 0 && atStart
+		if prepend && !strings.HasPrefix(w, p.replacement) {
+			w = p.replacement + w
+		}
+		out = append(out, w)
+	}
+	if p.splitter != nil {
+		out = p.splitter.split(out, atStart)
+	}
+	return out
+}
@@ -0,0 +1,240 @@
+// Package huggingface counts tokens exactly with the tokenizer of a
+// HuggingFace model, read from its tokenizer.json file. Its Tokenizer is a
+// tokenestimate.TokenCounter, so a preset for any open-weights model can be
+// fitted to the model's own tokenizer:
+//
+//	tok, err := huggingface.Load("tokenizer.json")
+//	samples, err := fit.Label(tok, corpus.Texts())
+//	estimator, report, err := fit.Fit(samples, fit.FitOptions{Method: fit.NNLS})
+//
+// The tokenizer is implemented in Go and supports the BPE, WordPiece,
+// Unigram and WordLevel models with the normalizers and pre-tokenizers
+// current models use. Post-processors are ignored: counts do not include
+// the beginning- and end-of-sequence tokens a template would add, as with
+// add_special_tokens=False in the tokenizers library.
+package huggingface
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/infinigence/tokenestimate"
+)
+
+// Tokenizer splits texts into the tokens of a tokenizer.json file. It is
+// safe for concurrent use.
+type Tokenizer struct {
+	raw        *addedVocab // added tokens matched in the original text
+	normalized *addedVocab // added tokens matched after normalization
+	normalizer normalizer
+	pre        preTokenizer
+	model      model
+}
+
+var _ tokenestimate.TokenCounter = (*Tokenizer)(nil)
+
+// file is the part of tokenizer.json a Tokenizer uses.
+type file struct {
+	AddedTokens  []addedToken    `json:"added_tokens"`
+	Normalizer   json.RawMessage `json:"normalizer"`
+	PreTokenizer json.RawMessage `json:"pre_tokenizer"`
+	Model        json.RawMessage `json:"model"`
+}
+
+// addedToken is a token matched as a whole before the model sees the
+// text, such as a special token.
+type addedToken struct {
+	ID         int    `json:"id"`
+	Content    string `json:"content"`
+	SingleWord bool   `json:"single_word"`
+	LStrip     bool   `json:"lstrip"`
+	RStrip     bool   `json:"rstrip"`
+	Normalized bool   `json:"normalized"`
+	Special    bool   `json:"special"`
+}
+
+// Load reads the tokenizer.json file at path.
+func Load(path string) (*Tokenizer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Parse parses the contents of a tokenizer.json file.
+func Parse(data []byte) (*Tokenizer, error) {
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	t := &Tokenizer{}
+	var err error
+	if t.normalizer, err = parseNormalizer(f.Normalizer); err != nil {
+		return nil, fmt.Errorf("normalizer: %w", err)
+	}
+	if t.pre, err = parsePreTokenizer(f.PreTokenizer); err != nil {
+		return nil, fmt.Errorf("pre_tokenizer: %w", err)
+	}
+	if t.model, err = parseModel(f.Model); err != nil {
+		return nil, fmt.Errorf("model: %w", err)
+	}
+	var raw, normalized []addedToken
+	for _, tok := range f.AddedTokens {
+		if tok.Normalized && !tok.Special {
+			normalized = append(normalized, tok)
+		} else {
+			raw = append(raw, tok)
+		}
+	}
+	t.raw, t.normalized = newAddedVocab(raw), newAddedVocab(normalized)
+	return t, nil
+}
+
+// Encode returns the IDs of the tokens text encodes to.
+func (t *Tokenizer) Encode(text string) []int {
+	var ids []int
+	for _, seg := range splitAdded(text, t.raw) {
+		if seg.id >= 0 {
+			ids = append(ids, seg.id)
+			continue
+		}
+		normalized := seg.text
+		if t.normalizer != nil {
+			normalized = t.normalizer.normalize(normalized)
+		}
+		for _, part := range splitAdded(normalized, t.normalized) {
+			if part.id >= 0 {
+				ids = append(ids, part.id)
+				continue
+			}
+			words := []string{part.text}
+			if t.pre != nil {
+				words = t.pre.split(words, seg.start && part.start)
+			}
+			for _, word := range words {
+				ids = t.model.tokenize(word, ids)
+			}
+		}
+	}
+	return ids
+}
+
+// Count returns the number of tokens text encodes to. The error is always
+// nil.
+func (t *Tokenizer) Count(text string) (int, error) {
+	return len(t.Encode(text)), nil
+}
+
+// segment is a part of a text: an added token, or text between them.
+type segment struct {
+	text  string
+	id    int  // ID of the added token, or -1 for text
+	start bool // whether the segment starts the text
+}
+
+// addedVocab holds added tokens by their first byte, longest first.
+type addedVocab [256][]addedToken
+
+// newAddedVocab returns the vocabulary of tokens, or nil if there are none.
+func newAddedVocab(tokens []addedToken) *addedVocab {
+	var v addedVocab
+	n := 0
+	for _, tok := range tokens {
+		if tok.Content != "" {
+			v[tok.Content[0]] = append(v[tok.Content[0]], tok)
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	// Longer tokens win when several match at the same position.
+	for _, list := range v {
+		slices.SortStableFunc(list, func(a, b addedToken) int { return len(b.Content) - len(a.Content) })
+	}
+	return &v
+}
+
+// splitAdded splits s into the added tokens it contains, leftmost first,
+// and the text between them.
+func splitAdded(s string, vocab *addedVocab) []segment {
+	if vocab == nil {
+		return []segment{{text: s, id: -1, start: true}}
+	}
+	var segments []segment
+	textStart := 0
+	for i := 0; i < len(s); {
+		tok, ok := vocab.match(s, i)
+		if !ok {
+			i++
+			continue
+		}
+		start, end := i, i+len(tok.Content)
+		if tok.LStrip {
+			start = len(strings.TrimRightFunc(s[textStart:start], unicode.IsSpace)) + textStart
+		}
+		if tok.RStrip {
+			end = len(s) - len(strings.TrimLeftFunc(s[end:], unicode.IsSpace))
+		}
+		if start > textStart {
+			segments = append(segments, segment{text: s[textStart:start], id: -1, start: textStart == 0})
+		}
+		segments = append(segments, segment{text: s[start:end], id: tok.ID})
+		textStart, i = end, end
+	}
+	if textStart < len(s) || len(segments) == 0 {
+		segments = append(segments, segment{text: s[textStart:], id: -1, start: textStart == 0})
+	}
+	return segments
+}
+
+// match returns the longest added token at position i of s.
+func (v *addedVocab) match(s string, i int) (addedToken, bool) {
+	for _, tok := range v[s[i]] {
+		if !strings.HasPrefix(s[i:], tok.Content) {
+			continue
+		}
+		if tok.SingleWord {
+			before, _ := utf8.DecodeLastRuneInString(s[:i])
+			after, _ := utf8.DecodeRuneInString(s[i+len(tok.Content):])
+			if isWordChar(before) || isWordChar(after) {
+				continue
+			}
+		}
+		return tok, true
+	}
+	return addedToken{}, false
+}
+
+// isWordChar reports whether r is part of a word, for single-word added
+// tokens.
+func isWordChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// component is the type tag of a normalizer, pre-tokenizer or model.
+type component struct {
+	Type string `json:"type"`
+}
+
+// typeOf returns the type tag of a component and whether raw holds one.
+func typeOf(raw json.RawMessage) (string, bool, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", false, nil
+	}
+	var c component
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return "", false, err
+	}
+	return c.Type, true, nil
+}
@@ -0,0 +1,232 @@
+package huggingface
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// parse parses a tokenizer.json document.
+func parse(t *testing.T, data string) *Tokenizer {
+	t.Helper()
+	tok, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return tok
+}
+
+// check compares the token IDs of texts with the expected ones.
+func check(t *testing.T, tok *Tokenizer, tests map[string][]int) {
+	t.Helper()
+	for text, want := range tests {
+		if got := tok.Encode(text); !slices.Equal(got, want) {
+			t.Errorf("Encode(%q): expected %v, got %v", text, want, got)
+		}
+		if n, err := tok.Count(text); err != nil || n != len(want) {
+			t.Errorf("Count(%q): expected %d, got %d, %v", text, len(want), n, err)
+		}
+	}
+}
+
+// gpt2 is a byte-level BPE tokenizer like GPT-2's, with added tokens.
+const gpt2 = `{
+  "added_tokens": [
+    {"id": 100, "content": "<|im_start|>", "special": true},
+    {"id": 101, "content": "<mask>", "lstrip": true, "special": true},
+    {"id": 102, "content": "foo", "single_word": true, "normalized": true}
+  ],
+  "normalizer": null,
+  "pre_tokenizer": {"type": "ByteLevel", "add_prefix_space": false, "trim_offsets": true, "use_regex": true},
+  "model": {
+    "type": "BPE",
+    "vocab": {"h": 0, "e": 1, "l": 2, "o": 3, "Ġ": 4, "w": 5, "r": 6, "d": 7, "he": 8, "ll": 9,
+      "llo": 10, "hello": 11, "Ġw": 12, "or": 13, "Ġwor": 14, "ld": 15, "Ġworld": 16, "!": 17,
+      "f": 18, "a": 19, "Ċ": 20},
+    "merges": ["h e", "l l", "ll o", "he llo", "Ġ w", "o r", "Ġw or", "l d", "Ġwor ld"]
+  }
+}`
+
+func TestByteLevelBPE(t *testing.T) {
+	check(t, parse(t, gpt2), map[string][]int{
+		"":                  nil,
+		"hello world!":      {11, 16, 17},
+		"hell":              {8, 9},
+		"hello\n":           {11, 20},
+		"<|im_start|>hello": {100, 11},
+		"hello <mask>":      {11, 101},
+		"a foo":             {19, 4, 102},
+		"afoo":              {19, 18, 3, 3},
+	})
+
+	// Merges may also be written as pairs.
+	pairs := strings.Replace(gpt2, `["h e", "l l", "ll o", "he llo", "Ġ w", "o r", "Ġw or", "l d", "Ġwor ld"]`,
+		`[["h", "e"], ["l", "l"], ["ll", "o"], ["he", "llo"], ["Ġ", "w"], ["o", "r"], ["Ġw", "or"], ["l", "d"], ["Ġwor", "ld"]]`, 1)
+	check(t, parse(t, pairs), map[string][]int{"hello world!": {11, 16, 17}})
+}
+
+// llama is a SentencePiece-style BPE tokenizer with byte fallback, like
+// Llama 2's.
+const llama = `{
+  "added_tokens": [{"id": 1, "content": "<s>", "special": true}],
+  "normalizer": {"type": "Sequence", "normalizers": [
+    {"type": "Prepend", "prepend": "▁"},
+    {"type": "Replace", "pattern": {"String": " "}, "content": "▁"}
+  ]},
+  "pre_tokenizer": null,
+  "model": {
+    "type": "BPE", "byte_fallback": true, "fuse_unk": true, "unk_token": "<unk>",
+    "vocab": {"<unk>": 0, "<s>": 1, "<0xE2>": 2, "<0x98>": 3, "<0x83>": 4, "▁": 5, "h": 6, "i": 7,
+      "▁h": 8, "▁hi": 9, "x": 10},
+    "merges": ["▁ h", "▁h i"]
+  }
+}`
+
+func TestByteFallback(t *testing.T) {
+	check(t, parse(t, llama), map[string][]int{
+		"hi ☃":    {9, 5, 2, 3, 4},
+		"<s>hi":   {1, 9},
+		"hi<s>hi": {9, 1, 9},
+	})
+
+	// Without byte fallback, uncovered characters are one unknown token.
+	noFallback := strings.Replace(llama, `"byte_fallback": true`, `"byte_fallback": false`, 1)
+	check(t, parse(t, noFallback), map[string][]int{"hi ☃☃": {9, 5, 0}})
+}
+
+func TestMetaspace(t *testing.T) {
+	const data = `{
+  "added_tokens": [{"id": 1, "content": "<s>", "special": true}],
+  "pre_tokenizer": {"type": "Metaspace", "replacement": "▁", "prepend_scheme": "%s", "split": %s},
+  "model": {"type": "WordLevel", "unk_token": "<unk>",
+    "vocab": {"<unk>": 0, "<s>": 1, "▁hi": 2, "hi": 3, "▁you": 4, "▁hi▁you": 5}}
+}`
+	tests := []struct {
+		scheme, split string
+		want          map[string][]int
+	}{
+		{"always", "true", map[string][]int{"hi you": {2, 4}, "<s>hi": {1, 2}}},
+		{"first", "true", map[string][]int{"hi you": {2, 4}, "<s>hi": {1, 3}}},
+		{"never", "true", map[string][]int{"hi you": {3, 4}}},
+		{"always", "false", map[string][]int{"hi you": {5}}},
+	}
+	for _, tt := range tests {
+		check(t, parse(t, strings.Replace(strings.Replace(data, "%s", tt.scheme, 1), "%s", tt.split, 1)), tt.want)
+	}
+}
+
+func TestWordPiece(t *testing.T) {
+	tok, err := Load(filepath.Join("testdata", "bert.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	check(t, tok, map[string][]int{
+		"Héllo, Unaffable 世界 café xyz": {1, 5, 2, 3, 4, 6, 7, 8, 0},
+		"[CLS] hello\x00\t":            {101, 1},
+	})
+}
+
+func TestUnigram(t *testing.T) {
+	tok := parse(t, `{
+  "normalizer": {"type": "Sequence", "normalizers": [{"type": "NFKC"}, {"type": "Lowercase"}]},
+  "pre_tokenizer": {"type": "Metaspace", "replacement": "▁", "prepend_scheme": "always"},
+  "model": {"type": "Unigram", "unk_id": 0, "vocab": [
+    ["<unk>", 0], ["▁", -2], ["▁hel", -3], ["lo", -3], ["▁hello", -5.5],
+    ["h", -4], ["e", -4], ["l", -4], ["o", -4]
+  ]}
+}`)
+	check(t, tok, map[string][]int{
+		"hello":  {4},
+		"ＨＥＬＬＯ":  {4},
+		"hellx":  {2, 7, 0},
+		"xx":     {1, 0},
+		"hel lo": {2, 1, 3},
+	})
+}
+
+func TestPreTokenizers(t *testing.T) {
+	tests := []struct {
+		config string
+		text   string
+		want   []string
+	}{
+		{`{"type": "Split", "pattern": {"String": "-"}, "behavior": "Removed"}`, "the-final--countdown", []string{"the", "final", "countdown"}},
+		{`{"type": "Split", "pattern": {"String": "-"}, "behavior": "Isolated"}`, "the-final--countdown", []string{"the", "-", "final", "-", "-", "countdown"}},
+		{`{"type": "Split", "pattern": {"String": "-"}, "behavior": "MergedWithPrevious"}`, "the-final--countdown", []string{"the-", "final-", "-", "countdown"}},
+		{`{"type": "Split", "pattern": {"String": "-"}, "behavior": "MergedWithNext"}`, "the-final--countdown", []string{"the", "-final", "-", "-countdown"}},
+		{`{"type": "Split", "pattern": {"String": "-"}, "behavior": "Contiguous"}`, "the-final--countdown", []string{"the", "-", "final", "--", "countdown"}},
+		{`{"type": "Split", "pattern": {"Regex": "\\p{N}{1,3}"}, "behavior": "Isolated", "invert": false}`, "a12345", []string{"a", "123", "45"}},
+		{`{"type": "Whitespace"}`, "Hey friend!     How are you?!?", []string{"Hey", "friend", "!", "How", "are", "you", "?!?"}},
+		{`{"type": "WhitespaceSplit"}`, "Hey friend!  ok", []string{"Hey", "friend!", "ok"}},
+		{`{"type": "BertPreTokenizer"}`, "Hey, friend$", []string{"Hey", ",", "friend", "$"}},
+		{`{"type": "Punctuation", "behavior": "Contiguous"}`, "Hey!!! you?", []string{"Hey", "!!!", " you", "?"}},
+		{`{"type": "Digits", "individual_digits": true}`, "Call 123", []string{"Call ", "1", "2", "3"}},
+		{`{"type": "Digits", "individual_digits": false}`, "Call 123", []string{"Call ", "123"}},
+		{`{"type": "CharDelimiterSplit", "delimiter": "|"}`, "a|b||c", []string{"a", "b", "c"}},
+		{`{"type": "ByteLevel", "add_prefix_space": true, "use_regex": true}`, "Hi there", []string{"ĠHi", "Ġthere"}},
+		{`{"type": "ByteLevel", "add_prefix_space": false, "use_regex": false}`, "é\n", []string{"Ã©Ċ"}},
+		{`{"type": "Sequence", "pretokenizers": [{"type": "WhitespaceSplit"}, {"type": "Digits", "individual_digits": true}]}`, "a 12", []string{"a", "1", "2"}},
+	}
+	for _, tt := range tests {
+		p, err := parsePreTokenizer([]byte(tt.config))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.config, err)
+		}
+		if got := p.split([]string{tt.text}, true); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: expected %q, got %q", tt.config, tt.want, got)
+		}
+	}
+}
+
+func TestNormalizers(t *testing.T) {
+	tests := []struct {
+		config string
+		text   string
+		want   string
+	}{
+		{`{"type": "NFKC"}`, "ﬁ①", "fi1"},
+		{`{"type": "NFD"}`, "é", "é"},
+		{`{"type": "StripAccents"}`, "Crème brûlée", "Creme brulee"},
+		{`{"type": "Strip", "strip_left": true, "strip_right": false}`, "  hi  ", "hi  "},
+		{`{"type": "Replace", "pattern": {"Regex": " {2,}"}, "content": "$1 "}`, "a   b", "a$1 b"},
+		{`{"type": "Prepend", "prepend": "▁"}`, "", ""},
+		{`{"type": "Nmt"}`, "a\u200bb\x01", "a b"},
+		{`{"type": "BertNormalizer", "clean_text": true, "handle_chinese_chars": true, "strip_accents": false, "lowercase": true}`, "Ça\u0085世", "ça 世 "},
+		{`{"type": "Precompiled", "precompiled_charsmap": ""}`, "Ａ", "Ａ"},
+	}
+	for _, tt := range tests {
+		n, err := parseNormalizer([]byte(tt.config))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.config, err)
+		}
+		if got := n.normalize(tt.text); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.config, tt.want, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		data    string
+		errText string
+	}{
+		{`{`, "unexpected end"},
+		{`{"normalizer": null}`, "missing model"},
+		{`{"normalizer": {"type": "Magic"}, "model": {"type": "WordLevel", "vocab": {}}}`, "unknown normalizer: Magic"},
+		{`{"pre_tokenizer": {"type": "Magic"}, "model": {"type": "WordLevel", "vocab": {}}}`, "unknown pre-tokenizer: Magic"},
+		{`{"pre_tokenizer": {"type": "Split", "pattern": {"Regex": "("}}, "model": {"type": "WordLevel", "vocab": {}}}`, "pre_tokenizer"},
+		{`{"pre_tokenizer": {"type": "Split", "pattern": {"String": "-"}, "behavior": "Sideways"}, "model": {"type": "WordLevel", "vocab": {}}}`, "unknown split behavior: Sideways"},
+		{`{"model": {"type": "Magic"}}`, "unknown model: Magic"},
+		{`{"model": {"type": "BPE", "vocab": {"a": 0}, "merges": ["a b"]}}`, `merge 0: ["a" "b"] not in the vocabulary`},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.errText) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.data, tt.errText, err)
+		}
+	}
+	if _, err := Load(filepath.Join("testdata", "missing.json")); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+}
@@ -0,0 +1,110 @@
+// Package charsmap applies the precompiled character maps SentencePiece
+// normalizes text with, which .model files embed in their normalizer spec
+// and tokenizer.json files in their Precompiled normalizer.
+//
+// A map is a 4-byte little-endian size followed by a darts-clone double
+// array of that many bytes, mapping UTF-8 sequences to offsets into the
+// NUL-terminated replacement strings that follow it.
+package charsmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// Map is a precompiled character map.
+type Map struct {
+	units   []uint32
+	strings []byte
+}
+
+// Parse parses a precompiled character map. An empty map normalizes
+// nothing.
+func Parse(data []byte) (*Map, error) {
+	if len(data) == 0 {
+		return &Map{}, nil
+	}
+	if len(data) < 4 {
+		return nil, errors.New("truncated character map")
+	}
+	size := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+	if size%4 != 0 || uint64(size) > uint64(len(data)) {
+		return nil, errors.New("invalid character map size")
+	}
+	units := make([]uint32, size/4)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint32(data[4*i:])
+	}
+	return &Map{units: units, strings: data[size:]}, nil
+}
+
+// Normalize replaces the longest mapped sequence at each position of s
+// with its replacement. Invalid UTF-8 becomes U+FFFD, as in SentencePiece.
+func (m *Map) Normalize(s string) string {
+	if len(m.units) == 0 && utf8.ValidString(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for len(s) > 0 {
+		replacement, n := m.Prefix(s)
+		b.WriteString(replacement)
+		s = s[n:]
+	}
+	return b.String()
+}
+
+// Prefix returns the normalization of the start of s and the number of
+// bytes it replaces: the replacement of the longest mapped prefix, or else
+// the first character of s, or U+FFFD for one byte of invalid UTF-8.
+func (m *Map) Prefix(s string) (replacement string, n int) {
+	if n, replacement, ok := m.longestPrefix(s); ok {
+		return replacement, n
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError && n == 1 {
+		return string(utf8.RuneError), 1
+	}
+	return s[:n], n
+}
+
+// longestPrefix returns the length of the longest mapped prefix of s and
+// its replacement.
+func (m *Map) longestPrefix(s string) (n int, replacement string, ok bool) {
+	if len(m.units) == 0 {
+		return 0, "", false
+	}
+	value := -1
+	pos := offset(m.units[0])
+	for i := 0; i < len(s); i++ {
+		c := uint32(s[i])
+		pos ^= c
+		if pos >= uint32(len(m.units)) {
+			break
+		}
+		unit := m.units[pos]
+		if unit&(1<<31|0xff) != c {
+			break
+		}
+		pos ^= offset(unit)
+		if unit>>8&1 == 1 && pos < uint32(len(m.units)) {
+			n, value = i+1, int(m.units[pos]&(1<<31-1))
+		}
+	}
+	if value < 0 || value >= len(m.strings) {
+		return 0, "", false
+	}
+	end := value
+	for end < len(m.strings) && m.strings[end] != 0 {
+		end++
+	}
+	return n, string(m.strings[value:end]), true
+}
+
+// offset returns the offset of the children of a double-array unit.
+func offset(unit uint32) uint32 {
+	return unit >> 10 << (unit & (1 << 9) >> 6)
+}
@@ -0,0 +1,113 @@
+package charsmap
+
+import (
+	"encoding/binary"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// build returns a character map of the given replacements, with each trie
+// node's children in a block of 256 units of its own. Units store the
+// offset of the block relative to their own position.
+func build(replacements map[string]string) []byte {
+	type node struct {
+		children map[byte]*node
+		value    int
+		base     uint32
+	}
+	var blob []byte
+	root := &node{children: map[byte]*node{}, value: -1}
+	nodes := []*node{root}
+	for _, key := range slices.Sorted(maps.Keys(replacements)) {
+		n := root
+		for i := 0; i < len(key); i++ {
+			child, ok := n.children[key[i]]
+			if !ok {
+				child = &node{children: map[byte]*node{}, value: -1}
+				n.children[key[i]] = child
+				nodes = append(nodes, child)
+			}
+			n = child
+		}
+		n.value = len(blob)
+		blob = append(append(blob, replacements[key]...), 0)
+	}
+	for i, n := range nodes {
+		n.base = uint32(256 * (i + 1))
+	}
+	units := make([]uint32, 256*(len(nodes)+1))
+	units[0] = root.base << 10
+	for _, n := range nodes {
+		for c, child := range n.children {
+			pos := n.base ^ uint32(c)
+			unit := (child.base^pos)<<10 | uint32(c)
+			if child.value >= 0 {
+				unit |= 1 << 8
+				units[child.base] = uint32(child.value) | 1<<31
+			}
+			units[pos] = unit
+		}
+	}
+	data := binary.LittleEndian.AppendUint32(nil, uint32(4*len(units)))
+	for _, u := range units {
+		data = binary.LittleEndian.AppendUint32(data, u)
+	}
+	return append(data, blob...)
+}
+
+func TestNormalize(t *testing.T) {
+	m, err := Parse(build(map[string]string{
+		"Ａ":  "A",
+		"ｆｉ": "fi",
+		"ｆ":  "f",
+		"①":  "1",
+		"\t": " ",
+		"x":  "",
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tests := map[string]string{
+		"":            "",
+		"Ａｆｉｆ①":       "Afif1",
+		"a\tb":        "a b",
+		"fox":         "fo",
+		"日本":          "日本",
+		"bad\xffutf8": "bad�utf8",
+	}
+	for in, want := range tests {
+		if got := m.Normalize(in); got != want {
+			t.Errorf("Normalize(%q): expected %q, got %q", in, want, got)
+		}
+	}
+
+	prefixes := []struct {
+		in          string
+		replacement string
+		n           int
+	}{
+		{"ｆｉｆ", "fi", 6},
+		{"ｆ①", "f", 3},
+		{"日本", "日", 3},
+		{"\xffx", "\ufffd", 1},
+	}
+	for _, tt := range prefixes {
+		if replacement, n := m.Prefix(tt.in); replacement != tt.replacement || n != tt.n {
+			t.Errorf("Prefix(%q): expected %q, %d, got %q, %d", tt.in, tt.replacement, tt.n, replacement, n)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	m, err := Parse(nil)
+	if err != nil || m.Normalize("Ａ") != "Ａ" {
+		t.Errorf("Expected an empty map to normalize nothing, got %v", err)
+	}
+	if _, err := Parse([]byte{1, 0}); err == nil {
+		t.Error("Expected a truncated map to fail")
+	}
+	if _, err := Parse([]byte{16, 0, 0, 0, 1}); err == nil {
+		t.Error("Expected a map larger than its data to fail")
+	}
+}
@@ -0,0 +1,233 @@
+package sentencepiece
+
+import (
+	"container/heap"
+	"math"
+	"unicode/utf8"
+)
+
+// unigram splits texts into the pieces whose probabilities have the
+// largest product, SentencePiece's default model.
+type unigram struct {
+	vocab    *vocab
+	unkScore float32
+	maxScore float32
+}
+
+func newUnigram(v *vocab) *unigram {
+	// SentencePiece starts the maximum at FLT_MIN, so that user-defined
+	// symbols score just below zero when every piece has a negative score.
+	minScore, maxScore := float32(math.MaxFloat32), float32(0x1p-126)
+	for _, p := range v.pieces {
+		if p.typ == normal {
+			minScore, maxScore = min(minScore, p.score), max(maxScore, p.score)
+		}
+	}
+	return &unigram{vocab: v, unkScore: minScore - 10, maxScore: maxScore}
+}
+
+// encode returns the most probable segmentation of text. User-defined
+// symbols always win, and characters no piece covers are unknown.
+func (m *unigram) encode(text string) []token {
+	type node struct {
+		score float32
+		start int // start of the last piece
+		id    int // ID of the last piece, or -1 if the position is unreached
+	}
+	best := make([]node, len(text)+1)
+	for i := 1; i <= len(text); i++ {
+		best[i].id = -1
+	}
+	relax := func(start, end, id int, score float32) {
+		if s := best[start].score + score; best[end].id < 0 || s > best[end].score {
+			best[end] = node{score: s, start: start, id: id}
+		}
+	}
+	for start := 0; start < len(text); start++ {
+		if start > 0 && best[start].id < 0 {
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		single := false
+		for end := start + 1; end <= min(len(text), start+m.vocab.maxLen); end++ {
+			if end < len(text) && !utf8.RuneStart(text[end]) {
+				continue
+			}
+			id, ok := m.vocab.ids[text[start:end]]
+			if !ok || m.vocab.pieces[id].typ == unused {
+				continue
+			}
+			score := m.vocab.pieces[id].score
+			if m.vocab.pieces[id].typ == userDefined {
+				score = float32(utf8.RuneCountInString(text[start:end]))*m.maxScore - 0.1
+			}
+			single = single || end == start+size
+			relax(start, end, id, score)
+		}
+		if !single {
+			relax(start, start+size, m.vocab.unk, m.unkScore)
+		}
+	}
+
+	var tokens []token
+	for end := len(text); end > 0; end = best[end].start {
+		tokens = append(tokens, token{text[best[end].start:end], best[end].id})
+	}
+	for i, j := 0, len(tokens)-1; i < j; i, j = i+1, j-1 {
+		tokens[i], tokens[j] = tokens[j], tokens[i]
+	}
+	return tokens
+}
+
+// bpe merges the characters of texts pair by pair, always merging the
+// pair whose merged piece scores highest, the model of Llama 2 and Gemma.
+type bpe struct {
+	vocab *vocab
+}
+
+func newBPE(v *vocab) *bpe {
+	return &bpe{vocab: v}
+}
+
+// symbol is a piece of a text being merged, in a linked list of the
+// pieces remaining.
+type symbol struct {
+	text       string // empty once merged into the previous symbol
+	prev, next int
+	fixed      bool // user-defined symbols are never merged
+}
+
+// candidate is a pair of adjacent symbols whose merge is a piece.
+type candidate struct {
+	left, right int
+	size        int // length of the merged piece, to detect stale pairs
+	score       float32
+}
+
+// candidates is a max-heap of pairs by score, then leftmost first.
+type candidates []candidate
+
+func (q candidates) Len() int { return len(q) }
+func (q candidates) Less(i, j int) bool {
+	if q[i].score != q[j].score {
+		return q[i].score > q[j].score
+	}
+	return q[i].left < q[j].left
+}
+func (q candidates) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *candidates) Push(x any)   { *q = append(*q, x.(candidate)) }
+func (q *candidates) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+func (m *bpe) encode(text string) []token {
+	var symbols []symbol
+	for text != "" {
+		n := m.vocab.userDefined.match(text)
+		fixed := n > 0
+		if !fixed {
+			_, n = utf8.DecodeRuneInString(text)
+		}
+		symbols = append(symbols, symbol{text: text[:n], prev: len(symbols) - 1, next: len(symbols) + 1, fixed: fixed})
+		text = text[n:]
+	}
+	if len(symbols) == 0 {
+		return nil
+	}
+	symbols[len(symbols)-1].next = -1
+
+	// Merges into unused pieces are undone at the end, so remember what
+	// they were merged from.
+	var unmerge map[string][2]string
+	var queue candidates
+	suggest := func(left, right int) {
+		if left < 0 || right < 0 || symbols[left].fixed || symbols[right].fixed {
+			return
+		}
+		merged := symbols[left].text + symbols[right].text
+		id, ok := m.vocab.ids[merged]
+		if !ok {
+			return
+		}
+		if m.vocab.pieces[id].typ == unused {
+			if unmerge == nil {
+				unmerge = make(map[string][2]string)
+			}
+			unmerge[merged] = [2]string{symbols[left].text, symbols[right].text}
+		}
+		heap.Push(&queue, candidate{left: left, right: right, size: len(merged), score: m.vocab.pieces[id].score})
+	}
+	for i := 1; i < len(symbols); i++ {
+		suggest(i-1, i)
+	}
+	for queue.Len() > 0 {
+		c := heap.Pop(&queue).(candidate)
+		left, right := &symbols[c.left], &symbols[c.right]
+		if left.text == "" || right.text == "" || len(left.text)+len(right.text) != c.size {
+			continue
+		}
+		left.text += right.text
+		left.next = right.next
+		if right.next >= 0 {
+			symbols[right.next].prev = c.left
+		}
+		right.text = ""
+		suggest(left.prev, c.left)
+		suggest(c.left, left.next)
+	}
+
+	var tokens []token
+	var resegment func(s string)
+	resegment = func(s string) {
+		id := m.vocab.id(s)
+		if parts, ok := unmerge[s]; ok && m.vocab.pieces[id].typ == unused {
+			resegment(parts[0])
+			resegment(parts[1])
+			return
+		}
+		tokens = append(tokens, token{s, id})
+	}
+	for i := 0; i >= 0; i = symbols[i].next {
+		resegment(symbols[i].text)
+	}
+	return tokens
+}
+
+// wordLevel maps each word, with the whitespace before it, or after it if
+// whitespace is a suffix, to a single piece.
+type wordLevel struct {
+	vocab  *vocab
+	suffix bool
+}
+
+func (m *wordLevel) encode(text string) []token {
+	var tokens []token
+	start, inSpace := 0, false
+	for i, r := range text {
+		space := r == '▁'
+		if m.suffix && inSpace && !space || !m.suffix && space && i > start {
+			tokens = append(tokens, token{text[start:i], m.vocab.id(text[start:i])})
+			start = i
+		}
+		inSpace = space
+	}
+	return append(tokens, token{text[start:], m.vocab.id(text[start:])})
+}
+
+// charLevel maps each character to a piece.
+type charLevel struct {
+	vocab *vocab
+}
+
+func (m *charLevel) encode(text string) []token {
+	tokens := make([]token, 0, len(text))
+	for text != "" {
+		_, n := utf8.DecodeRuneInString(text)
+		tokens = append(tokens, token{text[:n], m.vocab.id(text[:n])})
+		text = text[n:]
+	}
+	return tokens
+}
@@ -0,0 +1,184 @@
+package sentencepiece
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Piece types of sentencepiece_model.proto.
+const (
+	normal      = 1
+	unknownType = 2
+	control     = 3
+	userDefined = 4
+	unused      = 5
+	byteType    = 6
+)
+
+// Model types of sentencepiece_model.proto.
+const (
+	unigramModel = 1
+	bpeModel     = 2
+	wordModel    = 3
+	charModel    = 4
+)
+
+// modelProto is the part of a ModelProto message a Tokenizer uses.
+type modelProto struct {
+	pieces []piece
+
+	// trainer_spec
+	modelType               int
+	byteFallback            bool
+	treatWhitespaceAsSuffix bool
+
+	// normalizer_spec
+	charsmap               []byte
+	addDummyPrefix         bool
+	removeExtraWhitespaces bool
+	escapeWhitespaces      bool
+}
+
+// piece is a token of the vocabulary.
+type piece struct {
+	text  string
+	score float32
+	typ   int
+}
+
+// Wire types of the protocol buffer encoding.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// parseModelProto decodes a serialized ModelProto message.
+func parseModelProto(data []byte) (*modelProto, error) {
+	m := &modelProto{
+		modelType:              unigramModel,
+		addDummyPrefix:         true,
+		removeExtraWhitespaces: true,
+		escapeWhitespaces:      true,
+	}
+	err := fields(data, func(num, wire int, v uint64, b []byte) error {
+		switch {
+		case num == 1 && wire == wireBytes:
+			p, err := parsePiece(b)
+			if err != nil {
+				return fmt.Errorf("piece %d: %w", len(m.pieces), err)
+			}
+			m.pieces = append(m.pieces, p)
+		case num == 2 && wire == wireBytes:
+			if err := m.parseTrainerSpec(b); err != nil {
+				return fmt.Errorf("trainer_spec: %w", err)
+			}
+		case num == 3 && wire == wireBytes:
+			if err := m.parseNormalizerSpec(b); err != nil {
+				return fmt.Errorf("normalizer_spec: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func parsePiece(data []byte) (piece, error) {
+	p := piece{typ: normal}
+	err := fields(data, func(num, wire int, v uint64, b []byte) error {
+		switch {
+		case num == 1 && wire == wireBytes:
+			p.text = string(b)
+		case num == 2 && wire == wireFixed32:
+			p.score = math.Float32frombits(uint32(v))
+		case num == 3 && wire == wireVarint:
+			p.typ = int(v)
+		}
+		return nil
+	})
+	return p, err
+}
+
+func (m *modelProto) parseTrainerSpec(data []byte) error {
+	return fields(data, func(num, wire int, v uint64, b []byte) error {
+		if wire != wireVarint {
+			return nil
+		}
+		switch num {
+		case 3:
+			m.modelType = int(v)
+		case 24:
+			m.treatWhitespaceAsSuffix = v != 0
+		case 35:
+			m.byteFallback = v != 0
+		}
+		return nil
+	})
+}
+
+func (m *modelProto) parseNormalizerSpec(data []byte) error {
+	return fields(data, func(num, wire int, v uint64, b []byte) error {
+		switch {
+		case num == 2 && wire == wireBytes:
+			m.charsmap = b
+		case num == 3 && wire == wireVarint:
+			m.addDummyPrefix = v != 0
+		case num == 4 && wire == wireVarint:
+			m.removeExtraWhitespaces = v != 0
+		case num == 5 && wire == wireVarint:
+			m.escapeWhitespaces = v != 0
+		}
+		return nil
+	})
+}
+
+// fields calls fn with the number, wire type and value of each field of a
+// message: v holds varint and fixed-size values, b the contents of
+// length-delimited ones.
+func fields(data []byte, fn func(num, wire int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("truncated message")
+		}
+		data = data[n:]
+		num, wire := int(key>>3), int(key&7)
+		var v uint64
+		var b []byte
+		switch wire {
+		case wireVarint:
+			if v, n = binary.Uvarint(data); n <= 0 {
+				return errors.New("truncated message")
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return errors.New("truncated message")
+			}
+			v, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return errors.New("truncated message")
+			}
+			v, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || size > uint64(len(data)-n) {
+				return errors.New("truncated message")
+			}
+			b, data = data[n:n+int(size)], data[n+int(size):]
+		default:
+			return fmt.Errorf("unsupported wire type %d", wire)
+		}
+		if err := fn(num, wire, v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+// Package sentencepiece counts tokens exactly with a SentencePiece model,
+// read from the .model file that Gemma, T5, Llama 2 and many Chinese
+// models ship with. Its Tokenizer is a tokenestimate.TokenCounter, so a
+// preset can be fitted to the model's own tokenizer:
+//
+//	tok, err := sentencepiece.Load("tokenizer.model")
+//	samples, err := fit.Label(tok, corpus.Texts())
+//	estimator, report, err := fit.Fit(samples, fit.FitOptions{Method: fit.NNLS})
+//
+// The tokenizer is implemented in Go and supports the unigram, BPE, word
+// and character models, with the model's precompiled normalization,
+// user-defined symbols and byte fallback. Counts do not include the
+// beginning- and end-of-sentence tokens, which SentencePiece adds only
+// when asked to.
+package sentencepiece
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/infinigence/tokenestimate"
+	"github.com/infinigence/tokenestimate/tokenizers/internal/charsmap"
+)
+
+// Tokenizer splits texts into the pieces of a SentencePiece model. It is
+// safe for concurrent use.
+type Tokenizer struct {
+	vocab    *vocab
+	model    model
+	charsmap *charsmap.Map
+
+	byteFallback           bool
+	addDummyPrefix         bool
+	removeExtraWhitespaces bool
+	escapeWhitespaces      bool
+	whitespaceAsSuffix     bool
+}
+
+var _ tokenestimate.TokenCounter = (*Tokenizer)(nil)
+
+// vocab is the vocabulary of a model.
+type vocab struct {
+	pieces      []piece
+	ids         map[string]int // normal, user-defined and unused pieces
+	reserved    map[string]int // the other pieces
+	userDefined matcher
+	maxLen      int // longest piece of ids in bytes
+	unk         int
+	bytes       [256]int // byte-fallback pieces, or unk
+}
+
+// id returns the ID of the piece text, or the unknown piece's.
+func (v *vocab) id(text string) int {
+	if id, ok := v.reserved[text]; ok {
+		return id
+	}
+	if id, ok := v.ids[text]; ok {
+		return id
+	}
+	return v.unk
+}
+
+// matcher finds the user-defined symbols at the start of texts.
+type matcher [256][]string // symbols by first byte, longest first
+
+// match returns the length of the longest symbol s starts with, or 0.
+func (m *matcher) match(s string) int {
+	if s == "" {
+		return 0
+	}
+	for _, symbol := range m[s[0]] {
+		if strings.HasPrefix(s, symbol) {
+			return len(symbol)
+		}
+	}
+	return 0
+}
+
+// token is a piece of a normalized text.
+type token struct {
+	text string
+	id   int
+}
+
+// model splits a normalized text into pieces.
+type model interface {
+	encode(text string) []token
+}
+
+// Load reads the SentencePiece model at path.
+func Load(path string) (*Tokenizer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Parse parses the contents of a SentencePiece .model file, a serialized
+// ModelProto message.
+func Parse(data []byte) (*Tokenizer, error) {
+	m, err := parseModelProto(data)
+	if err != nil {
+		return nil, err
+	}
+	v, err := newVocab(m.pieces)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tokenizer{
+		vocab:                  v,
+		byteFallback:           m.byteFallback,
+		addDummyPrefix:         m.addDummyPrefix,
+		removeExtraWhitespaces: m.removeExtraWhitespaces,
+		escapeWhitespaces:      m.escapeWhitespaces,
+		whitespaceAsSuffix:     m.treatWhitespaceAsSuffix,
+	}
+	if t.charsmap, err = charsmap.Parse(m.charsmap); err != nil {
+		return nil, fmt.Errorf("normalizer_spec: %w", err)
+	}
+	switch m.modelType {
+	case unigramModel:
+		t.model = newUnigram(v)
+	case bpeModel:
+		t.model = newBPE(v)
+	case wordModel:
+		t.model = &wordLevel{vocab: v, suffix: m.treatWhitespaceAsSuffix}
+	case charModel:
+		t.model = &charLevel{vocab: v}
+	default:
+		return nil, fmt.Errorf("unknown model type: %d", m.modelType)
+	}
+	return t, nil
+}
+
+func newVocab(pieces []piece) (*vocab, error) {
+	v := &vocab{pieces: pieces, ids: make(map[string]int, len(pieces)), reserved: make(map[string]int), unk: -1}
+	var symbols []string
+	for id, p := range pieces {
+		if p.text == "" {
+			return nil, fmt.Errorf("piece %d is empty", id)
+		}
+		if _, ok := v.ids[p.text]; ok {
+			return nil, fmt.Errorf("piece %d: %q is already defined", id, p.text)
+		}
+		if _, ok := v.reserved[p.text]; ok {
+			return nil, fmt.Errorf("piece %d: %q is already defined", id, p.text)
+		}
+		switch p.typ {
+		case normal, userDefined, unused:
+			v.ids[p.text] = id
+			v.maxLen = max(v.maxLen, len(p.text))
+		default:
+			v.reserved[p.text] = id
+		}
+		switch p.typ {
+		case userDefined:
+			symbols = append(symbols, p.text)
+		case unknownType:
+			v.unk = id
+		}
+	}
+	if v.unk < 0 {
+		return nil, errors.New("missing unknown piece")
+	}
+	slices.SortFunc(symbols, func(a, b string) int { return len(b) - len(a) })
+	for _, s := range symbols {
+		v.userDefined[s[0]] = append(v.userDefined[s[0]], s)
+	}
+	for b := range v.bytes {
+		v.bytes[b] = v.unk
+		if id, ok := v.reserved[fmt.Sprintf("<0x%02X>", b)]; ok && pieces[id].typ == byteType {
+			v.bytes[b] = id
+		}
+	}
+	return v, nil
+}
+
+// Encode returns the IDs of the pieces text encodes to.
+func (t *Tokenizer) Encode(text string) []int {
+	normalized := t.normalize(text)
+	if normalized == "" {
+		return nil
+	}
+	var ids []int
+	prevUnk := false
+	for _, tok := range t.model.encode(normalized) {
+		unk := tok.id == t.vocab.unk
+		switch {
+		case unk && t.byteFallback:
+			for i := 0; i < len(tok.text); i++ {
+				ids = append(ids, t.vocab.bytes[tok.text[i]])
+			}
+		case unk && prevUnk:
+			// SentencePiece fuses runs of unknown pieces.
+		default:
+			ids = append(ids, tok.id)
+		}
+		prevUnk = unk
+	}
+	return ids
+}
+
+// Count returns the number of tokens text encodes to. It never fails.
+func (t *Tokenizer) Count(text string) (int, error) {
+	return len(t.Encode(text)), nil
+}
+
+// normalize applies the normalization of the model to s as SentencePiece
+// does: the precompiled character map, except to user-defined symbols,
+// then the whitespace options.
+func (t *Tokenizer) normalize(s string) string {
+	space := " "
+	if t.escapeWhitespaces {
+		space = "▁"
+	}
+	prefix := func(s string) (string, int) {
+		if n := t.vocab.userDefined.match(s); n > 0 {
+			return s[:n], n
+		}
+		return t.charsmap.Prefix(s)
+	}
+
+	if t.removeExtraWhitespaces {
+		for s != "" {
+			replacement, n := prefix(s)
+			if replacement != " " {
+				break
+			}
+			s = s[n:]
+		}
+	}
+	if s == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + len(space))
+	if t.addDummyPrefix && !t.whitespaceAsSuffix {
+		b.WriteString(space)
+	}
+	prevSpace := t.removeExtraWhitespaces
+	for s != "" {
+		replacement, n := prefix(s)
+		s = s[n:]
+		if prevSpace {
+			replacement = strings.TrimLeft(replacement, " ")
+		}
+		if replacement != "" {
+			prevSpace = strings.HasSuffix(replacement, " ")
+			if t.escapeWhitespaces {
+				replacement = strings.ReplaceAll(replacement, " ", space)
+			}
+			b.WriteString(replacement)
+		}
+		if !t.removeExtraWhitespaces {
+			prevSpace = false
+		}
+	}
+	out := b.String()
+	if t.removeExtraWhitespaces {
+		for strings.HasSuffix(out, space) {
+			out = strings.TrimSuffix(out, space)
+		}
+	}
+	if t.addDummyPrefix && t.whitespaceAsSuffix {
+		out += space
+	}
+	return out
+}
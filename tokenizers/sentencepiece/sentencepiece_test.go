@@ -0,0 +1,197 @@
+package sentencepiece
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// field encodes a protocol buffer field: a varint for integers, or a
+// length-delimited field for strings and messages.
+func field[T uint64 | string | []byte](num int, v T) []byte {
+	switch v := any(v).(type) {
+	case uint64:
+		return binary.AppendUvarint(binary.AppendUvarint(nil, uint64(num<<3|wireVarint)), v)
+	case string:
+		return field(num, []byte(v))
+	case []byte:
+		b := binary.AppendUvarint(nil, uint64(num<<3|wireBytes))
+		return append(binary.AppendUvarint(b, uint64(len(v))), v...)
+	}
+	panic("unreachable")
+}
+
+// encodeModel serializes a ModelProto with the given pieces, trainer_spec
+// fields and normalizer_spec fields.
+func encodeModel(pieces []piece, trainer, normalizer [][]byte) []byte {
+	var data []byte
+	for _, p := range pieces {
+		b := field(1, p.text)
+		b = binary.AppendUvarint(b, 2<<3|wireFixed32)
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(p.score))
+		b = append(b, field(3, uint64(p.typ))...)
+		data = append(data, field(1, b)...)
+	}
+	data = append(data, field(2, slices.Concat(trainer...))...)
+	return append(data, field(3, slices.Concat(normalizer...))...)
+}
+
+func parse(t *testing.T, data []byte) *Tokenizer {
+	t.Helper()
+	tok, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return tok
+}
+
+// check compares the piece IDs of texts with the expected ones.
+func check(t *testing.T, tok *Tokenizer, tests map[string][]int) {
+	t.Helper()
+	for text, want := range tests {
+		if got := tok.Encode(text); !slices.Equal(got, want) {
+			t.Errorf("Encode(%q): expected %v, got %v", text, want, got)
+		}
+		if n, err := tok.Count(text); err != nil || n != len(want) {
+			t.Errorf("Count(%q): expected %d, got %d, %v", text, len(want), n, err)
+		}
+	}
+}
+
+func TestUnigram(t *testing.T) {
+	tok := parse(t, encodeModel([]piece{
+		{"<unk>", 0, unknownType},
+		{"<s>", 0, control},
+		{"</s>", 0, control},
+		{"▁", -2, normal},
+		{"▁hello", -3, normal},
+		{"▁world", -3.5, normal},
+		{"lo", -1.5, normal},
+		{"h", -5, normal},
+		{"e", -5, normal},
+		{"l", -5, normal},
+		{"o", -5, normal},
+		{"<sep>", 0, userDefined},
+		{"▁wor", -0.1, unused},
+		{"ld", -0.5, normal},
+	}, nil, nil))
+	check(t, tok, map[string][]int{
+		"":                  nil,
+		"   ":               nil,
+		"hello world":       {4, 5},
+		"  hello   world  ": {4, 5},
+		"hel<sep>lo":        {3, 7, 8, 9, 11, 6},
+		"<sep>":             {3, 11},
+		"x☃":                {3, 0},
+		"<s>hello":          {3, 0, 7, 8, 9, 6},
+		"hello\xff":         {4, 0},
+		"hello ➊ ➋ world ➌": {4, 3, 0, 3, 0, 5, 3, 0},
+	})
+}
+
+func TestBPE(t *testing.T) {
+	pieces := []piece{
+		{"<unk>", 0, unknownType},
+		{"<s>", 0, control},
+		{"</s>", 0, control},
+		{"<0xE2>", 0, byteType},
+		{"<0x98>", 0, byteType},
+		{"<0x83>", 0, byteType},
+		{"▁", -10, normal},
+		{"h", -10, normal},
+		{"i", -10, normal},
+		{"▁h", -1, normal},
+		{"▁hi", -2, normal},
+		{"hi", 0, unused},
+		{"<b>", 0, userDefined},
+	}
+	trainer := field(3, uint64(bpeModel))
+	check(t, parse(t, encodeModel(pieces, [][]byte{trainer, field(35, uint64(1))}, nil)), map[string][]int{
+		"hi":      {10},
+		"hi ☃":    {10, 6, 3, 4, 5},
+		"hhi":     {9, 7, 8},
+		"hi<b>hi": {10, 12, 7, 8},
+		"é":       {6, 0, 0},
+	})
+
+	// Without byte fallback, runs of unknown characters are one piece.
+	check(t, parse(t, encodeModel(pieces, [][]byte{trainer}, nil)), map[string][]int{
+		"hi ☃é": {10, 6, 0},
+	})
+}
+
+func TestWordAndChar(t *testing.T) {
+	pieces := []piece{
+		{"<unk>", 0, unknownType},
+		{"▁a", 0, normal},
+		{"▁b", 0, normal},
+		{"a▁", 0, normal},
+		{"b▁", 0, normal},
+		{"▁", 0, normal},
+		{"a", 0, normal},
+	}
+	check(t, parse(t, encodeModel(pieces, [][]byte{field(3, uint64(wordModel))}, nil)), map[string][]int{
+		"a b c": {1, 2, 0},
+	})
+	suffix := [][]byte{field(3, uint64(wordModel)), field(24, uint64(1))}
+	check(t, parse(t, encodeModel(pieces, suffix, nil)), map[string][]int{
+		"a b c": {3, 4, 0},
+	})
+	check(t, parse(t, encodeModel(pieces, [][]byte{field(3, uint64(charModel))}, nil)), map[string][]int{
+		"a ab": {5, 6, 5, 6, 0},
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	pieces := []piece{{"<unk>", 0, unknownType}, {"<b>", 0, userDefined}}
+	tests := []struct {
+		trainer, normalizer [][]byte
+		text, want          string
+	}{
+		{nil, nil, "  a  b ", "▁a▁b"},
+		{nil, nil, "a<b>b", "▁a<b>b"},
+		{nil, [][]byte{field(4, uint64(0))}, " a  b ", "▁▁a▁▁b▁"},
+		{nil, [][]byte{field(3, uint64(0))}, "a b", "a▁b"},
+		{nil, [][]byte{field(5, uint64(0))}, "a  b", " a b"},
+		{[][]byte{field(24, uint64(1))}, nil, " a b ", "a▁b▁"},
+		{nil, nil, "a\xffb", "▁a�b"},
+	}
+	for _, tt := range tests {
+		tok := parse(t, encodeModel(pieces, tt.trainer, tt.normalizer))
+		if got := tok.normalize(tt.text); got != tt.want {
+			t.Errorf("normalize(%q): expected %q, got %q", tt.text, tt.want, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	unk := piece{"<unk>", 0, unknownType}
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{[]byte{0x0a, 0x05, 0x01}, "truncated message"},
+		{[]byte{0x0b}, "unsupported wire type 3"},
+		{encodeModel([]piece{{"a", 0, normal}}, nil, nil), "missing unknown piece"},
+		{encodeModel([]piece{unk, {"a", 0, normal}, {"a", 0, normal}}, nil, nil), `piece 2: "a" is already defined`},
+		{encodeModel([]piece{unk}, [][]byte{field(3, uint64(9))}, nil), "unknown model type: 9"},
+		{encodeModel([]piece{unk}, nil, [][]byte{field(2, []byte{1, 0})}), "normalizer_spec: truncated character map"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.data); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "bad.model")
+	if err := os.WriteFile(path, []byte{0x0b}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.HasPrefix(err.Error(), path+": ") {
+		t.Errorf("Expected an error naming %s, got %v", path, err)
+	}
+}
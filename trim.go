@@ -0,0 +1,48 @@
+package tokenestimate
+
+// TrimPolicy selects how TrimMessages shortens a conversation.
+type TrimPolicy int
+
+const (
+	// TrimDropOldest drops whole messages, oldest first.
+	TrimDropOldest TrimPolicy = iota
+	// TrimTruncateOldest drops whole messages, oldest first, until dropping
+	// the next one would be more than needed, then cuts the start of that
+	// message so the conversation uses as much of the budget as possible.
+	TrimTruncateOldest
+)
+
+// TrimMessages returns the most recent part of msgs whose estimated size is
+// at most budget, as measured by EstimateMessages. System messages are
+// always kept in place; the other messages are removed oldest first
+// according to policy. If the system messages alone exceed the budget,
+// only they are returned. msgs itself is not modified. The anomaly hooks
+// run on the contents of the returned messages only.
+func (e *Estimator) TrimMessages(msgs []Message, budget int, policy TrimPolicy) []Message {
+	costs := make([]int, len(msgs))
+	total := e.replyTokens()
+	for i, m := range msgs {
+		costs[i] = e.estimateQuietly(m.Content) + e.messageOverhead(m.Role)
+		total += costs[i]
+	}
+
+	trimmed := make([]Message, 0, len(msgs))
+	for i, m := range msgs {
+		if m.Role == RoleSystem || total <= budget {
+			trimmed = append(trimmed, m)
+			continue
+		}
+		if policy == TrimTruncateOldest && total-costs[i] < budget {
+			overhead := e.messageOverhead(m.Role)
+			if content := e.truncateStart(m.Content, budget-(total-costs[i])-overhead); content != "" {
+				m.Content = content
+				trimmed = append(trimmed, m)
+			}
+		}
+		total -= costs[i]
+	}
+	for _, m := range trimmed {
+		e.runAnomalyHooks(m.Content)
+	}
+	return trimmed
+}
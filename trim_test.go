@@ -0,0 +1,76 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrimMessages(t *testing.T) {
+	estimator := NewEstimator()
+	msgs := []Message{
+		{Role: RoleSystem, Content: "You are a helpful assistant."},
+		{Role: "user", Content: strings.Repeat("Tell me about the history of Rome. ", 10)},
+		{Role: "assistant", Content: strings.Repeat("Rome was founded in 753 BC. ", 10)},
+		{Role: "user", Content: "And what about Carthage?"},
+	}
+	total := estimator.EstimateMessages(msgs)
+	size := estimator.EstimateMessages
+
+	t.Run("Fits unchanged", func(t *testing.T) {
+		got := estimator.TrimMessages(msgs, total, TrimDropOldest)
+		if len(got) != len(msgs) {
+			t.Errorf("Expected %d messages, got %d", len(msgs), len(got))
+		}
+	})
+
+	t.Run("Drop oldest", func(t *testing.T) {
+		budget := total - 1
+		got := estimator.TrimMessages(msgs, budget, TrimDropOldest)
+		if len(got) != 3 || got[0] != msgs[0] || got[1] != msgs[2] || got[2] != msgs[3] {
+			t.Errorf("Expected the first user message dropped, got %+v", got)
+		}
+		if size(got) > budget {
+			t.Errorf("Trimmed conversation has %d tokens, budget %d", size(got), budget)
+		}
+	})
+
+	t.Run("Truncate oldest", func(t *testing.T) {
+		budget := total - 10
+		got := estimator.TrimMessages(msgs, budget, TrimTruncateOldest)
+		if len(got) != 4 {
+			t.Fatalf("Expected 4 messages, got %+v", got)
+		}
+		if !strings.HasSuffix(msgs[1].Content, got[1].Content) || got[1].Content == msgs[1].Content {
+			t.Errorf("Expected the start of the oldest message cut, got %q", got[1].Content)
+		}
+		if size(got) > budget {
+			t.Errorf("Trimmed conversation has %d tokens, budget %d", size(got), budget)
+		}
+		if msgs[1].Content == got[1].Content {
+			t.Error("TrimMessages modified its input")
+		}
+	})
+
+	t.Run("Preserves system prompt", func(t *testing.T) {
+		got := estimator.TrimMessages(msgs, size([]Message{msgs[0], msgs[3]}), TrimDropOldest)
+		if len(got) != 2 || got[0] != msgs[0] || got[1] != msgs[3] {
+			t.Errorf("Expected system prompt and last message, got %+v", got)
+		}
+		got = estimator.TrimMessages(msgs, 0, TrimTruncateOldest)
+		if len(got) != 1 || got[0] != msgs[0] {
+			t.Errorf("Expected only the system prompt, got %+v", got)
+		}
+	})
+
+	t.Run("Counts template overhead", func(t *testing.T) {
+		bare := estimator.Clone()
+		bare.ChatTemplate = nil
+		budget := bare.EstimateMessages(msgs)
+		if got := bare.TrimMessages(msgs, budget, TrimDropOldest); len(got) != 4 {
+			t.Errorf("Expected all messages to fit without a template, got %d", len(got))
+		}
+		if got := estimator.TrimMessages(msgs, budget, TrimDropOldest); len(got) != 3 {
+			t.Errorf("Expected the template overhead to force a drop, got %d messages", len(got))
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package tokenestimate
+
+import "sort"
+
+// Truncate returns the longest prefix of text, cut between two characters,
+// whose estimate is at most maxTokens. Text that already fits is returned
+// unchanged. The anomaly hooks run on the result only.
+func (e *Estimator) Truncate(text string, maxTokens int) string {
+	text = e.truncate(text, maxTokens)
+	e.runAnomalyHooks(text)
+	return text
+}
+
+// truncate implements Truncate without running the anomaly hooks.
+func (e *Estimator) truncate(text string, maxTokens int) string {
+	if e.estimateQuietly(text) <= maxTokens {
+		return text
+	}
+	cuts := runeCuts(text)
+	n := sort.Search(len(cuts), func(i int) bool {
+		return e.estimateQuietly(text[:cuts[i]]) > maxTokens
+	})
+	if n == 0 {
+		return ""
+	}
+	return text[:cuts[n-1]]
+}
+
+// truncateStart returns the longest suffix of text, cut between two
+// characters, whose estimate is at most maxTokens. Like truncate, it does
+// not run the anomaly hooks.
+func (e *Estimator) truncateStart(text string, maxTokens int) string {
+	if e.estimateQuietly(text) <= maxTokens {
+		return text
+	}
+	cuts := runeCuts(text)
+	n := sort.Search(len(cuts), func(i int) bool {
+		return e.estimateQuietly(text[cuts[i]:]) <= maxTokens
+	})
+	if n == len(cuts) {
+		return ""
+	}
+	return text[cuts[n]:]
+}
+
+// runeCuts returns the offset of every rune in text followed by len(text).
+func runeCuts(text string) []int {
+	cuts := make([]int, 0, len(text)+1)
+	for i := range text {
+		cuts = append(cuts, i)
+	}
+	return append(cuts, len(text))
+}
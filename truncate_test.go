@@ -0,0 +1,54 @@
+package tokenestimate
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	estimator := NewEstimator()
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+
+	t.Run("Fits unchanged", func(t *testing.T) {
+		if got := estimator.Truncate("Hello, world!", 100); got != "Hello, world!" {
+			t.Errorf("Expected text unchanged, got %q", got)
+		}
+	})
+
+	t.Run("Longest fitting prefix", func(t *testing.T) {
+		got := estimator.Truncate(text, 30)
+		if !strings.HasPrefix(text, got) {
+			t.Fatalf("Expected a prefix of the text, got %q", got)
+		}
+		if tokens := estimator.Estimate(got); tokens > 30 {
+			t.Errorf("Truncated text has %d tokens", tokens)
+		}
+		if longer := text[:len(got)+1]; estimator.Estimate(longer) <= 30 {
+			t.Errorf("Truncated text %q is not the longest prefix that fits", got)
+		}
+	})
+
+	t.Run("Cuts between characters", func(t *testing.T) {
+		got := estimator.Truncate(strings.Repeat("你好世界", 50), 10)
+		if !strings.HasPrefix(strings.Repeat("你好世界", 50), got) || !utf8.ValidString(got) {
+			t.Errorf("Expected a valid prefix, got %q", got)
+		}
+	})
+
+	t.Run("Start", func(t *testing.T) {
+		got := estimator.truncateStart(text, 30)
+		if !strings.HasSuffix(text, got) {
+			t.Fatalf("Expected a suffix of the text, got %q", got)
+		}
+		if tokens := estimator.Estimate(got); tokens > 30 {
+			t.Errorf("Truncated text has %d tokens", tokens)
+		}
+	})
+
+	t.Run("Zero budget", func(t *testing.T) {
+		if got := estimator.Truncate(text, 0); estimator.Estimate(got) != 0 || len(got) > 2 {
+			t.Errorf("Expected at most a zero-token prefix, got %q", got)
+		}
+	})
+}
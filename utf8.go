@@ -0,0 +1,68 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// UTF8Mode selects how an Estimator prices bytes that are not valid UTF-8.
+// Invalid bytes are always reported in Stats.InvalidBytes; use
+// EstimateStrict to reject such input instead.
+type UTF8Mode int
+
+const (
+	// UTF8Lenient prices each invalid byte like a U+FFFD replacement
+	// character, which is how range loops decode it.
+	UTF8Lenient UTF8Mode = iota
+	// UTF8ByteFallback prices each invalid byte with the preset's
+	// dedicated invalid-byte coefficient, matching byte-level tokenizers.
+	UTF8ByteFallback
+)
+
+// InvalidUTF8Error is returned by EstimateStrict for text that is not valid UTF-8.
+type InvalidUTF8Error struct {
+	Offset int // Byte offset of the first invalid byte
+}
+
+func (err *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("invalid UTF-8 at byte offset %d", err.Offset)
+}
+
+// WithUTF8Mode returns a clone of the estimator using the given mode for
+// invalid UTF-8 bytes.
+func (e *Estimator) WithUTF8Mode(mode UTF8Mode) *Estimator {
+	clone := e.Clone()
+	clone.UTF8Mode = mode
+	return clone
+}
+
+// EstimateStrict is like Estimate but returns an *InvalidUTF8Error if the
+// text is not valid UTF-8, and an *InputTooLargeError if it is rejected by
+// WithMaxInputBytes.
+func (e *Estimator) EstimateStrict(text string) (int, error) {
+	if err := e.checkInput(len(text)); err != nil {
+		return 0, err
+	}
+	if !utf8.ValidString(text) {
+		return 0, &InvalidUTF8Error{Offset: invalidUTF8Offset(text)}
+	}
+	return e.Estimate(text), nil
+}
+
+// invalidUTF8Offset returns the byte offset of the first invalid byte in
+// text, or -1 if text is valid UTF-8.
+func invalidUTF8Offset(text string) int {
+	for i, r := range text {
+		if r == utf8.RuneError && isInvalidAt(text, i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isInvalidAt reports whether the RuneError decoded at text[i] stands for an
+// invalid byte rather than a literal U+FFFD.
+func isInvalidAt(text string, i int) bool {
+	_, size := utf8.DecodeRuneInString(text[i:])
+	return size == 1
+}
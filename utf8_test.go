@@ -0,0 +1,73 @@
+package tokenestimate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestInvalidUTF8(t *testing.T) {
+	invalid := "abc\xff\xfedef"
+
+	t.Run("Analyze counts invalid bytes", func(t *testing.T) {
+		stats := NewEstimator().Analyze(invalid)
+		expected := Stats{LatinLetters: 6, InvalidBytes: 2, Words: 1}
+		if stats != expected {
+			t.Errorf("Expected stats %+v, got %+v", expected, stats)
+		}
+	})
+
+	t.Run("Literal replacement character is not invalid", func(t *testing.T) {
+		stats := NewEstimator().Analyze("abc�")
+		if stats.InvalidBytes != 0 || stats.Unknown != 1 {
+			t.Errorf("Expected 1 unknown and 0 invalid bytes, got %+v", stats)
+		}
+	})
+
+	t.Run("Lenient prices invalid bytes like U+FFFD", func(t *testing.T) {
+		estimator := NewEstimator()
+		want := estimator.Estimate(strings.Repeat("�", 20))
+		if got := estimator.Estimate(strings.Repeat("\xff", 20)); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Byte fallback", func(t *testing.T) {
+		estimator := NewEstimator().WithUTF8Mode(UTF8ByteFallback)
+		want := int(estimator.coefInvalidBytes*20 + 0.5)
+		if got := estimator.Estimate(strings.Repeat("\xff", 20)); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("EstimateStrict reports offset", func(t *testing.T) {
+		_, err := NewEstimator().EstimateStrict(invalid)
+		var utf8Err *InvalidUTF8Error
+		if !errors.As(err, &utf8Err) {
+			t.Fatalf("Expected *InvalidUTF8Error, got %v", err)
+		}
+		if utf8Err.Offset != 3 {
+			t.Errorf("Expected offset 3, got %d", utf8Err.Offset)
+		}
+	})
+
+	t.Run("EstimateStrict valid text", func(t *testing.T) {
+		estimator := NewEstimator()
+		got, err := estimator.EstimateStrict("Hello, 世界")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := estimator.Estimate("Hello, 世界"); got != want {
+			t.Errorf("Expected %d tokens, got %d", want, got)
+		}
+	})
+
+	t.Run("Sampling counts invalid bytes", func(t *testing.T) {
+		// 400 runes sampled with an interval of 5, coprime to the period of 4
+		estimator := NewEstimator().WithSampling(100, 70)
+		stats := estimator.Analyze(strings.Repeat("ab\xff\xff", 100))
+		if stats.InvalidBytes < 150 || stats.InvalidBytes > 250 {
+			t.Errorf("Expected around 200 invalid bytes, got %d", stats.InvalidBytes)
+		}
+	})
+}
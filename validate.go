@@ -0,0 +1,80 @@
+package tokenestimate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Bounds beyond which Validate considers a model absurd. The built-in
+// presets stay far below them: no piece of text costs more than a few
+// tokens per character or structure.
+const (
+	maxCoefficient = 100
+	maxIntercept   = 10000
+)
+
+// characterFeatures are the features counting characters or tokens of the
+// text, which cost at least nothing. The other features correct the cost
+// of characters already counted, such as the markup merged into fewer
+// tokens, and may be negative.
+var characterFeatures = map[string]bool{
+	"Symbols": true, "LatinLetters": true, "LatinExtended": true, "Digits": true,
+	"ChineseChars": true, "JapaneseKana": true, "KoreanHangul": true, "RussianChars": true, "ArabicChars": true,
+	"KhmerChars": true, "LaoChars": true, "MyanmarChars": true, "EthiopicChars": true,
+	"Spaces": true, "Tabs": true, "Unknown": true, "InvalidBytes": true,
+	"BlobChars": true, "SpecialTokens": true,
+}
+
+// Validate reports the first problem that would make the estimator's
+// numbers nonsense: a missing name, a coefficient or intercept that is not
+// finite or absurdly large, a negative coefficient for characters,
+// residual quantiles out of order, or sampling settings that contradict
+// each other. RegisterPreset, LoadPreset and Builder.Build validate the
+// estimators they are given.
+func (e *Estimator) Validate() error {
+	if e.Name == "" {
+		return errors.New("estimator has no name")
+	}
+	if e.Version < 0 {
+		return fmt.Errorf("invalid preset version: %d", e.Version)
+	}
+
+	if math.IsNaN(e.intercept) || math.Abs(e.intercept) > maxIntercept {
+		return fmt.Errorf("intercept %v is out of range", e.intercept)
+	}
+	for _, f := range features {
+		p := e.coefficientField(f.name)
+		switch {
+		case p == nil:
+		case math.IsNaN(*p) || math.Abs(*p) > maxCoefficient:
+			return fmt.Errorf("coefficient %s is out of range: %v", f.name, *p)
+		case *p < 0 && characterFeatures[f.name]:
+			return fmt.Errorf("coefficient %s is negative: %v", f.name, *p)
+		}
+	}
+
+	p10, p90 := e.residualP10, e.residualP90
+	if math.IsNaN(p10) || math.IsNaN(p90) || p10 < 0 || p90 < 0 || p90 > 0 && p10 > p90 || math.IsInf(p90, 0) {
+		return fmt.Errorf("invalid residual quantiles: %v, %v", p10, p90)
+	}
+
+	switch {
+	case e.SamplingThreshold < 0 || e.SamplingSize < 0 || e.SamplingBlockSize < 0:
+		return fmt.Errorf("negative sampling threshold, size or block size: %d, %d, %d",
+			e.SamplingThreshold, e.SamplingSize, e.SamplingBlockSize)
+	case e.EnableSampling && (e.SamplingThreshold == 0 || e.SamplingSize == 0):
+		return errors.New("sampling is enabled without a threshold or a sample size")
+	case e.EnableSampling && e.SamplingSize > e.SamplingThreshold:
+		return fmt.Errorf("sample size %d exceeds the sampling threshold %d", e.SamplingSize, e.SamplingThreshold)
+	case e.SamplingBlockSize > 0 && e.SamplingSize > 0 && e.SamplingBlockSize > e.SamplingSize:
+		return fmt.Errorf("sampling block size %d exceeds the sample size %d", e.SamplingBlockSize, e.SamplingSize)
+	case e.SamplingStrategy < SamplingStride || e.SamplingStrategy > SamplingStratified:
+		return fmt.Errorf("unknown sampling strategy: %d", e.SamplingStrategy)
+	case math.IsNaN(e.SamplingTargetError) || e.SamplingTargetError < 0:
+		return fmt.Errorf("invalid sampling target error: %v", e.SamplingTargetError)
+	case e.Concurrency < 0:
+		return fmt.Errorf("negative concurrency: %d", e.Concurrency)
+	}
+	return nil
+}
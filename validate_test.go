@@ -0,0 +1,77 @@
+package tokenestimate
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, name := range ListPresets() {
+		e, err := GetPresetByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := e.Validate(); err != nil {
+			t.Errorf("Preset %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(e *Estimator)
+		want   string // error substring, empty if valid
+	}{
+		{"valid", func(e *Estimator) {}, ""},
+		{"negative discount", func(e *Estimator) { e.coefMarkdownFences = -2 }, ""},
+		{"no name", func(e *Estimator) { e.Name = "" }, "has no name"},
+		{"negative version", func(e *Estimator) { e.Version = -1 }, "invalid preset version"},
+		{"NaN coefficient", func(e *Estimator) { e.coefChinese = math.NaN() }, "ChineseChars is out of range"},
+		{"infinite coefficient", func(e *Estimator) { e.coefDigits = math.Inf(1) }, "Digits is out of range"},
+		{"absurd coefficient", func(e *Estimator) { e.coefLatinLetters = 1e6 }, "LatinLetters is out of range"},
+		{"negative characters", func(e *Estimator) { e.coefLatinLetters = -0.1 }, "LatinLetters is negative"},
+		{"NaN intercept", func(e *Estimator) { e.intercept = math.NaN() }, "intercept"},
+		{"residuals out of order", func(e *Estimator) { e.residualP10, e.residualP90 = 1.2, 0.8 }, "residual quantiles"},
+		{"sampling without size", func(e *Estimator) { e.EnableSampling, e.SamplingSize = true, 0 }, "without a threshold or a sample size"},
+		{"sample above threshold", func(e *Estimator) {
+			e.EnableSampling, e.SamplingThreshold, e.SamplingSize = true, 1000, 2000
+		}, "exceeds the sampling threshold"},
+		{"block above sample", func(e *Estimator) { e.SamplingSize, e.SamplingBlockSize = 100, 200 }, "exceeds the sample size"},
+		{"unknown strategy", func(e *Estimator) { e.SamplingStrategy = SamplingStratified + 1 }, "unknown sampling strategy"},
+		{"negative target error", func(e *Estimator) { e.SamplingTargetError = -0.01 }, "sampling target error"},
+		{"negative concurrency", func(e *Estimator) { e.Concurrency = -1 }, "negative concurrency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEstimator()
+			tt.modify(e)
+			err := e.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Unexpected error: %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRegisterPresetValidates(t *testing.T) {
+	e := NewEstimator()
+	e.Name, e.Version = "invalid-test", 0
+	e.coefLatinLetters = math.NaN()
+	err := RegisterPreset(e)
+	if err == nil || !strings.Contains(err.Error(), "preset invalid-test: ") {
+		t.Fatalf("Expected an error for preset invalid-test, got %v", err)
+	}
+	if _, err := GetPresetByName("invalid-test"); err == nil {
+		t.Error("Expected the invalid preset not to be registered")
+	}
+
+	if _, err := LoadPreset(strings.NewReader(`{"name": "invalid-test", "coefficients": {"Digits": -1}}`)); err == nil {
+		t.Error("Expected LoadPreset to reject a negative coefficient")
+	}
+	if _, err := NewBuilder("invalid-test").Coef("Digits", 1e9).Build(); err == nil {
+		t.Error("Expected Build to reject an absurd coefficient")
+	}
+}
@@ -0,0 +1,247 @@
+package tokenestimate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a line of a YAML document without its indentation and
+// comment.
+type yamlLine struct {
+	num    int // 1-based line number
+	indent int
+	text   string
+}
+
+// parseYAML parses the block-style subset of YAML that configuration files
+// such as preset files use: nested mappings, sequences, flow sequences of
+// scalars, and plain, single- and double-quoted scalars, with comments. It
+// returns the values encoding/json decodes into an any: maps, slices,
+// strings, float64s, bools and nils.
+func parseYAML(data string) (any, error) {
+	var lines []yamlLine
+	for i, text := range strings.Split(data, "\n") {
+		text = strings.TrimRight(stripYAMLComment(strings.TrimSuffix(text, "\r")), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || len(lines) == 0 && trimmed == "---" {
+			continue
+		}
+		if trimmed == "..." {
+			break
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed in indentation", i+1)
+		}
+		lines = append(lines, yamlLine{num: i + 1, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	p := &yamlParser{lines: lines}
+	v, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[p.pos].num)
+	}
+	return v, nil
+}
+
+// yamlParser parses the block at the current line.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block parses the mapping or sequence whose lines are indented by indent.
+func (p *yamlParser) block(indent int) (any, error) {
+	if isYAMLItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+// sequence parses the items of a sequence indented by indent.
+func (p *yamlParser) sequence(indent int) (any, error) {
+	items := []any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLItem(p.lines[p.pos].text) {
+		line := p.lines[p.pos]
+		text := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		p.pos++
+		if text == "" {
+			v, err := p.nested(indent, false)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+			continue
+		}
+		if _, _, ok := splitYAMLKey(text); ok {
+			return nil, fmt.Errorf("line %d: mappings in sequence items are not supported", line.num)
+		}
+		v, err := parseYAMLValue(text, line.num)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+// mapping parses the entries of a mapping indented by indent.
+func (p *yamlParser) mapping(indent int) (any, error) {
+	m := map[string]any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		key, value, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected a key: %s", line.num, line.text)
+		}
+		key, err := parseYAMLKey(key, line.num)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key: %s", line.num, key)
+		}
+		p.pos++
+		var v any
+		if value == "" {
+			v, err = p.nested(indent, true)
+		} else {
+			v, err = parseYAMLValue(value, line.num)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// nested parses the block after a key or sequence item without a value,
+// which is indented further than indent, or a sequence at indent after a
+// key. It is nil if there is none.
+func (p *yamlParser) nested(indent int, key bool) (any, error) {
+	if p.pos == len(p.lines) {
+		return nil, nil
+	}
+	next := p.lines[p.pos]
+	if next.indent > indent || key && next.indent == indent && isYAMLItem(next.text) {
+		return p.block(next.indent)
+	}
+	return nil, nil
+}
+
+func isYAMLItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey splits "key: value" at the first colon outside quotes that
+// is followed by a space or ends the line.
+func splitYAMLKey(text string) (key, value string, ok bool) {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// stripYAMLComment removes a comment, which starts with a "#" at the start
+// of the line or after whitespace, outside quotes.
+func stripYAMLComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+func parseYAMLKey(key string, num int) (string, error) {
+	v, err := parseYAMLValue(key, num)
+	if err != nil {
+		return "", err
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return key, nil
+}
+
+var yamlNumber = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// parseYAMLValue parses a scalar or a flow sequence of scalars.
+func parseYAMLValue(text string, num int) (any, error) {
+	switch {
+	case strings.HasPrefix(text, `"`):
+		s, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid double-quoted string: %s", num, text)
+		}
+		return s, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") || strings.Contains(strings.ReplaceAll(text[1:len(text)-1], "''", ""), "'") {
+			return nil, fmt.Errorf("line %d: invalid single-quoted string: %s", num, text)
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	case strings.HasPrefix(text, "["):
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf("line %d: unterminated flow sequence: %s", num, text)
+		}
+		items := []any{}
+		if inner := strings.TrimSpace(text[1 : len(text)-1]); inner != "" {
+			for _, item := range strings.Split(inner, ",") {
+				v, err := parseYAMLValue(strings.TrimSpace(item), num)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, v)
+			}
+		}
+		return items, nil
+	case text == "{}":
+		return map[string]any{}, nil
+	case strings.HasPrefix(text, "{"), strings.HasPrefix(text, "|"), strings.HasPrefix(text, ">"),
+		strings.HasPrefix(text, "&"), strings.HasPrefix(text, "*"), strings.HasPrefix(text, "!"):
+		return nil, fmt.Errorf("line %d: unsupported YAML: %s", num, text)
+	}
+	switch text {
+	case "null", "Null", "NULL", "~":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if yamlNumber.MatchString(text) {
+		return strconv.ParseFloat(text, 64)
+	}
+	return text, nil
+}
@@ -0,0 +1,74 @@
+package tokenestimate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	doc := `---
+# A preset
+name: my-model   # trailing comment
+version: 2
+quoted: "a: b # c"
+single: 'it''s'
+empty:
+flag: true
+nothing: ~
+coefficients:
+  LatinLetters: 0.21
+  "Chinese Chars": -1.5e-1
+scripts:
+- Latin
+- Chinese
+flow: [1, two, "three"]
+nested:
+  list:
+    - x
+`
+	got, err := parseYAML(doc)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string]any{
+		"name":         "my-model",
+		"version":      2.0,
+		"quoted":       "a: b # c",
+		"single":       "it's",
+		"empty":        nil,
+		"flag":         true,
+		"nothing":      nil,
+		"coefficients": map[string]any{"LatinLetters": 0.21, "Chinese Chars": -0.15},
+		"scripts":      []any{"Latin", "Chinese"},
+		"flow":         []any{1.0, "two", "three"},
+		"nested":       map[string]any{"list": []any{"x"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	invalid := map[string]string{
+		"a: 1\na: 2":          "line 2: duplicate key: a",
+		"a: 1\n  b: 2":        "line 2: unexpected indentation",
+		"a:\n\t- b":           "line 2: tabs are not allowed",
+		"just text":           "line 1: expected a key",
+		"a: |\n  text":        "line 1: unsupported YAML",
+		"a:\n  - b: c":        "line 2: mappings in sequence items are not supported",
+		`a: "unterminated`:    "line 1: invalid double-quoted string",
+		"a: [1, 2":            "line 1: unterminated flow sequence",
+		"a: 1\n...\nignored ": "",
+	}
+	for doc, want := range invalid {
+		_, err := parseYAML(doc)
+		if want == "" {
+			if err != nil {
+				t.Errorf("parseYAML(%q): unexpected error: %v", doc, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parseYAML(%q): expected an error containing %q, got %v", doc, want, err)
+		}
+	}
+}